@@ -16,6 +16,7 @@ import (
 	internal "maxBot/internal/bot"
 	"maxBot/internal/di"
 	"maxBot/internal/repository"
+	"maxBot/internal/scheduler"
 )
 
 // main запускает бота с graceful shutdown
@@ -64,12 +65,30 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to init auth validator: %v", err)
 	}
+	authValidator.SetRevocationList(services.SessionService)
+	authValidator.SetReplayStore(services.SessionService)
+	go reloadAuthKeysOnSIGHUP(ctx, authValidator)
 
 	server, err := api.NewServer(cfg, services, authValidator)
 	if err != nil {
 		log.Fatalf("Failed to init HTTP server: %v", err)
 	}
 
+	botCfg, err := internal.LoadConfigFromEnv(token)
+	if err != nil {
+		log.Fatalf("Bot config error: %v", err)
+	}
+
+	bot, err := internal.NewBot(ctx, services, botCfg)
+	if err != nil {
+		log.Fatalf("Failed to create bot: %v", err)
+	}
+	defer bot.Close()
+
+	if botCfg.Transport == internal.TransportWebhook {
+		server.Handle(http.MethodPost, botCfg.WebhookPath(), bot.WebhookHandler())
+	}
+
 	go func() {
 		log.Printf("HTTPS server listening on %s", cfg.Addr)
 		if err := server.Run(); err != nil && err != http.ErrServerClosed {
@@ -87,13 +106,51 @@ func main() {
 		}
 	}()
 
-	bot, err := internal.NewBot(ctx, services)
-	if err != nil {
-		log.Fatalf("Failed to create bot: %v", err)
+	schedulerDone := make(chan struct{})
+	schedulerCfg := scheduler.LoadConfigFromEnv()
+	if schedulerCfg.Enabled {
+		notificationScheduler, err := scheduler.New(schedulerCfg, services)
+		if err != nil {
+			log.Fatalf("Failed to create scheduler: %v", err)
+		}
+		go func() {
+			defer close(schedulerDone)
+			notificationScheduler.Run(ctx)
+		}()
+	} else {
+		close(schedulerDone)
+	}
+
+	if err := bot.Run(ctx); err != nil {
+		log.Printf("Bot error: %v", err)
 	}
-	defer bot.Close()
 
-	bot.Start(ctx)
+	// Дожидаемся, пока планировщик сохранит результат последней отправки.
+	stop()
+	<-schedulerDone
 
 	log.Println("Bot stopped")
 }
+
+// reloadAuthKeysOnSIGHUP перечитывает ключи JWT по SIGHUP, чтобы ротация не требовала рестарта.
+// При ошибке остаются прежние ключи.
+func reloadAuthKeysOnSIGHUP(ctx context.Context, validator *auth.Validator) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			keys, err := auth.LoadKeySetFromEnv()
+			if err != nil {
+				log.Printf("JWT keys reload failed, keeping previous keys: %v", err)
+				continue
+			}
+			validator.SetKeySet(keys)
+			log.Printf("JWT keys reloaded, current kid: %s", keys.Current().ID)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+-- Drafts keep the state of the organizer event-creation wizard between bot steps
+CREATE TABLE event_drafts (
+    organizer_id BIGINT PRIMARY KEY REFERENCES organizers(id) ON DELETE CASCADE,
+    title TEXT,
+    description TEXT,
+    date TIMESTAMP,
+    duration_hours INT,
+    location TEXT,
+    location_lat DECIMAL(10, 8),
+    location_lon DECIMAL(11, 8),
+    category_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    max_volunteers INT,
+    contacts TEXT,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- State params keep context between bot steps that wait for free-text input
+CREATE TABLE user_state_params (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    params JSONB NOT NULL DEFAULT '{}'::jsonb,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
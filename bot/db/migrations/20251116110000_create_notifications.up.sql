@@ -0,0 +1,19 @@
+-- Notifications are persisted jobs for the scheduler: reminders and application updates
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    event_id INT REFERENCES events(id) ON DELETE CASCADE,
+    application_id INT REFERENCES volunteer_applications(id) ON DELETE CASCADE,
+    dedup_key TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'cancelled')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    scheduled_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_status_scheduled_at ON notifications(status, scheduled_at);
+CREATE INDEX idx_notifications_user_id ON notifications(user_id);
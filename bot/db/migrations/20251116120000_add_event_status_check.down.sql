@@ -0,0 +1,2 @@
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+UPDATE events SET status = 'open' WHERE status IN ('full', 'in_progress');
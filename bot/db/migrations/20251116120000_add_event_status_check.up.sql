@@ -0,0 +1,11 @@
+-- Event status becomes an explicit state machine: open -> full -> in_progress -> completed, cancelled from any active state
+UPDATE events SET status = 'open' WHERE status IS NULL OR status NOT IN ('open', 'full', 'in_progress', 'completed', 'cancelled');
+
+UPDATE events
+SET status = 'full'
+WHERE status = 'open'
+  AND COALESCE(current_volunteers, 0) >= max_volunteers;
+
+ALTER TABLE events ALTER COLUMN status SET DEFAULT 'open';
+ALTER TABLE events
+    ADD CONSTRAINT events_status_check CHECK (status IN ('open', 'full', 'in_progress', 'completed', 'cancelled'));
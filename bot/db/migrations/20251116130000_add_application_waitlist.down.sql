@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_volunteer_applications_waitlist;
@@ -0,0 +1,4 @@
+-- Waitlisted applications form a queue per event ordered by applied_at
+CREATE INDEX idx_volunteer_applications_waitlist
+    ON volunteer_applications(event_id, applied_at, id)
+    WHERE status = 'waitlisted';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS event_check_in_codes;
+
+ALTER TABLE event_participants
+    DROP COLUMN IF EXISTS attendance_marked_by,
+    DROP COLUMN IF EXISTS check_in_method,
+    DROP COLUMN IF EXISTS checked_out_at,
+    DROP COLUMN IF EXISTS checked_in_at;
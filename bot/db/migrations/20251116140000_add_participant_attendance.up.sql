@@ -0,0 +1,15 @@
+-- Attendance of event participants: check-in/check-out marks and who made them
+ALTER TABLE event_participants
+    ADD COLUMN checked_in_at TIMESTAMP,
+    ADD COLUMN checked_out_at TIMESTAMP,
+    ADD COLUMN check_in_method TEXT CHECK (check_in_method IN ('code', 'geo', 'organizer')),
+    ADD COLUMN attendance_marked_by BIGINT REFERENCES organizers(id) ON DELETE SET NULL;
+
+-- Short-lived check-in codes that organizers show to volunteers at the event
+CREATE TABLE event_check_in_codes (
+    event_id INT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_by BIGINT REFERENCES organizers(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
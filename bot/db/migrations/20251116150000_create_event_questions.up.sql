@@ -0,0 +1,23 @@
+-- Per-event questionnaire that volunteers fill in when applying
+CREATE TABLE event_questions (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('text', 'single_choice', 'multiple_choice', 'yes_no')),
+    text TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (event_id, position)
+);
+
+-- Answers are stored with the application; choice questions keep the selected options
+CREATE TABLE application_answers (
+    application_id INT NOT NULL REFERENCES volunteer_applications(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES event_questions(id) ON DELETE CASCADE,
+    answer TEXT[] NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (application_id, question_id)
+);
+
+CREATE INDEX idx_application_answers_question ON application_answers(question_id);
@@ -0,0 +1,22 @@
+-- Web app sessions: access tokens carry the session id as jti, so revoking a session kills them at once
+CREATE TABLE auth_sessions (
+    id TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    user_data JSONB NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_sessions_user_id ON auth_sessions(user_id);
+
+-- Refresh tokens are single-use: a used token is kept to detect replays, only its hash is stored
+CREATE TABLE auth_refresh_tokens (
+    token_hash TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES auth_sessions(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_refresh_tokens_session_id ON auth_refresh_tokens(session_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_events_location_point;
+DROP INDEX IF EXISTS idx_events_location_earth;
+
+CREATE INDEX IF NOT EXISTS idx_events_location_gist ON events USING gist (location_lon, location_lat);
+
+DROP EXTENSION IF EXISTS earthdistance;
+DROP EXTENSION IF EXISTS cube;
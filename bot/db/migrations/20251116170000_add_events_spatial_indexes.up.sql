@@ -0,0 +1,14 @@
+-- earthdistance (on top of cube) ships with contrib and is available in the stock postgres image
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- btree_gist over raw decimals helps neither radius nor bounding-box searches
+DROP INDEX IF EXISTS idx_events_location_gist;
+
+-- Radius search: earth_box(ll_to_earth(lat, lon), meters) @> ll_to_earth(...)
+CREATE INDEX idx_events_location_earth ON events
+    USING gist (ll_to_earth(location_lat::float8, location_lon::float8));
+
+-- Bounding-box search: point(lon, lat) <@ box(...)
+CREATE INDEX idx_events_location_point ON events
+    USING gist (point(location_lon::float8, location_lat::float8));
@@ -0,0 +1,8 @@
+-- Personal iCalendar feed tokens: one active token per user, only its hash is stored.
+-- Calendar apps cannot send headers, so the token travels in the feed URL; rotating or deleting it revokes the URL.
+CREATE TABLE calendar_feed_tokens (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE volunteer_applications DROP COLUMN IF EXISTS message;
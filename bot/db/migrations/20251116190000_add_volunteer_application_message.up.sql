@@ -0,0 +1,4 @@
+-- Volunteer's cover message for an application.
+-- The shipped 20251115110000 migration is empty, so the column is added here; IF NOT EXISTS
+-- keeps databases that already got it from an edited copy of that file working.
+ALTER TABLE volunteer_applications ADD COLUMN IF NOT EXISTS message TEXT;
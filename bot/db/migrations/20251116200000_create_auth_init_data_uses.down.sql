@@ -0,0 +1 @@
+DROP TABLE IF EXISTS auth_init_data_uses;
@@ -0,0 +1,8 @@
+-- Used init data keys (hash and query_id), shared by all API replicas so that init data
+-- can be exchanged for a session only once across the deployment, not once per process.
+CREATE TABLE auth_init_data_uses (
+    key TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_auth_init_data_uses_expires_at ON auth_init_data_uses(expires_at);
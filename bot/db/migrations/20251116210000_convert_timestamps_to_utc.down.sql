@@ -0,0 +1,18 @@
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT c.table_name, c.column_name
+        FROM information_schema.columns c
+        JOIN information_schema.tables t USING (table_schema, table_name)
+        WHERE c.table_schema = current_schema()
+          AND t.table_type = 'BASE TABLE'
+          AND c.data_type = 'timestamp without time zone'
+    LOOP
+        EXECUTE format(
+            'UPDATE %I SET %I = (%I AT TIME ZONE ''UTC'') AT TIME ZONE current_setting(''TimeZone'') WHERE %I IS NOT NULL',
+            col.table_name, col.column_name, col.column_name, col.column_name
+        );
+    END LOOP;
+END $$;
@@ -0,0 +1,21 @@
+-- TIMESTAMP columns used to hold the server's local wall time; from now on they hold UTC.
+-- Existing rows are shifted from the session time zone, which must match the bot's TZ
+-- (the old scheduler compared these columns with NOW() and relied on the same assumption).
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT c.table_name, c.column_name
+        FROM information_schema.columns c
+        JOIN information_schema.tables t USING (table_schema, table_name)
+        WHERE c.table_schema = current_schema()
+          AND t.table_type = 'BASE TABLE'
+          AND c.data_type = 'timestamp without time zone'
+    LOOP
+        EXECUTE format(
+            'UPDATE %I SET %I = (%I AT TIME ZONE current_setting(''TimeZone'')) AT TIME ZONE ''UTC'' WHERE %I IS NOT NULL',
+            col.table_name, col.column_name, col.column_name, col.column_name
+        );
+    END LOOP;
+END $$;
@@ -0,0 +1,86 @@
+-- name: CreateAuthSession :one
+INSERT INTO auth_sessions (
+    id,
+    user_id,
+    user_data,
+    expires_at
+) VALUES (
+    sqlc.arg(id),
+    sqlc.arg(user_id),
+    sqlc.arg(user_data),
+    sqlc.arg(expires_at)
+)
+RETURNING *;
+
+-- name: GetAuthSessionForUpdate :one
+SELECT *
+FROM auth_sessions
+WHERE id = sqlc.arg(id)
+FOR UPDATE;
+
+-- name: ExtendAuthSession :one
+UPDATE auth_sessions
+SET expires_at = sqlc.arg(expires_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- Сессия активна, пока не отозвана и не истекла. Время сравнивается в UTC, как его пишет сервис.
+-- name: IsAuthSessionActive :one
+SELECT EXISTS (
+    SELECT 1
+    FROM auth_sessions
+    WHERE id = sqlc.arg(id)
+      AND revoked_at IS NULL
+      AND expires_at > sqlc.arg(now)
+);
+
+-- name: RevokeAuthSession :exec
+UPDATE auth_sessions
+SET revoked_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND revoked_at IS NULL;
+
+-- name: RevokeUserAuthSessions :exec
+UPDATE auth_sessions
+SET revoked_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+  AND revoked_at IS NULL;
+
+-- name: CreateRefreshToken :exec
+INSERT INTO auth_refresh_tokens (
+    token_hash,
+    session_id,
+    expires_at
+) VALUES (
+    sqlc.arg(token_hash),
+    sqlc.arg(session_id),
+    sqlc.arg(expires_at)
+);
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT *
+FROM auth_refresh_tokens
+WHERE token_hash = sqlc.arg(token_hash)
+FOR UPDATE;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE auth_refresh_tokens
+SET used_at = NOW()
+WHERE token_hash = sqlc.arg(token_hash);
+
+-- Отмечает ключ initData использованным. Возвращает 0 строк, если ключ уже занят и ещё не истёк.
+-- name: ClaimInitDataKey :execrows
+INSERT INTO auth_init_data_uses (
+    key,
+    expires_at
+) VALUES (
+    sqlc.arg(key),
+    sqlc.arg(expires_at)
+)
+ON CONFLICT (key) DO UPDATE
+SET expires_at = EXCLUDED.expires_at
+WHERE auth_init_data_uses.expires_at <= sqlc.arg(now);
+
+-- name: DeleteExpiredInitDataKeys :exec
+DELETE FROM auth_init_data_uses
+WHERE expires_at <= sqlc.arg(now);
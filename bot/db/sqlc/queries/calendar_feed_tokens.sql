@@ -0,0 +1,24 @@
+-- Новый токен сразу заменяет прежний, старая ссылка на календарь перестаёт работать.
+-- name: UpsertCalendarFeedToken :exec
+INSERT INTO calendar_feed_tokens (
+    user_id,
+    token_hash
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(token_hash)
+)
+ON CONFLICT (user_id) DO UPDATE
+SET token_hash = EXCLUDED.token_hash,
+    last_used_at = NULL,
+    created_at = NOW();
+
+-- Возвращает владельца токена и отмечает обращение календаря к ленте.
+-- name: UseCalendarFeedToken :one
+UPDATE calendar_feed_tokens
+SET last_used_at = NOW()
+WHERE token_hash = sqlc.arg(token_hash)
+RETURNING user_id;
+
+-- name: DeleteCalendarFeedToken :exec
+DELETE FROM calendar_feed_tokens
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,27 @@
+-- name: UpsertEventCheckInCode :one
+INSERT INTO event_check_in_codes (
+    event_id,
+    code,
+    expires_at,
+    created_by
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(code),
+    sqlc.arg(expires_at),
+    sqlc.arg(created_by)
+)
+ON CONFLICT (event_id) DO UPDATE
+SET code = EXCLUDED.code,
+    expires_at = EXCLUDED.expires_at,
+    created_by = EXCLUDED.created_by,
+    created_at = NOW()
+RETURNING *;
+
+-- name: GetEventCheckInCode :one
+SELECT *
+FROM event_check_in_codes
+WHERE event_id = sqlc.arg(event_id);
+
+-- name: DeleteEventCheckInCode :exec
+DELETE FROM event_check_in_codes
+WHERE event_id = sqlc.arg(event_id);
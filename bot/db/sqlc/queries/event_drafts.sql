@@ -0,0 +1,49 @@
+-- name: GetEventDraft :one
+SELECT *
+FROM event_drafts
+WHERE organizer_id = sqlc.arg(organizer_id);
+
+-- name: UpsertEventDraft :one
+INSERT INTO event_drafts (
+    organizer_id,
+    title,
+    description,
+    date,
+    duration_hours,
+    location,
+    location_lat,
+    location_lon,
+    category_id,
+    max_volunteers,
+    contacts
+) VALUES (
+    sqlc.arg(organizer_id),
+    sqlc.arg(title),
+    sqlc.arg(description),
+    sqlc.arg(date),
+    sqlc.arg(duration_hours),
+    sqlc.arg(location),
+    sqlc.arg(location_lat),
+    sqlc.arg(location_lon),
+    sqlc.arg(category_id),
+    sqlc.arg(max_volunteers),
+    sqlc.arg(contacts)
+)
+ON CONFLICT (organizer_id) DO UPDATE
+SET
+    title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    date = EXCLUDED.date,
+    duration_hours = EXCLUDED.duration_hours,
+    location = EXCLUDED.location,
+    location_lat = EXCLUDED.location_lat,
+    location_lon = EXCLUDED.location_lon,
+    category_id = EXCLUDED.category_id,
+    max_volunteers = EXCLUDED.max_volunteers,
+    contacts = EXCLUDED.contacts,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteEventDraft :exec
+DELETE FROM event_drafts
+WHERE organizer_id = sqlc.arg(organizer_id);
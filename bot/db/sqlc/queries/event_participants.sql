@@ -56,3 +56,54 @@ OFFSET sqlc.arg('offset')::int;
 SELECT COUNT(*) AS count
 FROM event_participants
 WHERE event_id = sqlc.arg(event_id);
+
+-- name: CheckInParticipant :one
+-- Отмечает приход участника; повторная отметка не перезаписывает время.
+UPDATE event_participants
+SET checked_in_at = NOW(),
+    check_in_method = sqlc.arg(check_in_method),
+    attendance_marked_by = sqlc.narg(marked_by)
+WHERE event_id = sqlc.arg(event_id)
+  AND volunteer_id = sqlc.arg(volunteer_id)
+  AND checked_in_at IS NULL
+RETURNING *;
+
+-- name: CheckOutParticipant :one
+-- Отмечает уход участника, который ранее отметил приход.
+UPDATE event_participants
+SET checked_out_at = NOW(),
+    attendance_marked_by = COALESCE(sqlc.narg(marked_by), attendance_marked_by)
+WHERE event_id = sqlc.arg(event_id)
+  AND volunteer_id = sqlc.arg(volunteer_id)
+  AND checked_in_at IS NOT NULL
+  AND checked_out_at IS NULL
+RETURNING *;
+
+-- name: ResetParticipantAttendance :one
+UPDATE event_participants
+SET checked_in_at = NULL,
+    checked_out_at = NULL,
+    check_in_method = NULL,
+    attendance_marked_by = NULL
+WHERE event_id = sqlc.arg(event_id)
+  AND volunteer_id = sqlc.arg(volunteer_id)
+RETURNING *;
+
+-- name: GetVolunteerHoursServed :one
+-- Суммирует время участия: от отметки прихода до ухода, а если уход не отмечен
+-- и событие завершено — до запланированного окончания события. Для события без длительности
+-- окончание считается так же, как в CompletePastEvents: через default_duration_minutes.
+SELECT
+    (COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM (
+        COALESCE(
+            ep.checked_out_at,
+            CASE WHEN e.status = 'completed'
+                THEN e.date + COALESCE(make_interval(hours => e.duration_hours), make_interval(mins => sqlc.arg(default_duration_minutes)::int))
+            END
+        ) - ep.checked_in_at
+    )), 0)), 0) / 3600)::float8 AS hours_served,
+    COUNT(*) AS events_attended
+FROM event_participants ep
+JOIN events e ON e.id = ep.event_id
+WHERE ep.volunteer_id = sqlc.arg(volunteer_id)
+  AND ep.checked_in_at IS NOT NULL;
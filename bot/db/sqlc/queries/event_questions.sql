@@ -0,0 +1,51 @@
+-- name: CreateEventQuestion :one
+INSERT INTO event_questions (
+    event_id,
+    position,
+    type,
+    text,
+    options,
+    required
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(position),
+    sqlc.arg(type),
+    sqlc.arg(text),
+    sqlc.arg(options),
+    sqlc.arg(required)
+)
+RETURNING *;
+
+-- name: ListEventQuestions :many
+SELECT *
+FROM event_questions
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY position ASC, id ASC;
+
+-- name: DeleteEventQuestions :exec
+DELETE FROM event_questions
+WHERE event_id = sqlc.arg(event_id);
+
+-- name: CreateApplicationAnswer :exec
+INSERT INTO application_answers (
+    application_id,
+    question_id,
+    answer
+) VALUES (
+    sqlc.arg(application_id),
+    sqlc.arg(question_id),
+    sqlc.arg(answer)
+);
+
+-- name: ListApplicationAnswers :many
+-- Ответы на анкету для набора заявок в порядке вопросов.
+SELECT
+    aa.application_id,
+    aa.question_id,
+    q.type AS question_type,
+    q.text AS question_text,
+    aa.answer
+FROM application_answers aa
+JOIN event_questions q ON q.id = aa.question_id
+WHERE aa.application_id = ANY(sqlc.arg(application_ids)::int[])
+ORDER BY aa.application_id ASC, q.position ASC, q.id ASC;
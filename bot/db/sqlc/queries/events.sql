@@ -50,16 +50,24 @@ SET
     category_id = sqlc.arg(category_id),
     contacts = sqlc.arg(contacts),
     max_volunteers = sqlc.arg(max_volunteers),
+    status = CASE
+        WHEN status NOT IN ('open', 'full') THEN status
+        WHEN COALESCE(current_volunteers, 0) >= sqlc.arg(max_volunteers) THEN 'full'
+        ELSE 'open'
+    END,
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
 -- name: UpdateEventStatus :one
+-- Меняет статус, только если событие всё ещё в статусе from_status (проверка перехода в сервисе).
 UPDATE events
 SET
     status = sqlc.arg(status),
+    completed_at = CASE WHEN sqlc.arg(status) = 'completed' THEN NOW() ELSE completed_at END,
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(from_status)
 RETURNING *;
 
 -- name: CancelEvent :one
@@ -69,6 +77,7 @@ SET
     cancelled_reason = sqlc.arg(reason),
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(from_status)
 RETURNING *;
 
 -- name: CompleteEvent :one
@@ -78,12 +87,19 @@ SET
     completed_at = NOW(),
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(from_status)
 RETURNING *;
 
 -- name: IncrementEventVolunteers :one
+-- Вместе со счётчиком переводит событие между open и full.
 UPDATE events
 SET
     current_volunteers = current_volunteers + sqlc.arg(delta),
+    status = CASE
+        WHEN status NOT IN ('open', 'full') THEN status
+        WHEN current_volunteers + sqlc.arg(delta) >= max_volunteers THEN 'full'
+        ELSE 'open'
+    END,
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING current_volunteers;
@@ -93,10 +109,45 @@ UPDATE events
 SET
     current_volunteers = sqlc.arg(current_volunteers),
     max_volunteers = sqlc.arg(max_volunteers),
+    status = CASE
+        WHEN status NOT IN ('open', 'full') THEN status
+        WHEN sqlc.arg(current_volunteers) >= sqlc.arg(max_volunteers) THEN 'full'
+        ELSE 'open'
+    END,
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING current_volunteers, max_volunteers;
 
+-- name: SyncEventCapacityStatuses :many
+-- Приводит статус open/full в соответствие со счётчиком участников.
+UPDATE events
+SET
+    status = CASE WHEN COALESCE(current_volunteers, 0) >= max_volunteers THEN 'full' ELSE 'open' END,
+    updated_at = NOW()
+WHERE status IN ('open', 'full')
+  AND status <> CASE WHEN COALESCE(current_volunteers, 0) >= max_volunteers THEN 'full' ELSE 'open' END
+RETURNING *;
+
+-- name: StartDueEvents :many
+UPDATE events
+SET
+    status = 'in_progress',
+    updated_at = NOW()
+WHERE status IN ('open', 'full')
+  AND date <= NOW()
+RETURNING *;
+
+-- name: CompletePastEvents :many
+-- Завершает события, которые закончились. Если длительность не указана, используется default_duration_minutes.
+UPDATE events
+SET
+    status = 'completed',
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE status IN ('open', 'full', 'in_progress')
+  AND date + COALESCE(make_interval(hours => duration_hours), make_interval(mins => sqlc.arg(default_duration_minutes)::int)) <= NOW()
+RETURNING *;
+
 -- name: GetEventByID :one
 SELECT *
 FROM events
@@ -125,7 +176,7 @@ SELECT COUNT(*) FROM events;
 -- name: CountAvailableEventsForVolunteer :one
 SELECT COUNT(*)
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND NOT EXISTS (
     SELECT 1
     FROM volunteer_applications va
@@ -140,9 +191,10 @@ WHERE e.status = 'open'
   );
 
 -- name: ListAvailableEventsForVolunteer :many
+-- Keyset-пагинация: следующая страница начинается после (after_date, after_id) прошлой.
 SELECT *
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND NOT EXISTS (
     SELECT 1
     FROM volunteer_applications va
@@ -155,14 +207,17 @@ WHERE e.status = 'open'
     WHERE ep.event_id = e.id
       AND ep.volunteer_id = sqlc.arg(volunteer_id)
   )
+  AND (
+    sqlc.narg('after_id')::int IS NULL OR
+    (e.date, e.id) < (sqlc.narg('after_date')::timestamp, sqlc.narg('after_id')::int)
+  )
 ORDER BY e.date DESC, e.id DESC
-LIMIT sqlc.arg('limit')::int
-OFFSET sqlc.arg('offset')::int;
+LIMIT sqlc.arg('limit')::int;
 
 -- name: CountAvailableEventsForVolunteerWithCategories :one
 SELECT COUNT(*)
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND (sqlc.arg(category_ids) IS NULL OR array_length(sqlc.arg(category_ids), 1) = 0 OR e.category_id = ANY(sqlc.arg(category_ids)))
   AND NOT EXISTS (
     SELECT 1
@@ -180,7 +235,7 @@ WHERE e.status = 'open'
 -- name: ListAvailableEventsForVolunteerWithCategories :many
 SELECT *
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND (sqlc.arg(category_ids) IS NULL OR array_length(sqlc.arg(category_ids), 1) = 0 OR e.category_id = ANY(sqlc.arg(category_ids)))
   AND NOT EXISTS (
     SELECT 1
@@ -194,9 +249,12 @@ WHERE e.status = 'open'
     WHERE ep.event_id = e.id
       AND ep.volunteer_id = sqlc.arg(volunteer_id)
   )
+  AND (
+    sqlc.narg('after_id')::int IS NULL OR
+    (e.date, e.id) < (sqlc.narg('after_date')::timestamp, sqlc.narg('after_id')::int)
+  )
 ORDER BY e.date DESC, e.id DESC
-LIMIT sqlc.arg('limit')::int
-OFFSET sqlc.arg('offset')::int;
+LIMIT sqlc.arg('limit')::int;
 
 -- name: ListEventsByOrganizer :many
 SELECT *
@@ -206,6 +264,11 @@ ORDER BY date DESC, id DESC
 LIMIT sqlc.arg('limit')::int
 OFFSET sqlc.arg('offset')::int;
 
+-- name: CountEventsByOrganizer :one
+SELECT COUNT(*) AS count
+FROM events
+WHERE organizer_id = sqlc.arg(organizer_id);
+
 -- name: ListEventsByStatus :many
 SELECT *
 FROM events
@@ -236,9 +299,16 @@ SELECT *
 FROM events
 WHERE status = 'open'
   AND date >= sqlc.arg(start_date)
-  AND ABS(location_lat - sqlc.arg(target_lat)) <= sqlc.arg(lat_delta)
-  AND ABS(location_lon - sqlc.arg(target_lon)) <= sqlc.arg(lon_delta)
-ORDER BY POWER(location_lat - sqlc.arg(target_lat), 2) + POWER(location_lon - sqlc.arg(target_lon), 2)
+  AND earth_box(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8), sqlc.arg(radius_km)::float8 * 1000)
+      @> ll_to_earth(location_lat::float8, location_lon::float8)
+  AND earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(location_lat::float8, location_lon::float8)
+      ) <= sqlc.arg(radius_km)::float8 * 1000
+ORDER BY earth_distance(
+    ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+    ll_to_earth(location_lat::float8, location_lon::float8)
+)
 LIMIT sqlc.arg('limit')::int
 OFFSET sqlc.arg('offset')::int;
 
@@ -251,6 +321,17 @@ ORDER BY e.date DESC, e.id DESC
 LIMIT sqlc.arg('limit')::int
 OFFSET sqlc.arg('offset')::int;
 
+-- Лента календаря волонтёра: мероприятия, где он участник, начиная с since.
+-- Отменённые остаются в выборке, чтобы календарь пометил их у себя, а не потерял молча.
+-- name: ListCalendarEventsForVolunteer :many
+SELECT e.*
+FROM events e
+JOIN event_participants ep ON ep.event_id = e.id
+WHERE ep.volunteer_id = sqlc.arg(volunteer_id)
+  AND e.date >= sqlc.arg(since)
+ORDER BY e.date, e.id
+LIMIT sqlc.arg('limit')::int;
+
 -- name: ListEventsWithPendingApplications :many
 SELECT e.*
 FROM events e
@@ -265,183 +346,347 @@ LIMIT sqlc.arg('limit')::int
 OFFSET sqlc.arg('offset')::int;
 
 -- name: ListEventsForMap :many
-WITH candidate AS (
+-- earth_box отбирает кандидатов по GiST-индексу idx_events_location_earth, earth_distance отсекает углы куба.
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km
+FROM events e
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE e.status IN ('open', 'full')
+  AND (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+  )
+  AND earth_box(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8), sqlc.arg(radius_km)::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= sqlc.arg(radius_km)::float8 * 1000
+  AND (
+    sqlc.narg('after_id')::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > (sqlc.narg('after_distance_km')::float8, sqlc.narg('after_date')::timestamp, sqlc.narg('after_id')::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT sqlc.arg('limit')::int;
+
+-- name: ListEventsForMapInBBox :many
+-- Область карты ищется по GiST-индексу idx_events_location_point; distance_km считается от lat/lon (центра области).
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km
+FROM events e
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE e.status IN ('open', 'full')
+  AND (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+  )
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point(sqlc.arg(min_lon)::float8, sqlc.arg(min_lat)::float8),
+        point(sqlc.arg(max_lon)::float8, sqlc.arg(max_lat)::float8)
+      )
+  AND (
+    sqlc.narg('after_id')::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > (sqlc.narg('after_distance_km')::float8, sqlc.narg('after_date')::timestamp, sqlc.narg('after_id')::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT sqlc.arg('limit')::int;
+
+-- name: ListEventClustersInBBox :many
+-- Сетка кластеров считается в базе: ячейка — номер квадрата grid_size×grid_size в проекции Меркатора,
+-- как у тайлов карты. Строка — одна категория одной ячейки, сумма координат нужна для центроида.
+WITH cells AS (
     SELECT
-        e.id,
-        e.title,
-        e.description,
-        e.date,
-        e.duration_hours,
-        e.location,
-        e.location_lat,
-        e.location_lon,
         e.category_id,
-        e.organizer_id,
-        e.contacts,
-        e.chat,
-        e.max_volunteers,
-        COALESCE(e.current_volunteers, 0) AS current_volunteers,
-        e.status,
-        e.cancelled_reason,
-        e.completed_at,
-        e.created_at,
-        e.updated_at,
-        c.name AS category_name,
-        GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
-        CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians(sqlc.arg('lat'))) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians(sqlc.arg('lon'))) +
-                    sin(radians(sqlc.arg('lat'))) * sin(radians(e.location_lat::float8))
-                )
-            )
-        ) AS double precision) AS distance_km
+        e.location_lat::float8 AS lat,
+        e.location_lon::float8 AS lon,
+        LEAST(GREATEST(
+            floor((e.location_lon::float8 + 180) / 360 * sqlc.arg(grid_size)::float8), 0
+        ), sqlc.arg(grid_size)::float8 - 1)::int AS cell_x,
+        LEAST(GREATEST(
+            floor((1 - asinh(tan(radians(LEAST(GREATEST(e.location_lat::float8, -85.05112878), 85.05112878)))) / pi()) / 2 * sqlc.arg(grid_size)::float8), 0
+        ), sqlc.arg(grid_size)::float8 - 1)::int AS cell_y
     FROM events e
-    LEFT JOIN categories c ON c.id = e.category_id
-    WHERE e.status = 'open'
-      AND e.location_lat IS NOT NULL
-      AND e.location_lon IS NOT NULL
-      AND (
-            e.max_volunteers = 0 OR
-            e.current_volunteers IS NULL OR
-            e.current_volunteers < e.max_volunteers
-      )
+    WHERE e.status IN ('open', 'full')
+      AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+            point(sqlc.arg(min_lon)::float8, sqlc.arg(min_lat)::float8),
+            point(sqlc.arg(max_lon)::float8, sqlc.arg(max_lat)::float8)
+          )
       AND (
         sqlc.narg('category_ids')::int[] IS NULL OR
         e.category_id = ANY(sqlc.narg('category_ids')::int[])
       )
-      AND CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians(sqlc.arg('lat'))) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians(sqlc.arg('lon'))) +
-                    sin(radians(sqlc.arg('lat'))) * sin(radians(e.location_lat::float8))
-                )
-            )
-    ) AS double precision) <= sqlc.arg('radius_km')::double precision
 )
 SELECT
-    id,
-    title,
-    description,
-    date,
-    duration_hours,
-    location,
-    location_lat,
-    location_lon,
-    category_id,
-    organizer_id,
-    contacts,
-    chat,
-    max_volunteers,
-    current_volunteers,
-    status,
-    cancelled_reason,
-    completed_at,
-    created_at,
-    updated_at,
-    category_name,
-    slots_left::int4 AS slots_left,
-    distance_km::double precision AS distance_km
-FROM candidate
-ORDER BY distance_km ASC, date ASC
-LIMIT sqlc.arg('limit')::int
-OFFSET sqlc.arg('offset')::int;
+    cells.cell_x::int AS cell_x,
+    cells.cell_y::int AS cell_y,
+    cells.category_id,
+    c.name AS category_name,
+    count(*) AS count,
+    sum(cells.lat)::float8 AS sum_lat,
+    sum(cells.lon)::float8 AS sum_lon
+FROM cells
+LEFT JOIN categories c ON c.id = cells.category_id
+GROUP BY cells.cell_x, cells.cell_y, cells.category_id, c.name
+ORDER BY cells.cell_x, cells.cell_y, cells.category_id;
+
+-- name: ListEventPointsInBBox :many
+-- Лёгкие точки для крупных масштабов карты: без описаний и расстояний, только координаты и категория.
+SELECT
+    e.id,
+    e.title,
+    e.date,
+    e.location_lat::float8 AS location_lat,
+    e.location_lon::float8 AS location_lon,
+    e.category_id,
+    c.name AS category_name
+FROM events e
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE e.status IN ('open', 'full')
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point(sqlc.arg(min_lon)::float8, sqlc.arg(min_lat)::float8),
+        point(sqlc.arg(max_lon)::float8, sqlc.arg(max_lat)::float8)
+      )
+  AND (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+  )
+ORDER BY e.id
+LIMIT sqlc.arg('limit')::int;
 
 -- name: ListEventsForMapByVolunteer :many
-WITH user_apps AS (
-    SELECT
-        va.event_id,
-        va.status
-    FROM volunteer_applications va
-    WHERE va.volunteer_id = sqlc.arg(volunteer_id)
-),
-candidate AS (
-    SELECT
-        e.id,
-        e.title,
-        e.description,
-        e.date,
-        e.duration_hours,
-        e.location,
-        e.location_lat,
-        e.location_lon,
-        e.category_id,
-        e.organizer_id,
-        e.contacts,
-        e.chat,
-        e.max_volunteers,
-        COALESCE(e.current_volunteers, 0) AS current_volunteers,
-        e.status,
-        e.cancelled_reason,
-        e.completed_at,
-        e.created_at,
-        e.updated_at,
-        c.name AS category_name,
-        GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
-        CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians(sqlc.arg('lat'))) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians(sqlc.arg('lon'))) +
-                    sin(radians(sqlc.arg('lat'))) * sin(radians(e.location_lat::float8))
-                )
-            )
-        ) AS double precision) AS distance_km,
-        ua.status AS application_status
-    FROM events e
-    JOIN user_apps ua ON ua.event_id = e.id
-    LEFT JOIN categories c ON c.id = e.category_id
-    WHERE e.location_lat IS NOT NULL
-      AND e.location_lon IS NOT NULL
-      AND (
-        sqlc.narg('category_ids')::int[] IS NULL OR
-        e.category_id = ANY(sqlc.narg('category_ids')::int[])
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km,
+    va.status AS application_status
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = sqlc.arg(volunteer_id)
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+)
+  AND earth_box(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8), sqlc.arg(radius_km)::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= sqlc.arg(radius_km)::float8 * 1000
+  AND (
+    sqlc.narg('after_id')::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > (sqlc.narg('after_distance_km')::float8, sqlc.narg('after_date')::timestamp, sqlc.narg('after_id')::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT sqlc.arg('limit')::int;
+
+-- name: ListEventsForMapByVolunteerInBBox :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km,
+    va.status AS application_status
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = sqlc.arg(volunteer_id)
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+)
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point(sqlc.arg(min_lon)::float8, sqlc.arg(min_lat)::float8),
+        point(sqlc.arg(max_lon)::float8, sqlc.arg(max_lat)::float8)
       )
-      AND CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians(sqlc.arg('lat'))) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians(sqlc.arg('lon'))) +
-                    sin(radians(sqlc.arg('lat'))) * sin(radians(e.location_lat::float8))
-                )
-            )
-    ) AS double precision) <= sqlc.arg('radius_km')::double precision
+  AND (
+    sqlc.narg('after_id')::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > (sqlc.narg('after_distance_km')::float8, sqlc.narg('after_date')::timestamp, sqlc.narg('after_id')::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT sqlc.arg('limit')::int;
+
+-- name: CountEventsForMap :one
+SELECT COUNT(*)
+FROM events e
+WHERE e.status IN ('open', 'full')
+  AND (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+  )
+  AND earth_box(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8), sqlc.arg(radius_km)::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= sqlc.arg(radius_km)::float8 * 1000;
+
+-- name: CountEventsForMapInBBox :one
+SELECT COUNT(*)
+FROM events e
+WHERE e.status IN ('open', 'full')
+  AND (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+  )
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point(sqlc.arg(min_lon)::float8, sqlc.arg(min_lat)::float8),
+        point(sqlc.arg(max_lon)::float8, sqlc.arg(max_lat)::float8)
+      );
+
+-- name: CountEventsForMapByVolunteer :one
+SELECT COUNT(*)
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = sqlc.arg(volunteer_id)
+WHERE (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
+)
+  AND earth_box(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8), sqlc.arg(radius_km)::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lon)::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= sqlc.arg(radius_km)::float8 * 1000;
+
+-- name: CountEventsForMapByVolunteerInBBox :one
+SELECT COUNT(*)
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = sqlc.arg(volunteer_id)
+WHERE (
+    sqlc.narg('category_ids')::int[] IS NULL OR
+    e.category_id = ANY(sqlc.narg('category_ids')::int[])
 )
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point(sqlc.arg(min_lon)::float8, sqlc.arg(min_lat)::float8),
+        point(sqlc.arg(max_lon)::float8, sqlc.arg(max_lat)::float8)
+      );
+
+-- name: ListOrganizerEventsWithPendingApplications :many
 SELECT
-    id,
-    title,
-    description,
-    date,
-    duration_hours,
-    location,
-    location_lat,
-    location_lon,
-    category_id,
-    organizer_id,
-    contacts,
-    chat,
-    max_volunteers,
-    current_volunteers,
-    status,
-    cancelled_reason,
-    completed_at,
-    created_at,
-    updated_at,
-    category_name,
-    slots_left::int4 AS slots_left,
-    distance_km::double precision AS distance_km,
-    application_status
-FROM candidate
-ORDER BY distance_km ASC, date ASC
+    e.id,
+    e.title,
+    e.date,
+    e.max_volunteers,
+    e.current_volunteers,
+    COUNT(va.id)::bigint AS pending_count
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.status = 'pending'
+WHERE e.organizer_id = sqlc.arg(organizer_id)
+GROUP BY e.id
+ORDER BY MIN(va.applied_at) ASC, e.id ASC
 LIMIT sqlc.arg('limit')::int
 OFFSET sqlc.arg('offset')::int;
+
+-- name: CountOrganizerEventsWithPendingApplications :one
+SELECT COUNT(DISTINCT e.id)
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.status = 'pending'
+WHERE e.organizer_id = sqlc.arg(organizer_id);
+
+-- name: GetEventByIDForUpdate :one
+SELECT *
+FROM events
+WHERE id = sqlc.arg(id)
+FOR UPDATE;
@@ -0,0 +1,131 @@
+-- name: EnqueueNotification :exec
+-- Ставит одно уведомление, например о переводе из листа ожидания. Можно вызывать внутри транзакции:
+-- отправит его планировщик уже после коммита.
+INSERT INTO notifications (
+    user_id,
+    kind,
+    event_id,
+    application_id,
+    dedup_key
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(kind),
+    sqlc.arg(event_id),
+    sqlc.arg(application_id),
+    sqlc.arg(dedup_key)
+)
+ON CONFLICT (dedup_key) DO NOTHING;
+
+-- name: EnqueueEventReminders :execrows
+-- Ставит напоминание участникам событий, до начала которых осталось от min_lead до lead минут.
+-- Дата события входит в ключ, поэтому после переноса события напоминание придёт ещё раз.
+INSERT INTO notifications (
+    user_id,
+    kind,
+    event_id,
+    dedup_key
+)
+SELECT
+    ep.volunteer_id,
+    sqlc.arg(kind)::text,
+    e.id,
+    sqlc.arg(kind)::text || ':' || e.id || ':' || ep.volunteer_id || ':' || EXTRACT(EPOCH FROM e.date)::bigint
+FROM events e
+JOIN event_participants ep ON ep.event_id = e.id
+WHERE e.status NOT IN ('cancelled', 'completed')
+  AND ep.volunteer_id IS NOT NULL
+  AND e.date > NOW() + make_interval(mins => sqlc.arg(min_lead_minutes)::int)
+  AND e.date <= NOW() + make_interval(mins => sqlc.arg(lead_minutes)::int)
+ON CONFLICT (dedup_key) DO NOTHING;
+
+-- name: EnqueueApplicationStatusNotifications :execrows
+-- Сообщает волонтёру о решении по заявке. Время рассмотрения входит в ключ,
+-- чтобы повторное решение после сброса тоже дошло.
+INSERT INTO notifications (
+    user_id,
+    kind,
+    event_id,
+    application_id,
+    dedup_key
+)
+SELECT
+    va.volunteer_id,
+    'application_status',
+    va.event_id,
+    va.id,
+    'application_status:' || va.id || ':' || va.status || ':' || EXTRACT(EPOCH FROM va.reviewed_at)::bigint
+FROM volunteer_applications va
+WHERE va.status IN ('approved', 'rejected')
+  AND va.volunteer_id IS NOT NULL
+  -- Заявки без reviewed_by одобрены автоматически из листа ожидания, о них сообщает EnqueueNotification.
+  AND va.reviewed_by IS NOT NULL
+  AND va.reviewed_at > NOW() - make_interval(mins => sqlc.arg(lookback_minutes)::int)
+ON CONFLICT (dedup_key) DO NOTHING;
+
+-- name: EnqueueNewApplicationNotifications :execrows
+-- Сообщает организатору о новых заявках на его события.
+INSERT INTO notifications (
+    user_id,
+    kind,
+    event_id,
+    application_id,
+    dedup_key
+)
+SELECT
+    e.organizer_id,
+    'application_created',
+    e.id,
+    va.id,
+    'application_created:' || va.id
+FROM volunteer_applications va
+JOIN events e ON e.id = va.event_id
+WHERE va.status = 'pending'
+  AND e.organizer_id IS NOT NULL
+  AND va.applied_at > NOW() - make_interval(mins => sqlc.arg(lookback_minutes)::int)
+ON CONFLICT (dedup_key) DO NOTHING;
+
+-- name: ClaimDueNotifications :many
+-- Забирает пачку готовых к отправке уведомлений. SKIP LOCKED позволяет запускать несколько
+-- экземпляров бота, а зависшие в статусе sending записи возвращаются в работу после lock_timeout.
+UPDATE notifications
+SET
+    status = 'sending',
+    attempts = attempts + 1,
+    locked_at = NOW()
+WHERE id IN (
+    SELECT n.id
+    FROM notifications n
+    WHERE (n.status = 'pending' AND n.scheduled_at <= NOW())
+       OR (n.status = 'sending' AND n.locked_at < NOW() - make_interval(secs => sqlc.arg(lock_timeout_seconds)::int))
+    ORDER BY n.scheduled_at ASC, n.id ASC
+    LIMIT sqlc.arg('limit')::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET
+    status = 'sent',
+    sent_at = NOW(),
+    locked_at = NULL,
+    last_error = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: MarkNotificationFailed :exec
+-- После max_attempts попыток уведомление остаётся в статусе failed, иначе повторяется через retry_after.
+UPDATE notifications
+SET
+    status = CASE WHEN attempts >= sqlc.arg(max_attempts)::int THEN 'failed' ELSE 'pending' END,
+    scheduled_at = NOW() + make_interval(secs => sqlc.arg(retry_after_seconds)::int),
+    locked_at = NULL,
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: MarkNotificationCancelled :exec
+UPDATE notifications
+SET
+    status = 'cancelled',
+    locked_at = NULL,
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
@@ -38,3 +38,40 @@ SET organizer_comment = sqlc.arg(organizer_comment),
         submitted_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING *;
+
+-- name: GetOrganizerVerificationRequestByID :one
+SELECT *
+FROM organizer_verification_requests
+WHERE id = sqlc.arg(id);
+
+-- name: ListPendingOrganizerVerificationRequests :many
+SELECT
+    ovr.id,
+    ovr.organizer_id,
+    ovr.submitted_at,
+    o.organization_name,
+    u.name,
+    u.username
+FROM organizer_verification_requests ovr
+JOIN organizers o ON o.id = ovr.organizer_id
+JOIN users u ON u.id = ovr.organizer_id
+WHERE ovr.status = 'pending'
+ORDER BY ovr.submitted_at ASC, ovr.id ASC
+LIMIT sqlc.arg('limit')::int
+OFFSET sqlc.arg('offset')::int;
+
+-- name: CountPendingOrganizerVerificationRequests :one
+SELECT COUNT(*)
+FROM organizer_verification_requests
+WHERE status = 'pending';
+
+-- name: ReviewOrganizerVerificationRequest :one
+UPDATE organizer_verification_requests
+SET
+    status = sqlc.arg(status),
+    admin_comment = sqlc.arg(admin_comment),
+    reviewed_by = sqlc.arg(reviewed_by),
+    reviewed_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'pending'
+RETURNING *;
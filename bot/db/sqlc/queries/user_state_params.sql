@@ -0,0 +1,21 @@
+-- name: GetUserStateParams :one
+SELECT params
+FROM user_state_params
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: SetUserStateParams :exec
+INSERT INTO user_state_params (
+    user_id,
+    params
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(params)
+)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    params = EXCLUDED.params,
+    updated_at = NOW();
+
+-- name: DeleteUserStateParams :exec
+DELETE FROM user_state_params
+WHERE user_id = sqlc.arg(user_id);
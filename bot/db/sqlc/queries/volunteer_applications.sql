@@ -5,14 +5,16 @@ INSERT INTO volunteer_applications (
     status,
     rejection_reason,
     reviewed_by,
-    reviewed_at
+    reviewed_at,
+    message
 ) VALUES (
     sqlc.arg(event_id),
     sqlc.arg(volunteer_id),
     COALESCE(sqlc.arg(status), 'pending'),
     sqlc.arg(rejection_reason),
     sqlc.arg(reviewed_by),
-    sqlc.arg(reviewed_at)
+    sqlc.arg(reviewed_at),
+    sqlc.arg(message)
 )
 RETURNING *;
 
@@ -116,3 +118,102 @@ SET
     reviewed_at = NULL
 WHERE id = sqlc.arg(id)
 RETURNING *;
+
+-- name: ListPendingApplicantsByEvent :many
+SELECT
+    va.id,
+    va.event_id,
+    va.volunteer_id,
+    va.applied_at,
+    va.message,
+    u.name,
+    u.username,
+    v.about
+FROM volunteer_applications va
+JOIN users u ON u.id = va.volunteer_id
+LEFT JOIN volunteers v ON v.id = va.volunteer_id
+WHERE va.event_id = sqlc.arg(event_id)
+  AND va.status = 'pending'
+ORDER BY va.applied_at ASC, va.id ASC
+LIMIT sqlc.arg('limit')::int
+OFFSET sqlc.arg('offset')::int;
+
+-- name: CountApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = sqlc.arg(event_id);
+
+-- name: CountPendingApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = sqlc.arg(event_id)
+  AND status = 'pending';
+
+-- name: CountActiveApplicationsByEvent :one
+-- Заявки, которые пропадут при удалении события: ожидающие, в листе ожидания и одобренные.
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = sqlc.arg(event_id)
+  AND COALESCE(status, 'pending') IN ('pending', 'waitlisted', 'approved');
+
+-- name: GetNextWaitlistedApplication :one
+SELECT *
+FROM volunteer_applications
+WHERE event_id = sqlc.arg(event_id)
+  AND status = 'waitlisted'
+ORDER BY applied_at ASC, id ASC
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetWaitlistPosition :one
+-- Позиция заявки в листе ожидания, начиная с 1. Для заявки не из листа ожидания возвращает 0.
+SELECT COUNT(*)
+FROM volunteer_applications w
+JOIN volunteer_applications me ON me.event_id = w.event_id
+WHERE me.event_id = sqlc.arg(event_id)
+  AND me.volunteer_id = sqlc.arg(volunteer_id)
+  AND me.status = 'waitlisted'
+  AND w.status = 'waitlisted'
+  AND (w.applied_at, w.id) <= (me.applied_at, me.id);
+
+-- name: CountWaitlistedApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = sqlc.arg(event_id)
+  AND status = 'waitlisted';
+
+-- name: CountApplicationsByVolunteerGroupedByStatus :many
+SELECT COALESCE(status, 'pending')::text AS status, COUNT(*) AS count
+FROM volunteer_applications
+WHERE volunteer_id = sqlc.arg(volunteer_id)
+GROUP BY 1
+ORDER BY 1;
+
+-- name: ListVolunteerApplicationsWithEvents :many
+-- Заявки волонтёра вместе с событием, сгруппированные по статусу заявки.
+SELECT va.*, e.title AS event_title, e.date AS event_date, e.status AS event_status
+FROM volunteer_applications va
+JOIN events e ON e.id = va.event_id
+WHERE va.volunteer_id = sqlc.arg(volunteer_id)
+  AND (sqlc.narg(status)::text IS NULL OR va.status = sqlc.narg(status)::text)
+  AND (sqlc.narg(event_statuses)::text[] IS NULL OR e.status = ANY(sqlc.narg(event_statuses)::text[]))
+ORDER BY
+    CASE va.status
+        WHEN 'pending' THEN 0
+        WHEN 'waitlisted' THEN 1
+        WHEN 'approved' THEN 2
+        WHEN 'rejected' THEN 3
+        ELSE 4
+    END,
+    va.applied_at DESC,
+    va.id DESC
+LIMIT sqlc.arg('limit')::int
+OFFSET sqlc.arg('offset')::int;
+
+-- name: CountVolunteerApplicationsWithEvents :one
+SELECT COUNT(*)
+FROM volunteer_applications va
+JOIN events e ON e.id = va.event_id
+WHERE va.volunteer_id = sqlc.arg(volunteer_id)
+  AND (sqlc.narg(status)::text IS NULL OR va.status = sqlc.narg(status)::text)
+  AND (sqlc.narg(event_statuses)::text[] IS NULL OR e.status = ANY(sqlc.narg(event_statuses)::text[]));
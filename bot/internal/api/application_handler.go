@@ -0,0 +1,255 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+)
+
+const maxApplicationMessageLength = 1000
+
+// applicationScopeEventStatuses срезы заявок по статусу события, как в разделе «Мои заявки» бота.
+var applicationScopeEventStatuses = map[string][]string{
+	"active":    {model.EventStatusOpen, model.EventStatusFull, model.EventStatusInProgress},
+	"completed": {model.EventStatusCompleted},
+	"cancelled": {model.EventStatusCancelled},
+}
+
+var applicationStatuses = map[string]bool{
+	"pending":    true,
+	"waitlisted": true,
+	"approved":   true,
+	"rejected":   true,
+	"cancelled":  true,
+}
+
+type applicationHandler struct {
+	applications service.VolunteerApplicationService
+	reviews      service.ApplicationReviewService
+}
+
+func newApplicationHandler(applications service.VolunteerApplicationService, reviews service.ApplicationReviewService) *applicationHandler {
+	if applications == nil || reviews == nil {
+		return nil
+	}
+	return &applicationHandler{applications: applications, reviews: reviews}
+}
+
+type applyRequest struct {
+	Message *string         `json:"message"`
+	Answers []answerRequest `json:"answers"`
+}
+
+type answerRequest struct {
+	QuestionID int32    `json:"questionId"`
+	Values     []string `json:"values"`
+}
+
+type applicationResponse struct {
+	ID              int32      `json:"id"`
+	EventID         *int32     `json:"eventId"`
+	Status          string     `json:"status"`
+	Message         *string    `json:"message"`
+	RejectionReason *string    `json:"rejectionReason"`
+	AppliedAt       time.Time  `json:"appliedAt"`
+	ReviewedAt      *time.Time `json:"reviewedAt"`
+}
+
+type applicationEventSummary struct {
+	Title  string    `json:"title"`
+	Date   time.Time `json:"date"`
+	Status string    `json:"status"`
+}
+
+type myApplicationResponse struct {
+	applicationResponse
+	Event applicationEventSummary `json:"event"`
+}
+
+type myApplicationsResponse struct {
+	Data []myApplicationResponse `json:"data"`
+	Meta map[string]any          `json:"meta"`
+}
+
+func (h *applicationHandler) register(r *gin.RouterGroup, authMW *authMiddleware) {
+	if h == nil || authMW == nil {
+		return
+	}
+	volunteerOnly := []gin.HandlerFunc{authMW.requireUser(), authMW.requireRole(roleVolunteer)}
+
+	eventApplications := r.Group("/events/:id/applications", volunteerOnly...)
+	eventApplications.POST("", h.apply)
+	eventApplications.DELETE("/me", h.withdraw)
+
+	me := r.Group("/me", volunteerOnly...)
+	me.GET("/applications", h.listMine)
+}
+
+func (h *applicationHandler) apply(c *gin.Context) {
+	user, _ := getCurrentUser(c)
+	eventID, ok := parseEventIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req applyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "тело запроса должно быть корректным JSON")
+			return
+		}
+	}
+	req.Message = trimOptional(req.Message)
+	if req.Message != nil && utf8.RuneCountInString(*req.Message) > maxApplicationMessageLength {
+		respondValidationError(c, validationErrors{"message": "сообщение не должно превышать 1000 символов"})
+		return
+	}
+
+	application, err := h.reviews.ApplyToEvent(c.Request.Context(), eventID, user.ID, req.Message, req.answers())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, errCodeNotFound, "событие не найдено")
+			return
+		}
+		respondServiceError(c, err, "не удалось подать заявку")
+		return
+	}
+
+	c.JSON(http.StatusCreated, newApplicationResponse(application))
+}
+
+func (h *applicationHandler) withdraw(c *gin.Context) {
+	user, _ := getCurrentUser(c)
+	eventID, ok := parseEventIDParam(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	application, err := h.applications.GetVolunteerApplication(ctx, &eventID, &user.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, errCodeNotFound, "заявка не найдена")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить заявку")
+		return
+	}
+	// Статусы заявки и события проверяет сервис под блокировкой события.
+	if err := h.reviews.WithdrawApplication(ctx, application.ID, user.ID); err != nil {
+		respondServiceError(c, err, "не удалось отозвать заявку")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *applicationHandler) listMine(c *gin.Context) {
+	user, _ := getCurrentUser(c)
+
+	var status *string
+	if value := strings.TrimSpace(c.Query("status")); value != "" {
+		if !applicationStatuses[value] {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "status должен быть одним из: pending, waitlisted, approved, rejected, cancelled")
+			return
+		}
+		status = &value
+	}
+	var eventStatuses []string
+	if scope := strings.TrimSpace(c.Query("scope")); scope != "" {
+		statuses, ok := applicationScopeEventStatuses[scope]
+		if !ok {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "scope должен быть одним из: active, completed, cancelled")
+			return
+		}
+		eventStatuses = statuses
+	}
+	limit := parseInt32Bound(c.Query("limit"), 1, 100, 20)
+	offset := parseInt32Bound(c.Query("offset"), 0, 10_000, 0)
+
+	ctx := c.Request.Context()
+	items, err := h.applications.ListVolunteerApplicationsWithEvents(ctx, user.ID, status, eventStatuses, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить заявки")
+		return
+	}
+	total, err := h.applications.CountVolunteerApplicationsWithEvents(ctx, user.ID, status, eventStatuses)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить заявки")
+		return
+	}
+
+	data := make([]myApplicationResponse, 0, len(items))
+	for _, item := range items {
+		eventStatus := model.EventStatusOpen
+		if item.EventStatus != nil && *item.EventStatus != "" {
+			eventStatus = *item.EventStatus
+		}
+		data = append(data, myApplicationResponse{
+			applicationResponse: newApplicationResponse(item.Application),
+			Event: applicationEventSummary{
+				Title:  item.EventTitle,
+				Date:   item.EventDate,
+				Status: eventStatus,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, myApplicationsResponse{
+		Data: data,
+		Meta: map[string]any{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(data),
+			"total":  total,
+		},
+	})
+}
+
+func parseEventIDParam(c *gin.Context) (int32, bool) {
+	eventID, err := strconv.ParseInt(strings.TrimSpace(c.Param("id")), 10, 32)
+	if err != nil || eventID <= 0 {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "id события должен быть положительным числом")
+		return 0, false
+	}
+	return int32(eventID), true
+}
+
+func applicationStatus(a model.VolunteerApplication) string {
+	if a.Status == nil || *a.Status == "" {
+		return "pending"
+	}
+	return *a.Status
+}
+
+func (r applyRequest) answers() []model.ApplicationAnswer {
+	if len(r.Answers) == 0 {
+		return nil
+	}
+	result := make([]model.ApplicationAnswer, 0, len(r.Answers))
+	for _, answer := range r.Answers {
+		result = append(result, model.ApplicationAnswer{QuestionID: answer.QuestionID, Values: answer.Values})
+	}
+	return result
+}
+
+func newApplicationResponse(a model.VolunteerApplication) applicationResponse {
+	return applicationResponse{
+		ID:              a.ID,
+		EventID:         a.EventID,
+		Status:          applicationStatus(a),
+		Message:         a.Message,
+		RejectionReason: a.RejectionReason,
+		AppliedAt:       a.AppliedAt,
+		ReviewedAt:      a.ReviewedAt,
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+)
+
+const (
+	applicantID    int64 = 20
+	notVolunteerID int64 = 21
+)
+
+type stubVolunteerService struct {
+	service.VolunteerService
+	ids map[int64]bool
+}
+
+func (s stubVolunteerService) GetVolunteer(_ context.Context, id int64) (model.Volunteer, error) {
+	if !s.ids[id] {
+		return model.Volunteer{}, pgx.ErrNoRows
+	}
+	return model.Volunteer{ID: id}, nil
+}
+
+type stubApplicationService struct {
+	service.VolunteerApplicationService
+	applications map[int32]model.VolunteerApplication
+}
+
+func (s stubApplicationService) GetVolunteerApplication(_ context.Context, eventID *int32, _ *int64) (model.VolunteerApplication, error) {
+	application, ok := s.applications[*eventID]
+	if !ok {
+		return model.VolunteerApplication{}, pgx.ErrNoRows
+	}
+	return application, nil
+}
+
+// stubReviewService возвращает ошибку из errs по id события (подача) или заявки (отзыв);
+// в тестах id заявки совпадает с id её события.
+type stubReviewService struct {
+	service.ApplicationReviewService
+	errs map[int32]error
+}
+
+func (s stubReviewService) ApplyToEvent(_ context.Context, eventID int32, volunteerID int64, message *string, _ []model.ApplicationAnswer) (model.VolunteerApplication, error) {
+	if err := s.errs[eventID]; err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	return model.VolunteerApplication{ID: 1, EventID: &eventID, VolunteerID: &volunteerID, Message: message}, nil
+}
+
+func (s stubReviewService) WithdrawApplication(_ context.Context, applicationID int32, _ int64) error {
+	return s.errs[applicationID]
+}
+
+func newApplicationTestRouter(t *testing.T) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	validator, secret := newTestValidator(t)
+
+	services := newTestServices(
+		model.User{ID: applicantID, Name: "Волонтёр"},
+		model.User{ID: notVolunteerID, Name: "Без профиля"},
+	)
+	services.VolunteerService = stubVolunteerService{ids: map[int64]bool{applicantID: true}}
+	applications := stubApplicationService{applications: map[int32]model.VolunteerApplication{
+		1: {ID: 1},
+		2: {ID: 2},
+		3: {ID: 3},
+	}}
+	reviews := stubReviewService{errs: map[int32]error{
+		2: service.ErrApplicationNotWithdrawable,
+		3: service.ErrApplicationNotFound,
+		5: pgx.ErrNoRows,
+		6: service.ErrEventFull,
+		7: service.ErrAlreadyApplied,
+		8: service.ErrEventNotOpen,
+		9: &service.QuestionnaireError{Err: service.ErrInvalidAnswer, QuestionID: 42, Reason: "ответ обязателен"},
+	}}
+
+	router := gin.New()
+	newApplicationHandler(applications, reviews).register(router.Group("/api/v1"), newAuthMiddleware(validator, services))
+	return router, secret
+}
+
+func TestApplicationHandlerValidationErrorShape(t *testing.T) {
+	router, secret := newApplicationTestRouter(t)
+
+	code, resp := serveAPI(t, router, secret, applicantID, http.MethodPost, "/api/v1/events/1/applications",
+		`{"message":"`+strings.Repeat("я", maxApplicationMessageLength+1)+`"}`)
+	if code != http.StatusUnprocessableEntity || resp.Code != errCodeValidation || resp.Fields["message"] == "" {
+		t.Fatalf("long message: got %d %+v", code, resp)
+	}
+
+	// Ошибка анкеты указывает поле с id вопроса.
+	code, resp = serveAPI(t, router, secret, applicantID, http.MethodPost, "/api/v1/events/9/applications", `{"answers":[{"questionId":42,"values":[]}]}`)
+	if code != http.StatusUnprocessableEntity || resp.Code != errCodeValidation || resp.Fields["answers[42]"] != "ответ обязателен" {
+		t.Fatalf("questionnaire error: got %d %+v", code, resp)
+	}
+}
+
+func TestApplicationHandlerStatusCodes(t *testing.T) {
+	router, secret := newApplicationTestRouter(t)
+
+	cases := []struct {
+		name     string
+		userID   int64
+		method   string
+		path     string
+		body     string
+		want     int
+		wantCode string
+	}{
+		{"apply", applicantID, http.MethodPost, "/api/v1/events/1/applications", `{"message":"Хочу помочь"}`, http.StatusCreated, ""},
+		{"apply without body", applicantID, http.MethodPost, "/api/v1/events/1/applications", "", http.StatusCreated, ""},
+		{"apply malformed json", applicantID, http.MethodPost, "/api/v1/events/1/applications", `{"message":`, http.StatusBadRequest, errCodeBadRequest},
+		{"apply bad id", applicantID, http.MethodPost, "/api/v1/events/abc/applications", "", http.StatusBadRequest, errCodeBadRequest},
+		{"apply not volunteer", notVolunteerID, http.MethodPost, "/api/v1/events/1/applications", "", http.StatusForbidden, errCodeForbidden},
+		{"apply missing event", applicantID, http.MethodPost, "/api/v1/events/5/applications", "", http.StatusNotFound, errCodeNotFound},
+		{"apply full event", applicantID, http.MethodPost, "/api/v1/events/6/applications", "", http.StatusConflict, errCodeEventFull},
+		{"apply twice", applicantID, http.MethodPost, "/api/v1/events/7/applications", "", http.StatusConflict, errCodeAlreadyApplied},
+		{"apply closed event", applicantID, http.MethodPost, "/api/v1/events/8/applications", "", http.StatusConflict, errCodeEventNotOpen},
+		{"withdraw", applicantID, http.MethodDelete, "/api/v1/events/1/applications/me", "", http.StatusNoContent, ""},
+		{"withdraw not allowed", applicantID, http.MethodDelete, "/api/v1/events/2/applications/me", "", http.StatusConflict, errCodeConflict},
+		{"withdraw removed meanwhile", applicantID, http.MethodDelete, "/api/v1/events/3/applications/me", "", http.StatusNotFound, errCodeNotFound},
+		{"withdraw without application", applicantID, http.MethodDelete, "/api/v1/events/4/applications/me", "", http.StatusNotFound, errCodeNotFound},
+		{"withdraw not volunteer", notVolunteerID, http.MethodDelete, "/api/v1/events/1/applications/me", "", http.StatusForbidden, errCodeForbidden},
+	}
+	for _, tc := range cases {
+		code, resp := serveAPI(t, router, secret, tc.userID, tc.method, tc.path, tc.body)
+		if code != tc.want || resp.Code != tc.wantCode {
+			t.Errorf("%s: got %d %q, want %d %q", tc.name, code, resp.Code, tc.want, tc.wantCode)
+		}
+	}
+}
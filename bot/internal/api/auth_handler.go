@@ -1,48 +1,234 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 
 	"maxBot/internal/auth"
+	"maxBot/internal/service"
 )
 
 type authHandler struct {
 	validator *auth.Validator
+	sessions  service.SessionService
+	users     service.UserService
 }
 
-func newAuthHandler(validator *auth.Validator) *authHandler {
-	return &authHandler{validator: validator}
+func newAuthHandler(validator *auth.Validator, sessions service.SessionService, users service.UserService) *authHandler {
+	if validator == nil || sessions == nil || users == nil {
+		return nil
+	}
+	return &authHandler{validator: validator, sessions: sessions, users: users}
 }
 
 func (h *authHandler) register(r *gin.RouterGroup) {
-	r.GET("/auth/session", h.createSession)
+	if h == nil {
+		return
+	}
+	r.POST("/auth/session", h.createSession)
+	r.POST("/auth/refresh", h.refresh)
+	r.POST("/auth/logout", h.logout)
+}
+
+type sessionRequest struct {
+	InitData string `json:"initData"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 type sessionResponse struct {
-	Token     string       `json:"token"`
-	ExpiresIn int64        `json:"expiresIn"`
-	User      auth.MaxUser `json:"user"`
+	Token            string       `json:"token"`
+	ExpiresIn        int64        `json:"expiresIn"`
+	RefreshToken     string       `json:"refreshToken"`
+	RefreshExpiresIn int64        `json:"refreshExpiresIn"`
+	User             auth.MaxUser `json:"user"`
 }
 
+// createSession обменивает initData на пару токенов. initData принимается только в теле запроса
+// или в заголовке Authorization: tma <initData>, чтобы не попадать в логи доступа.
 func (h *authHandler) createSession(c *gin.Context) {
-	initData := strings.TrimSpace(c.Query("session"))
-	if initData == "" {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: "initData обязателен"})
+	initData, ok := extractInitData(c)
+	if !ok {
 		return
 	}
 
-	result, err := h.validator.ValidateAndIssue(initData)
+	result, err := h.validator.ValidateInitData(c.Request.Context(), initData)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, errorResponse{Message: err.Error()})
+		if !isInitDataRejection(err) {
+			log.Printf("auth: validate init data failed: %v", err)
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось создать сессию")
+			return
+		}
+		reason, message := initDataFailure(err)
+		// Сам initData не логируем: в нём подпись и персональные данные.
+		log.Printf("auth: session exchange rejected reason=%s ip=%s", reason, c.ClientIP())
+		respondError(c, http.StatusUnauthorized, errCodeUnauthorized, message)
 		return
 	}
+	userData, err := json.Marshal(result.User)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось создать сессию")
+		return
+	}
+
+	session, refreshToken, err := h.sessions.CreateSession(c.Request.Context(), result.User.ID, userData, h.validator.RefreshTTL())
+	if err != nil {
+		log.Printf("create auth session for user %d failed: %v", result.User.ID, err)
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось создать сессию")
+		return
+	}
+	h.respondSession(c, result.User, session.ID, refreshToken)
+}
 
+// refresh обменивает refresh-токен на новую пару токенов; старый refresh-токен больше не действует.
+func (h *authHandler) refresh(c *gin.Context) {
+	refreshToken, ok := bindRefreshToken(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	session, newRefreshToken, err := h.sessions.RefreshSession(ctx, refreshToken, h.validator.RefreshTTL())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "refresh-токен уже использован, сессия завершена")
+		case errors.Is(err, service.ErrInvalidRefreshToken):
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "некорректный или истёкший refresh-токен")
+		default:
+			log.Printf("refresh auth session failed: %v", err)
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось обновить сессию")
+		}
+		return
+	}
+
+	// Пользователь мог не успеть начать диалог с ботом, поэтому отсутствие в users не мешает обновлению.
+	user, err := h.users.GetUserByID(ctx, session.UserID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось проверить пользователя")
+		return
+	}
+	if err == nil && user.IsBlocked {
+		if err := h.sessions.RevokeSession(ctx, session.ID); err != nil {
+			log.Printf("revoke session of blocked user %d failed: %v", session.UserID, err)
+		}
+		respondError(c, http.StatusForbidden, errCodeForbidden, "пользователь заблокирован")
+		return
+	}
+
+	var maxUser auth.MaxUser
+	if err := json.Unmarshal(session.UserData, &maxUser); err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось обновить сессию")
+		return
+	}
+	h.respondSession(c, maxUser, session.ID, newRefreshToken)
+}
+
+// logout завершает сессию refresh-токена; выданные по ней access-токены перестают приниматься сразу.
+func (h *authHandler) logout(c *gin.Context) {
+	refreshToken, ok := bindRefreshToken(c)
+	if !ok {
+		return
+	}
+	if err := h.sessions.RevokeSessionByRefreshToken(c.Request.Context(), refreshToken); err != nil {
+		log.Printf("logout failed: %v", err)
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось завершить сессию")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *authHandler) respondSession(c *gin.Context, user auth.MaxUser, sessionID, refreshToken string) {
+	token, err := h.validator.IssueToken(user, sessionID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось выпустить токен")
+		return
+	}
 	c.JSON(http.StatusOK, sessionResponse{
-		Token:     result.Token,
-		ExpiresIn: int64(h.validator.SessionTTL().Seconds()),
-		User:      result.User,
+		Token:            token,
+		ExpiresIn:        int64(h.validator.SessionTTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(h.validator.RefreshTTL().Seconds()),
+		User:             user,
 	})
 }
+
+func extractInitData(c *gin.Context) (string, bool) {
+	const scheme = "tma "
+	if header := strings.TrimSpace(c.GetHeader("Authorization")); header != "" {
+		if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "ожидается заголовок Authorization: tma <initData>")
+			return "", false
+		}
+		return strings.TrimSpace(header[len(scheme):]), true
+	}
+
+	var req sessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "передайте initData в теле запроса или в заголовке Authorization: tma <initData>")
+		return "", false
+	}
+	initData := strings.TrimSpace(req.InitData)
+	if initData == "" {
+		respondValidationError(c, validationErrors{"initData": "initData обязателен"})
+		return "", false
+	}
+	return initData, true
+}
+
+// initDataFailure возвращает причину отказа для лога и сообщение для клиента без деталей разбора.
+func initDataFailure(err error) (reason, message string) {
+	switch {
+	case errors.Is(err, auth.ErrInitDataReplayed):
+		return "replayed", "initData уже использован, обновите сессию через refresh-токен"
+	case errors.Is(err, auth.ErrInitDataExpired):
+		return "expired", "initData устарел, откройте приложение заново"
+	case errors.Is(err, auth.ErrInitDataSignature):
+		return "bad_signature", "подпись initData не прошла проверку"
+	case errors.Is(err, auth.ErrInitDataUser):
+		return "bad_user", "в initData нет корректного пользователя"
+	default:
+		return "malformed", "initData имеет неверный формат"
+	}
+}
+
+// isInitDataRejection отличает отказ по самому initData от сбоя журнала повторов.
+func isInitDataRejection(err error) bool {
+	for _, reason := range []error{auth.ErrInitDataMalformed, auth.ErrInitDataSignature, auth.ErrInitDataExpired, auth.ErrInitDataUser, auth.ErrInitDataReplayed} {
+		if errors.Is(err, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+func bindRefreshToken(c *gin.Context) (string, bool) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "тело запроса должно быть корректным JSON")
+		return "", false
+	}
+	token := strings.TrimSpace(req.RefreshToken)
+	if token == "" {
+		respondValidationError(c, validationErrors{"refreshToken": "refresh-токен обязателен"})
+		return "", false
+	}
+	return token, true
+}
+
+// jwksHandler публикует открытые ключи для проверки наших токенов партнёрскими сервисами.
+// HMAC-ключи в ответ не попадают.
+func jwksHandler(validator *auth.Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, validator.KeySet().PublicJWKS())
+	}
+}
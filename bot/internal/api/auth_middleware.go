@@ -1,52 +1,220 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 
 	"maxBot/internal/auth"
+	"maxBot/internal/di"
+	"maxBot/internal/model"
 )
 
 type contextKey string
 
 const (
-	ctxUserKey  contextKey = "max.auth.user"
-	ctxTokenKey contextKey = "max.auth.token"
+	ctxUserKey        contextKey = "max.auth.user"
+	ctxTokenKey       contextKey = "max.auth.token"
+	ctxCurrentUserKey contextKey = "max.auth.current_user"
+	ctxOrganizerKey   contextKey = "max.auth.organizer"
+	ctxOwnedEventKey  contextKey = "max.auth.owned_event"
+)
+
+// Роли, которые можно требовать через requireRole.
+const (
+	roleVolunteer = "volunteer"
+	roleOrganizer = "organizer"
+	roleAdmin     = "admin"
 )
 
 type authMiddleware struct {
 	validator *auth.Validator
+	services  *di.Services
 }
 
-func newAuthMiddleware(validator *auth.Validator) *authMiddleware {
-	if validator == nil {
+func newAuthMiddleware(validator *auth.Validator, services *di.Services) *authMiddleware {
+	if validator == nil || services == nil || services.UserService == nil {
 		return nil
 	}
-	return &authMiddleware{validator: validator}
+	return &authMiddleware{validator: validator, services: services}
 }
 
+// requireUser проверяет JWT и находит пользователя в БД. Заблокированные и незарегистрированные
+// в боте пользователи получают 403, даже если токен валиден.
 func (m *authMiddleware) requireUser() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := extractBearerToken(c.GetHeader("Authorization"))
 		if token == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{Message: "требуется заголовок Authorization"})
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется заголовок Authorization")
 			return
 		}
 
-		user, err := m.validator.ParseToken(token)
+		authUser, err := m.validator.ParseToken(c.Request.Context(), token)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{Message: "некорректный или истёкший токен"})
+			if errors.Is(err, auth.ErrTokenRevoked) {
+				respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "сессия завершена, войдите заново")
+				return
+			}
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "некорректный или истёкший токен")
+			return
+		}
+
+		user, err := m.services.UserService.GetUserByID(c.Request.Context(), authUser.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				respondError(c, http.StatusForbidden, errCodeForbidden, "пользователь не зарегистрирован, начните диалог с ботом")
+				return
+			}
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось проверить пользователя")
+			return
+		}
+		if user.IsBlocked {
+			respondError(c, http.StatusForbidden, errCodeForbidden, "пользователь заблокирован")
 			return
 		}
 
-		c.Set(string(ctxUserKey), user)
+		c.Set(string(ctxUserKey), authUser)
 		c.Set(string(ctxTokenKey), token)
+		c.Set(string(ctxCurrentUserKey), user)
+		c.Next()
+	}
+}
+
+// requireRole пропускает только пользователей с записью в таблице роли: volunteers, organizers или admins.
+// Поле users.role не используется, так как это текущий режим бота, который пользователь выбирает сам.
+// Должен стоять после requireUser.
+func (m *authMiddleware) requireRole(role string) gin.HandlerFunc {
+	switch role {
+	case roleVolunteer:
+		return func(c *gin.Context) {
+			user, ok := getCurrentUser(c)
+			if !ok {
+				respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
+				return
+			}
+			if _, err := m.services.VolunteerService.GetVolunteer(c.Request.Context(), user.ID); err != nil {
+				m.abortRoleCheck(c, err, "доступно только волонтёрам")
+				return
+			}
+			c.Next()
+		}
+	case roleOrganizer:
+		return func(c *gin.Context) {
+			user, ok := getCurrentUser(c)
+			if !ok {
+				respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
+				return
+			}
+			organizer, err := m.services.OrganizerService.GetOrganizer(c.Request.Context(), user.ID)
+			if err != nil {
+				m.abortRoleCheck(c, err, "доступно только организаторам")
+				return
+			}
+			c.Set(string(ctxOrganizerKey), organizer)
+			c.Next()
+		}
+	case roleAdmin:
+		return func(c *gin.Context) {
+			user, ok := getCurrentUser(c)
+			if !ok {
+				respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
+				return
+			}
+			if _, err := m.services.AdminService.GetAdmin(c.Request.Context(), user.ID); err != nil {
+				m.abortRoleCheck(c, err, "доступно только администраторам")
+				return
+			}
+			c.Next()
+		}
+	default:
+		panic(fmt.Sprintf("api: unknown role %q", role))
+	}
+}
+
+// requireVerifiedOrganizer дополняет requireRole("organizer") проверкой верификации.
+func (m *authMiddleware) requireVerifiedOrganizer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizer, ok := getCurrentOrganizer(c)
+		if !ok {
+			respondError(c, http.StatusForbidden, errCodeForbidden, "доступно только организаторам")
+			return
+		}
+		if organizer.VerifiedAt == nil {
+			respondError(c, http.StatusForbidden, errCodeForbidden, "организатор не верифицирован")
+			return
+		}
 		c.Next()
 	}
 }
 
+// requireEventOwner загружает событие из параметра пути и проверяет, что текущий пользователь — его организатор.
+// Найденное событие доступно через getOwnedEvent.
+func (m *authMiddleware) requireEventOwner(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := getCurrentUser(c)
+		if !ok {
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
+			return
+		}
+		eventID, err := strconv.ParseInt(strings.TrimSpace(c.Param(param)), 10, 32)
+		if err != nil || eventID <= 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "id события должен быть положительным числом")
+			return
+		}
+
+		event, err := m.services.EventService.GetEventByID(c.Request.Context(), int32(eventID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				respondError(c, http.StatusNotFound, errCodeNotFound, "событие не найдено")
+				return
+			}
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить событие")
+			return
+		}
+		if event.OrganizerID == nil || *event.OrganizerID != user.ID {
+			respondError(c, http.StatusForbidden, errCodeForbidden, "событие принадлежит другому организатору")
+			return
+		}
+
+		c.Set(string(ctxOwnedEventKey), event)
+		c.Next()
+	}
+}
+
+// requireSelf пропускает запрос, только если параметр пути совпадает с id текущего пользователя.
+func (m *authMiddleware) requireSelf(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseInt(strings.TrimSpace(c.Param(param)), 10, 64)
+		if err != nil || userID <= 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, param+" должен быть положительным числом")
+			return
+		}
+		user, ok := getCurrentUser(c)
+		if !ok {
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
+			return
+		}
+		if user.ID != userID {
+			respondError(c, http.StatusForbidden, errCodeForbidden, "нельзя запрашивать данные другого пользователя")
+			return
+		}
+		c.Next()
+	}
+}
+
+func (m *authMiddleware) abortRoleCheck(c *gin.Context, err error, forbiddenMessage string) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusForbidden, errCodeForbidden, forbiddenMessage)
+		return
+	}
+	respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось проверить права доступа")
+}
+
 func extractBearerToken(header string) string {
 	const prefix = "Bearer "
 	trimmed := strings.TrimSpace(header)
@@ -65,6 +233,36 @@ func getAuthenticatedUser(c *gin.Context) (auth.MaxUser, bool) {
 	return auth.MaxUser{}, false
 }
 
+// getCurrentUser возвращает пользователя из БД, найденного requireUser.
+func getCurrentUser(c *gin.Context) (model.User, bool) {
+	if value, ok := c.Get(string(ctxCurrentUserKey)); ok {
+		if user, ok := value.(model.User); ok {
+			return user, true
+		}
+	}
+	return model.User{}, false
+}
+
+// getCurrentOrganizer возвращает организатора, найденного requireRole("organizer").
+func getCurrentOrganizer(c *gin.Context) (model.Organizer, bool) {
+	if value, ok := c.Get(string(ctxOrganizerKey)); ok {
+		if organizer, ok := value.(model.Organizer); ok {
+			return organizer, true
+		}
+	}
+	return model.Organizer{}, false
+}
+
+// getOwnedEvent возвращает событие, проверенное requireEventOwner.
+func getOwnedEvent(c *gin.Context) (model.Event, bool) {
+	if value, ok := c.Get(string(ctxOwnedEventKey)); ok {
+		if event, ok := value.(model.Event); ok {
+			return event, true
+		}
+	}
+	return model.Event{}, false
+}
+
 func getTokenFromContext(c *gin.Context) (string, bool) {
 	if value, ok := c.Get(string(ctxTokenKey)); ok {
 		if token, ok := value.(string); ok {
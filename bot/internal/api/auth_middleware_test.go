@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,8 +10,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
 
 	"maxBot/internal/auth"
+	"maxBot/internal/di"
+	"maxBot/internal/model"
+	"maxBot/internal/service"
 )
 
 func TestAuthMiddlewareAllowsValidToken(t *testing.T) {
@@ -19,11 +24,15 @@ func TestAuthMiddlewareAllowsValidToken(t *testing.T) {
 	token := signTestToken(t, secret, auth.MaxUser{ID: 99, FirstName: "Auth"})
 
 	router := gin.New()
-	router.GET("/secure", newAuthMiddleware(validator).requireUser(), func(c *gin.Context) {
+	services := newTestServices(model.User{ID: 99, Name: "Auth"})
+	router.GET("/secure", newAuthMiddleware(validator, services).requireUser(), func(c *gin.Context) {
 		user, ok := getAuthenticatedUser(c)
 		if !ok {
 			t.Fatalf("user missing in context")
 		}
+		if current, ok := getCurrentUser(c); !ok || current.ID != user.ID {
+			t.Fatalf("current user missing in context")
+		}
 		c.JSON(http.StatusOK, gin.H{"userID": user.ID})
 	})
 
@@ -43,7 +52,7 @@ func TestAuthMiddlewareRejectsInvalidToken(t *testing.T) {
 	validator, _ := newTestValidator(t)
 
 	router := gin.New()
-	router.GET("/secure", newAuthMiddleware(validator).requireUser(), func(c *gin.Context) {
+	router.GET("/secure", newAuthMiddleware(validator, newTestServices()).requireUser(), func(c *gin.Context) {
 		c.Status(http.StatusOK)
 	})
 
@@ -58,6 +67,118 @@ func TestAuthMiddlewareRejectsInvalidToken(t *testing.T) {
 	}
 }
 
+func TestAuthMiddlewareRejectsBlockedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, secret := newTestValidator(t)
+	token := signTestToken(t, secret, auth.MaxUser{ID: 7, FirstName: "Blocked"})
+
+	router := gin.New()
+	services := newTestServices(model.User{ID: 7, Name: "Blocked", IsBlocked: true})
+	router.GET("/secure", newAuthMiddleware(validator, services).requireUser(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if code := serveWithToken(router, "/secure", token); code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", code)
+	}
+}
+
+func TestAuthMiddlewareRejectsUnknownUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, secret := newTestValidator(t)
+	token := signTestToken(t, secret, auth.MaxUser{ID: 8, FirstName: "Ghost"})
+
+	router := gin.New()
+	router.GET("/secure", newAuthMiddleware(validator, newTestServices()).requireUser(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if code := serveWithToken(router, "/secure", token); code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", code)
+	}
+}
+
+func TestRequireRoleAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, secret := newTestValidator(t)
+	services := newTestServices(model.User{ID: 1, Name: "Admin"}, model.User{ID: 2, Name: "Volunteer", Role: roleAdmin})
+	services.AdminService = stubAdminService{ids: map[int64]bool{1: true}}
+	authMW := newAuthMiddleware(validator, services)
+
+	router := gin.New()
+	router.GET("/admin", authMW.requireUser(), authMW.requireRole(roleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if code := serveWithToken(router, "/admin", signTestToken(t, secret, auth.MaxUser{ID: 1})); code != http.StatusOK {
+		t.Fatalf("expected 200 for admin, got %d", code)
+	}
+	// Роль в users не даёт прав без записи в admins.
+	if code := serveWithToken(router, "/admin", signTestToken(t, secret, auth.MaxUser{ID: 2})); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", code)
+	}
+}
+
+func TestRequireSelf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, secret := newTestValidator(t)
+	authMW := newAuthMiddleware(validator, newTestServices(model.User{ID: 5, Name: "Self"}))
+
+	router := gin.New()
+	router.GET("/users/:userID", authMW.requireUser(), authMW.requireSelf("userID"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	token := signTestToken(t, secret, auth.MaxUser{ID: 5})
+
+	if code := serveWithToken(router, "/users/5", token); code != http.StatusOK {
+		t.Fatalf("expected 200 for own id, got %d", code)
+	}
+	if code := serveWithToken(router, "/users/6", token); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for foreign id, got %d", code)
+	}
+}
+
+type stubUserService struct {
+	service.UserService
+	users map[int64]model.User
+}
+
+func (s stubUserService) GetUserByID(_ context.Context, id int64) (model.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return model.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+type stubAdminService struct {
+	service.AdminService
+	ids map[int64]bool
+}
+
+func (s stubAdminService) GetAdmin(_ context.Context, id int64) (model.Admin, error) {
+	if !s.ids[id] {
+		return model.Admin{}, pgx.ErrNoRows
+	}
+	return model.Admin{ID: id}, nil
+}
+
+func newTestServices(users ...model.User) *di.Services {
+	stub := stubUserService{users: make(map[int64]model.User, len(users))}
+	for _, user := range users {
+		stub.users[user.ID] = user
+	}
+	return &di.Services{UserService: stub}
+}
+
+func serveWithToken(router *gin.Engine, path, token string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
 func newTestValidator(t *testing.T) (*auth.Validator, string) {
 	t.Helper()
 	secret := "test-jwt-secret"
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"maxBot/internal/service"
+)
+
+const calendarFeedPath = "/api/v1/calendar/"
+
+type calendarHandler struct {
+	calendar service.CalendarService
+}
+
+func newCalendarHandler(calendar service.CalendarService) *calendarHandler {
+	if calendar == nil {
+		return nil
+	}
+	return &calendarHandler{calendar: calendar}
+}
+
+type calendarFeedTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+func (h *calendarHandler) register(r *gin.RouterGroup, authMW *authMiddleware) {
+	if h == nil || authMW == nil {
+		return
+	}
+	// Лента открывается календарём без заголовков, поэтому авторизуется токеном из ссылки.
+	r.GET("/calendar/:feed", h.feed)
+
+	me := r.Group("/me/calendar", authMW.requireUser(), authMW.requireRole(roleVolunteer))
+	me.POST("/token", h.issueToken)
+	me.DELETE("/token", h.revokeToken)
+}
+
+// issueToken выпускает ссылку на ленту; повторный вызов перевыпускает её, и старая ссылка перестаёт работать.
+func (h *calendarHandler) issueToken(c *gin.Context) {
+	user, _ := getCurrentUser(c)
+	token, err := h.calendar.IssueFeedToken(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Printf("issue calendar feed token for user %d failed: %v", user.ID, err)
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось выпустить ссылку на календарь")
+		return
+	}
+	c.JSON(http.StatusCreated, calendarFeedTokenResponse{
+		Token: token,
+		URL:   requestBaseURL(c) + calendarFeedPath + token + ".ics",
+	})
+}
+
+func (h *calendarHandler) revokeToken(c *gin.Context) {
+	user, _ := getCurrentUser(c)
+	if err := h.calendar.RevokeFeedToken(c.Request.Context(), user.ID); err != nil {
+		log.Printf("revoke calendar feed token for user %d failed: %v", user.ID, err)
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось отозвать ссылку на календарь")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// feed отдаёт iCalendar с мероприятиями, где волонтёр участник.
+func (h *calendarHandler) feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("feed"), ".ics")
+	if token == "" {
+		respondError(c, http.StatusNotFound, errCodeNotFound, "календарь не найден")
+		return
+	}
+	_, events, err := h.calendar.ListFeedEvents(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFeedToken) {
+			respondError(c, http.StatusNotFound, errCodeNotFound, "календарь не найден или ссылка отозвана")
+			return
+		}
+		log.Printf("calendar feed failed: %v", err)
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить календарь")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", writeICalendar(events))
+}
+
+// requestBaseURL восстанавливает внешний адрес сервера с учётом TLS-терминации на прокси.
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"maxBot/internal/service"
+)
+
+// errorResponse — единый формат ошибки REST API. Code стабилен и предназначен для клиента,
+// Message — человекочитаемое описание, Fields — ошибки валидации по полям запроса.
+type errorResponse struct {
+	Code    string            `json:"code,omitempty"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// Коды ошибок API.
+const (
+	errCodeBadRequest   = "bad_request"
+	errCodeValidation   = "validation_failed"
+	errCodeUnauthorized = "unauthorized"
+	errCodeForbidden    = "forbidden"
+	errCodeNotFound     = "not_found"
+	errCodeConflict     = "conflict"
+	errCodeInternal     = "internal_error"
+
+	errCodeEventFull      = "event_full"
+	errCodeEventNotOpen   = "event_not_open"
+	errCodeAlreadyApplied = "already_applied"
+)
+
+// validationErrors накапливает ошибки валидации тела запроса.
+type validationErrors map[string]string
+
+func (v validationErrors) add(field, message string) {
+	if _, exists := v[field]; !exists {
+		v[field] = message
+	}
+}
+
+func respondError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, errorResponse{Code: code, Message: message})
+}
+
+func respondValidationError(c *gin.Context, fields validationErrors) {
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse{
+		Code:    errCodeValidation,
+		Message: "некорректные данные запроса",
+		Fields:  fields,
+	})
+}
+
+// respondServiceError переводит ошибку сервисного слоя в HTTP-ответ. fallback используется
+// как сообщение для неизвестных ошибок, чтобы не раскрывать детали клиенту.
+func respondServiceError(c *gin.Context, err error, fallback string) {
+	var questionnaireErr *service.QuestionnaireError
+	switch {
+	case errors.As(err, &questionnaireErr):
+		respondValidationError(c, questionnaireErrorFields(questionnaireErr))
+	case errors.Is(err, service.ErrQuestionnaireLocked):
+		respondError(c, http.StatusConflict, errCodeConflict, "на событие уже подавали заявки, анкету изменить нельзя")
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(c, http.StatusNotFound, errCodeNotFound, "объект не найден")
+	case errors.Is(err, service.ErrNotEventOwner):
+		respondError(c, http.StatusForbidden, errCodeForbidden, "событие принадлежит другому организатору")
+	case errors.Is(err, service.ErrMaxVolunteersBelowCurrent):
+		respondValidationError(c, validationErrors{"maxVolunteers": maxVolunteersBelowCurrentMessage})
+	case errors.Is(err, service.ErrEventFinished):
+		respondError(c, http.StatusConflict, errCodeConflict, "завершённое или отменённое событие нельзя изменить")
+	case errors.Is(err, service.ErrEventHasApplications):
+		respondError(c, http.StatusConflict, errCodeConflict, "у события есть участники или заявки, отмените его вместо удаления")
+	case errors.Is(err, service.ErrEventStatusTransition):
+		respondError(c, http.StatusConflict, errCodeConflict, "переход в этот статус невозможен")
+	case errors.Is(err, service.ErrEventStatusChanged):
+		respondError(c, http.StatusConflict, errCodeConflict, "статус события изменился, повторите запрос")
+	case errors.Is(err, service.ErrApplicationNotFound):
+		respondError(c, http.StatusNotFound, errCodeNotFound, "заявка не найдена")
+	case errors.Is(err, service.ErrApplicationNotWithdrawable):
+		respondError(c, http.StatusConflict, errCodeConflict, "заявка уже закрыта или событие уже началось")
+	case errors.Is(err, service.ErrEventFull):
+		respondError(c, http.StatusConflict, errCodeEventFull, "свободных мест не осталось")
+	case errors.Is(err, service.ErrEventNotOpen):
+		respondError(c, http.StatusConflict, errCodeEventNotOpen, "событие уже началось, завершено или отменено")
+	case errors.Is(err, service.ErrAlreadyApplied):
+		respondError(c, http.StatusConflict, errCodeAlreadyApplied, "заявка на это событие уже подана")
+	case errors.Is(err, service.ErrInvalidEventStatus):
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "неизвестный статус события")
+	default:
+		respondError(c, http.StatusInternalServerError, errCodeInternal, fallback)
+	}
+}
+
+// questionnaireErrorFields указывает поле с ошибкой: номер вопроса анкеты или id вопроса в ответах.
+func questionnaireErrorFields(err *service.QuestionnaireError) validationErrors {
+	if errors.Is(err, service.ErrInvalidQuestion) {
+		if err.Position == 0 {
+			return validationErrors{"questions": err.Reason}
+		}
+		return validationErrors{fmt.Sprintf("questions[%d]", err.Position-1): err.Reason}
+	}
+	return validationErrors{fmt.Sprintf("answers[%d]", err.QuestionID): err.Reason}
+}
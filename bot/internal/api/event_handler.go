@@ -0,0 +1,353 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+)
+
+// Ограничения совпадают с проверками мастера создания события в боте.
+const (
+	minEventTitleLength      = 3
+	maxEventTitleLength      = 200
+	maxEventDescriptionLen   = 2000
+	maxEventContactsLength   = 500
+	maxEventLocationLength   = 500
+	maxCancelReasonLength    = 500
+	minEventDurationHours    = 1
+	maxEventDurationHours    = 72
+	minEventVolunteersNumber = 1
+	maxEventVolunteersNumber = 1000
+)
+
+const maxVolunteersBelowCurrentMessage = "нельзя сделать меньше числа уже одобренных волонтёров"
+
+type eventHandler struct {
+	events     service.EventService
+	categories service.CategoryService
+}
+
+func newEventHandler(events service.EventService, categories service.CategoryService) *eventHandler {
+	if events == nil {
+		return nil
+	}
+	return &eventHandler{events: events, categories: categories}
+}
+
+// eventRequest — тело запросов на создание и изменение события.
+type eventRequest struct {
+	Title         string     `json:"title"`
+	Description   *string    `json:"description"`
+	Chat          *int64     `json:"chat"`
+	Date          *time.Time `json:"date"`
+	DurationHours *int32     `json:"durationHours"`
+	Location      string     `json:"location"`
+	Lat           *float64   `json:"lat"`
+	Lon           *float64   `json:"lon"`
+	CategoryID    *int32     `json:"categoryId"`
+	Contacts      *string    `json:"contacts"`
+	MaxVolunteers *int32     `json:"maxVolunteers"`
+}
+
+type cancelEventRequest struct {
+	Reason *string `json:"reason"`
+}
+
+type eventResponse struct {
+	ID                int32      `json:"id"`
+	Title             string     `json:"title"`
+	Description       *string    `json:"description"`
+	Chat              *int64     `json:"chat"`
+	Date              time.Time  `json:"date"`
+	DurationHours     *int32     `json:"durationHours"`
+	Location          string     `json:"location"`
+	Lat               float64    `json:"lat"`
+	Lon               float64    `json:"lon"`
+	CategoryID        *int32     `json:"categoryId"`
+	OrganizerID       *int64     `json:"organizerId"`
+	Contacts          *string    `json:"contacts"`
+	MaxVolunteers     int32      `json:"maxVolunteers"`
+	CurrentVolunteers int32      `json:"currentVolunteers"`
+	Status            string     `json:"status"`
+	CancelledReason   *string    `json:"cancelledReason"`
+	CompletedAt       *time.Time `json:"completedAt"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+func (h *eventHandler) register(r *gin.RouterGroup, authMW *authMiddleware) {
+	if h == nil || authMW == nil {
+		return
+	}
+	group := r.Group("/events")
+	group.Use(authMW.requireUser(), authMW.requireRole(roleOrganizer), authMW.requireVerifiedOrganizer())
+	group.POST("", h.createEvent)
+
+	owned := group.Group("/:id")
+	owned.Use(authMW.requireEventOwner("id"))
+	owned.PUT("", h.updateEvent)
+	owned.DELETE("", h.deleteEvent)
+	owned.POST("/cancel", h.cancelEvent)
+	owned.POST("/complete", h.completeEvent)
+}
+
+func (h *eventHandler) createEvent(c *gin.Context) {
+	organizer, ok := getCurrentOrganizer(c)
+	if !ok {
+		respondError(c, http.StatusForbidden, errCodeForbidden, "доступно только организаторам")
+		return
+	}
+
+	var req eventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "тело запроса должно быть корректным JSON")
+		return
+	}
+	req.normalize()
+	fields := req.validate(time.Now())
+	h.validateCategory(c, req.CategoryID, fields)
+	if len(fields) > 0 {
+		respondValidationError(c, fields)
+		return
+	}
+
+	event, err := h.events.CreateEvent(
+		c.Request.Context(),
+		req.Title,
+		req.Description,
+		req.Chat,
+		*req.Date,
+		req.DurationHours,
+		req.Location,
+		*req.Lat,
+		*req.Lon,
+		req.CategoryID,
+		req.Contacts,
+		*req.MaxVolunteers,
+		organizer.ID,
+	)
+	if err != nil {
+		respondServiceError(c, err, "не удалось создать событие")
+		return
+	}
+
+	c.JSON(http.StatusCreated, newEventResponse(event))
+}
+
+func (h *eventHandler) updateEvent(c *gin.Context) {
+	event, ok := getOwnedEvent(c)
+	if !ok {
+		respondError(c, http.StatusForbidden, errCodeForbidden, "событие недоступно")
+		return
+	}
+
+	var req eventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "тело запроса должно быть корректным JSON")
+		return
+	}
+	req.normalize()
+	// Дату в прошлом допускаем только если она не менялась: у идущего события она уже прошла.
+	notBefore := time.Now()
+	if req.Date != nil && req.Date.Equal(event.Date) {
+		notBefore = time.Time{}
+	}
+	fields := req.validate(notBefore)
+	h.validateCategory(c, req.CategoryID, fields)
+	// Статус и число участников окончательно проверяет сервис под блокировкой события,
+	// здесь лимит сверяется заранее, чтобы вернуть его вместе с остальными ошибками полей.
+	if req.MaxVolunteers != nil && event.CurrentVolunteers != nil && *req.MaxVolunteers < *event.CurrentVolunteers {
+		fields.add("maxVolunteers", maxVolunteersBelowCurrentMessage)
+	}
+	if len(fields) > 0 {
+		respondValidationError(c, fields)
+		return
+	}
+
+	updated, err := h.events.UpdateEvent(
+		c.Request.Context(),
+		event.ID,
+		req.Title,
+		req.Description,
+		req.Chat,
+		*req.Date,
+		req.DurationHours,
+		req.Location,
+		*req.Lat,
+		*req.Lon,
+		req.CategoryID,
+		req.Contacts,
+		*req.MaxVolunteers,
+	)
+	if err != nil {
+		respondServiceError(c, err, "не удалось обновить событие")
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(updated))
+}
+
+func (h *eventHandler) deleteEvent(c *gin.Context) {
+	event, ok := getOwnedEvent(c)
+	if !ok {
+		respondError(c, http.StatusForbidden, errCodeForbidden, "событие недоступно")
+		return
+	}
+
+	if err := h.events.DeleteEvent(c.Request.Context(), event.ID); err != nil {
+		respondServiceError(c, err, "не удалось удалить событие")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *eventHandler) cancelEvent(c *gin.Context) {
+	event, ok := getOwnedEvent(c)
+	if !ok {
+		respondError(c, http.StatusForbidden, errCodeForbidden, "событие недоступно")
+		return
+	}
+
+	var req cancelEventRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "тело запроса должно быть корректным JSON")
+			return
+		}
+	}
+	req.Reason = trimOptional(req.Reason)
+	if req.Reason != nil && utf8.RuneCountInString(*req.Reason) > maxCancelReasonLength {
+		respondValidationError(c, validationErrors{"reason": "причина не должна превышать 500 символов"})
+		return
+	}
+
+	cancelled, err := h.events.CancelEvent(c.Request.Context(), event.ID, req.Reason)
+	if err != nil {
+		respondServiceError(c, err, "не удалось отменить событие")
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(cancelled))
+}
+
+func (h *eventHandler) completeEvent(c *gin.Context) {
+	event, ok := getOwnedEvent(c)
+	if !ok {
+		respondError(c, http.StatusForbidden, errCodeForbidden, "событие недоступно")
+		return
+	}
+
+	completed, err := h.events.CompleteEvent(c.Request.Context(), event.ID)
+	if err != nil {
+		respondServiceError(c, err, "не удалось завершить событие")
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(completed))
+}
+
+func (h *eventHandler) validateCategory(c *gin.Context, categoryID *int32, fields validationErrors) {
+	if categoryID == nil || h.categories == nil {
+		return
+	}
+	if _, err := h.categories.GetCategory(c.Request.Context(), *categoryID); err != nil {
+		fields.add("categoryId", "категория не найдена")
+	}
+}
+
+func (r *eventRequest) normalize() {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Location = strings.TrimSpace(r.Location)
+	r.Description = trimOptional(r.Description)
+	r.Contacts = trimOptional(r.Contacts)
+}
+
+// validate проверяет обязательные поля и диапазоны. notBefore — нижняя граница даты события.
+func (r *eventRequest) validate(notBefore time.Time) validationErrors {
+	fields := validationErrors{}
+
+	if length := utf8.RuneCountInString(r.Title); length < minEventTitleLength || length > maxEventTitleLength {
+		fields.add("title", "название должно быть от 3 до 200 символов")
+	}
+	if r.Description != nil && utf8.RuneCountInString(*r.Description) > maxEventDescriptionLen {
+		fields.add("description", "описание не должно превышать 2000 символов")
+	}
+	if r.Date == nil {
+		fields.add("date", "дата обязательна")
+	} else if !r.Date.After(notBefore) {
+		fields.add("date", "дата должна быть в будущем")
+	}
+	if r.DurationHours != nil && (*r.DurationHours < minEventDurationHours || *r.DurationHours > maxEventDurationHours) {
+		fields.add("durationHours", "длительность должна быть от 1 до 72 часов")
+	}
+	if r.Location == "" {
+		fields.add("location", "адрес обязателен")
+	} else if utf8.RuneCountInString(r.Location) > maxEventLocationLength {
+		fields.add("location", "адрес не должен превышать 500 символов")
+	}
+	if r.Lat == nil || *r.Lat < -90 || *r.Lat > 90 {
+		fields.add("lat", "широта обязательна и должна быть в диапазоне [-90, 90]")
+	}
+	if r.Lon == nil || *r.Lon < -180 || *r.Lon > 180 {
+		fields.add("lon", "долгота обязательна и должна быть в диапазоне [-180, 180]")
+	}
+	if r.Contacts != nil && utf8.RuneCountInString(*r.Contacts) > maxEventContactsLength {
+		fields.add("contacts", "контакты не должны превышать 500 символов")
+	}
+	if r.MaxVolunteers == nil || *r.MaxVolunteers < minEventVolunteersNumber || *r.MaxVolunteers > maxEventVolunteersNumber {
+		fields.add("maxVolunteers", "количество волонтёров должно быть от 1 до 1000")
+	}
+	return fields
+}
+
+func newEventResponse(e model.Event) eventResponse {
+	resp := eventResponse{
+		ID:              e.ID,
+		Title:           e.Title,
+		Description:     e.Description,
+		Chat:            e.Chat,
+		Date:            e.Date,
+		DurationHours:   e.DurationHours,
+		Location:        e.Location,
+		Lat:             e.LocationLat,
+		Lon:             e.LocationLon,
+		CategoryID:      e.CategoryID,
+		OrganizerID:     e.OrganizerID,
+		Contacts:        e.Contacts,
+		MaxVolunteers:   e.MaxVolunteers,
+		Status:          eventStatusOrDefault(e),
+		CancelledReason: e.CancelledReason,
+		CompletedAt:     e.CompletedAt,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+	if e.CurrentVolunteers != nil {
+		resp.CurrentVolunteers = *e.CurrentVolunteers
+	}
+	return resp
+}
+
+func eventStatusOrDefault(e model.Event) string {
+	if e.Status == nil || *e.Status == "" {
+		return model.EventStatusOpen
+	}
+	return *e.Status
+}
+
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
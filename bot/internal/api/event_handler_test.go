@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"maxBot/internal/auth"
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+)
+
+const (
+	verifiedOrganizerID   int64 = 10
+	unverifiedOrganizerID int64 = 11
+	plainVolunteerID      int64 = 12
+	otherOrganizerID      int64 = 99
+
+	openEventID     int32 = 1
+	foreignEventID  int32 = 2
+	finishedEventID int32 = 3
+	occupiedEventID int32 = 4
+	lockedEventID   int32 = 5
+	racedEventID    int32 = 6
+)
+
+const eventRequestBody = `{"title":"Уборка парка","date":"2099-05-01T10:00:00Z","location":"Парк Горького","lat":55.73,"lon":37.6,"maxVolunteers":10}`
+
+// stubEventService отдаёт снимок события из events, а UpdateEvent и DeleteEvent возвращают ошибку из errs,
+// как сервис после проверок под блокировкой события.
+type stubEventService struct {
+	service.EventService
+	events map[int32]model.Event
+	errs   map[int32]error
+}
+
+func (s stubEventService) GetEventByID(_ context.Context, id int32) (model.Event, error) {
+	event, ok := s.events[id]
+	if !ok {
+		return model.Event{}, pgx.ErrNoRows
+	}
+	return event, nil
+}
+
+func (s stubEventService) CreateEvent(_ context.Context, title string, description *string, chat *int64, date time.Time, durationHours *int32, location string, lat, lon float64, categoryID *int32, contacts *string, maxVolunteers int32, organizerID int64) (model.Event, error) {
+	return model.Event{ID: 100, Title: title, Date: date, Location: location, LocationLat: lat, LocationLon: lon, MaxVolunteers: maxVolunteers, OrganizerID: &organizerID}, nil
+}
+
+func (s stubEventService) UpdateEvent(_ context.Context, id int32, title string, description *string, chat *int64, date time.Time, durationHours *int32, location string, lat, lon float64, categoryID *int32, contacts *string, maxVolunteers int32) (model.Event, error) {
+	if err := s.errs[id]; err != nil {
+		return model.Event{}, err
+	}
+	event := s.events[id]
+	event.Title, event.Date, event.Location, event.MaxVolunteers = title, date, location, maxVolunteers
+	return event, nil
+}
+
+func (s stubEventService) DeleteEvent(_ context.Context, id int32) error {
+	return s.errs[id]
+}
+
+func (s stubEventService) CancelEvent(_ context.Context, id int32, reason *string) (model.Event, error) {
+	event := s.events[id]
+	status := model.EventStatusCancelled
+	event.Status, event.CancelledReason = &status, reason
+	return event, nil
+}
+
+// CompleteEvent отказывает событию lockedEventID, как сервис при гонке со сменой статуса.
+func (s stubEventService) CompleteEvent(_ context.Context, id int32) (model.Event, error) {
+	if id == lockedEventID {
+		return model.Event{}, service.ErrEventStatusChanged
+	}
+	event := s.events[id]
+	status := model.EventStatusCompleted
+	event.Status = &status
+	return event, nil
+}
+
+type stubOrganizerService struct {
+	service.OrganizerService
+	organizers map[int64]model.Organizer
+}
+
+func (s stubOrganizerService) GetOrganizer(_ context.Context, id int64) (model.Organizer, error) {
+	organizer, ok := s.organizers[id]
+	if !ok {
+		return model.Organizer{}, pgx.ErrNoRows
+	}
+	return organizer, nil
+}
+
+func newEventTestRouter(t *testing.T) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	validator, secret := newTestValidator(t)
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	organizer, other := verifiedOrganizerID, otherOrganizerID
+	completed := model.EventStatusCompleted
+	two := int32(2)
+	services := newTestServices(
+		model.User{ID: verifiedOrganizerID, Name: "Организатор"},
+		model.User{ID: unverifiedOrganizerID, Name: "Новичок"},
+		model.User{ID: plainVolunteerID, Name: "Волонтёр"},
+	)
+	services.OrganizerService = stubOrganizerService{organizers: map[int64]model.Organizer{
+		verifiedOrganizerID:   {ID: verifiedOrganizerID, VerifiedAt: &verifiedAt},
+		unverifiedOrganizerID: {ID: unverifiedOrganizerID},
+	}}
+	services.EventService = stubEventService{events: map[int32]model.Event{
+		openEventID:     {ID: openEventID, OrganizerID: &organizer, Date: time.Now().Add(24 * time.Hour), MaxVolunteers: 10},
+		foreignEventID:  {ID: foreignEventID, OrganizerID: &other},
+		finishedEventID: {ID: finishedEventID, OrganizerID: &organizer, Status: &completed},
+		occupiedEventID: {ID: occupiedEventID, OrganizerID: &organizer, MaxVolunteers: 10, CurrentVolunteers: &two},
+		lockedEventID:   {ID: lockedEventID, OrganizerID: &organizer},
+		racedEventID:    {ID: racedEventID, OrganizerID: &organizer, MaxVolunteers: 10},
+	}, errs: map[int32]error{
+		finishedEventID: service.ErrEventFinished,
+		occupiedEventID: service.ErrEventHasApplications,
+		lockedEventID:   service.ErrEventHasApplications,
+		racedEventID:    service.ErrMaxVolunteersBelowCurrent,
+	}}
+
+	router := gin.New()
+	newEventHandler(services.EventService, nil).register(router.Group("/api/v1"), newAuthMiddleware(validator, services))
+	return router, secret
+}
+
+// serveAPI выполняет запрос от имени userID и разбирает ответ об ошибке, если он есть.
+func serveAPI(t *testing.T, router *gin.Engine, secret string, userID int64, method, path, body string) (int, errorResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, secret, auth.MaxUser{ID: userID}))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp errorResponse
+	if w.Code >= http.StatusBadRequest {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: error body %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w.Code, resp
+}
+
+func TestEventHandlerValidationErrorShape(t *testing.T) {
+	router, secret := newEventTestRouter(t)
+
+	code, resp := serveAPI(t, router, secret, verifiedOrganizerID, http.MethodPost, "/api/v1/events",
+		`{"title":"ab","date":"2000-01-01T10:00:00Z","durationHours":0,"lat":100,"lon":37.6,"maxVolunteers":0}`)
+	if code != http.StatusUnprocessableEntity || resp.Code != errCodeValidation || resp.Message == "" {
+		t.Fatalf("got %d %+v, want 422 %s", code, resp, errCodeValidation)
+	}
+	for _, field := range []string{"title", "date", "durationHours", "location", "lat", "maxVolunteers"} {
+		if resp.Fields[field] == "" {
+			t.Errorf("field %q missing in %v", field, resp.Fields)
+		}
+	}
+	if _, ok := resp.Fields["lon"]; ok {
+		t.Errorf("valid lon reported as invalid: %v", resp.Fields)
+	}
+
+	code, resp = serveAPI(t, router, secret, verifiedOrganizerID, http.MethodPut, "/api/v1/events/4",
+		strings.Replace(eventRequestBody, `"maxVolunteers":10`, `"maxVolunteers":1`, 1))
+	if code != http.StatusUnprocessableEntity || resp.Fields["maxVolunteers"] == "" {
+		t.Fatalf("shrinking below approved volunteers: got %d %+v", code, resp)
+	}
+
+	// Волонтёров одобрили уже после чтения снимка: ошибку сервиса возвращаем в том же виде.
+	code, resp = serveAPI(t, router, secret, verifiedOrganizerID, http.MethodPut, "/api/v1/events/6",
+		strings.Replace(eventRequestBody, `"maxVolunteers":10`, `"maxVolunteers":1`, 1))
+	if code != http.StatusUnprocessableEntity || resp.Code != errCodeValidation || resp.Fields["maxVolunteers"] == "" {
+		t.Fatalf("approved concurrently: got %d %+v", code, resp)
+	}
+
+	code, resp = serveAPI(t, router, secret, verifiedOrganizerID, http.MethodPost, "/api/v1/events/1/cancel",
+		`{"reason":"`+strings.Repeat("я", maxCancelReasonLength+1)+`"}`)
+	if code != http.StatusUnprocessableEntity || resp.Fields["reason"] == "" {
+		t.Fatalf("long cancel reason: got %d %+v", code, resp)
+	}
+}
+
+func TestEventHandlerStatusCodes(t *testing.T) {
+	router, secret := newEventTestRouter(t)
+
+	cases := []struct {
+		name     string
+		userID   int64
+		method   string
+		path     string
+		body     string
+		want     int
+		wantCode string
+	}{
+		{"create", verifiedOrganizerID, http.MethodPost, "/api/v1/events", eventRequestBody, http.StatusCreated, ""},
+		{"create malformed json", verifiedOrganizerID, http.MethodPost, "/api/v1/events", `{"title":`, http.StatusBadRequest, errCodeBadRequest},
+		{"create by volunteer", plainVolunteerID, http.MethodPost, "/api/v1/events", eventRequestBody, http.StatusForbidden, errCodeForbidden},
+		{"create unverified", unverifiedOrganizerID, http.MethodPost, "/api/v1/events", eventRequestBody, http.StatusForbidden, errCodeForbidden},
+		{"update", verifiedOrganizerID, http.MethodPut, "/api/v1/events/1", eventRequestBody, http.StatusOK, ""},
+		{"update foreign", verifiedOrganizerID, http.MethodPut, "/api/v1/events/2", eventRequestBody, http.StatusForbidden, errCodeForbidden},
+		{"update missing", verifiedOrganizerID, http.MethodPut, "/api/v1/events/404", eventRequestBody, http.StatusNotFound, errCodeNotFound},
+		{"update bad id", verifiedOrganizerID, http.MethodPut, "/api/v1/events/abc", eventRequestBody, http.StatusBadRequest, errCodeBadRequest},
+		{"update finished", verifiedOrganizerID, http.MethodPut, "/api/v1/events/3", eventRequestBody, http.StatusConflict, errCodeConflict},
+		{"delete", verifiedOrganizerID, http.MethodDelete, "/api/v1/events/1", "", http.StatusNoContent, ""},
+		{"delete with participants", verifiedOrganizerID, http.MethodDelete, "/api/v1/events/4", "", http.StatusConflict, errCodeConflict},
+		{"delete with open applications", verifiedOrganizerID, http.MethodDelete, "/api/v1/events/5", "", http.StatusConflict, errCodeConflict},
+		{"delete foreign", verifiedOrganizerID, http.MethodDelete, "/api/v1/events/2", "", http.StatusForbidden, errCodeForbidden},
+		{"cancel without body", verifiedOrganizerID, http.MethodPost, "/api/v1/events/1/cancel", "", http.StatusOK, ""},
+		{"complete", verifiedOrganizerID, http.MethodPost, "/api/v1/events/1/complete", "", http.StatusOK, ""},
+		{"complete concurrently changed", verifiedOrganizerID, http.MethodPost, "/api/v1/events/5/complete", "", http.StatusConflict, errCodeConflict},
+	}
+	for _, tc := range cases {
+		code, resp := serveAPI(t, router, secret, tc.userID, tc.method, tc.path, tc.body)
+		if code != tc.want || resp.Code != tc.wantCode {
+			t.Errorf("%s: got %d %q, want %d %q", tc.name, code, resp.Code, tc.want, tc.wantCode)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+)
+
+const (
+	// streamHeartbeat — период комментариев-пингов, чтобы прокси не закрывали простаивающее соединение.
+	streamHeartbeat = 15 * time.Second
+	// streamRetry — через сколько браузерный EventSource переподключается после обрыва.
+	streamRetry = 3 * time.Second
+)
+
+type eventStreamHandler struct {
+	hub *service.EventHub
+}
+
+func newEventStreamHandler(hub *service.EventHub) *eventStreamHandler {
+	if hub == nil {
+		return nil
+	}
+	return &eventStreamHandler{hub: hub}
+}
+
+func (h *eventStreamHandler) register(r *gin.RouterGroup) {
+	if h == nil {
+		return
+	}
+	r.GET("/events/stream", h.stream)
+}
+
+// stream отдаёт изменения мероприятий в формате Server-Sent Events. Фильтры те же, что у /map/events:
+// bbox либо lat, lon и radius_km, а также категории. Id сообщения — номер в хабе: после обрыва клиент
+// присылает его в Last-Event-ID (или lastEventId, если переподключается сам) и получает пропущенное.
+func (h *eventStreamHandler) stream(c *gin.Context) {
+	params, err := parseMapQueryParams(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
+		return
+	}
+	lastSeq, err := parseLastEventID(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
+		return
+	}
+	filter := service.EventStreamFilter{
+		BBox:        params.BBox,
+		Lat:         params.Lat,
+		Lon:         params.Lon,
+		RadiusKm:    params.RadiusKm,
+		CategoryIDs: params.CategoryIDs,
+	}
+
+	sub, replay, complete := h.hub.Subscribe(filter, lastSeq)
+	defer h.hub.Unsubscribe(sub)
+
+	// Поток живёт дольше WriteTimeout сервера, поэтому дедлайн записи для него снимается.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("event stream: reset write deadline: %v", err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if !complete {
+		// Пропущенное уже вытеснено из истории: клиент должен перечитать карту через /map/events.
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, update := range replay {
+		if err := writeStreamUpdate(w, update, filter); err != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		case update, ok := <-sub.Updates():
+			if !ok {
+				// Хаб отключил подписчика: очередь переполнена или сервер останавливается.
+				return
+			}
+			if err := writeStreamUpdate(w, update, filter); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+func writeStreamUpdate(w gin.ResponseWriter, update model.EventUpdate, filter service.EventStreamFilter) error {
+	if update.Event != nil {
+		// Снимок общий для всех подписчиков, поэтому distanceKm считается на копии.
+		event := *update.Event
+		event.DistanceKm = service.DistanceKm(filter.Lat, filter.Lon, event.LocationLat, event.LocationLon)
+		update.Event = &event
+	}
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", update.Seq, update.Type, data)
+	return err
+}
+
+func parseLastEventID(c *gin.Context) (uint64, error) {
+	raw := strings.TrimSpace(c.GetHeader("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(c.Query("lastEventId"))
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Last-Event-ID должен быть числом")
+	}
+	return seq, nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"maxBot/internal/model"
+)
+
+const geoJSONContentType = "application/geo+json"
+
+// geoJSONFeatureCollection — ответ карты в GeoJSON (RFC 7946). Метаданные пагинации лежат
+// в foreign member meta, который GIS-инструменты игнорируют.
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+	Meta     map[string]any   `json:"meta,omitempty"`
+}
+
+type geoJSONFeature struct {
+	Type       string         `json:"type"`
+	ID         int32          `json:"id"`
+	Geometry   geoJSONPoint   `json:"geometry"`
+	Properties model.MapEvent `json:"properties"`
+}
+
+// geoJSONPoint хранит координаты в порядке GeoJSON: долгота, затем широта.
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+func newGeoJSONFeatureCollection(events []model.MapEvent, meta map[string]any) geoJSONFeatureCollection {
+	features := make([]geoJSONFeature, 0, len(events))
+	for _, event := range events {
+		features = append(features, geoJSONFeature{
+			Type: "Feature",
+			ID:   event.ID,
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{event.LocationLon, event.LocationLat},
+			},
+			Properties: event,
+		})
+	}
+	return geoJSONFeatureCollection{Type: "FeatureCollection", Features: features, Meta: meta}
+}
+
+// wantsGeoJSON выбирает формат ответа: параметр format=json|geojson важнее заголовка Accept.
+func wantsGeoJSON(c *gin.Context) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(c.Query("format"))) {
+	case "geojson":
+		return true, true
+	case "json":
+		return false, true
+	case "":
+	default:
+		return false, false
+	}
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mediaType == geoJSONContentType {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// respondMapEvents отдаёт страницу мероприятий в согласованном формате.
+func respondMapEvents(c *gin.Context, geoJSON bool, events []model.MapEvent, meta map[string]any) {
+	c.Header("Vary", "Accept")
+	if geoJSON {
+		// render.JSON не перезаписывает уже выставленный Content-Type.
+		c.Header("Content-Type", geoJSONContentType)
+		c.JSON(http.StatusOK, newGeoJSONFeatureCollection(events, meta))
+		return
+	}
+	c.JSON(http.StatusOK, mapEventsResponse{Data: events, Meta: meta})
+}
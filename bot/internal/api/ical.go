@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"maxBot/internal/model"
+)
+
+const (
+	icalTimeLayout = "20060102T150405Z"
+	// icalDefaultDuration — длительность мероприятия без duration_hours, как у планировщика статусов.
+	icalDefaultDuration = 3 * time.Hour
+	// icalLineLimit — максимальная длина строки iCalendar в октетах без CRLF (RFC 5545, 3.1).
+	icalLineLimit = 75
+)
+
+// writeICalendar собирает ленту VCALENDAR из мероприятий волонтёра.
+func writeICalendar(events []model.Event) []byte {
+	var buf bytes.Buffer
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:-//maxBot//Volunteer events//RU")
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	writeICalLine(&buf, "X-WR-CALNAME:"+escapeICalText("Волонтёрские мероприятия"))
+	writeICalLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICalLine(&buf, "X-PUBLISHED-TTL:PT1H")
+	for _, event := range events {
+		writeICalEvent(&buf, event)
+	}
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func writeICalEvent(buf *bytes.Buffer, event model.Event) {
+	start := event.Date
+	duration := icalDefaultDuration
+	if event.DurationHours != nil && *event.DurationHours > 0 {
+		duration = time.Duration(*event.DurationHours) * time.Hour
+	}
+
+	writeICalLine(buf, "BEGIN:VEVENT")
+	// UID не зависит от адреса сервера, чтобы календарь не задвоил события при смене домена.
+	writeICalLine(buf, fmt.Sprintf("UID:event-%d@maxbot", event.ID))
+	writeICalLine(buf, "DTSTAMP:"+event.UpdatedAt.UTC().Format(icalTimeLayout))
+	writeICalLine(buf, "LAST-MODIFIED:"+event.UpdatedAt.UTC().Format(icalTimeLayout))
+	writeICalLine(buf, "DTSTART:"+start.UTC().Format(icalTimeLayout))
+	writeICalLine(buf, "DTEND:"+start.Add(duration).UTC().Format(icalTimeLayout))
+	writeICalLine(buf, "SUMMARY:"+escapeICalText(event.Title))
+	if description := icalDescription(event); description != "" {
+		writeICalLine(buf, "DESCRIPTION:"+escapeICalText(description))
+	}
+	if event.Location != "" {
+		writeICalLine(buf, "LOCATION:"+escapeICalText(event.Location))
+	}
+	writeICalLine(buf, fmt.Sprintf("GEO:%.6f;%.6f", event.LocationLat, event.LocationLon))
+	if event.Status != nil && *event.Status == model.EventStatusCancelled {
+		writeICalLine(buf, "STATUS:CANCELLED")
+	} else {
+		writeICalLine(buf, "STATUS:CONFIRMED")
+	}
+	writeICalLine(buf, "END:VEVENT")
+}
+
+func icalDescription(event model.Event) string {
+	var parts []string
+	if event.Status != nil && *event.Status == model.EventStatusCancelled && event.CancelledReason != nil {
+		parts = append(parts, "Отменено: "+*event.CancelledReason)
+	}
+	if event.Description != nil && *event.Description != "" {
+		parts = append(parts, *event.Description)
+	}
+	if event.Contacts != nil && *event.Contacts != "" {
+		parts = append(parts, "Контакты: "+*event.Contacts)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func escapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// writeICalLine пишет строку с CRLF, перенося её по 75 октетов без разрыва UTF-8 символов.
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Пробел в начале строки продолжения входит в её лимит.
+		limit = icalLineLimit - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
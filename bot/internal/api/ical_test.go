@@ -0,0 +1,75 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"maxBot/internal/model"
+)
+
+func TestWriteICalLineFoldsByOctets(t *testing.T) {
+	var buf bytes.Buffer
+	line := "SUMMARY:" + strings.Repeat("Уборка парка ", 20)
+	writeICalLine(&buf, line)
+
+	raw := buf.String()
+	if !strings.HasSuffix(raw, "\r\n") {
+		t.Fatalf("line must end with CRLF: %q", raw)
+	}
+	physical := strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n")
+	if len(physical) < 2 {
+		t.Fatalf("expected folded line, got %d parts", len(physical))
+	}
+	var unfolded strings.Builder
+	for i, part := range physical {
+		if len(part) > icalLineLimit {
+			t.Fatalf("part %d has %d octets", i, len(part))
+		}
+		if !utf8.ValidString(part) {
+			t.Fatalf("part %d splits a UTF-8 character: %q", i, part)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(part, " ") {
+				t.Fatalf("continuation %d must start with a space: %q", i, part)
+			}
+			part = part[1:]
+		}
+		unfolded.WriteString(part)
+	}
+	if unfolded.String() != line {
+		t.Fatalf("unfolded line differs:\n%q\n%q", unfolded.String(), line)
+	}
+}
+
+func TestWriteICalendarEscapesAndMarksCancelled(t *testing.T) {
+	status := model.EventStatusCancelled
+	reason := "дождь; перенос"
+	hours := int32(2)
+	events := []model.Event{{
+		ID:              7,
+		Title:           "Субботник, парк",
+		Date:            time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC),
+		DurationHours:   &hours,
+		Location:        "Парк Горького",
+		Status:          &status,
+		CancelledReason: &reason,
+		UpdatedAt:       time.Date(2025, 11, 18, 9, 0, 0, 0, time.UTC),
+	}}
+
+	out := string(writeICalendar(events))
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:event-7@maxbot\r\n",
+		`SUMMARY:Субботник\, парк` + "\r\n",
+		`DESCRIPTION:Отменено: дождь\; перенос` + "\r\n",
+		"STATUS:CANCELLED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("calendar does not contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -26,106 +27,184 @@ type mapEventsResponse struct {
 	Meta map[string]any   `json:"meta"`
 }
 
-type errorResponse struct {
-	Message string `json:"message"`
-}
-
 func (h *mapHandler) register(r *gin.RouterGroup, authMW *authMiddleware) {
 	r.GET("/map/events", h.listEvents)
+	r.GET("/map/clusters", h.listClusters)
 	usersGroup := r.Group("/map")
 	if authMW != nil {
 		usersGroup.Use(authMW.requireUser())
 	}
-	usersGroup.GET("/users/:userID/events", h.listUserEvents)
+	usersGroup.GET("/users/:userID/events", authMW.requireSelf("userID"), h.listUserEvents)
 }
 
 func (h *mapHandler) listEvents(c *gin.Context) {
+	geoJSON, ok := wantsGeoJSON(c)
+	if !ok {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "format должен быть json или geojson")
+		return
+	}
 	params, err := parseMapQueryParams(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: err.Error()})
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
 		return
 	}
 
-	items, err := h.events.ListEventsForMap(c.Request.Context(), params)
+	page, err := h.events.ListEventsForMap(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Message: "не удалось получить события"})
+		if errors.Is(err, service.ErrInvalidCursor) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, invalidCursorMessage)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить события")
 		return
 	}
 
-	resp := mapEventsResponse{
-		Data: items,
-		Meta: map[string]any{
-			"limit":      params.Limit,
-			"offset":     params.Offset,
-			"count":      len(items),
-			"radiusKm":   params.RadiusKm,
-			"categories": params.CategoryIDs,
-			"lat":        params.Lat,
-			"lon":        params.Lon,
-		},
-	}
+	respondMapEvents(c, geoJSON, page.Items, mapPageMeta(params, page))
+}
 
-	c.JSON(http.StatusOK, resp)
+type mapClustersResponse struct {
+	Data model.MapClusters `json:"data"`
+	Meta map[string]any    `json:"meta"`
 }
 
-func (h *mapHandler) listUserEvents(c *gin.Context) {
-	userIDStr := strings.TrimSpace(c.Param("userID"))
-	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: "userID обязателен"})
+// listClusters отдаёт кластеры видимой области: bbox=minLon,minLat,maxLon,maxLat и zoom карты.
+// При minLon > maxLon область пересекает 180-й меридиан.
+func (h *mapHandler) listClusters(c *gin.Context) {
+	bbox, bboxErr := parseBBox(c.Query("bbox"), true)
+	zoom, zoomErr := strconv.Atoi(strings.TrimSpace(c.Query("zoom")))
+	categories, categoriesErr := parseCategoryIDs(c)
+
+	fields := validationErrors{}
+	if bboxErr != nil {
+		fields["bbox"] = bboxErr.Error()
+	}
+	if zoomErr != nil || zoom < 0 || zoom > service.MapMaxZoom {
+		fields["zoom"] = fmt.Sprintf("zoom обязателен и должен быть от 0 до %d", service.MapMaxZoom)
+	}
+	if categoriesErr != nil {
+		fields["categories"] = categoriesErr.Error()
+	}
+	if len(fields) > 0 {
+		respondValidationError(c, fields)
 		return
 	}
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil || userID <= 0 {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: "userID должен быть положительным числом"})
+
+	result, err := h.events.ListMapClusters(c.Request.Context(), service.ListMapClustersParams{
+		BBox:        bbox,
+		Zoom:        zoom,
+		CategoryIDs: categories,
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить кластеры")
 		return
 	}
 
-	authUser, ok := getAuthenticatedUser(c)
+	mode := "clusters"
+	if zoom >= service.MapPointsMinZoom {
+		mode = "points"
+	}
+	c.JSON(http.StatusOK, mapClustersResponse{
+		Data: result,
+		Meta: map[string]any{
+			"mode":       mode,
+			"bbox":       bbox,
+			"categories": categories,
+		},
+	})
+}
+
+func (h *mapHandler) listUserEvents(c *gin.Context) {
+	// Совпадение userID с текущим пользователем проверяет requireSelf.
+	user, ok := getCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse{Message: "требуется авторизация"})
+		respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
 		return
 	}
-	if authUser.ID != userID {
-		c.JSON(http.StatusForbidden, errorResponse{Message: "нельзя запрашивать чужие события"})
+	userID := user.ID
+
+	geoJSON, ok := wantsGeoJSON(c)
+	if !ok {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "format должен быть json или geojson")
 		return
 	}
-
 	params, err := parseMapQueryParams(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: err.Error()})
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
 		return
 	}
 
-	items, err := h.events.ListEventsForMapByVolunteer(c.Request.Context(), service.ListMapEventsForVolunteerParams{
+	page, err := h.events.ListEventsForMapByVolunteer(c.Request.Context(), service.ListMapEventsForVolunteerParams{
 		ListMapEventsParams: params,
 		VolunteerID:         userID,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Message: "не удалось получить события пользователя"})
+		if errors.Is(err, service.ErrInvalidCursor) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, invalidCursorMessage)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить события пользователя")
 		return
 	}
 
-	resp := mapEventsResponse{
-		Data: items,
-		Meta: map[string]any{
-			"limit":      params.Limit,
-			"offset":     params.Offset,
-			"count":      len(items),
-			"radiusKm":   params.RadiusKm,
-			"categories": params.CategoryIDs,
-			"lat":        params.Lat,
-			"lon":        params.Lon,
-			"userId":     userID,
-		},
-	}
+	meta := mapPageMeta(params, page)
+	meta["userId"] = userID
+	respondMapEvents(c, geoJSON, page.Items, meta)
+}
 
-	c.JSON(http.StatusOK, resp)
+const invalidCursorMessage = "cursor не подходит к запросу, начните с первой страницы"
+
+// mapPageMeta описывает страницу: nextCursor равен null на последней странице,
+// totalEstimate приходит только при total=true.
+func mapPageMeta(params service.ListMapEventsParams, page service.Page[model.MapEvent]) map[string]any {
+	meta := map[string]any{
+		"limit":      params.Limit,
+		"count":      len(page.Items),
+		"nextCursor": nil,
+		"radiusKm":   params.RadiusKm,
+		"bbox":       params.BBox,
+		"categories": params.CategoryIDs,
+		"lat":        params.Lat,
+		"lon":        params.Lon,
+	}
+	if page.NextCursor != "" {
+		meta["nextCursor"] = page.NextCursor
+	}
+	if page.TotalEstimate != nil {
+		meta["totalEstimate"] = *page.TotalEstimate
+	}
+	return meta
 }
 
 func parseFloatQuery(val string) (float64, error) {
 	return strconv.ParseFloat(strings.TrimSpace(val), 64)
 }
 
+// parseBBox разбирает bbox в порядке minLon,minLat,maxLon,maxLat, как в GeoJSON. Область через
+// антимеридиан (minLon > maxLon) принимается только при allowAntimeridian: постраничный список
+// такие области не поддерживает, и клиент запрашивает их двумя запросами.
+func parseBBox(raw string, allowAntimeridian bool) (model.MapBBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return model.MapBBox{}, fmt.Errorf("bbox обязателен в формате minLon,minLat,maxLon,maxLat")
+	}
+	values := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		value, err := parseFloatQuery(part)
+		if err != nil {
+			return model.MapBBox{}, fmt.Errorf("bbox должен состоять из чисел")
+		}
+		values = append(values, value)
+	}
+	bbox := model.MapBBox{MinLon: values[0], MinLat: values[1], MaxLon: values[2], MaxLat: values[3]}
+	if bbox.MinLat < -90 || bbox.MaxLat > 90 || bbox.MinLon < -180 || bbox.MaxLon > 180 {
+		return model.MapBBox{}, fmt.Errorf("bbox выходит за пределы координат")
+	}
+	if bbox.MinLat > bbox.MaxLat || (bbox.MinLon > bbox.MaxLon && !allowAntimeridian) {
+		return model.MapBBox{}, fmt.Errorf("в bbox минимум должен быть не больше максимума")
+	}
+	return bbox, nil
+}
+
 func parseCategoryIDs(c *gin.Context) ([]int32, error) {
 	var raw []string
 	if list := c.QueryArray("category_id"); len(list) > 0 {
@@ -152,11 +231,40 @@ func parseCategoryIDs(c *gin.Context) ([]int32, error) {
 	return deduplicate(result), nil
 }
 
+// parseMapQueryParams принимает либо bbox, либо lat, lon и radius_km.
 func parseMapQueryParams(c *gin.Context) (service.ListMapEventsParams, error) {
+	if c.Query("offset") != "" {
+		return service.ListMapEventsParams{}, fmt.Errorf("offset не поддерживается, передайте cursor из meta.nextCursor")
+	}
+	limit := parseInt32Bound(c.Query("limit"), 1, 100, 50)
+	cursor := strings.TrimSpace(c.Query("cursor"))
+	withTotal := c.Query("total") == "true"
+	categories, err := parseCategoryIDs(c)
+	if err != nil {
+		return service.ListMapEventsParams{}, err
+	}
+
+	if raw := c.Query("bbox"); raw != "" {
+		bbox, err := parseBBox(raw, false)
+		if err != nil {
+			return service.ListMapEventsParams{}, err
+		}
+		// distanceKm в режиме bbox считается от центра области.
+		return service.ListMapEventsParams{
+			Cursor:      cursor,
+			WithTotal:   withTotal,
+			Limit:       limit,
+			Lat:         (bbox.MinLat + bbox.MaxLat) / 2,
+			Lon:         (bbox.MinLon + bbox.MaxLon) / 2,
+			BBox:        &bbox,
+			CategoryIDs: categories,
+		}, nil
+	}
+
 	latStr := c.Query("lat")
 	lat, err := parseFloatQuery(latStr)
 	if err != nil {
-		return service.ListMapEventsParams{}, fmt.Errorf("lat обязателен и должен быть числом")
+		return service.ListMapEventsParams{}, fmt.Errorf("укажите bbox либо lat, lon и radius_km; lat должен быть числом")
 	}
 	lonStr := c.Query("lon")
 	lon, err := parseFloatQuery(lonStr)
@@ -172,15 +280,9 @@ func parseMapQueryParams(c *gin.Context) (service.ListMapEventsParams, error) {
 		return service.ListMapEventsParams{}, fmt.Errorf("radius_km обязателен и должен быть > 0")
 	}
 
-	limit := parseInt32Bound(c.Query("limit"), 1, 100, 50)
-	offset := parseInt32Bound(c.Query("offset"), 0, 10_000, 0)
-	categories, err := parseCategoryIDs(c)
-	if err != nil {
-		return service.ListMapEventsParams{}, err
-	}
-
 	return service.ListMapEventsParams{
-		Offset:      offset,
+		Cursor:      cursor,
+		WithTotal:   withTotal,
 		Limit:       limit,
 		Lat:         lat,
 		Lon:         lon,
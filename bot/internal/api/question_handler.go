@@ -0,0 +1,131 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+)
+
+type questionHandler struct {
+	events    service.EventService
+	questions service.EventQuestionService
+}
+
+func newQuestionHandler(events service.EventService, questions service.EventQuestionService) *questionHandler {
+	if events == nil || questions == nil {
+		return nil
+	}
+	return &questionHandler{events: events, questions: questions}
+}
+
+type questionRequest struct {
+	Type     string   `json:"type"`
+	Text     string   `json:"text"`
+	Options  []string `json:"options"`
+	Required *bool    `json:"required"`
+}
+
+type replaceQuestionsRequest struct {
+	Questions []questionRequest `json:"questions"`
+}
+
+type questionResponse struct {
+	ID       int32    `json:"id"`
+	Position int32    `json:"position"`
+	Type     string   `json:"type"`
+	Text     string   `json:"text"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
+
+type questionsResponse struct {
+	Data []questionResponse `json:"data"`
+}
+
+func (h *questionHandler) register(r *gin.RouterGroup, authMW *authMiddleware) {
+	if h == nil || authMW == nil {
+		return
+	}
+	group := r.Group("/events/:id/questions", authMW.requireUser())
+	group.GET("", h.listQuestions)
+	group.PUT("", authMW.requireRole(roleOrganizer), authMW.requireVerifiedOrganizer(), authMW.requireEventOwner("id"), h.replaceQuestions)
+}
+
+func (h *questionHandler) listQuestions(c *gin.Context) {
+	eventID, ok := parseEventIDParam(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	if _, err := h.events.GetEventByID(ctx, eventID); err != nil {
+		respondServiceError(c, err, "не удалось получить событие")
+		return
+	}
+	questions, err := h.questions.ListEventQuestions(ctx, eventID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "не удалось получить анкету")
+		return
+	}
+
+	c.JSON(http.StatusOK, newQuestionsResponse(questions))
+}
+
+// replaceQuestions заменяет анкету целиком; пустой список убирает анкету у события.
+func (h *questionHandler) replaceQuestions(c *gin.Context) {
+	event, ok := getOwnedEvent(c)
+	if !ok {
+		respondError(c, http.StatusForbidden, errCodeForbidden, "событие принадлежит другому организатору")
+		return
+	}
+
+	var req replaceQuestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "тело запроса должно быть корректным JSON")
+		return
+	}
+
+	questions := make([]model.EventQuestion, 0, len(req.Questions))
+	for _, question := range req.Questions {
+		required := true
+		if question.Required != nil {
+			required = *question.Required
+		}
+		questions = append(questions, model.EventQuestion{
+			Type:     question.Type,
+			Text:     question.Text,
+			Options:  question.Options,
+			Required: required,
+		})
+	}
+
+	saved, err := h.questions.ReplaceEventQuestions(c.Request.Context(), event.ID, *event.OrganizerID, questions)
+	if err != nil {
+		respondServiceError(c, err, "не удалось сохранить анкету")
+		return
+	}
+
+	c.JSON(http.StatusOK, newQuestionsResponse(saved))
+}
+
+func newQuestionsResponse(questions []model.EventQuestion) questionsResponse {
+	data := make([]questionResponse, 0, len(questions))
+	for _, question := range questions {
+		options := question.Options
+		if options == nil {
+			options = []string{}
+		}
+		data = append(data, questionResponse{
+			ID:       question.ID,
+			Position: question.Position,
+			Type:     question.Type,
+			Text:     question.Text,
+			Options:  options,
+			Required: question.Required,
+		})
+	}
+	return questionsResponse{Data: data}
+}
@@ -14,8 +14,9 @@ import (
 
 // Server инкапсулирует HTTP-слой приложения.
 type Server struct {
-	cfg  RestConfig
-	http *http.Server
+	cfg    RestConfig
+	engine *gin.Engine
+	http   *http.Server
 }
 
 // NewServer создаёт gin.Engine и HTTP-сервер.
@@ -38,12 +39,19 @@ func NewServer(cfg RestConfig, services *di.Services, validator *auth.Validator)
 	corsCfg := buildCORSConfig(cfg.AllowedOrigins)
 	engine.Use(cors.New(corsCfg))
 
+	engine.GET("/.well-known/jwks.json", jwksHandler(validator))
+
 	apiV1 := engine.Group("/api/v1")
-	authMW := newAuthMiddleware(validator)
+	authMW := newAuthMiddleware(validator, services)
 	mapHandler := newMapHandler(services.EventService)
 	mapHandler.register(apiV1, authMW)
 	newUserHandler(services.UserService).register(apiV1, authMW)
-	newAuthHandler(validator).register(apiV1)
+	newEventHandler(services.EventService, services.CategoryService).register(apiV1, authMW)
+	newApplicationHandler(services.ApplicationService, services.ReviewService).register(apiV1, authMW)
+	newQuestionHandler(services.EventService, services.EventQuestionService).register(apiV1, authMW)
+	newCalendarHandler(services.CalendarService).register(apiV1, authMW)
+	newEventStreamHandler(services.EventHub).register(apiV1)
+	newAuthHandler(validator, services.SessionService, services.UserService).register(apiV1)
 
 	httpServer := &http.Server{
 		Addr:         cfg.Addr,
@@ -51,13 +59,23 @@ func NewServer(cfg RestConfig, services *di.Services, validator *auth.Validator)
 		ReadTimeout:  cfg.ReadTimeout,
 		WriteTimeout: cfg.WriteTimeout,
 	}
+	if services.EventHub != nil {
+		// Shutdown ждёт завершения запросов, а SSE-потоки сами не заканчиваются.
+		httpServer.RegisterOnShutdown(services.EventHub.Close)
+	}
 
 	return &Server{
-		cfg:  cfg,
-		http: httpServer,
+		cfg:    cfg,
+		engine: engine,
+		http:   httpServer,
 	}, nil
 }
 
+// Handle добавляет маршрут вне /api/v1, например для webhook бота. Вызывается до Run.
+func (s *Server) Handle(method, path string, handler http.Handler) {
+	s.engine.Handle(method, path, gin.WrapH(handler))
+}
+
 // Run запускает HTTPS сервер и блокирует выполнение.
 func (s *Server) Run() error {
 	return s.http.ListenAndServe()
@@ -1,14 +1,10 @@
 package api
 
 import (
-	"errors"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
 
 	"maxBot/internal/service"
 )
@@ -37,44 +33,19 @@ func (h *userHandler) register(r *gin.RouterGroup, authMW *authMiddleware) {
 	}
 	group := r.Group("/users")
 	group.Use(authMW.requireUser())
-	group.GET("/:userID/location", h.getUserLocation)
+	group.GET("/:userID/location", authMW.requireSelf("userID"), h.getUserLocation)
 }
 
 func (h *userHandler) getUserLocation(c *gin.Context) {
-	userIDStr := strings.TrimSpace(c.Param("userID"))
-	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: "userID обязателен"})
-		return
-	}
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil || userID <= 0 {
-		c.JSON(http.StatusBadRequest, errorResponse{Message: "userID должен быть положительным числом"})
-		return
-	}
-
-	authUser, ok := getAuthenticatedUser(c)
+	// requireUser уже загрузил пользователя, а requireSelf проверил, что он запрашивает себя.
+	user, ok := getCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse{Message: "требуется авторизация"})
-		return
-	}
-	if authUser.ID != userID {
-		c.JSON(http.StatusForbidden, errorResponse{Message: "нельзя запрашивать геолокацию другого пользователя"})
-		return
-	}
-
-	ctx := c.Request.Context()
-	user, err := h.users.GetUserByID(ctx, userID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, errorResponse{Message: "пользователь не найден"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, errorResponse{Message: "не удалось получить пользователя"})
+		respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "требуется авторизация")
 		return
 	}
 
 	if user.LocationLat == nil || user.LocationLon == nil {
-		c.JSON(http.StatusNotFound, errorResponse{Message: "у пользователя нет сохранённой геолокации"})
+		respondError(c, http.StatusNotFound, errCodeNotFound, "у пользователя нет сохранённой геолокации")
 		return
 	}
 
@@ -3,6 +3,7 @@ package auth
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -11,23 +12,30 @@ const (
 	envJWTSecret  = "AUTH_JWT_SECRET"
 	envAuthMaxAge = "AUTH_MAX_AGE"
 	envSessionTTL = "AUTH_SESSION_TTL"
+	envRefreshTTL = "AUTH_REFRESH_TTL"
+	envReplaySize = "AUTH_REPLAY_CACHE_SIZE"
 )
 
 // LoadConfigFromEnv builds Config using env vars and provided bot token.
 func LoadConfigFromEnv(botToken string) (Config, error) {
 	cfg := Config{
 		BotToken:   strings.TrimSpace(botToken),
-		JWTSecret:  strings.TrimSpace(os.Getenv(envJWTSecret)),
 		MaxAge:     parseDurationEnv(envAuthMaxAge),
 		SessionTTL: parseDurationEnv(envSessionTTL),
+		RefreshTTL: parseDurationEnv(envRefreshTTL),
+	}
+	if size, err := strconv.Atoi(strings.TrimSpace(os.Getenv(envReplaySize))); err == nil {
+		cfg.ReplayCacheSize = size
 	}
 
 	if cfg.BotToken == "" {
 		return Config{}, fmt.Errorf("bot token is required for auth config")
 	}
-	if cfg.JWTSecret == "" {
-		return Config{}, fmt.Errorf("%s is required", envJWTSecret)
+	keys, err := LoadKeySetFromEnv()
+	if err != nil {
+		return Config{}, err
 	}
+	cfg.Keys = keys
 
 	return cfg, nil
 }
@@ -0,0 +1,313 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT algorithms.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	envJWTKeysFile       = "AUTH_JWT_KEYS_FILE"
+	envJWTKeys           = "AUTH_JWT_KEYS"
+	envJWTPrevSecrets    = "AUTH_JWT_PREVIOUS_SECRETS"
+	legacySecretKIDBytes = 8
+)
+
+// Key is a single JWT key. Keys without private material can only verify tokens.
+type Key struct {
+	ID        string
+	Algorithm string
+	signKey   any
+	verifyKey any
+}
+
+// CanSign reports whether the key holds a secret or a private key.
+func (k Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+func (k Key) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgRS256:
+		return jwt.SigningMethodRS256
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// KeySet holds the current signing key and every key still accepted for verification.
+type KeySet struct {
+	current string
+	keys    map[string]Key
+}
+
+// NewKeySet builds a key set; current must reference a key that can sign.
+func NewKeySet(current string, keys ...Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key set is empty")
+	}
+	set := &KeySet{current: current, keys: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key id is required")
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+	currentKey, ok := set.keys[current]
+	if !ok {
+		return nil, fmt.Errorf("current key %q not found", current)
+	}
+	if !currentKey.CanSign() {
+		return nil, fmt.Errorf("current key %q has no private key", current)
+	}
+	return set, nil
+}
+
+// NewHMACKey creates an HS256 key.
+func NewHMACKey(id string, secret []byte) Key {
+	return Key{ID: id, Algorithm: AlgHS256, signKey: secret, verifyKey: secret}
+}
+
+// NewSecretKeySet builds an HS256 key set from the current secret and previous secrets that
+// are still accepted. Key ids are derived from the secrets, so tokens survive restarts and rotation.
+func NewSecretKeySet(current string, previous ...string) (*KeySet, error) {
+	if current == "" {
+		return nil, errors.New("jwt secret is required")
+	}
+	currentKey := NewHMACKey(secretKeyID(current), []byte(current))
+	keys := []Key{currentKey}
+	for _, secret := range previous {
+		if secret == "" || secret == current {
+			continue
+		}
+		keys = append(keys, NewHMACKey(secretKeyID(secret), []byte(secret)))
+	}
+	return NewKeySet(currentKey.ID, keys...)
+}
+
+// Current returns the signing key.
+func (s *KeySet) Current() Key {
+	return s.keys[s.current]
+}
+
+// Lookup returns a verification key by kid.
+func (s *KeySet) Lookup(kid string) (Key, bool) {
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// JWK is a public key in RFC 7517 format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWKS returns asymmetric public keys sorted by kid. HMAC secrets are never published.
+func (s *KeySet) PublicJWKS() JWKS {
+	result := JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		switch pub := key.verifyKey.(type) {
+		case ed25519.PublicKey:
+			result.Keys = append(result.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Algorithm: AlgEdDSA,
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		case *rsa.PublicKey:
+			result.Keys = append(result.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: AlgRS256,
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		}
+	}
+	sort.Slice(result.Keys, func(i, j int) bool { return result.Keys[i].KeyID < result.Keys[j].KeyID })
+	return result
+}
+
+// keySetFile is the JSON format of AUTH_JWT_KEYS and AUTH_JWT_KEYS_FILE.
+type keySetFile struct {
+	Current string         `json:"current"`
+	Keys    []keyFileEntry `json:"keys"`
+}
+
+type keyFileEntry struct {
+	ID     string `json:"kid"`
+	Alg    string `json:"alg"`
+	Secret string `json:"secret"`
+	// PrivateKey is a PKCS#8 (or PKCS#1 for RSA) PEM block.
+	PrivateKey string `json:"privateKey"`
+	// PublicKey is a PKIX PEM block for verify-only keys.
+	PublicKey string `json:"publicKey"`
+}
+
+// ParseKeySet decodes a key set from its JSON representation.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var file keySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode key set: %w", err)
+	}
+	keys := make([]Key, 0, len(file.Keys))
+	for _, entry := range file.Keys {
+		key, err := entry.key()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeySet(file.Current, keys...)
+}
+
+func (e keyFileEntry) key() (Key, error) {
+	key := Key{ID: e.ID, Algorithm: e.Alg}
+	switch e.Alg {
+	case AlgHS256:
+		if e.Secret == "" {
+			return Key{}, errors.New("secret is required for HS256")
+		}
+		return NewHMACKey(e.ID, []byte(e.Secret)), nil
+	case AlgEdDSA, AlgRS256:
+		if e.Secret != "" {
+			return Key{}, fmt.Errorf("secret is not used with %s", e.Alg)
+		}
+	default:
+		return Key{}, fmt.Errorf("unsupported alg %q", e.Alg)
+	}
+
+	if e.PrivateKey != "" {
+		private, err := parsePrivateKey(e.PrivateKey)
+		if err != nil {
+			return Key{}, err
+		}
+		key.signKey = private
+		key.verifyKey = private.Public()
+	} else if e.PublicKey != "" {
+		public, err := parsePublicKey(e.PublicKey)
+		if err != nil {
+			return Key{}, err
+		}
+		key.verifyKey = public
+	} else {
+		return Key{}, errors.New("privateKey or publicKey is required")
+	}
+
+	switch key.verifyKey.(type) {
+	case ed25519.PublicKey:
+		if e.Alg != AlgEdDSA {
+			return Key{}, fmt.Errorf("ed25519 key cannot be used with %s", e.Alg)
+		}
+	case *rsa.PublicKey:
+		if e.Alg != AlgRS256 {
+			return Key{}, fmt.Errorf("rsa key cannot be used with %s", e.Alg)
+		}
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T", key.verifyKey)
+	}
+	return key, nil
+}
+
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	return key, nil
+}
+
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadKeySetFromEnv reads keys from AUTH_JWT_KEYS_FILE, AUTH_JWT_KEYS or AUTH_JWT_SECRET
+// (with AUTH_JWT_PREVIOUS_SECRETS), in that order of precedence. It is called again on SIGHUP.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	if path := strings.TrimSpace(os.Getenv(envJWTKeysFile)); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", envJWTKeysFile, err)
+		}
+		return ParseKeySet(data)
+	}
+	if raw := strings.TrimSpace(os.Getenv(envJWTKeys)); raw != "" {
+		return ParseKeySet([]byte(raw))
+	}
+	secret := strings.TrimSpace(os.Getenv(envJWTSecret))
+	if secret == "" {
+		return nil, fmt.Errorf("%s, %s or %s is required", envJWTKeysFile, envJWTKeys, envJWTSecret)
+	}
+	var previous []string
+	for _, item := range strings.Split(os.Getenv(envJWTPrevSecrets), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			previous = append(previous, item)
+		}
+	}
+	return NewSecretKeySet(secret, previous...)
+}
+
+// secretKeyID derives a stable kid without revealing the secret.
+func secretKeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return "hs-" + hex.EncodeToString(sum[:legacySecretKIDBytes])
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+)
+
+func TestSecretRotationKeepsPreviousTokensValid(t *testing.T) {
+	oldKeys, err := NewSecretKeySet("old-secret")
+	if err != nil {
+		t.Fatalf("old key set: %v", err)
+	}
+	validator, err := NewValidator(Config{BotToken: "token", Keys: oldKeys})
+	if err != nil {
+		t.Fatalf("init validator: %v", err)
+	}
+	user := MaxUser{ID: 11}
+	oldToken, err := validator.IssueToken(user, "session")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	rotated, err := NewSecretKeySet("new-secret", "old-secret")
+	if err != nil {
+		t.Fatalf("rotated key set: %v", err)
+	}
+	validator.SetKeySet(rotated)
+
+	if _, err := validator.ParseToken(context.Background(), oldToken); err != nil {
+		t.Fatalf("token signed with previous key must stay valid: %v", err)
+	}
+	newToken, err := validator.IssueToken(user, "session")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	withoutOld, err := NewSecretKeySet("new-secret")
+	if err != nil {
+		t.Fatalf("key set: %v", err)
+	}
+	validator.SetKeySet(withoutOld)
+
+	if _, err := validator.ParseToken(context.Background(), oldToken); err == nil {
+		t.Fatalf("token of a removed key must be rejected")
+	}
+	if _, err := validator.ParseToken(context.Background(), newToken); err != nil {
+		t.Fatalf("token of the current key must be valid: %v", err)
+	}
+}
+
+func TestEd25519KeySetSignsAndPublishesJWKS(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	file, err := json.Marshal(keySetFile{
+		Current: "ed-1",
+		Keys: []keyFileEntry{
+			{ID: "ed-1", Alg: AlgEdDSA, PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))},
+			{ID: "hs-legacy", Alg: AlgHS256, Secret: "legacy"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal key set: %v", err)
+	}
+	keys, err := ParseKeySet(file)
+	if err != nil {
+		t.Fatalf("parse key set: %v", err)
+	}
+
+	validator, err := NewValidator(Config{BotToken: "token", Keys: keys})
+	if err != nil {
+		t.Fatalf("init validator: %v", err)
+	}
+	token, err := validator.IssueToken(MaxUser{ID: 5}, "session")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	if user, err := validator.ParseToken(context.Background(), token); err != nil || user.ID != 5 {
+		t.Fatalf("parse token: user %d, err %v", user.ID, err)
+	}
+
+	jwks := keys.PublicJWKS()
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("expected only the Ed25519 key to be published, got %d keys", len(jwks.Keys))
+	}
+	if jwk := jwks.Keys[0]; jwk.KeyID != "ed-1" || jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" || jwk.X == "" {
+		t.Fatalf("unexpected jwk: %+v", jwk)
+	}
+}
+
+func TestParseKeySetRejectsCurrentKeyWithoutPrivateKey(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	file, err := json.Marshal(keySetFile{
+		Current: "partner",
+		Keys: []keyFileEntry{
+			{ID: "partner", Alg: AlgEdDSA, PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal key set: %v", err)
+	}
+	if _, err := ParseKeySet(file); err == nil {
+		t.Fatalf("expected verify-only current key to be rejected")
+	}
+}
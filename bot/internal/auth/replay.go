@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const defaultReplayCacheSize = 100_000
+
+// ReplayCache remembers recently used init data within a TTL and a fixed capacity.
+// All entries share one TTL, so insertion order is also expiry order and the oldest entry
+// is evicted first when the cache is full.
+type ReplayCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type replayEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewReplayCache creates a cache keeping at most capacity keys for ttl.
+func NewReplayCache(capacity int, ttl time.Duration) *ReplayCache {
+	if capacity <= 0 {
+		capacity = defaultReplayCacheSize
+	}
+	return &ReplayCache{
+		ttl:      ttl,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Seen records every key and reports whether any of them was already recorded and has not expired.
+func (c *ReplayCache) Seen(keys ...string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+
+	for _, key := range keys {
+		if _, ok := c.entries[key]; ok {
+			return true
+		}
+	}
+	for _, key := range keys {
+		if c.order.Len() >= c.capacity {
+			c.remove(c.order.Front())
+		}
+		c.entries[key] = c.order.PushBack(replayEntry{key: key, expiresAt: now.Add(c.ttl)})
+	}
+	return false
+}
+
+// Len returns the number of remembered keys.
+func (c *ReplayCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *ReplayCache) evictExpired(now time.Time) {
+	for front := c.order.Front(); front != nil; front = c.order.Front() {
+		if front.Value.(replayEntry).expiresAt.After(now) {
+			return
+		}
+		c.remove(front)
+	}
+}
+
+func (c *ReplayCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(replayEntry).key)
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayCacheExpiresAndEvictsOldest(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewReplayCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	if cache.Seen("a") {
+		t.Fatalf("first use of a must pass")
+	}
+	if !cache.Seen("a") {
+		t.Fatalf("second use of a must be detected")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if cache.Seen("a") {
+		t.Fatalf("expired key must be accepted again")
+	}
+
+	cache.Seen("b")
+	cache.Seen("c")
+	if cache.Len() != 2 {
+		t.Fatalf("cache must stay bounded, got %d entries", cache.Len())
+	}
+	if cache.Seen("a") {
+		t.Fatalf("oldest key must be evicted when the cache is full")
+	}
+}
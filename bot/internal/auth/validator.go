@@ -1,10 +1,12 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,21 +14,55 @@ import (
 	"maxBot/internal/auth/initdatahash"
 )
 
+var (
+	// ErrTokenRevoked is returned by ParseToken when the session behind the token was revoked or expired.
+	ErrTokenRevoked = errors.New("token revoked")
+
+	// Init data rejection reasons; ValidateInitData wraps one of them.
+	ErrInitDataMalformed = errors.New("init data malformed")
+	ErrInitDataSignature = errors.New("init data signature invalid")
+	ErrInitDataExpired   = errors.New("init data expired")
+	ErrInitDataReplayed  = errors.New("init data already used")
+	ErrInitDataUser      = errors.New("init data user invalid")
+)
+
 // Config controls validation and JWT generation.
 type Config struct {
-	BotToken   string
-	JWTSecret  string
-	MaxAge     time.Duration
+	BotToken string
+	// Keys signs and verifies tokens. When nil, a single HS256 key is built from JWTSecret.
+	Keys      *KeySet
+	JWTSecret string
+	MaxAge    time.Duration
+	// SessionTTL is the access token lifetime.
 	SessionTTL time.Duration
+	// RefreshTTL is the refresh token lifetime; every refresh extends the session by it.
+	RefreshTTL time.Duration
+	// ReplayCacheSize limits how many used init data hashes are remembered for MaxAge.
+	ReplayCacheSize int
+}
+
+// RevocationList reports whether the session referenced by a token jti is no longer active.
+type RevocationList interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// ReplayStore records used init data keys in storage shared by all API replicas.
+// ClaimInitData reports true if any key was already claimed and has not expired.
+type ReplayStore interface {
+	ClaimInitData(ctx context.Context, keys []string, expiresAt time.Time) (bool, error)
 }
 
 // Validator validates MAX init data and issues JWT tokens.
 type Validator struct {
-	botToken   string
-	jwtSecret  []byte
-	maxAge     time.Duration
-	sessionTTL time.Duration
-	now        func() time.Time
+	botToken    string
+	keys        atomic.Pointer[KeySet]
+	maxAge      time.Duration
+	sessionTTL  time.Duration
+	refreshTTL  time.Duration
+	replays     *ReplayCache
+	replayStore ReplayStore
+	revocations RevocationList
+	now         func() time.Time
 }
 
 type maxClaims struct {
@@ -39,27 +75,59 @@ func NewValidator(cfg Config) (*Validator, error) {
 	if cfg.BotToken == "" {
 		return nil, errors.New("bot token is required")
 	}
-	if cfg.JWTSecret == "" {
-		return nil, errors.New("jwt secret is required")
+	if cfg.Keys == nil {
+		keys, err := NewSecretKeySet(cfg.JWTSecret)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Keys = keys
 	}
 	if cfg.MaxAge <= 0 {
 		cfg.MaxAge = time.Hour
 	}
 	if cfg.SessionTTL <= 0 {
-		cfg.SessionTTL = 24 * time.Hour
+		cfg.SessionTTL = 15 * time.Minute
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = 30 * 24 * time.Hour
 	}
-	return &Validator{
+	v := &Validator{
 		botToken:   cfg.BotToken,
-		jwtSecret:  []byte(cfg.JWTSecret),
 		maxAge:     cfg.MaxAge,
 		sessionTTL: cfg.SessionTTL,
+		refreshTTL: cfg.RefreshTTL,
+		replays:    NewReplayCache(cfg.ReplayCacheSize, cfg.MaxAge),
 		now:        time.Now,
-	}, nil
+	}
+	v.keys.Store(cfg.Keys)
+	return v, nil
 }
 
-// AuthResult contains parsed payload and issued JWT token.
+// SetKeySet atomically replaces signing and verification keys, e.g. after SIGHUP.
+func (v *Validator) SetKeySet(keys *KeySet) {
+	if keys != nil {
+		v.keys.Store(keys)
+	}
+}
+
+// KeySet returns keys currently in use.
+func (v *Validator) KeySet() *KeySet {
+	return v.keys.Load()
+}
+
+// SetRevocationList enables the jti check in ParseToken. Once set, tokens without jti are rejected.
+func (v *Validator) SetRevocationList(list RevocationList) {
+	v.revocations = list
+}
+
+// SetReplayStore moves the replay check from the per-process cache to shared storage,
+// so init data used on one replica is rejected on the others too.
+func (v *Validator) SetReplayStore(store ReplayStore) {
+	v.replayStore = store
+}
+
+// AuthResult contains the validated init data payload.
 type AuthResult struct {
-	Token     string
 	User      MaxUser
 	RawParams url.Values
 }
@@ -74,37 +142,60 @@ type MaxUser struct {
 	PhotoURL     *string `json:"photo_url"`
 }
 
-// ValidateAndIssue validates MAX init data and returns a signed JWT.
-func (v *Validator) ValidateAndIssue(rawInitData string) (AuthResult, error) {
+// ValidateInitData checks the signature and freshness of MAX init data and extracts the user.
+// Each init data is accepted once: its hash and query_id are remembered until it expires.
+// Rejections wrap one of the ErrInitData* reasons; other errors come from the replay store.
+func (v *Validator) ValidateInitData(ctx context.Context, rawInitData string) (AuthResult, error) {
 	decoded, err := url.QueryUnescape(rawInitData)
 	if err != nil {
-		return AuthResult{}, fmt.Errorf("init data decode: %w", err)
+		return AuthResult{}, fmt.Errorf("%w: decode: %w", ErrInitDataMalformed, err)
 	}
 
 	values, err := url.ParseQuery(decoded)
 	if err != nil {
-		return AuthResult{}, fmt.Errorf("init data parse: %w", err)
+		return AuthResult{}, fmt.Errorf("%w: parse: %w", ErrInitDataMalformed, err)
 	}
 
 	if err := v.verifyHash(values, values.Get("hash")); err != nil {
-		return AuthResult{}, err
+		return AuthResult{}, fmt.Errorf("%w: %w", ErrInitDataSignature, err)
 	}
 
 	if err := v.verifyFreshness(values.Get("auth_date")); err != nil {
-		return AuthResult{}, err
+		return AuthResult{}, fmt.Errorf("%w: %w", ErrInitDataExpired, err)
 	}
 
 	user, err := parseUser(values.Get("user"))
 	if err != nil {
-		return AuthResult{}, err
+		return AuthResult{}, fmt.Errorf("%w: %w", ErrInitDataUser, err)
 	}
 
-	token, err := v.issueJWT(user)
+	// Replays are checked last so that forged payloads cannot fill the cache.
+	replayKeys := []string{"hash:" + initdatahash.NormalizeHash(values.Get("hash"))}
+	if queryID := values.Get("query_id"); queryID != "" {
+		replayKeys = append(replayKeys, "query_id:"+queryID)
+	}
+	replayed, err := v.seen(ctx, replayKeys)
 	if err != nil {
 		return AuthResult{}, err
 	}
+	if replayed {
+		return AuthResult{}, ErrInitDataReplayed
+	}
+
+	return AuthResult{User: user, RawParams: values}, nil
+}
 
-	return AuthResult{Token: token, User: user, RawParams: values}, nil
+// seen checks replay keys in the shared store if one is set, otherwise in the local cache.
+// Keys are kept for MaxAge, like in the cache: after that the freshness check rejects the init data anyway.
+func (v *Validator) seen(ctx context.Context, keys []string) (bool, error) {
+	if v.replayStore == nil {
+		return v.replays.Seen(keys...), nil
+	}
+	replayed, err := v.replayStore.ClaimInitData(ctx, keys, v.now().Add(v.maxAge))
+	if err != nil {
+		return false, fmt.Errorf("check init data replay: %w", err)
+	}
+	return replayed, nil
 }
 
 func (v *Validator) verifyHash(values url.Values, provided string) error {
@@ -128,18 +219,22 @@ func (v *Validator) verifyFreshness(authDate string) error {
 	return initdatahash.CheckFreshness(authDate, v.maxAge, v.now())
 }
 
-func (v *Validator) issueJWT(user MaxUser) (string, error) {
+// IssueToken signs an access token for the session; the session id becomes the jti claim.
+func (v *Validator) IssueToken(user MaxUser, sessionID string) (string, error) {
 	now := v.now()
 	claims := &maxClaims{
 		User: user,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			Subject:   fmt.Sprint(user.ID),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(v.sessionTTL)),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString(v.jwtSecret)
+	key := v.keys.Load().Current()
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.signKey)
 	if err != nil {
 		return "", fmt.Errorf("sign jwt: %w", err)
 	}
@@ -147,28 +242,59 @@ func (v *Validator) issueJWT(user MaxUser) (string, error) {
 }
 
 // ParseToken validates a JWT issued by this validator and extracts the user payload.
-func (v *Validator) ParseToken(token string) (MaxUser, error) {
+// With a revocation list configured it also rejects tokens of revoked sessions.
+func (v *Validator) ParseToken(ctx context.Context, token string) (MaxUser, error) {
 	claims := &maxClaims{}
-	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method %T", t.Method)
-		}
-		return v.jwtSecret, nil
-	})
+	parsed, err := jwt.ParseWithClaims(token, claims, v.verificationKey)
 	if err != nil {
 		return MaxUser{}, fmt.Errorf("parse token: %w", err)
 	}
 	if !parsed.Valid {
 		return MaxUser{}, errors.New("token invalid")
 	}
+	if v.revocations != nil {
+		if claims.ID == "" {
+			return MaxUser{}, errors.New("token has no session id")
+		}
+		revoked, err := v.revocations.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return MaxUser{}, fmt.Errorf("check revocation: %w", err)
+		}
+		if revoked {
+			return MaxUser{}, ErrTokenRevoked
+		}
+	}
 	return claims.User, nil
 }
 
-// SessionTTL returns configured session lifetime.
+// verificationKey picks the key by the kid header. Tokens issued before key ids were introduced
+// have no kid and are checked against the current key.
+func (v *Validator) verificationKey(t *jwt.Token) (any, error) {
+	keys := v.keys.Load()
+	key := keys.Current()
+	if kid, ok := t.Header["kid"].(string); ok {
+		found, exists := keys.Lookup(kid)
+		if !exists {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		key = found
+	}
+	if t.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", t.Method.Alg(), key.ID)
+	}
+	return key.verifyKey, nil
+}
+
+// SessionTTL returns configured access token lifetime.
 func (v *Validator) SessionTTL() time.Duration {
 	return v.sessionTTL
 }
 
+// RefreshTTL returns configured refresh token lifetime.
+func (v *Validator) RefreshTTL() time.Duration {
+	return v.refreshTTL
+}
+
 func parseUser(raw string) (MaxUser, error) {
 	if raw == "" {
 		return MaxUser{}, errors.New("user payload missing")
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/url"
 	"strconv"
 	"testing"
@@ -10,7 +12,7 @@ import (
 	"maxBot/internal/auth/initdatahash"
 )
 
-func TestValidateAndIssueAndParseToken(t *testing.T) {
+func TestValidateInitDataAndParseToken(t *testing.T) {
 	cfg := Config{
 		BotToken:   "test-token",
 		JWTSecret:  "jwt-secret",
@@ -25,19 +27,23 @@ func TestValidateAndIssueAndParseToken(t *testing.T) {
 	user := MaxUser{ID: 321, FirstName: "Test", LastName: "User"}
 	initData := buildInitData(t, cfg.BotToken, user, time.Now())
 
-	res, err := validator.ValidateAndIssue(initData)
+	res, err := validator.ValidateInitData(context.Background(), initData)
 	if err != nil {
-		t.Fatalf("ValidateAndIssue returned error: %v", err)
-	}
-
-	if res.Token == "" {
-		t.Fatalf("expected token to be issued")
+		t.Fatalf("ValidateInitData returned error: %v", err)
 	}
 	if res.User.ID != user.ID {
 		t.Fatalf("unexpected user id: got %d want %d", res.User.ID, user.ID)
 	}
 
-	parsedUser, err := validator.ParseToken(res.Token)
+	token, err := validator.IssueToken(res.User, "session-1")
+	if err != nil {
+		t.Fatalf("IssueToken returned error: %v", err)
+	}
+	if token == "" {
+		t.Fatalf("expected token to be issued")
+	}
+
+	parsedUser, err := validator.ParseToken(context.Background(), token)
 	if err != nil {
 		t.Fatalf("ParseToken returned error: %v", err)
 	}
@@ -46,17 +52,113 @@ func TestValidateAndIssueAndParseToken(t *testing.T) {
 	}
 }
 
+func TestValidateInitDataRejectsReplay(t *testing.T) {
+	cfg := Config{BotToken: "test-token", JWTSecret: "jwt-secret"}
+	validator, err := NewValidator(cfg)
+	if err != nil {
+		t.Fatalf("init validator: %v", err)
+	}
+	initData := buildInitData(t, cfg.BotToken, MaxUser{ID: 1}, time.Now())
+
+	if _, err := validator.ValidateInitData(context.Background(), initData); err != nil {
+		t.Fatalf("first exchange failed: %v", err)
+	}
+	if _, err := validator.ValidateInitData(context.Background(), initData); !errors.Is(err, ErrInitDataReplayed) {
+		t.Fatalf("expected ErrInitDataReplayed, got %v", err)
+	}
+	if _, err := validator.ValidateInitData(context.Background(), initData+"0"); !errors.Is(err, ErrInitDataSignature) {
+		t.Fatalf("expected ErrInitDataSignature for tampered payload, got %v", err)
+	}
+}
+
+// memoryReplayStore stands in for the database table shared by API replicas.
+type memoryReplayStore struct {
+	used map[string]time.Time
+}
+
+func (s *memoryReplayStore) ClaimInitData(_ context.Context, keys []string, expiresAt time.Time) (bool, error) {
+	for _, key := range keys {
+		if exp, ok := s.used[key]; ok && exp.After(time.Now()) {
+			return true, nil
+		}
+	}
+	for _, key := range keys {
+		s.used[key] = expiresAt
+	}
+	return false, nil
+}
+
+func TestValidateInitDataRejectsReplayOnOtherReplica(t *testing.T) {
+	cfg := Config{BotToken: "test-token", JWTSecret: "jwt-secret"}
+	store := &memoryReplayStore{used: make(map[string]time.Time)}
+	replicas := make([]*Validator, 2)
+	for i := range replicas {
+		validator, err := NewValidator(cfg)
+		if err != nil {
+			t.Fatalf("init validator: %v", err)
+		}
+		validator.SetReplayStore(store)
+		replicas[i] = validator
+	}
+	initData := buildInitData(t, cfg.BotToken, MaxUser{ID: 1}, time.Now())
+
+	if _, err := replicas[0].ValidateInitData(context.Background(), initData); err != nil {
+		t.Fatalf("first exchange failed: %v", err)
+	}
+	if _, err := replicas[1].ValidateInitData(context.Background(), initData); !errors.Is(err, ErrInitDataReplayed) {
+		t.Fatalf("expected ErrInitDataReplayed on the other replica, got %v", err)
+	}
+}
+
 func TestParseTokenFailsForInvalidValue(t *testing.T) {
 	validator, err := NewValidator(Config{BotToken: "token", JWTSecret: "secret"})
 	if err != nil {
 		t.Fatalf("init validator: %v", err)
 	}
 
-	if _, err := validator.ParseToken("invalid-token"); err == nil {
+	if _, err := validator.ParseToken(context.Background(), "invalid-token"); err == nil {
 		t.Fatalf("expected error for invalid token")
 	}
 }
 
+func TestParseTokenChecksRevocationList(t *testing.T) {
+	validator, err := NewValidator(Config{BotToken: "token", JWTSecret: "secret"})
+	if err != nil {
+		t.Fatalf("init validator: %v", err)
+	}
+	user := MaxUser{ID: 42, FirstName: "Test"}
+	active, err := validator.IssueToken(user, "active")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	revoked, err := validator.IssueToken(user, "revoked")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	withoutSession, err := validator.IssueToken(user, "")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	validator.SetRevocationList(stubRevocationList{"revoked": true})
+
+	if _, err := validator.ParseToken(context.Background(), active); err != nil {
+		t.Fatalf("expected active session token to pass, got %v", err)
+	}
+	if _, err := validator.ParseToken(context.Background(), revoked); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("expected ErrTokenRevoked, got %v", err)
+	}
+	if _, err := validator.ParseToken(context.Background(), withoutSession); err == nil {
+		t.Fatalf("expected token without jti to be rejected")
+	}
+}
+
+type stubRevocationList map[string]bool
+
+func (l stubRevocationList) IsRevoked(_ context.Context, jti string) (bool, error) {
+	return l[jti], nil
+}
+
 func buildInitData(t *testing.T, botToken string, user MaxUser, now time.Time) string {
 	t.Helper()
 	values := url.Values{}
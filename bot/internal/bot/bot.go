@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"log"
 	"strings"
-	"sync"
 
 	"github.com/rectid/max-bot-api-client-go/schemes"
 
+	"maxBot/internal/bot/handler"
 	"maxBot/internal/di"
 	"maxBot/internal/fsm"
 	"maxBot/internal/model"
@@ -17,10 +17,14 @@ import (
 type Bot struct {
 	router   *Router
 	services *di.Services
+	cfg      Config
+	// ctx обработки не отменяется при остановке: уже принятые обновления дорабатываются до конца.
+	ctx        context.Context
+	dispatcher *dispatcher
 }
 
 // NewBot создаёт новый экземпляр бота используя заранее инициализированные сервисы
-func NewBot(ctx context.Context, services *di.Services) (*Bot, error) {
+func NewBot(ctx context.Context, services *di.Services, cfg Config) (*Bot, error) {
 	if services == nil {
 		return nil, fmt.Errorf("services are required")
 	}
@@ -28,30 +32,73 @@ func NewBot(ctx context.Context, services *di.Services) (*Bot, error) {
 		return nil, fmt.Errorf("api client is required")
 	}
 
+	handler.ConfigurePayloads(cfg.CallbackSecret, cfg.CallbackTTL)
 	router := NewRouter(services)
 
 	return &Bot{
-		router:   router,
-		services: services,
+		router:     router,
+		services:   services,
+		cfg:        cfg,
+		ctx:        context.WithoutCancel(ctx),
+		dispatcher: newDispatcher(cfg.Workers, cfg.QueueSize),
 	}, nil
 }
 
-// Start запускает обработку обновлений от API
-func (b *Bot) Start(ctx context.Context) {
-	log.Println("Bot started...")
-
-	wg := sync.WaitGroup{}
+// Run получает обновления выбранным транспортом до отмены ctx и дожидается обработки уже полученных.
+// В режиме webhook обновления приходят через WebhookHandler, а Run только оформляет подписку.
+func (b *Bot) Run(ctx context.Context) error {
+	// Очередь закрывается и в случае ошибки подписки, иначе воркеры переживут бота.
+	defer b.dispatcher.Close()
 
 	api := b.services.API
-	for upd := range api.GetUpdates(ctx) {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			b.handleUpdate(ctx, upd)
-		}()
+	cfg := b.cfg
+	switch cfg.Transport {
+	case TransportWebhook:
+		if _, err := api.Subscriptions.Subscribe(ctx, cfg.WebhookURL, webhookUpdateTypes, cfg.WebhookSecret); err != nil {
+			return fmt.Errorf("subscribe webhook: %w", err)
+		}
+		log.Printf("Bot started in webhook mode: %s", cfg.WebhookURL)
+		<-ctx.Done()
+	default:
+		// Пока у бота есть webhook-подписка, MAX не отдаёт обновления через long polling.
+		b.dropSubscriptions(ctx)
+		log.Println("Bot started...")
+		for upd := range api.GetUpdates(ctx) {
+			if err := b.dispatch(upd); err != nil {
+				log.Printf("Failed to dispatch update: %v", err)
+			}
+		}
 	}
 
-	wg.Wait()
+	log.Println("Bot is draining pending updates...")
+	return nil
+}
+
+// dispatch ставит обновление в очередь его пользователя: обновления одного пользователя
+// обрабатываются по порядку, иначе два быстрых нажатия гоняются за user.State.
+func (b *Bot) dispatch(update schemes.UpdateInterface) error {
+	return b.dispatcher.Submit(update.GetUserID(), func() {
+		b.handleUpdate(b.ctx, update)
+	})
+}
+
+func (b *Bot) dropSubscriptions(ctx context.Context) {
+	api := b.services.API
+	result, err := api.Subscriptions.GetSubscriptions(ctx)
+	if err != nil {
+		log.Printf("Failed to list webhook subscriptions: %v", err)
+		return
+	}
+	if result == nil {
+		return
+	}
+	for _, sub := range result.Subscriptions {
+		if _, err := api.Subscriptions.Unsubscribe(ctx, sub.Url); err != nil {
+			log.Printf("Failed to remove webhook subscription %s: %v", sub.Url, err)
+			continue
+		}
+		log.Printf("Removed webhook subscription %s to switch to long polling", sub.Url)
+	}
 }
 
 // handleUpdate обрабатывает одно обновление от пользователя
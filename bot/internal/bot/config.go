@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"maxBot/internal/bot/handler"
+)
+
+// Способы получения обновлений от MAX.
+const (
+	TransportPolling = "polling"
+	TransportWebhook = "webhook"
+)
+
+const (
+	defaultWorkers   = 8
+	defaultQueueSize = 64
+)
+
+// webhookSecretPattern — ограничения MAX на секрет подписки.
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{5,256}$`)
+
+// Config описывает, как бот получает обновления.
+type Config struct {
+	// Transport — polling (long polling, один экземпляр) или webhook (MAX сам присылает обновления на HTTP-сервер).
+	Transport string
+	// WebhookURL — публичный HTTPS-адрес, на который MAX присылает обновления. Его путь становится маршрутом на сервере.
+	WebhookURL string
+	// WebhookSecret — общий секрет подписки, MAX передаёт его в заголовке X-Max-Bot-Api-Secret.
+	WebhookSecret string
+	// Workers — сколько обновлений обрабатывается одновременно. Обновления одного пользователя
+	// всегда идут через один воркер по порядку.
+	Workers int
+	// QueueSize — длина очереди каждого воркера; при заполнении приём обновлений притормаживает.
+	QueueSize int
+	// CallbackSecret — ключ подписи payload кнопок. Если BOT_CALLBACK_SECRET не задан, ключ выводится
+	// из токена бота, поэтому кнопки переживают рестарт и принимаются всеми экземплярами.
+	CallbackSecret string
+	// CallbackTTL — сколько действуют кнопки; нажатие на более старую просит воспользоваться новым сообщением.
+	CallbackTTL time.Duration
+}
+
+// LoadConfigFromEnv читает настройки из переменных окружения. botToken нужен для ключа подписи кнопок
+// по умолчанию.
+func LoadConfigFromEnv(botToken string) (Config, error) {
+	cfg := Config{
+		Transport:      strings.ToLower(strings.TrimSpace(os.Getenv("BOT_TRANSPORT"))),
+		WebhookURL:     strings.TrimSpace(os.Getenv("BOT_WEBHOOK_URL")),
+		WebhookSecret:  strings.TrimSpace(os.Getenv("BOT_WEBHOOK_SECRET")),
+		CallbackSecret: strings.TrimSpace(os.Getenv("BOT_CALLBACK_SECRET")),
+		CallbackTTL:    handler.DefaultPayloadTTL,
+	}
+	if cfg.Transport == "" {
+		cfg.Transport = TransportPolling
+	}
+
+	var err error
+	if cfg.Workers, err = intFromEnv("BOT_WORKERS", defaultWorkers, 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.QueueSize, err = intFromEnv("BOT_QUEUE_SIZE", defaultQueueSize, 0); err != nil {
+		return Config{}, err
+	}
+	if val := strings.TrimSpace(os.Getenv("BOT_CALLBACK_TTL")); val != "" {
+		ttl, err := time.ParseDuration(val)
+		if err != nil || ttl <= 0 {
+			return Config{}, fmt.Errorf("BOT_CALLBACK_TTL должен быть положительной длительностью, например 24h")
+		}
+		cfg.CallbackTTL = ttl
+	}
+	switch {
+	case cfg.CallbackSecret != "" && len(cfg.CallbackSecret) < 32:
+		return Config{}, fmt.Errorf("BOT_CALLBACK_SECRET должен быть не короче 32 символов")
+	case cfg.CallbackSecret == "" && strings.TrimSpace(botToken) == "":
+		return Config{}, fmt.Errorf("для подписи кнопок нужен BOT_CALLBACK_SECRET или токен бота")
+	case cfg.CallbackSecret == "":
+		cfg.CallbackSecret = callbackSecretFromToken(strings.TrimSpace(botToken))
+	}
+
+	switch cfg.Transport {
+	case TransportPolling:
+		return cfg, nil
+	case TransportWebhook:
+	default:
+		return Config{}, fmt.Errorf("BOT_TRANSPORT должен быть %s или %s", TransportPolling, TransportWebhook)
+	}
+
+	u, err := url.Parse(cfg.WebhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return Config{}, fmt.Errorf("BOT_WEBHOOK_URL должен быть абсолютным https-адресом")
+	}
+	if u.Path == "" || u.Path == "/" {
+		return Config{}, fmt.Errorf("BOT_WEBHOOK_URL должен содержать путь, например https://example.com/bot/webhook")
+	}
+	if !webhookSecretPattern.MatchString(cfg.WebhookSecret) {
+		return Config{}, fmt.Errorf("BOT_WEBHOOK_SECRET обязателен: 5–256 символов A-Z, a-z, 0-9, _ и -")
+	}
+	return cfg, nil
+}
+
+// WebhookPath — маршрут на HTTP-сервере, куда MAX присылает обновления.
+func (c Config) WebhookPath() string {
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+// callbackSecretFromToken выводит ключ подписи кнопок из токена бота. Ключ одинаков у всех экземпляров
+// с этим токеном, но сам токен по нему не восстановить.
+func callbackSecretFromToken(token string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte("maxBot callback payload"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func intFromEnv(name string, def, min int) (int, error) {
+	val := strings.TrimSpace(os.Getenv(name))
+	if val == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < min {
+		return 0, fmt.Errorf("%s должен быть целым числом не меньше %d", name, min)
+	}
+	return n, nil
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallbackSecretDefaultsToTokenDerivedKey(t *testing.T) {
+	t.Setenv("BOT_TRANSPORT", "")
+	t.Setenv("BOT_CALLBACK_SECRET", "")
+
+	first, err := LoadConfigFromEnv("bot-token")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	second, err := LoadConfigFromEnv("bot-token")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if first.CallbackSecret == "" || first.CallbackSecret != second.CallbackSecret {
+		t.Fatalf("key changes between runs: %q, %q", first.CallbackSecret, second.CallbackSecret)
+	}
+	if strings.Contains(first.CallbackSecret, "bot-token") {
+		t.Fatalf("key exposes the bot token: %q", first.CallbackSecret)
+	}
+
+	other, err := LoadConfigFromEnv("other-token")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if other.CallbackSecret == first.CallbackSecret {
+		t.Fatal("different tokens produce the same key")
+	}
+
+	if _, err := LoadConfigFromEnv(""); err == nil {
+		t.Fatal("config without token and BOT_CALLBACK_SECRET must fail")
+	}
+}
+
+func TestCallbackSecretFromEnvWins(t *testing.T) {
+	secret := strings.Repeat("s", 32)
+	t.Setenv("BOT_TRANSPORT", "")
+	t.Setenv("BOT_CALLBACK_SECRET", secret)
+
+	cfg, err := LoadConfigFromEnv("bot-token")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.CallbackSecret != secret {
+		t.Fatalf("got %q, want BOT_CALLBACK_SECRET", cfg.CallbackSecret)
+	}
+
+	t.Setenv("BOT_CALLBACK_SECRET", "short")
+	if _, err := LoadConfigFromEnv("bot-token"); err == nil {
+		t.Fatal("short BOT_CALLBACK_SECRET must fail")
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"errors"
+	"sync"
+)
+
+var errDispatcherClosed = errors.New("dispatcher is closed")
+
+// dispatcher раскладывает задачи по воркерам по ID пользователя: задачи одного пользователя
+// всегда попадают в одну очередь и выполняются строго по порядку, а число одновременно
+// обрабатываемых обновлений не превышает числа воркеров.
+type dispatcher struct {
+	// mu защищает closed и не даёт закрыть очереди, пока в них идёт отправка.
+	mu     sync.RWMutex
+	closed bool
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+// newDispatcher запускает workers воркеров с очередью queueSize задач у каждого.
+func newDispatcher(workers, queueSize int) *dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	d := &dispatcher{queues: make([]chan func(), workers)}
+	for i := range d.queues {
+		queue := make(chan func(), queueSize)
+		d.queues[i] = queue
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for job := range queue {
+				job()
+			}
+		}()
+	}
+	return d
+}
+
+// Submit ставит задачу в очередь пользователя. Если очередь заполнена, Submit ждёт, пока
+// освободится место: так long polling и webhook притормаживают вместо неограниченного роста памяти.
+func (d *dispatcher) Submit(userID int64, job func()) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return errDispatcherClosed
+	}
+	d.queues[d.shard(userID)] <- job
+	return nil
+}
+
+// Close перестаёт принимать задачи и ждёт, пока воркеры выполнят уже поставленные.
+func (d *dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *dispatcher) shard(userID int64) int {
+	return int(uint64(userID) % uint64(len(d.queues)))
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatcherKeepsPerUserOrder(t *testing.T) {
+	const (
+		users   = 20
+		updates = 200
+	)
+	d := newDispatcher(4, 8)
+
+	// Срезы пишутся без блокировок: если задачи одного пользователя пойдут параллельно,
+	// это поймает race detector, а порядок проверяется ниже.
+	seen := make([][]int, users)
+	var submitters sync.WaitGroup
+	for u := 0; u < users; u++ {
+		submitters.Add(1)
+		go func() {
+			defer submitters.Done()
+			for i := 0; i < updates; i++ {
+				if err := d.Submit(int64(u), func() { seen[u] = append(seen[u], i) }); err != nil {
+					t.Errorf("user %d: submit: %v", u, err)
+					return
+				}
+			}
+		}()
+	}
+	submitters.Wait()
+	d.Close()
+
+	for u, got := range seen {
+		if len(got) != updates {
+			t.Fatalf("user %d: processed %d of %d updates", u, len(got), updates)
+		}
+		for i, v := range got {
+			if v != i {
+				t.Fatalf("user %d: update %d processed at position %d", u, v, i)
+			}
+		}
+	}
+}
+
+func TestDispatcherBoundsConcurrency(t *testing.T) {
+	const workers = 3
+	d := newDispatcher(workers, 4)
+
+	var active, peak atomic.Int32
+	for u := 0; u < 50; u++ {
+		if err := d.Submit(int64(u), func() {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			active.Add(-1)
+		}); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+	d.Close()
+
+	if got := peak.Load(); got > workers {
+		t.Fatalf("%d updates processed concurrently, limit is %d", got, workers)
+	}
+}
+
+func TestDispatcherDrainsOnClose(t *testing.T) {
+	d := newDispatcher(2, 16)
+
+	var processed atomic.Int32
+	for i := 0; i < 20; i++ {
+		if err := d.Submit(int64(i%5), func() {
+			time.Sleep(time.Millisecond)
+			processed.Add(1)
+		}); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+	d.Close()
+
+	if got := processed.Load(); got != 20 {
+		t.Fatalf("Close returned after %d of 20 updates", got)
+	}
+	if err := d.Submit(1, func() {}); !errors.Is(err, errDispatcherClosed) {
+		t.Fatalf("submit after close: got %v, want errDispatcherClosed", err)
+	}
+	d.Close()
+}
+
+func TestDispatcherCloseWhileSubmitting(t *testing.T) {
+	d := newDispatcher(2, 1)
+
+	var accepted, processed atomic.Int32
+	var submitters sync.WaitGroup
+	for u := 0; u < 8; u++ {
+		submitters.Add(1)
+		go func() {
+			defer submitters.Done()
+			for {
+				if err := d.Submit(int64(u), func() { processed.Add(1) }); err != nil {
+					return
+				}
+				accepted.Add(1)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	d.Close()
+	submitters.Wait()
+
+	if accepted.Load() != processed.Load() {
+		t.Fatalf("accepted %d updates, processed %d", accepted.Load(), processed.Load())
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// AboutHandler показывает профиль пользователя со статистикой и кнопками редактирования.
+// Волонтёр редактирует «О себе», радиус поиска и категории, организатор — название и описание организации.
+type AboutHandler struct {
+	services *di.Services
+}
+
+func NewAboutHandler(services *di.Services) *AboutHandler {
+	return &AboutHandler{services: services}
+}
+
+func (h *AboutHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	userID := update.GetUserID()
+	user, err := h.services.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Профиль\n\n")
+	builder.WriteString("Имя: ")
+	builder.WriteString(user.Name)
+	if user.Username != nil && *user.Username != "" {
+		builder.WriteString(" (@")
+		builder.WriteString(*user.Username)
+		builder.WriteString(")")
+	}
+	builder.WriteString("\n")
+
+	keyboard := &maxbot.Keyboard{}
+
+	if volunteer, err := h.services.VolunteerService.GetVolunteer(ctx, userID); err == nil {
+		h.writeVolunteer(ctx, &builder, volunteer)
+		keyboard.AddRow().AddCallback("Изменить «О себе»", schemes.DEFAULT, EncodePayload(fsm.AboutToProfileEdit, map[string]string{"field": profileFieldVolunteerAbout}))
+		keyboard.AddRow().AddCallback("Изменить радиус поиска", schemes.DEFAULT, EncodePayload(fsm.AboutToProfileEdit, map[string]string{"field": profileFieldSearchRadius}))
+		keyboard.AddRow().AddCallback("Выбрать категории", schemes.DEFAULT, EncodePayload(fsm.AboutToCategoriesFilter, map[string]string{"page": "1", "from": "about"}))
+	}
+
+	if organizer, err := h.services.OrganizerService.GetOrganizer(ctx, userID); err == nil {
+		h.writeOrganizer(ctx, &builder, organizer)
+		keyboard.AddRow().AddCallback("Изменить название организации", schemes.DEFAULT, EncodePayload(fsm.AboutToProfileEdit, map[string]string{"field": profileFieldOrganizationName}))
+		keyboard.AddRow().AddCallback("Изменить описание организации", schemes.DEFAULT, EncodePayload(fsm.AboutToProfileEdit, map[string]string{"field": profileFieldOrganizerAbout}))
+	}
+
+	keyboard.AddRow().AddCallback("← Главное меню", schemes.NEGATIVE, EncodePayload(fsm.AboutToMainMenu, nil))
+
+	text := builder.String()
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *AboutHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
+
+func (h *AboutHandler) writeVolunteer(ctx context.Context, builder *strings.Builder, volunteer model.Volunteer) {
+	builder.WriteString("\nВолонтёр\n")
+	builder.WriteString("О себе: ")
+	builder.WriteString(valueOrDash(volunteer.About))
+	builder.WriteString("\nРадиус поиска: ")
+	if volunteer.SearchRadius != nil {
+		builder.WriteString(fmt.Sprintf("%d км", *volunteer.SearchRadius))
+	} else {
+		builder.WriteString("не задан")
+	}
+	builder.WriteString("\nКатегории: ")
+	builder.WriteString(h.categoryNames(ctx, volunteer.CategoryIDs))
+	builder.WriteString("\n")
+
+	if hours, err := h.services.AttendanceService.GetVolunteerHours(ctx, volunteer.ID); err == nil {
+		builder.WriteString(fmt.Sprintf("Посещено событий: %d\n", hours.EventsAttended))
+		builder.WriteString(fmt.Sprintf("Отработано часов: %s\n", formatHours(hours.HoursServed)))
+	} else {
+		log.Printf("failed to get hours for volunteer %d: %v", volunteer.ID, err)
+	}
+	if counts, err := h.services.ApplicationService.CountApplicationsByVolunteerStatus(ctx, volunteer.ID); err == nil {
+		builder.WriteString(fmt.Sprintf("Заявок на рассмотрении: %d\n", counts["pending"]))
+		if counts["waitlisted"] > 0 {
+			builder.WriteString(fmt.Sprintf("В листе ожидания: %d\n", counts["waitlisted"]))
+		}
+		builder.WriteString(fmt.Sprintf("Одобренных заявок: %d\n", counts["approved"]))
+	} else {
+		log.Printf("failed to count applications for volunteer %d: %v", volunteer.ID, err)
+	}
+}
+
+func (h *AboutHandler) writeOrganizer(ctx context.Context, builder *strings.Builder, organizer model.Organizer) {
+	builder.WriteString("\nОрганизатор\n")
+	builder.WriteString("Организация: ")
+	builder.WriteString(valueOrDash(&organizer.OrganizationName))
+	builder.WriteString("\nОписание: ")
+	builder.WriteString(valueOrDash(organizer.About))
+	builder.WriteString("\nВерификация: ")
+	builder.WriteString(formatOrganizerVerified(organizer))
+	if organizer.VerifiedAt == nil {
+		if history, err := h.services.OrganizerService.ListOrganizerVerificationHistory(ctx, organizer.ID, 1, 0); err == nil && len(history) > 0 {
+			builder.WriteString(" (последняя заявка: ")
+			builder.WriteString(strings.ToLower(translateVerificationStatus(history[0].Status)))
+			builder.WriteString(")")
+		}
+	}
+	builder.WriteString("\n")
+
+	if count, err := h.services.EventService.CountEventsByOrganizer(ctx, organizer.ID); err == nil {
+		builder.WriteString(fmt.Sprintf("Создано событий: %d\n", count))
+	}
+	if count, err := h.services.EventService.CountOrganizerEventsWithPendingApplications(ctx, organizer.ID); err == nil {
+		builder.WriteString(fmt.Sprintf("Событий с новыми заявками: %d\n", count))
+	}
+}
+
+// categoryNames перечисляет названия выбранных категорий через запятую.
+func (h *AboutHandler) categoryNames(ctx context.Context, ids []int32) string {
+	if len(ids) == 0 {
+		return "все"
+	}
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		category, err := h.services.CategoryService.GetCategory(ctx, id)
+		if err != nil {
+			continue
+		}
+		names = append(names, category.Name)
+	}
+	if len(names) == 0 {
+		return "все"
+	}
+	return strings.Join(names, ", ")
+}
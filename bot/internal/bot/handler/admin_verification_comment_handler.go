@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/service"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// AdminVerificationCommentHandler принимает комментарий администратора и фиксирует решение по заявке.
+type AdminVerificationCommentHandler struct {
+	services *di.Services
+}
+
+func NewAdminVerificationCommentHandler(services *di.Services) *AdminVerificationCommentHandler {
+	return &AdminVerificationCommentHandler{services: services}
+}
+
+func (h *AdminVerificationCommentHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	adminID := update.GetUserID()
+	// Комментарий придёт текстом без payload, поэтому запоминаем заявку и решение.
+	if params["request_id"] != "" {
+		if err := h.services.UserService.SetUserStateParams(ctx, adminID, map[string]string{
+			"request_id": params["request_id"],
+			"decision":   params["decision"],
+		}); err != nil {
+			return err
+		}
+	}
+	saved, err := h.services.UserService.GetUserStateParams(ctx, adminID)
+	if err != nil {
+		return err
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	text := "Напишите причину отказа — организатор получит её в уведомлении."
+	if saved["decision"] == "approved" {
+		text = "Напишите комментарий для организатора или одобрите заявку без комментария."
+		keyboard.AddRow().AddCallback("Одобрить без комментария", schemes.POSITIVE, EncodePayload(fsm.AdminVerificationCommentToAdminVerifications, map[string]string{"action": "submit"}))
+	}
+	keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, EncodePayload(fsm.AdminVerificationCommentToAdminVerification, nil))
+
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *AdminVerificationCommentHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		comment := strings.TrimSpace(upd.Message.Body.Text)
+		if comment == "" {
+			return fsm.Error, nil, fmt.Errorf("комментарий не может быть пустым")
+		}
+		if utf8.RuneCountInString(comment) > 1000 {
+			return fsm.Error, nil, fmt.Errorf("комментарий не должен превышать 1000 символов")
+		}
+		return h.submit(ctx, update.GetUserID(), &comment)
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		if params["action"] == "submit" {
+			return h.submit(ctx, update.GetUserID(), nil)
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите комментарий текстом")
+	}
+}
+
+func (h *AdminVerificationCommentHandler) submit(ctx context.Context, adminID int64, comment *string) (fsm.Transition, map[string]string, error) {
+	saved, err := h.services.UserService.GetUserStateParams(ctx, adminID)
+	if err != nil {
+		return fsm.Error, nil, fmt.Errorf("не удалось загрузить заявку. Попробуйте позже")
+	}
+	requestID, err := strconv.Atoi(saved["request_id"])
+	if err != nil {
+		return fsm.Error, nil, fmt.Errorf("заявка не выбрана, вернитесь к списку")
+	}
+	approve := saved["decision"] == "approved"
+	if !approve && comment == nil {
+		return fsm.Error, nil, fmt.Errorf("укажите причину отказа")
+	}
+
+	if _, err := h.services.VerificationReviewService.ReviewVerificationRequest(ctx, int32(requestID), adminID, approve, comment); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotAdmin):
+			return fsm.Error, nil, fmt.Errorf("действие доступно только администраторам")
+		case errors.Is(err, service.ErrVerificationRequestNotFound):
+			return fsm.Error, nil, fmt.Errorf("заявка не найдена")
+		case errors.Is(err, service.ErrVerificationRequestNotPending):
+			return fsm.Error, nil, fmt.Errorf("заявка уже рассмотрена")
+		default:
+			log.Printf("verification review failed: %v", err)
+			return fsm.Error, nil, fmt.Errorf("не удалось сохранить решение. Попробуйте позже")
+		}
+	}
+
+	notice := "Заявка отклонена, организатор уведомлён"
+	if approve {
+		notice = "Организация верифицирована, организатор уведомлён"
+	}
+	return fsm.AdminVerificationCommentToAdminVerifications, map[string]string{"notice": notice}, nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// AdminVerificationHandler показывает администратору заявку, профиль организатора и историю проверок.
+type AdminVerificationHandler struct {
+	services *di.Services
+}
+
+func NewAdminVerificationHandler(services *di.Services) *AdminVerificationHandler {
+	return &AdminVerificationHandler{services: services}
+}
+
+func (h *AdminVerificationHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	adminID := update.GetUserID()
+	backKeyboard := &maxbot.Keyboard{}
+	backKeyboard.AddRow().AddCallback("← К списку", schemes.NEGATIVE, EncodePayload(fsm.AdminVerificationToAdminVerifications, nil))
+
+	if !isAdmin(ctx, h.services, adminID) {
+		return sendOrEditMessage(ctx, h.services, update, "Раздел доступен только администраторам.", backKeyboard)
+	}
+
+	requestIDStr := params["request_id"]
+	if requestIDStr == "" {
+		saved, err := h.services.UserService.GetUserStateParams(ctx, adminID)
+		if err == nil {
+			requestIDStr = saved["request_id"]
+		}
+	}
+	requestID, err := strconv.Atoi(requestIDStr)
+	if err != nil {
+		return sendOrEditMessage(ctx, h.services, update, "Заявка не выбрана.", backKeyboard)
+	}
+
+	request, err := h.services.OrganizerService.GetOrganizerVerificationRequest(ctx, int32(requestID))
+	if err != nil {
+		return sendOrEditMessage(ctx, h.services, update, "Заявка не найдена.", backKeyboard)
+	}
+	organizer, err := h.services.OrganizerService.GetOrganizer(ctx, request.OrganizerID)
+	if err != nil {
+		return sendOrEditMessage(ctx, h.services, update, "Организатор не найден.", backKeyboard)
+	}
+	user, err := h.services.UserService.GetUserByID(ctx, request.OrganizerID)
+	if err != nil {
+		return sendOrEditMessage(ctx, h.services, update, "Пользователь не найден.", backKeyboard)
+	}
+	history, err := h.services.OrganizerService.ListOrganizerVerificationHistory(ctx, organizer.ID, 5, 0)
+	if err != nil {
+		history = nil
+	}
+
+	if err := h.services.UserService.SetUserStateParams(ctx, adminID, map[string]string{
+		"request_id": strconv.Itoa(requestID),
+	}); err != nil {
+		log.Printf("failed to save verification params for admin %d: %v", adminID, err)
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	if strings.EqualFold(request.Status, "pending") {
+		keyboard.AddRow().AddCallback("Одобрить", schemes.POSITIVE, EncodePayload(fsm.AdminVerificationToAdminVerificationComment, map[string]string{
+			"request_id": strconv.Itoa(requestID),
+			"decision":   "approved",
+		}))
+		keyboard.AddRow().AddCallback("Отклонить", schemes.NEGATIVE, EncodePayload(fsm.AdminVerificationToAdminVerificationComment, map[string]string{
+			"request_id": strconv.Itoa(requestID),
+			"decision":   "rejected",
+		}))
+	}
+	keyboard.AddRow().AddCallback("← К списку", schemes.DEFAULT, EncodePayload(fsm.AdminVerificationToAdminVerifications, nil))
+
+	text := formatAdminVerification(request, organizer, user, history)
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *AdminVerificationHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
+
+func formatAdminVerification(request model.OrganizerVerificationRequest, organizer model.Organizer, user model.User, history []model.OrganizerVerificationRequest) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Заявка №%d от %s\n", request.ID, request.SubmittedAt.Local().Format("02.01.2006 15:04")))
+	builder.WriteString("Статус: ")
+	builder.WriteString(translateVerificationStatus(request.Status))
+	builder.WriteString("\n\nОрганизация: ")
+	builder.WriteString(organizer.OrganizationName)
+	builder.WriteString("\nКонтактное лицо: ")
+	builder.WriteString(user.Name)
+	if user.Username != nil && *user.Username != "" {
+		builder.WriteString(" (@")
+		builder.WriteString(*user.Username)
+		builder.WriteString(")")
+	}
+	builder.WriteString("\nО себе: ")
+	builder.WriteString(valueOrDash(organizer.About))
+	builder.WriteString("\nВерификация: ")
+	builder.WriteString(formatOrganizerVerified(organizer))
+	builder.WriteString("\nЗарегистрирован: ")
+	builder.WriteString(organizer.CreatedAt.Local().Format("02.01.2006"))
+	builder.WriteString("\n\nКомментарий организатора:\n")
+	builder.WriteString(valueOrDash(request.OrganizerComment))
+	builder.WriteString("\n\n")
+	builder.WriteString(formatVerificationHistory(history))
+	return builder.String()
+}
+
+// formatOrganizerVerified описывает текущий статус верификации организатора.
+func formatOrganizerVerified(organizer model.Organizer) string {
+	if organizer.VerifiedAt == nil {
+		return "не пройдена"
+	}
+	return "пройдена " + organizer.VerifiedAt.Local().Format("02.01.2006")
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// AdminVerificationsHandler показывает администратору очередь заявок на верификацию.
+type AdminVerificationsHandler struct {
+	services *di.Services
+}
+
+func NewAdminVerificationsHandler(services *di.Services) *AdminVerificationsHandler {
+	return &AdminVerificationsHandler{services: services}
+}
+
+func (h *AdminVerificationsHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	keyboard := &maxbot.Keyboard{}
+	if !isAdmin(ctx, h.services, update.GetUserID()) {
+		keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, EncodePayload(fsm.AdminVerificationsToMainMenu, nil))
+		return sendOrEditMessage(ctx, h.services, update, "Раздел доступен только администраторам.", keyboard)
+	}
+
+	page, err := strconv.Atoi(params["page"])
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := int32(8)
+	offset := int32(page-1) * limit
+
+	requests, err := h.services.OrganizerService.ListPendingVerificationRequests(ctx, limit, offset)
+	if err != nil {
+		return err
+	}
+	count, err := h.services.OrganizerService.CountPendingVerificationRequests(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		buttonText := fmt.Sprintf("%s — %s", request.OrganizationName, request.SubmittedAt.Local().Format("02.01 15:04"))
+		payload := EncodePayload(fsm.AdminVerificationsToAdminVerification, map[string]string{
+			"request_id": strconv.Itoa(int(request.ID)),
+		})
+		keyboard.AddRow().AddCallback(buttonText, schemes.DEFAULT, payload)
+	}
+
+	totalPages := int((count + int64(limit) - 1) / int64(limit))
+	if totalPages > 1 {
+		row := keyboard.AddRow()
+		if page > 1 {
+			row.AddCallback("<<", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page - 1)}))
+		}
+		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page)}))
+		if page < totalPages {
+			row.AddCallback(">>", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page + 1)}))
+		}
+	}
+	keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, EncodePayload(fsm.AdminVerificationsToMainMenu, nil))
+
+	text := fmt.Sprintf("Заявки на верификацию, ожидающие проверки: %d", count)
+	if count == 0 {
+		text = "Нет заявок на верификацию."
+	}
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *AdminVerificationsHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if event == fsm.Loop {
+			return fsm.Loop, params, nil
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
+
+// isAdmin проверяет наличие записи в таблице admins.
+func isAdmin(ctx context.Context, services *di.Services, userID int64) bool {
+	_, err := services.AdminService.GetAdmin(ctx, userID)
+	return err == nil
+}
+
+// isOrganizer проверяет, что у пользователя есть профиль организатора.
+func isOrganizer(ctx context.Context, services *di.Services, userID int64) bool {
+	_, err := services.OrganizerService.GetOrganizer(ctx, userID)
+	return err == nil
+}
@@ -0,0 +1,21 @@
+package handler
+
+import "strings"
+
+var applicationStatusTranslations = map[string]string{
+	"pending":    "На рассмотрении",
+	"approved":   "Одобрена",
+	"rejected":   "Отклонена",
+	"cancelled":  "Отозвана",
+	"waitlisted": "В листе ожидания",
+}
+
+func translateApplicationStatus(status string) string {
+	if status == "" {
+		return "Неизвестно"
+	}
+	if translated, ok := applicationStatusTranslations[strings.ToLower(status)]; ok {
+		return translated
+	}
+	return capitalize(status)
+}
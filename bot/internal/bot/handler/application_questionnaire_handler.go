@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+const maxApplicationMessageLength = 1000
+
+// ApplicationQuestionnaireHandler проводит волонтёра по анкете события и отправляет заявку
+// с ответами и сопроводительным сообщением.
+type ApplicationQuestionnaireHandler struct {
+	services *di.Services
+}
+
+func NewApplicationQuestionnaireHandler(services *di.Services) *ApplicationQuestionnaireHandler {
+	return &ApplicationQuestionnaireHandler{services: services}
+}
+
+// questionnaireState прогресс заполнения анкеты, хранится в параметрах шага пользователя.
+// Ответы лежат под ключами "a_<id вопроса>" в виде JSON-массива.
+type questionnaireState struct {
+	params    map[string]string
+	eventID   int32
+	step      int
+	questions []model.EventQuestion
+}
+
+func (h *ApplicationQuestionnaireHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	// Ответы на текстовые вопросы придут сообщением без payload, поэтому прогресс хранится в БД.
+	if params["id"] != "" {
+		if err := h.services.UserService.SetUserStateParams(ctx, update.GetUserID(), map[string]string{
+			"event_id": params["id"],
+			"from":     params["from"],
+			"step":     "0",
+		}); err != nil {
+			return err
+		}
+	}
+
+	state, err := h.loadState(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+	event, err := h.services.EventService.GetEventByID(ctx, state.eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Заявка на событие «%s»\n\n", event.Title))
+
+	if question, ok := state.current(); ok {
+		text.WriteString(fmt.Sprintf("Вопрос %d из %d", state.step+1, len(state.questions)))
+		if !question.Required {
+			text.WriteString(" (необязательный)")
+		}
+		text.WriteString("\n")
+		text.WriteString(question.Text)
+		text.WriteString("\n")
+
+		questionID := strconv.Itoa(int(question.ID))
+		selected := state.answer(question.ID)
+		switch question.Type {
+		case model.QuestionTypeText:
+			text.WriteString(fmt.Sprintf("\nНапишите ответ сообщением (до %d символов).", service.MaxTextAnswerLength))
+			if len(selected) > 0 {
+				text.WriteString("\nТекущий ответ: " + selected[0])
+			}
+		case model.QuestionTypeYesNo:
+			keyboard.AddRow().
+				AddCallback("Да", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"action": "answer", "q": questionID, "v": model.AnswerYes})).
+				AddCallback("Нет", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"action": "answer", "q": questionID, "v": model.AnswerNo}))
+		case model.QuestionTypeSingleChoice:
+			for i, option := range question.Options {
+				keyboard.AddRow().AddCallback(option, schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"action": "answer", "q": questionID, "v": strconv.Itoa(i)}))
+			}
+		case model.QuestionTypeMultipleChoice:
+			text.WriteString("\nОтметьте подходящие варианты и нажмите «Готово».")
+			for i, option := range question.Options {
+				label := option
+				if slices.Contains(selected, option) {
+					label = "✅ " + option
+				}
+				keyboard.AddRow().AddCallback(label, schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"action": "toggle", "q": questionID, "v": strconv.Itoa(i)}))
+			}
+			keyboard.AddRow().AddCallback("Готово", schemes.POSITIVE, EncodePayload(fsm.Loop, map[string]string{"action": "done", "q": questionID}))
+		}
+		if !question.Required {
+			keyboard.AddRow().AddCallback("Пропустить", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"action": "skip", "q": questionID}))
+		}
+	} else {
+		if summary := state.summary(); summary != "" {
+			text.WriteString("Ваши ответы:\n")
+			text.WriteString(summary)
+			text.WriteString("\n")
+		}
+		text.WriteString(fmt.Sprintf("Напишите сопроводительное сообщение для организатора (до %d символов) или отправьте заявку без него.", maxApplicationMessageLength))
+		keyboard.AddRow().AddCallback("Отправить без сообщения", schemes.POSITIVE, EncodePayload(fsm.Loop, map[string]string{"action": "submit"}))
+	}
+
+	if state.step > 0 {
+		keyboard.AddRow().AddCallback("← Предыдущий вопрос", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"action": "prev"}))
+	}
+	keyboard.AddRow().AddCallback("Отменить заявку", schemes.NEGATIVE, EncodePayload(fsm.ApplicationQuestionnaireToEvent, nil))
+
+	return sendOrEditMessage(ctx, h.services, update, text.String(), keyboard)
+}
+
+func (h *ApplicationQuestionnaireHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	userID := update.GetUserID()
+	state, err := h.loadState(ctx, userID)
+	if err != nil {
+		return fsm.Error, nil, fmt.Errorf("анкета не найдена, откройте событие заново")
+	}
+	back := map[string]string{"id": state.params["event_id"], "from": state.params["from"]}
+
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		value := strings.TrimSpace(upd.Message.Body.Text)
+		question, ok := state.current()
+		if !ok {
+			if utf8.RuneCountInString(value) > maxApplicationMessageLength {
+				return fsm.Error, nil, fmt.Errorf("сообщение не должно превышать %d символов", maxApplicationMessageLength)
+			}
+			var message *string
+			if value != "" {
+				message = &value
+			}
+			return h.submit(ctx, userID, state, message, back)
+		}
+		if question.Type != model.QuestionTypeText {
+			return fsm.Error, nil, fmt.Errorf("выберите ответ кнопками")
+		}
+		if err := state.setAnswer(question, []string{value}); err != nil {
+			return fsm.Error, nil, questionnaireError(err)
+		}
+		state.step++
+		return h.saveAndLoop(ctx, userID, state)
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if event != fsm.Loop {
+			if !containsTransition(availableTransitions, event.String()) {
+				return fsm.Error, nil, fmt.Errorf("действие недоступно")
+			}
+			return event, back, nil
+		}
+
+		switch params["action"] {
+		case "submit":
+			return h.submit(ctx, userID, state, nil, back)
+		case "prev":
+			if state.step > 0 {
+				state.step--
+			}
+			return h.saveAndLoop(ctx, userID, state)
+		}
+
+		question, ok := state.current()
+		// Кнопки устаревшего сообщения относятся к другому вопросу: просто показываем текущий.
+		if !ok || params["q"] != strconv.Itoa(int(question.ID)) {
+			return fsm.Loop, nil, nil
+		}
+		switch params["action"] {
+		case "answer":
+			value := params["v"]
+			if question.Type == model.QuestionTypeSingleChoice {
+				index, err := strconv.Atoi(value)
+				if err != nil || index < 0 || index >= len(question.Options) {
+					return fsm.Error, nil, fmt.Errorf("неверный вариант ответа")
+				}
+				value = question.Options[index]
+			}
+			if err := state.setAnswer(question, []string{value}); err != nil {
+				return fsm.Error, nil, questionnaireError(err)
+			}
+			state.step++
+		case "toggle":
+			index, err := strconv.Atoi(params["v"])
+			if err != nil || index < 0 || index >= len(question.Options) {
+				return fsm.Error, nil, fmt.Errorf("неверный вариант ответа")
+			}
+			selected := state.answer(question.ID)
+			option := question.Options[index]
+			if i := slices.Index(selected, option); i >= 0 {
+				selected = slices.Delete(selected, i, i+1)
+			} else {
+				selected = append(selected, option)
+			}
+			state.storeAnswer(question.ID, selected)
+		case "done":
+			if err := state.setAnswer(question, state.answer(question.ID)); err != nil {
+				return fsm.Error, nil, questionnaireError(err)
+			}
+			state.step++
+		case "skip":
+			if question.Required {
+				return fsm.Error, nil, fmt.Errorf("на этот вопрос нужно ответить")
+			}
+			state.storeAnswer(question.ID, nil)
+			state.step++
+		default:
+			return fsm.Loop, nil, nil
+		}
+		return h.saveAndLoop(ctx, userID, state)
+	default:
+		return fsm.Error, nil, fmt.Errorf("ответьте сообщением или воспользуйтесь кнопками")
+	}
+}
+
+// submit подаёт заявку с накопленными ответами. Если анкета не прошла проверку сервиса,
+// волонтёр возвращается к вопросу с ошибкой.
+func (h *ApplicationQuestionnaireHandler) submit(ctx context.Context, userID int64, state *questionnaireState, message *string, back map[string]string) (fsm.Transition, map[string]string, error) {
+	answers := make([]model.ApplicationAnswer, 0, len(state.questions))
+	for _, question := range state.questions {
+		if values := state.answer(question.ID); len(values) > 0 {
+			answers = append(answers, model.ApplicationAnswer{QuestionID: question.ID, Values: values})
+		}
+	}
+
+	if _, err := h.services.ReviewService.ApplyToEvent(ctx, state.eventID, userID, message, answers); err != nil {
+		var questionnaireErr *service.QuestionnaireError
+		if errors.As(err, &questionnaireErr) {
+			if i := slices.IndexFunc(state.questions, func(q model.EventQuestion) bool { return q.ID == questionnaireErr.QuestionID }); i >= 0 {
+				state.step = i
+			}
+			if saveErr := h.saveState(ctx, userID, state); saveErr != nil {
+				log.Printf("failed to save questionnaire for user %d: %v", userID, saveErr)
+			}
+			return fsm.Error, nil, questionnaireError(err)
+		}
+		return fsm.Error, nil, applyError(err)
+	}
+
+	if err := h.services.UserService.ClearUserStateParams(ctx, userID); err != nil {
+		log.Printf("failed to clear questionnaire for user %d: %v", userID, err)
+	}
+	back["notice"] = "Вы успешно подали заявку на участие в событии!"
+	return fsm.ApplicationQuestionnaireToEvent, back, nil
+}
+
+func (h *ApplicationQuestionnaireHandler) saveAndLoop(ctx context.Context, userID int64, state *questionnaireState) (fsm.Transition, map[string]string, error) {
+	if err := h.saveState(ctx, userID, state); err != nil {
+		return fsm.Error, nil, fmt.Errorf("не удалось сохранить ответ, попробуйте ещё раз")
+	}
+	return fsm.Loop, nil, nil
+}
+
+func (h *ApplicationQuestionnaireHandler) loadState(ctx context.Context, userID int64) (*questionnaireState, error) {
+	params, err := h.services.UserService.GetUserStateParams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		return nil, fmt.Errorf("event id not saved")
+	}
+	questions, err := h.services.EventQuestionService.ListEventQuestions(ctx, int32(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list questions: %w", err)
+	}
+	step, _ := strconv.Atoi(params["step"])
+	step = min(max(step, 0), len(questions))
+	return &questionnaireState{params: params, eventID: int32(eventID), step: step, questions: questions}, nil
+}
+
+func (h *ApplicationQuestionnaireHandler) saveState(ctx context.Context, userID int64, state *questionnaireState) error {
+	state.params["step"] = strconv.Itoa(state.step)
+	return h.services.UserService.SetUserStateParams(ctx, userID, state.params)
+}
+
+// current возвращает вопрос текущего шага; после последнего вопроса идёт шаг сопроводительного сообщения.
+func (s *questionnaireState) current() (model.EventQuestion, bool) {
+	if s.step >= len(s.questions) {
+		return model.EventQuestion{}, false
+	}
+	return s.questions[s.step], true
+}
+
+func (s *questionnaireState) answer(questionID int32) []string {
+	var values []string
+	if raw := s.params[answerKey(questionID)]; raw != "" {
+		_ = json.Unmarshal([]byte(raw), &values)
+	}
+	return values
+}
+
+// setAnswer проверяет ответ теми же правилами, что и сервис при подаче заявки, и запоминает его.
+func (s *questionnaireState) setAnswer(question model.EventQuestion, values []string) error {
+	normalized, err := service.ValidateAnswer(question, values)
+	if err != nil {
+		return err
+	}
+	s.storeAnswer(question.ID, normalized)
+	return nil
+}
+
+func (s *questionnaireState) storeAnswer(questionID int32, values []string) {
+	if len(values) == 0 {
+		delete(s.params, answerKey(questionID))
+		return
+	}
+	raw, _ := json.Marshal(values)
+	s.params[answerKey(questionID)] = string(raw)
+}
+
+func (s *questionnaireState) summary() string {
+	var builder strings.Builder
+	for _, question := range s.questions {
+		values := s.answer(question.ID)
+		if len(values) == 0 {
+			continue
+		}
+		answer := model.ApplicationAnswer{QuestionType: question.Type, Values: values}
+		builder.WriteString(fmt.Sprintf("%s — %s\n", question.Text, formatAnswerValues(answer)))
+	}
+	return builder.String()
+}
+
+func answerKey(questionID int32) string {
+	return "a_" + strconv.Itoa(int(questionID))
+}
+
+// formatAnswerValues показывает ответ на анкету так, как его видит организатор.
+func formatAnswerValues(answer model.ApplicationAnswer) string {
+	if answer.QuestionType == model.QuestionTypeYesNo && len(answer.Values) == 1 {
+		switch answer.Values[0] {
+		case model.AnswerYes:
+			return "да"
+		case model.AnswerNo:
+			return "нет"
+		}
+	}
+	return strings.Join(answer.Values, ", ")
+}
+
+// questionnaireError возвращает пользователю причину, по которой ответ не подошёл.
+func questionnaireError(err error) error {
+	var questionnaireErr *service.QuestionnaireError
+	if errors.As(err, &questionnaireErr) {
+		return errors.New(questionnaireErr.Reason)
+	}
+	return fmt.Errorf("не удалось сохранить ответ, попробуйте ещё раз")
+}
+
+// applyError переводит ошибки подачи заявки в сообщения для волонтёра.
+func applyError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrEventFull):
+		return fmt.Errorf("свободных мест не осталось, встаньте в лист ожидания")
+	case errors.Is(err, service.ErrAlreadyApplied):
+		return fmt.Errorf("вы уже подали заявку на это событие")
+	case errors.Is(err, service.ErrEventNotOpen):
+		return fmt.Errorf("событие уже началось, завершено или отменено")
+	default:
+		log.Printf("apply to event failed: %v", err)
+		return fmt.Errorf("не удалось подать заявку, попробуйте позже")
+	}
+}
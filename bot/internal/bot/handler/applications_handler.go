@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// Срезы заявок по статусу события, которые открываются из «Моих событий».
+const (
+	applicationScopeActive    = "active"
+	applicationScopeCompleted = "completed"
+	applicationScopeCancelled = "cancelled"
+)
+
+var applicationScopeEventStatuses = map[string][]string{
+	applicationScopeActive:    {model.EventStatusOpen, model.EventStatusFull, model.EventStatusInProgress},
+	applicationScopeCompleted: {model.EventStatusCompleted},
+	applicationScopeCancelled: {model.EventStatusCancelled},
+}
+
+var applicationScopeTitles = map[string]string{
+	applicationScopeActive:    "Активные события",
+	applicationScopeCompleted: "Завершённые события",
+	applicationScopeCancelled: "Отменённые события",
+}
+
+// applicationStatusTabs порядок вкладок совпадает с порядком групп в списке.
+var applicationStatusTabs = []string{"pending", "waitlisted", "approved", "rejected", "cancelled"}
+
+// ApplicationsHandler показывает заявки волонтёра, сгруппированные по статусу, и позволяет
+// отозвать заявку или открыть событие.
+type ApplicationsHandler struct {
+	services *di.Services
+}
+
+func NewApplicationsHandler(services *di.Services) *ApplicationsHandler {
+	return &ApplicationsHandler{services: services}
+}
+
+func (h *ApplicationsHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	userID := update.GetUserID()
+	params = h.withSavedParams(ctx, userID, transition, params)
+
+	if _, err := h.services.VolunteerService.GetVolunteer(ctx, userID); err != nil {
+		keyboard := &maxbot.Keyboard{}
+		keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, h.backPayload(params))
+		return sendOrEditMessage(ctx, h.services, update, "Раздел доступен только волонтёрам.", keyboard)
+	}
+
+	var status *string
+	if params["status"] != "" {
+		value := params["status"]
+		status = &value
+	}
+	eventStatuses := applicationScopeEventStatuses[params["scope"]]
+
+	page, err := strconv.Atoi(params["page"])
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := int32(5)
+
+	count, err := h.services.ApplicationService.CountVolunteerApplicationsWithEvents(ctx, userID, status, eventStatuses)
+	if err != nil {
+		return err
+	}
+	totalPages := int((count + int64(limit) - 1) / int64(limit))
+	// После отзыва последней заявки на странице возвращаемся на предыдущую.
+	if totalPages > 0 && page > totalPages {
+		page = totalPages
+	}
+	offset := int32(page-1) * limit
+
+	applications, err := h.services.ApplicationService.ListVolunteerApplicationsWithEvents(ctx, userID, status, eventStatuses, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	params["page"] = strconv.Itoa(page)
+	if err := h.services.UserService.SetUserStateParams(ctx, userID, map[string]string{
+		"status": params["status"],
+		"scope":  params["scope"],
+		"from":   params["from"],
+		"page":   params["page"],
+	}); err != nil {
+		log.Printf("failed to save applications params for user %d: %v", userID, err)
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	if params["scope"] == "" {
+		h.addStatusTabs(ctx, keyboard, userID, params["status"])
+	}
+
+	for i, item := range applications {
+		number := strconv.Itoa(int(offset) + i + 1)
+		row := keyboard.AddRow()
+		if item.Application.EventID != nil {
+			row.AddCallback("📄 "+number, schemes.DEFAULT, EncodePayload(fsm.ApplicationsToEvent, map[string]string{
+				"id":   strconv.Itoa(int(*item.Application.EventID)),
+				"from": "applications",
+			}))
+		}
+		if service.CanWithdrawApplication(item.Application.Status, item.EventStatus) {
+			row.AddCallback("✖ "+number, schemes.NEGATIVE, EncodePayload(fsm.Loop, map[string]string{
+				"action":         "withdraw",
+				"application_id": strconv.Itoa(int(item.Application.ID)),
+			}))
+		}
+	}
+
+	if totalPages > 1 {
+		row := keyboard.AddRow()
+		if page > 1 {
+			row.AddCallback("<<", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page - 1)}))
+		}
+		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page)}))
+		if page < totalPages {
+			row.AddCallback(">>", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page + 1)}))
+		}
+	}
+	keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, h.backPayload(params))
+
+	text := formatApplications(applicationsTitle(params), applications, int(offset), params["status"] == "")
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *ApplicationsHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if event == fsm.Loop {
+			if params["action"] != "withdraw" {
+				return fsm.Loop, params, nil
+			}
+			applicationID, err := strconv.Atoi(params["application_id"])
+			if err != nil {
+				return fsm.Error, nil, fmt.Errorf("неверный ID заявки")
+			}
+			if err := h.services.ReviewService.WithdrawApplication(ctx, int32(applicationID), update.GetUserID()); err != nil {
+				return fsm.Error, nil, withdrawError(err)
+			}
+			return fsm.Loop, map[string]string{"notice": "Заявка отозвана"}, nil
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
+
+// withSavedParams дополняет параметры фильтрами, сохранёнными при предыдущем показе экрана.
+// При входе из меню фильтры приходят в payload и сохранённые не используются.
+func (h *ApplicationsHandler) withSavedParams(ctx context.Context, userID int64, transition fsm.Transition, params map[string]string) map[string]string {
+	result := make(map[string]string, len(params)+4)
+	for key, value := range params {
+		result[key] = value
+	}
+	if transition == fsm.MainMenuToApplications || transition == fsm.PersonalEventsToApplications {
+		return result
+	}
+	saved, err := h.services.UserService.GetUserStateParams(ctx, userID)
+	if err != nil {
+		return result
+	}
+	for _, key := range []string{"status", "scope", "from", "page"} {
+		if _, ok := result[key]; !ok {
+			result[key] = saved[key]
+		}
+	}
+	return result
+}
+
+func (h *ApplicationsHandler) backPayload(params map[string]string) string {
+	if params["from"] == "personal" {
+		return EncodePayload(fsm.ApplicationsToPersonalEvents, nil)
+	}
+	return EncodePayload(fsm.ApplicationsToMainMenu, nil)
+}
+
+// addStatusTabs добавляет вкладки статусов с количеством заявок; выбранная вкладка отмечена точкой.
+func (h *ApplicationsHandler) addStatusTabs(ctx context.Context, keyboard *maxbot.Keyboard, userID int64, selected string) {
+	counts, err := h.services.ApplicationService.CountApplicationsByVolunteerStatus(ctx, userID)
+	if err != nil {
+		log.Printf("failed to count applications for user %d: %v", userID, err)
+		counts = map[string]int64{}
+	}
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+
+	tab := func(status, title string, count int64) (string, string) {
+		if status == selected {
+			title = "• " + title
+		}
+		return fmt.Sprintf("%s (%d)", title, count), EncodePayload(fsm.Loop, map[string]string{"status": status, "page": "1"})
+	}
+
+	text, payload := tab("", "Все", total)
+	row := keyboard.AddRow().AddCallback(text, schemes.DEFAULT, payload)
+	for i, status := range applicationStatusTabs {
+		if (i+1)%2 == 0 {
+			row = keyboard.AddRow()
+		}
+		text, payload := tab(status, translateApplicationStatus(status), counts[status])
+		row.AddCallback(text, schemes.DEFAULT, payload)
+	}
+}
+
+func applicationsTitle(params map[string]string) string {
+	if title, ok := applicationScopeTitles[params["scope"]]; ok {
+		return title
+	}
+	if params["status"] == "rejected" && params["from"] == "personal" {
+		return "Участие отклонено"
+	}
+	return "Мои заявки"
+}
+
+// formatApplications выводит заявки; в общем списке они разбиты на группы по статусу.
+func formatApplications(title string, applications []model.VolunteerApplicationWithEvent, offset int, grouped bool) string {
+	var builder strings.Builder
+	builder.WriteString(title)
+	builder.WriteString("\n")
+
+	if len(applications) == 0 {
+		builder.WriteString("\nЗаявок нет.")
+		return builder.String()
+	}
+
+	currentGroup := ""
+	for i, item := range applications {
+		status := "pending"
+		if item.Application.Status != nil {
+			status = *item.Application.Status
+		}
+		if grouped && status != currentGroup {
+			currentGroup = status
+			builder.WriteString("\n— ")
+			builder.WriteString(translateApplicationStatus(status))
+			builder.WriteString(" —\n")
+		} else if !grouped && i == 0 {
+			builder.WriteString("\n")
+		}
+
+		builder.WriteString(fmt.Sprintf("%d. %s\n", offset+i+1, item.EventTitle))
+		builder.WriteString(fmt.Sprintf("   Дата: %s", item.EventDate.Local().Format("02.01.2006 15:04")))
+		if item.EventStatus != nil {
+			builder.WriteString(" · ")
+			builder.WriteString(strings.ToLower(translateEventStatus(*item.EventStatus)))
+		}
+		builder.WriteString("\n")
+		builder.WriteString(fmt.Sprintf("   Заявка: %s, подана %s", strings.ToLower(translateApplicationStatus(status)), item.Application.AppliedAt.Local().Format("02.01.2006")))
+		if item.Application.ReviewedAt != nil {
+			builder.WriteString(", рассмотрена ")
+			builder.WriteString(item.Application.ReviewedAt.Local().Format("02.01.2006"))
+		}
+		builder.WriteString("\n")
+		if item.Application.RejectionReason != nil && *item.Application.RejectionReason != "" {
+			builder.WriteString("   Причина отказа: ")
+			builder.WriteString(*item.Application.RejectionReason)
+			builder.WriteString("\n")
+		}
+	}
+	builder.WriteString("\n📄 — открыть событие, ✖ — отозвать заявку")
+	return builder.String()
+}
+
+// withdrawError переводит ошибки отзыва заявки в сообщения для волонтёра.
+func withdrawError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrApplicationNotFound):
+		return fmt.Errorf("заявка не найдена")
+	case errors.Is(err, service.ErrApplicationNotWithdrawable):
+		return fmt.Errorf("заявку уже нельзя отозвать: она закрыта или событие уже началось")
+	default:
+		log.Printf("withdraw application failed: %v", err)
+		return fmt.Errorf("не удалось отозвать заявку, попробуйте позже")
+	}
+}
@@ -74,6 +74,7 @@ func (h *CategoryFilterHandler) EnterState(ctx context.Context, update schemes.U
 		categoryPayload := EncodePayload(fsm.Loop, map[string]string{
 			"page":        pageStr,
 			"category_id": categoryIDStr,
+			"from":        params["from"],
 		})
 
 		keyboard.AddRow().AddCallback(buttonText, schemes.DEFAULT, categoryPayload)
@@ -87,22 +88,25 @@ func (h *CategoryFilterHandler) EnterState(ctx context.Context, update schemes.U
 
 		if page > 1 {
 			previousPageStr := strconv.Itoa(page - 1)
-			previousPayload := EncodePayload(fsm.Loop, map[string]string{"page": previousPageStr})
+			previousPayload := EncodePayload(fsm.Loop, map[string]string{"page": previousPageStr, "from": params["from"]})
 			row.AddCallback("<<", schemes.DEFAULT, previousPayload)
 		}
 
-		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, fsm.Loop.String())
+		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": pageStr, "from": params["from"]}))
 
 		if page < totalPages {
 			nextPageStr := strconv.Itoa(page + 1)
-			nextPayload := EncodePayload(fsm.Loop, map[string]string{"page": nextPageStr})
+			nextPayload := EncodePayload(fsm.Loop, map[string]string{"page": nextPageStr, "from": params["from"]})
 			row.AddCallback(">>", schemes.DEFAULT, nextPayload)
 		}
 	}
 
-	// Кнопка "Назад"
-	eventsPayload := EncodePayload(fsm.CategoriesFilterToEvents, map[string]string{"page": "1"})
-	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, eventsPayload)
+	// Кнопка "Назад": в профиль, если категории открыли оттуда, иначе к списку событий
+	backPayload := EncodePayload(fsm.CategoriesFilterToEvents, map[string]string{"page": "1"})
+	if params["from"] == "about" {
+		backPayload = EncodePayload(fsm.CategoriesFilterToAbout, nil)
+	}
+	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, backPayload)
 
 	msg := maxbot.NewMessage().
 		SetUser(update.GetUserID()).
@@ -126,7 +130,7 @@ func (h *CategoryFilterHandler) LeaveState(ctx context.Context, update schemes.U
 		// Обработка callback кнопок
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 
 		// Если это fsm.Loop, обрабатываем переключение категории
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// CheckInHandler принимает от волонтёра геолокацию или код организатора для отметки на событии.
+type CheckInHandler struct {
+	services *di.Services
+}
+
+func NewCheckInHandler(services *di.Services) *CheckInHandler {
+	return &CheckInHandler{services: services}
+}
+
+func (h *CheckInHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	// Геолокация и код придут обычным сообщением без payload, поэтому запоминаем событие.
+	if params["id"] != "" {
+		if err := h.services.UserService.SetUserStateParams(ctx, update.GetUserID(), map[string]string{
+			"event_id": params["id"],
+		}); err != nil {
+			return err
+		}
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	keyboard.AddRow().AddGeolocation("Отправить геолокацию", false)
+	keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, EncodePayload(fsm.CheckInToEvent, nil))
+
+	text := "Чтобы отметиться на событии, отправьте геолокацию или введите код, который покажет организатор."
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *CheckInHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	eventID, err := h.savedEventID(ctx, update.GetUserID())
+	if err != nil {
+		return fsm.Error, nil, err
+	}
+	back := map[string]string{"id": strconv.Itoa(int(eventID))}
+
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		if lat, lon, ok := extractLocation(upd.Message.Body); ok {
+			if !validCoordinates(lat, lon) {
+				return fsm.Error, nil, fmt.Errorf("некорректные координаты")
+			}
+			if _, err := h.services.AttendanceService.CheckInWithLocation(ctx, eventID, update.GetUserID(), lat, lon); err != nil {
+				return fsm.Error, nil, attendanceError(err)
+			}
+		} else {
+			code := strings.TrimSpace(upd.Message.Body.Text)
+			if code == "" {
+				return fsm.Error, nil, fmt.Errorf("отправьте геолокацию или введите код")
+			}
+			if _, err := h.services.AttendanceService.CheckInWithCode(ctx, eventID, update.GetUserID(), code); err != nil {
+				return fsm.Error, nil, attendanceError(err)
+			}
+		}
+		back["notice"] = "Вы отметились на событии. Не забудьте отметить уход, когда закончите."
+		return fsm.CheckInToEvent, back, nil
+	case *schemes.MessageCallbackUpdate:
+		event, _, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, back, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("отправьте геолокацию, введите код или воспользуйтесь кнопками")
+	}
+}
+
+func (h *CheckInHandler) savedEventID(ctx context.Context, userID int64) (int32, error) {
+	saved, err := h.services.UserService.GetUserStateParams(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось загрузить событие. Попробуйте позже")
+	}
+	eventID, err := strconv.Atoi(saved["event_id"])
+	if err != nil {
+		return 0, fmt.Errorf("событие не выбрано, вернитесь к списку событий")
+	}
+	return int32(eventID), nil
+}
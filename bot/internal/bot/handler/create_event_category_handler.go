@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventCategoryHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventCategoryHandler(services *di.Services) *CreateEventCategoryHandler {
+	return &CreateEventCategoryHandler{services: services}
+}
+
+func (h *CreateEventCategoryHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	page, err := strconv.Atoi(params["page"])
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := int32(8)
+	offset := int32(page-1) * limit
+
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.services.CategoryService.ListActiveCategories(ctx, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	count, err := h.services.CategoryService.CountActiveCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	for _, category := range categories {
+		buttonText := category.Name
+		if draft.CategoryID != nil && *draft.CategoryID == category.ID {
+			buttonText = "✅ " + buttonText
+		}
+		payload := EncodePayload(fsm.CreateEventCategoryToMaxVolunteers, map[string]string{
+			"category_id": strconv.Itoa(int(category.ID)),
+		})
+		keyboard.AddRow().AddCallback(buttonText, schemes.DEFAULT, payload)
+	}
+
+	totalPages := int((count + int64(limit) - 1) / int64(limit))
+	if totalPages > 1 {
+		row := keyboard.AddRow()
+		if page > 1 {
+			row.AddCallback("<<", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page - 1)}))
+		}
+		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page)}))
+		if page < totalPages {
+			row.AddCallback(">>", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page + 1)}))
+		}
+	}
+
+	keyboard.AddRow().AddCallback("Без категории", schemes.DEFAULT, EncodePayload(fsm.CreateEventCategoryToMaxVolunteers, map[string]string{"category_id": "0"}))
+	keyboard.AddRow().AddCallback("Отмена", schemes.NEGATIVE, EncodePayload(fsm.CreateEventToMainMenu, nil))
+
+	return sendOrEditMessage(ctx, h.services, update, createEventStepHeader(6)+"Выберите категорию события:", keyboard)
+}
+
+func (h *CreateEventCategoryHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		if event != fsm.CreateEventCategoryToMaxVolunteers {
+			return event, params, nil
+		}
+
+		categoryID, err := strconv.Atoi(params["category_id"])
+		if err != nil || categoryID < 0 {
+			return fsm.Error, nil, fmt.Errorf("неверный ID категории")
+		}
+
+		var categoryIDPtr *int32
+		if categoryID != 0 {
+			category, err := h.services.CategoryService.GetCategory(ctx, int32(categoryID))
+			if err != nil {
+				return fsm.Error, nil, fmt.Errorf("категория не найдена")
+			}
+			if category.IsActive != nil && !*category.IsActive {
+				return fsm.Error, nil, fmt.Errorf("категория недоступна")
+			}
+			categoryIDPtr = &category.ID
+		}
+
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.CategoryID = categoryIDPtr
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return event, nil, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("выберите категорию кнопками")
+	}
+}
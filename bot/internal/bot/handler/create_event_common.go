@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// createEventStepsTotal количество шагов мастера до экрана подтверждения.
+const createEventStepsTotal = 8
+
+func createEventStepHeader(step int) string {
+	return fmt.Sprintf("Создание события — шаг %d из %d\n\n", step, createEventStepsTotal)
+}
+
+// createEventKeyboard собирает клавиатуру шага: опциональную кнопку пропуска и кнопку отмены мастера.
+func createEventKeyboard(skipText string, skip fsm.Transition) *maxbot.Keyboard {
+	keyboard := &maxbot.Keyboard{}
+	if skipText != "" {
+		keyboard.AddRow().AddCallback(skipText, schemes.DEFAULT, EncodePayload(skip, nil))
+	}
+	keyboard.AddRow().AddCallback("Отмена", schemes.NEGATIVE, EncodePayload(fsm.CreateEventToMainMenu, nil))
+	return keyboard
+}
+
+// updateEventDraft загружает черновик организатора, применяет изменения и сохраняет его.
+func updateEventDraft(ctx context.Context, services *di.Services, organizerID int64, apply func(draft *model.EventDraft)) (model.EventDraft, error) {
+	draft, err := services.EventDraftService.GetEventDraft(ctx, organizerID)
+	if err != nil {
+		return model.EventDraft{}, fmt.Errorf("не удалось загрузить черновик: %w", err)
+	}
+	apply(&draft)
+	saved, err := services.EventDraftService.SaveEventDraft(ctx, draft)
+	if err != nil {
+		return model.EventDraft{}, fmt.Errorf("не удалось сохранить черновик: %w", err)
+	}
+	return saved, nil
+}
+
+// decodeCreateEventCallback разбирает нажатие кнопки на шаге мастера. При отмене черновик удаляется.
+func decodeCreateEventCallback(ctx context.Context, services *di.Services, upd *schemes.MessageCallbackUpdate, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	event, params, err := DecodePayload(upd.Callback.Payload)
+	if err != nil {
+		return fsm.Error, nil, callbackError(err)
+	}
+	if event == fsm.Loop {
+		return fsm.Loop, params, nil
+	}
+	if !containsTransition(availableTransitions, event.String()) {
+		return fsm.Error, nil, fmt.Errorf("действие недоступно")
+	}
+	if event == fsm.CreateEventToMainMenu {
+		if err := services.EventDraftService.DeleteEventDraft(ctx, upd.GetUserID()); err != nil {
+			return fsm.Error, nil, fmt.Errorf("не удалось удалить черновик: %w", err)
+		}
+		return event, map[string]string{"notice": "Создание события отменено"}, nil
+	}
+	return event, params, nil
+}
+
+// formatEventDraft формирует текстовое описание черновика для экрана подтверждения.
+func formatEventDraft(draft model.EventDraft, categoryName string) string {
+	var builder strings.Builder
+	builder.WriteString("Название: ")
+	builder.WriteString(valueOrDash(draft.Title))
+	builder.WriteString("\nОписание: ")
+	builder.WriteString(valueOrDash(draft.Description))
+	builder.WriteString("\nДата: ")
+	if draft.Date != nil {
+		builder.WriteString(draft.Date.Local().Format("02.01.2006 15:04"))
+	} else {
+		builder.WriteString("—")
+	}
+	builder.WriteString("\nДлительность: ")
+	if draft.DurationHours != nil {
+		builder.WriteString(fmt.Sprintf("%d ч.", *draft.DurationHours))
+	} else {
+		builder.WriteString("—")
+	}
+	builder.WriteString("\nМесто: ")
+	builder.WriteString(valueOrDash(draft.Location))
+	if draft.LocationLat != nil && draft.LocationLon != nil {
+		builder.WriteString(" (")
+		builder.WriteString(formatCoordinates(*draft.LocationLat, *draft.LocationLon))
+		builder.WriteString(")")
+	}
+	builder.WriteString("\nКатегория: ")
+	if categoryName == "" {
+		builder.WriteString("—")
+	} else {
+		builder.WriteString(categoryName)
+	}
+	builder.WriteString("\nМаксимум волонтёров: ")
+	if draft.MaxVolunteers != nil {
+		builder.WriteString(fmt.Sprintf("%d", *draft.MaxVolunteers))
+	} else {
+		builder.WriteString("—")
+	}
+	builder.WriteString("\nКонтакты: ")
+	builder.WriteString(valueOrDash(draft.Contacts))
+	return builder.String()
+}
+
+func formatCoordinates(lat, lon float64) string {
+	return fmt.Sprintf("%.5f, %.5f", lat, lon)
+}
+
+func valueOrDash(value *string) string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return "—"
+	}
+	return *value
+}
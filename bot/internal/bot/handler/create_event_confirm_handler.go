@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventConfirmHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventConfirmHandler(services *di.Services) *CreateEventConfirmHandler {
+	return &CreateEventConfirmHandler{services: services}
+}
+
+func (h *CreateEventConfirmHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	categoryName := ""
+	if draft.CategoryID != nil {
+		if category, err := h.services.CategoryService.GetCategory(ctx, *draft.CategoryID); err == nil {
+			categoryName = category.Name
+		}
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	keyboard.AddRow().AddCallback("Опубликовать", schemes.POSITIVE, EncodePayload(fsm.CreateEventConfirmToMainMenu, nil))
+	keyboard.AddRow().AddCallback("Изменить", schemes.DEFAULT, EncodePayload(fsm.CreateEventConfirmToTitle, nil))
+	keyboard.AddRow().AddCallback("Отмена", schemes.NEGATIVE, EncodePayload(fsm.CreateEventToMainMenu, nil))
+
+	text := "Проверьте данные события:\n\n" + formatEventDraft(draft, categoryName)
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *CreateEventConfirmHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		if event != fsm.CreateEventConfirmToMainMenu {
+			return event, params, nil
+		}
+		// Профиль организатора могли удалить, пока заполнялся черновик.
+		if !isOrganizer(ctx, h.services, update.GetUserID()) {
+			return fsm.Error, nil, fmt.Errorf("публиковать события могут только организаторы")
+		}
+
+		draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+		if err != nil {
+			return fsm.Error, nil, fmt.Errorf("не удалось загрузить черновик: %w", err)
+		}
+		if draft.Title == nil || draft.Date == nil || draft.Location == nil ||
+			draft.LocationLat == nil || draft.LocationLon == nil || draft.MaxVolunteers == nil {
+			return fsm.Error, nil, fmt.Errorf("заполнены не все обязательные поля, нажмите «Изменить»")
+		}
+		if !draft.Date.After(time.Now()) {
+			return fsm.Error, nil, fmt.Errorf("дата события уже прошла, нажмите «Изменить» и укажите новую")
+		}
+
+		created, err := h.services.EventService.CreateEvent(
+			ctx,
+			*draft.Title,
+			draft.Description,
+			nil,
+			*draft.Date,
+			draft.DurationHours,
+			*draft.Location,
+			*draft.LocationLat,
+			*draft.LocationLon,
+			draft.CategoryID,
+			draft.Contacts,
+			*draft.MaxVolunteers,
+			update.GetUserID(),
+		)
+		if err != nil {
+			return fsm.Error, nil, fmt.Errorf("не удалось создать событие: %w", err)
+		}
+
+		// Событие уже сохранено, поэтому ошибка удаления черновика не должна откатывать переход.
+		if err := h.services.EventDraftService.DeleteEventDraft(ctx, update.GetUserID()); err != nil {
+			log.Printf("failed to delete event draft for organizer %d: %v", update.GetUserID(), err)
+		}
+
+		return event, map[string]string{
+			"notice": fmt.Sprintf("Событие «%s» опубликовано", created.Title),
+		}, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
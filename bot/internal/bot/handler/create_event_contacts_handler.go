@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventContactsHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventContactsHandler(services *di.Services) *CreateEventContactsHandler {
+	return &CreateEventContactsHandler{services: services}
+}
+
+func (h *CreateEventContactsHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(8) + "Укажите контакты для связи с организатором (до 500 символов) или пропустите шаг:"
+	skipText := "Пропустить"
+	if draft.Contacts != nil {
+		text += fmt.Sprintf("\n\nТекущее значение: %s", *draft.Contacts)
+		skipText = "Оставить как есть"
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, createEventKeyboard(skipText, fsm.CreateEventContactsToConfirm))
+}
+
+func (h *CreateEventContactsHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		contacts := strings.TrimSpace(upd.Message.Body.Text)
+		if contacts == "" {
+			return fsm.Error, nil, fmt.Errorf("контакты не могут быть пустыми, воспользуйтесь кнопкой «Пропустить»")
+		}
+		if utf8.RuneCountInString(contacts) > 500 {
+			return fsm.Error, nil, fmt.Errorf("контакты не должны превышать 500 символов")
+		}
+
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.Contacts = &contacts
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.CreateEventContactsToConfirm, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите контакты текстом")
+	}
+}
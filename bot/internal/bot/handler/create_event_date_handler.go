@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// createEventDateLayout формат ввода даты и времени события.
+const createEventDateLayout = "02.01.2006 15:04"
+
+type CreateEventDateHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventDateHandler(services *di.Services) *CreateEventDateHandler {
+	return &CreateEventDateHandler{services: services}
+}
+
+func (h *CreateEventDateHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(3) + "Введите дату и время начала в формате ДД.ММ.ГГГГ ЧЧ:ММ, например 25.12.2025 10:00:"
+	skipText := ""
+	if draft.Date != nil {
+		text += fmt.Sprintf("\n\nТекущее значение: %s", draft.Date.Local().Format(createEventDateLayout))
+		skipText = "Оставить как есть"
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, createEventKeyboard(skipText, fsm.CreateEventDateToDuration))
+}
+
+func (h *CreateEventDateHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		text := strings.TrimSpace(upd.Message.Body.Text)
+		date, err := time.ParseInLocation(createEventDateLayout, text, time.Local)
+		if err != nil {
+			return fsm.Error, nil, fmt.Errorf("неверный формат даты, используйте ДД.ММ.ГГГГ ЧЧ:ММ")
+		}
+		if !date.After(time.Now()) {
+			return fsm.Error, nil, fmt.Errorf("дата события должна быть в будущем")
+		}
+
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.Date = &date
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.CreateEventDateToDuration, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		if event == fsm.CreateEventDateToDuration {
+			draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+			if err != nil {
+				return fsm.Error, nil, fmt.Errorf("не удалось загрузить черновик: %w", err)
+			}
+			if draft.Date == nil {
+				return fsm.Error, nil, fmt.Errorf("введите дату и время события")
+			}
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите дату текстом в формате ДД.ММ.ГГГГ ЧЧ:ММ")
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventDescriptionHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventDescriptionHandler(services *di.Services) *CreateEventDescriptionHandler {
+	return &CreateEventDescriptionHandler{services: services}
+}
+
+func (h *CreateEventDescriptionHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(2) + "Введите описание события (до 2000 символов) или пропустите шаг:"
+	skipText := "Пропустить"
+	if draft.Description != nil {
+		text += fmt.Sprintf("\n\nТекущее значение: %s", *draft.Description)
+		skipText = "Оставить как есть"
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, createEventKeyboard(skipText, fsm.CreateEventDescriptionToDate))
+}
+
+func (h *CreateEventDescriptionHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		description := strings.TrimSpace(upd.Message.Body.Text)
+		if description == "" {
+			return fsm.Error, nil, fmt.Errorf("описание не может быть пустым, воспользуйтесь кнопкой «Пропустить»")
+		}
+		if utf8.RuneCountInString(description) > 2000 {
+			return fsm.Error, nil, fmt.Errorf("описание не должно превышать 2000 символов")
+		}
+
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.Description = &description
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.CreateEventDescriptionToDate, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите описание события текстом")
+	}
+}
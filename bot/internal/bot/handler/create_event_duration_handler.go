@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventDurationHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventDurationHandler(services *di.Services) *CreateEventDurationHandler {
+	return &CreateEventDurationHandler{services: services}
+}
+
+func (h *CreateEventDurationHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(4) + "Введите длительность события в часах (число от 1 до 72) или пропустите шаг:"
+	skipText := "Пропустить"
+	if draft.DurationHours != nil {
+		text += fmt.Sprintf("\n\nТекущее значение: %d ч.", *draft.DurationHours)
+		skipText = "Оставить как есть"
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, createEventKeyboard(skipText, fsm.CreateEventDurationToLocation))
+}
+
+func (h *CreateEventDurationHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		text := strings.TrimSpace(upd.Message.Body.Text)
+		hours, err := strconv.Atoi(text)
+		if err != nil {
+			return fsm.Error, nil, fmt.Errorf("длительность должна быть числом")
+		}
+		if hours < 1 || hours > 72 {
+			return fsm.Error, nil, fmt.Errorf("длительность должна быть от 1 до 72 часов")
+		}
+
+		hoursInt32 := int32(hours)
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.DurationHours = &hoursInt32
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.CreateEventDurationToLocation, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите число часов")
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// coordinatesSuffix выделяет координаты «широта, долгота» в конце адреса.
+var coordinatesSuffix = regexp.MustCompile(`^(.*?)[\s,;]*(-?\d{1,2}\.\d+)\s*[,;\s]\s*(-?\d{1,3}\.\d+)$`)
+
+type CreateEventLocationHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventLocationHandler(services *di.Services) *CreateEventLocationHandler {
+	return &CreateEventLocationHandler{services: services}
+}
+
+func (h *CreateEventLocationHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(5) +
+		"Отправьте геолокацию места проведения или введите адрес текстом.\n" +
+		"Координаты можно указать в конце адреса: «ул. Ленина, 1; 55.75580, 37.61730»."
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	hasCoordinates := draft.LocationLat != nil && draft.LocationLon != nil
+	if draft.Location != nil {
+		text += fmt.Sprintf("\n\nАдрес: %s", *draft.Location)
+	}
+	if hasCoordinates {
+		text += fmt.Sprintf("\nКоординаты: %s", formatCoordinates(*draft.LocationLat, *draft.LocationLon))
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	keyboard.AddRow().AddGeolocation("Отправить геолокацию", false)
+	if draft.Location != nil && hasCoordinates {
+		keyboard.AddRow().AddCallback("Оставить как есть", schemes.DEFAULT, EncodePayload(fsm.CreateEventLocationToCategory, nil))
+	}
+	keyboard.AddRow().AddCallback("Отмена", schemes.NEGATIVE, EncodePayload(fsm.CreateEventToMainMenu, nil))
+
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *CreateEventLocationHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		if lat, lon, ok := extractLocation(upd.Message.Body); ok {
+			if !validCoordinates(lat, lon) {
+				return fsm.Error, nil, fmt.Errorf("некорректные координаты")
+			}
+			if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+				draft.LocationLat = &lat
+				draft.LocationLon = &lon
+				if draft.Location == nil {
+					address := formatCoordinates(lat, lon)
+					draft.Location = &address
+				}
+			}); err != nil {
+				return fsm.Error, nil, err
+			}
+			return fsm.CreateEventLocationToCategory, nil, nil
+		}
+
+		address := strings.TrimSpace(upd.Message.Body.Text)
+		if address == "" {
+			return fsm.Error, nil, fmt.Errorf("отправьте геолокацию или введите адрес")
+		}
+
+		var lat, lon *float64
+		if match := coordinatesSuffix.FindStringSubmatch(address); match != nil {
+			parsedLat, latErr := strconv.ParseFloat(match[2], 64)
+			parsedLon, lonErr := strconv.ParseFloat(match[3], 64)
+			if latErr == nil && lonErr == nil {
+				if !validCoordinates(parsedLat, parsedLon) {
+					return fsm.Error, nil, fmt.Errorf("некорректные координаты")
+				}
+				lat, lon = &parsedLat, &parsedLon
+				address = strings.TrimSpace(match[1])
+				if address == "" {
+					address = formatCoordinates(parsedLat, parsedLon)
+				}
+			}
+		}
+		if utf8.RuneCountInString(address) > 500 {
+			return fsm.Error, nil, fmt.Errorf("адрес не должен превышать 500 символов")
+		}
+
+		draft, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.Location = &address
+			if lat != nil && lon != nil {
+				draft.LocationLat = lat
+				draft.LocationLon = lon
+			}
+		})
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		if draft.LocationLat == nil || draft.LocationLon == nil {
+			return fsm.Loop, map[string]string{
+				"notice": "Адрес сохранён. Теперь отправьте геолокацию, чтобы событие появилось на карте.",
+			}, nil
+		}
+		return fsm.CreateEventLocationToCategory, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("отправьте геолокацию или введите адрес")
+	}
+}
+
+func validCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
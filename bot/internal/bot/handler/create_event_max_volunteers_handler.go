@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventMaxVolunteersHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventMaxVolunteersHandler(services *di.Services) *CreateEventMaxVolunteersHandler {
+	return &CreateEventMaxVolunteersHandler{services: services}
+}
+
+func (h *CreateEventMaxVolunteersHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(7) + "Введите максимальное количество волонтёров (число от 1 до 1000):"
+	skipText := ""
+	if draft.MaxVolunteers != nil {
+		text += fmt.Sprintf("\n\nТекущее значение: %d", *draft.MaxVolunteers)
+		skipText = "Оставить как есть"
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, createEventKeyboard(skipText, fsm.CreateEventMaxVolunteersToContacts))
+}
+
+func (h *CreateEventMaxVolunteersHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		text := strings.TrimSpace(upd.Message.Body.Text)
+		maxVolunteers, err := strconv.Atoi(text)
+		if err != nil {
+			return fsm.Error, nil, fmt.Errorf("количество волонтёров должно быть числом")
+		}
+		if maxVolunteers < 1 || maxVolunteers > 1000 {
+			return fsm.Error, nil, fmt.Errorf("количество волонтёров должно быть от 1 до 1000")
+		}
+
+		maxVolunteersInt32 := int32(maxVolunteers)
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.MaxVolunteers = &maxVolunteersInt32
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.CreateEventMaxVolunteersToContacts, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		if event == fsm.CreateEventMaxVolunteersToContacts {
+			draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+			if err != nil {
+				return fsm.Error, nil, fmt.Errorf("не удалось загрузить черновик: %w", err)
+			}
+			if draft.MaxVolunteers == nil {
+				return fsm.Error, nil, fmt.Errorf("введите количество волонтёров")
+			}
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите число волонтёров")
+	}
+}
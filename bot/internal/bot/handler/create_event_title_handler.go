@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+type CreateEventTitleHandler struct {
+	services *di.Services
+}
+
+func NewCreateEventTitleHandler(services *di.Services) *CreateEventTitleHandler {
+	return &CreateEventTitleHandler{services: services}
+}
+
+func (h *CreateEventTitleHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	// Новый запуск мастера из главного меню начинается с чистого черновика.
+	if transition == fsm.MainMenuToCreateEvent {
+		if err := h.services.EventDraftService.DeleteEventDraft(ctx, update.GetUserID()); err != nil {
+			return err
+		}
+	}
+
+	draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	text := createEventStepHeader(1) + "Введите название события (от 3 до 200 символов):"
+	skipText := ""
+	if draft.Title != nil {
+		text += fmt.Sprintf("\n\nТекущее значение: %s", *draft.Title)
+		skipText = "Оставить как есть"
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, createEventKeyboard(skipText, fsm.CreateEventTitleToDescription))
+}
+
+func (h *CreateEventTitleHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		title := strings.TrimSpace(upd.Message.Body.Text)
+		length := utf8.RuneCountInString(title)
+		if length < 3 || length > 200 {
+			return fsm.Error, nil, fmt.Errorf("название должно быть от 3 до 200 символов")
+		}
+
+		if _, err := updateEventDraft(ctx, h.services, update.GetUserID(), func(draft *model.EventDraft) {
+			draft.Title = &title
+		}); err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.CreateEventTitleToDescription, nil, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := decodeCreateEventCallback(ctx, h.services, upd, availableTransitions)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		if event == fsm.CreateEventTitleToDescription {
+			draft, err := h.services.EventDraftService.GetEventDraft(ctx, update.GetUserID())
+			if err != nil {
+				return fsm.Error, nil, fmt.Errorf("не удалось загрузить черновик: %w", err)
+			}
+			if draft.Title == nil {
+				return fsm.Error, nil, fmt.Errorf("введите название события")
+			}
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите название события текстом")
+	}
+}
@@ -82,7 +82,7 @@ func (h *EditGeoFilterHandler) LeaveState(ctx context.Context, update schemes.Up
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !containsTransition(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("действие недоступно")
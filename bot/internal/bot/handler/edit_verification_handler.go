@@ -109,7 +109,7 @@ func (h *EditVerificationHandler) LeaveState(ctx context.Context, update schemes
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !containsTransition(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("действие недоступно")
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// EventAttendanceHandler показывает участников события с отметками и позволяет организатору
+// отметить приход и уход или выдать код для самостоятельной отметки.
+type EventAttendanceHandler struct {
+	services *di.Services
+}
+
+func NewEventAttendanceHandler(services *di.Services) *EventAttendanceHandler {
+	return &EventAttendanceHandler{services: services}
+}
+
+func (h *EventAttendanceHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	organizerID := update.GetUserID()
+	eventID, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		return h.sendBack(ctx, update, "Событие не выбрано.")
+	}
+	event, err := h.services.EventService.GetEventByID(ctx, int32(eventID))
+	if err != nil || event.OrganizerID == nil || *event.OrganizerID != organizerID {
+		return h.sendBack(ctx, update, "Событие не найдено.")
+	}
+
+	page, err := strconv.Atoi(params["page"])
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := int32(5)
+	offset := int32(page-1) * limit
+
+	count, err := h.services.ParticipantService.CountParticipantsForEvent(ctx, &event.ID)
+	if err != nil {
+		return err
+	}
+	participants, err := h.services.AttendanceService.ListAttendance(ctx, event.ID, organizerID, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	idStr := strconv.Itoa(int(event.ID))
+	pageStr := strconv.Itoa(page)
+	keyboard := &maxbot.Keyboard{}
+	for i, item := range participants {
+		if item.Participant.VolunteerID == nil {
+			continue
+		}
+		number := strconv.Itoa(int(offset) + i + 1)
+		actionPayload := func(action string) string {
+			return EncodePayload(fsm.Loop, map[string]string{
+				"action":       action,
+				"event_id":     idStr,
+				"volunteer_id": strconv.FormatInt(*item.Participant.VolunteerID, 10),
+				"page":         pageStr,
+			})
+		}
+		row := keyboard.AddRow()
+		switch {
+		case item.Participant.CheckedInAt == nil:
+			row.AddCallback("✅ "+number, schemes.POSITIVE, actionPayload("checkin"))
+		case item.Participant.CheckedOutAt == nil:
+			row.AddCallback("🏁 "+number, schemes.DEFAULT, actionPayload("checkout"))
+			row.AddCallback("↩ "+number, schemes.NEGATIVE, actionPayload("reset"))
+		default:
+			row.AddCallback("↩ "+number, schemes.NEGATIVE, actionPayload("reset"))
+		}
+	}
+
+	totalPages := int((count + int64(limit) - 1) / int64(limit))
+	if totalPages > 1 {
+		row := keyboard.AddRow()
+		if page > 1 {
+			row.AddCallback("<<", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"event_id": idStr, "page": strconv.Itoa(page - 1)}))
+		}
+		row.AddCallback(pageStr, schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"event_id": idStr, "page": pageStr}))
+		if page < totalPages {
+			row.AddCallback(">>", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"event_id": idStr, "page": strconv.Itoa(page + 1)}))
+		}
+	}
+	keyboard.AddRow().AddCallback("Выдать код для отметки", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{
+		"action":   "code",
+		"event_id": idStr,
+		"page":     pageStr,
+	}))
+	keyboard.AddRow().AddCallback("← К событиям", schemes.NEGATIVE, EncodePayload(fsm.EventAttendanceToOrganizerEvents, nil))
+
+	text := formatEventAttendance(event, participants, int(offset))
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *EventAttendanceHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if event == fsm.Loop {
+			return h.handleAction(ctx, update.GetUserID(), params)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
+
+func (h *EventAttendanceHandler) handleAction(ctx context.Context, organizerID int64, params map[string]string) (fsm.Transition, map[string]string, error) {
+	action := params["action"]
+	if action == "" {
+		return fsm.Loop, params, nil
+	}
+	back := map[string]string{"event_id": params["event_id"], "page": params["page"]}
+
+	eventID, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		return fsm.Error, back, fmt.Errorf("неверный ID события")
+	}
+
+	if action == "code" {
+		code, err := h.services.AttendanceService.GenerateCheckInCode(ctx, int32(eventID), organizerID)
+		if err != nil {
+			return fsm.Error, back, attendanceError(err)
+		}
+		minutes := int(time.Until(code.ExpiresAt).Round(time.Minute).Minutes())
+		back["notice"] = fmt.Sprintf("Код для отметки: %s\nПокажите его волонтёрам, код действует %d мин. Предыдущий код больше не работает.", code.Code, minutes)
+		return fsm.Loop, back, nil
+	}
+
+	volunteerID, err := strconv.ParseInt(params["volunteer_id"], 10, 64)
+	if err != nil {
+		return fsm.Error, back, fmt.Errorf("неверный ID участника")
+	}
+	switch action {
+	case "checkin":
+		_, err = h.services.AttendanceService.MarkCheckIn(ctx, int32(eventID), volunteerID, organizerID)
+		back["notice"] = "Приход отмечен"
+	case "checkout":
+		_, err = h.services.AttendanceService.MarkCheckOut(ctx, int32(eventID), volunteerID, organizerID)
+		back["notice"] = "Уход отмечен"
+	case "reset":
+		_, err = h.services.AttendanceService.ResetAttendance(ctx, int32(eventID), volunteerID, organizerID)
+		back["notice"] = "Отметки сброшены"
+	default:
+		return fsm.Loop, back, nil
+	}
+	if err != nil {
+		delete(back, "notice")
+		return fsm.Error, back, attendanceError(err)
+	}
+	return fsm.Loop, back, nil
+}
+
+func (h *EventAttendanceHandler) sendBack(ctx context.Context, update schemes.UpdateInterface, text string) error {
+	keyboard := &maxbot.Keyboard{}
+	keyboard.AddRow().AddCallback("← К событиям", schemes.NEGATIVE, EncodePayload(fsm.EventAttendanceToOrganizerEvents, nil))
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func formatEventAttendance(event model.Event, participants []model.ParticipantAttendance, offset int) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Событие: %s\nДата: %s\n", event.Title, event.Date.Local().Format("02.01.2006 15:04")))
+	if event.Status != nil {
+		builder.WriteString(fmt.Sprintf("Статус: %s\n", translateEventStatus(*event.Status)))
+	}
+	builder.WriteString("\n")
+
+	if len(participants) == 0 {
+		builder.WriteString("Участников пока нет.")
+		return builder.String()
+	}
+
+	for i, item := range participants {
+		builder.WriteString(fmt.Sprintf("%d. %s", offset+i+1, item.Name))
+		if item.Username != nil && *item.Username != "" {
+			builder.WriteString(" (@")
+			builder.WriteString(*item.Username)
+			builder.WriteString(")")
+		}
+		builder.WriteString(" — ")
+		builder.WriteString(formatAttendance(item.Participant))
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n✅ — пришёл, 🏁 — ушёл, ↩ — сбросить отметки")
+	return builder.String()
+}
+
+// formatAttendance описывает отметки участника одной строкой.
+func formatAttendance(participant model.EventParticipant) string {
+	if participant.CheckedInAt == nil {
+		return "не отмечен"
+	}
+	text := "пришёл в " + participant.CheckedInAt.Local().Format("15:04")
+	if participant.CheckInMethod != nil {
+		text += " (" + translateCheckInMethod(*participant.CheckInMethod) + ")"
+	}
+	if participant.CheckedOutAt != nil {
+		text += ", ушёл в " + participant.CheckedOutAt.Local().Format("15:04")
+	}
+	return text
+}
+
+func translateCheckInMethod(method string) string {
+	switch method {
+	case model.CheckInMethodCode:
+		return "по коду"
+	case model.CheckInMethodGeo:
+		return "по геолокации"
+	case model.CheckInMethodOrganizer:
+		return "организатором"
+	default:
+		return method
+	}
+}
+
+// attendanceError переводит ошибки отметки присутствия в сообщения для пользователя.
+func attendanceError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotEventOwner):
+		return fmt.Errorf("это событие другого организатора")
+	case errors.Is(err, service.ErrParticipantNotFound):
+		return fmt.Errorf("волонтёр не участвует в событии")
+	case errors.Is(err, service.ErrCheckInClosed):
+		return fmt.Errorf("отметка на этом событии сейчас недоступна")
+	case errors.Is(err, service.ErrInvalidCheckInCode):
+		return fmt.Errorf("неверный или просроченный код, попросите организатора выдать новый")
+	case errors.Is(err, service.ErrTooFarFromEvent):
+		return fmt.Errorf("вы слишком далеко от места проведения события")
+	case errors.Is(err, service.ErrEventLocationUnknown):
+		return fmt.Errorf("у события не указаны координаты, отметьтесь по коду")
+	case errors.Is(err, service.ErrAlreadyCheckedIn):
+		return fmt.Errorf("приход уже отмечен")
+	case errors.Is(err, service.ErrNotCheckedIn):
+		return fmt.Errorf("сначала нужно отметить приход")
+	default:
+		log.Printf("attendance action failed: %v", err)
+		return fmt.Errorf("не удалось сохранить отметку, попробуйте позже")
+	}
+}
@@ -2,13 +2,17 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"maxBot/internal/di"
 	"maxBot/internal/fsm"
 	"maxBot/internal/model"
+	"maxBot/internal/service"
 	"slices"
 	"strconv"
 
+	"github.com/jackc/pgx/v5"
 	maxbot "github.com/rectid/max-bot-api-client-go"
 	"github.com/rectid/max-bot-api-client-go/schemes"
 )
@@ -35,6 +39,8 @@ func (h *EventHandler) EnterState(ctx context.Context, update schemes.UpdateInte
 	if err != nil {
 		return fmt.Errorf("failed to get event: %w", err)
 	}
+	// from запоминает экран, с которого открыли событие, чтобы «Назад» вёл туда же
+	from := params["from"]
 
 	user, err := h.services.UserService.GetUserByID(ctx, update.GetUserID())
 	if err != nil {
@@ -50,40 +56,12 @@ func (h *EventHandler) EnterState(ctx context.Context, update schemes.UpdateInte
 		}
 	}
 
-	// Handle actions
-	if action := params["action"]; action != "" {
-		switch action {
-		case "apply":
-			if user.Role != "volunteer" {
-				return fmt.Errorf("only volunteers can apply")
-			}
-			if application != nil {
-				return fmt.Errorf("you have already applied")
-			}
-			_, err := h.services.ApplicationService.CreateVolunteerApplication(ctx, event.ID, user.ID)
-			if err != nil {
-				return fmt.Errorf("failed to apply: %w", err)
-			}
-			// Send success message
-			h.services.API.Messages.Send(ctx, maxbot.NewMessage().
-				SetUser(update.GetUserID()).
-				SetText("Вы успешно подали заявку на участие в событии!"))
-			// Re-fetch application
-			app, _ := h.services.ApplicationService.GetVolunteerApplication(ctx, &event.ID, &user.ID)
-			application = &app
-		case "cancel":
-			if application == nil {
-				return fmt.Errorf("you have not applied")
-			}
-			err := h.services.ApplicationService.DeleteVolunteerApplication(ctx, application.ID)
-			if err != nil {
-				return fmt.Errorf("failed to cancel application: %w", err)
-			}
-			// Send success message
-			h.services.API.Messages.Send(ctx, maxbot.NewMessage().
-				SetUser(update.GetUserID()).
-				SetText("Заявка отменена."))
-			application = nil
+	// Отметка доступна только участникам события
+	var participant *model.EventParticipant
+	if application != nil && application.Status != nil && *application.Status == "approved" {
+		ep, err := h.services.ParticipantService.GetEventParticipant(ctx, &event.ID, &user.ID)
+		if err == nil {
+			participant = &ep
 		}
 	}
 
@@ -92,7 +70,7 @@ func (h *EventHandler) EnterState(ctx context.Context, update schemes.UpdateInte
 	if event.Description != nil {
 		text += fmt.Sprintf("Описание: %s\n\n", *event.Description)
 	}
-	text += fmt.Sprintf("Дата: %s\n", event.Date.Format("02.01.2006 15:04"))
+	text += fmt.Sprintf("Дата: %s\n", event.Date.Local().Format("02.01.2006 15:04"))
 	if event.DurationHours != nil {
 		text += fmt.Sprintf("Длительность: %d часов\n", *event.DurationHours)
 	}
@@ -105,31 +83,60 @@ func (h *EventHandler) EnterState(ctx context.Context, update schemes.UpdateInte
 		text += fmt.Sprintf("Контакты: %s\n", *event.Contacts)
 	}
 	if event.Status != nil {
-		text += fmt.Sprintf("Статус: %s\n", *event.Status)
+		text += fmt.Sprintf("Статус: %s\n", translateEventStatus(*event.Status))
+	}
+	if application != nil && application.Status != nil && *application.Status == "waitlisted" {
+		position, err := h.services.ApplicationService.GetWaitlistPosition(ctx, event.ID, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get waitlist position: %w", err)
+		}
+		text += fmt.Sprintf("\nВы в листе ожидания, ваша позиция: %d\n", position)
+	}
+	if participant != nil {
+		text += fmt.Sprintf("\nВаша отметка: %s\n", formatAttendance(*participant))
+	}
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
 	}
 
 	keyboard := &maxbot.Keyboard{}
 
 	if user.Role == "volunteer" {
-		if application == nil {
-			applyPayload := EncodePayload(fsm.Loop, map[string]string{"id": idStr, "action": "apply"})
+		switch {
+		case application == nil && service.CanJoinWaitlist(event):
+			waitlistPayload := EncodePayload(fsm.Loop, map[string]string{"id": idStr, "action": "waitlist", "from": from})
+			keyboard.AddRow().AddCallback("Встать в лист ожидания", schemes.DEFAULT, waitlistPayload)
+		case application == nil && service.CanApplyToEvent(event):
+			// Анкету и сопроводительное сообщение заполняют на отдельном экране.
+			applyPayload := EncodePayload(fsm.EventToApplicationQuestionnaire, map[string]string{"id": idStr, "from": from})
 			keyboard.AddRow().AddCallback("Подать заявку", schemes.DEFAULT, applyPayload)
-		} else {
-			cancelPayload := EncodePayload(fsm.Loop, map[string]string{"id": idStr, "action": "cancel"})
+		case application.Status != nil && *application.Status == "waitlisted":
+			cancelPayload := EncodePayload(fsm.Loop, map[string]string{"id": idStr, "action": "cancel", "from": from})
+			keyboard.AddRow().AddCallback("Покинуть лист ожидания", schemes.DEFAULT, cancelPayload)
+		case service.CanWithdrawApplication(application.Status, event.Status):
+			cancelPayload := EncodePayload(fsm.Loop, map[string]string{"id": idStr, "action": "cancel", "from": from})
 			keyboard.AddRow().AddCallback("Отменить заявку", schemes.DEFAULT, cancelPayload)
 		}
 	}
 
+	if participant != nil && !isEventClosed(event) {
+		switch {
+		case participant.CheckedInAt == nil:
+			keyboard.AddRow().AddCallback("Отметиться на событии", schemes.POSITIVE, EncodePayload(fsm.EventToCheckIn, map[string]string{"id": idStr}))
+		case participant.CheckedOutAt == nil:
+			checkoutPayload := EncodePayload(fsm.Loop, map[string]string{"id": idStr, "action": "checkout", "from": from})
+			keyboard.AddRow().AddCallback("Отметить уход", schemes.DEFAULT, checkoutPayload)
+		}
+	}
+
 	backPayload := EncodePayload(fsm.EventToEvents, map[string]string{"page": "1"})
+	if from == "applications" {
+		backPayload = EncodePayload(fsm.EventToApplications, nil)
+	}
 	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, backPayload)
 
-	msg := maxbot.NewMessage().
-		SetUser(update.GetUserID()).
-		SetText(text).
-		AddKeyboard(keyboard)
-
-	err = h.services.API.Messages.EditMessage(ctx, update.(*schemes.MessageCallbackUpdate).Message.Body.Mid, msg)
-	return err
+	// После отметки по геолокации или коду экран открывается из обычного сообщения, а не из callback.
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
 }
 
 func (h *EventHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
@@ -137,10 +144,21 @@ func (h *EventHandler) LeaveState(ctx context.Context, update schemes.UpdateInte
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if event == fsm.Loop {
-			return fsm.Loop, params, nil
+			if params["action"] == "" {
+				return fsm.Loop, params, nil
+			}
+			// Действие выполняется здесь, а не в EnterState: ошибку роутер отправит пользователю
+			// и заново покажет экран события.
+			screen := map[string]string{"id": params["id"], "from": params["from"]}
+			notice, err := h.runAction(ctx, update.GetUserID(), params)
+			if err != nil {
+				return fsm.Error, screen, err
+			}
+			screen["notice"] = notice
+			return fsm.Loop, screen, nil
 		}
 		if !slices.Contains(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("неверный ответ, воспользуйтесь кнопками")
@@ -149,3 +167,68 @@ func (h *EventHandler) LeaveState(ctx context.Context, update schemes.UpdateInte
 	}
 	return fsm.Error, nil, fmt.Errorf("неверный ответ")
 }
+
+// runAction выполняет действие волонтёра с экрана события и возвращает уведомление об успехе.
+// Ошибки уже переведены в сообщения для пользователя.
+func (h *EventHandler) runAction(ctx context.Context, userID int64, params map[string]string) (string, error) {
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		return "", fmt.Errorf("неверный ID события")
+	}
+	eventID := int32(id)
+	user, err := h.services.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("get user %d: %v", userID, err)
+		return "", fmt.Errorf("не удалось выполнить действие, попробуйте позже")
+	}
+	if user.Role != "volunteer" {
+		return "", fmt.Errorf("действие доступно только волонтёрам")
+	}
+
+	switch params["action"] {
+	case "waitlist":
+		if _, err := h.services.ReviewService.JoinWaitlist(ctx, eventID, userID); err != nil {
+			return "", waitlistError(err)
+		}
+		return "Вы в листе ожидания. Как только освободится место, мы переведём вас в участники и пришлём уведомление.", nil
+	case "cancel":
+		application, err := h.services.ApplicationService.GetVolunteerApplication(ctx, &eventID, &userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("вы не подавали заявку на это событие")
+		}
+		if err != nil {
+			return "", withdrawError(err)
+		}
+		if err := h.services.ReviewService.WithdrawApplication(ctx, application.ID, userID); err != nil {
+			return "", withdrawError(err)
+		}
+		return "Заявка отменена.", nil
+	case "checkout":
+		if _, err := h.services.AttendanceService.CheckOut(ctx, eventID, userID); err != nil {
+			return "", attendanceError(err)
+		}
+		return "Уход отмечен, спасибо за помощь!", nil
+	default:
+		return "", fmt.Errorf("неизвестное действие")
+	}
+}
+
+// isEventClosed сообщает, что событие завершено или отменено и отмечаться на нём нельзя.
+func isEventClosed(event model.Event) bool {
+	return event.Status != nil && (*event.Status == model.EventStatusCompleted || *event.Status == model.EventStatusCancelled)
+}
+
+// waitlistError переводит ошибки записи в лист ожидания в сообщения для волонтёра.
+func waitlistError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrEventHasFreeSlots):
+		return fmt.Errorf("на событии есть свободные места, подайте обычную заявку")
+	case errors.Is(err, service.ErrAlreadyApplied):
+		return fmt.Errorf("вы уже подали заявку на это событие")
+	case errors.Is(err, service.ErrEventNotOpen):
+		return fmt.Errorf("событие уже началось, завершено или отменено")
+	default:
+		log.Printf("join waitlist failed: %v", err)
+		return fmt.Errorf("не удалось встать в лист ожидания, попробуйте позже")
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import "strings"
+
+var eventStatusTranslations = map[string]string{
+	"open":        "Идёт набор",
+	"full":        "Набор закрыт, мест нет",
+	"in_progress": "Проходит сейчас",
+	"completed":   "Завершено",
+	"cancelled":   "Отменено",
+}
+
+func translateEventStatus(status string) string {
+	if status == "" {
+		return "Неизвестно"
+	}
+	if translated, ok := eventStatusTranslations[strings.ToLower(status)]; ok {
+		return translated
+	}
+	return capitalize(status)
+}
@@ -2,10 +2,13 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"maxBot/internal/di"
 	"maxBot/internal/fsm"
 	"maxBot/internal/model"
+	"maxBot/internal/service"
 	"slices"
 	"strconv"
 
@@ -30,40 +33,69 @@ func (h *EventsHandler) EnterState(ctx context.Context, update schemes.UpdateInt
 	}
 
 	limit := int32(8)
-	offset := int32(page-1) * limit
+	userID := update.GetUserID()
+
+	// Курсоры страниц храним в параметрах состояния: cursors[i] открывает страницу i+1.
+	// Если их потеряли, листание начинается заново с первой страницы.
+	var cursors []string
+	if page > 1 {
+		if saved, err := h.services.UserService.GetUserStateParams(ctx, userID); err == nil {
+			_ = json.Unmarshal([]byte(saved["cursors"]), &cursors)
+		}
+		if page > len(cursors) {
+			page, cursors = 1, nil
+		}
+	}
 
 	// Get volunteer's category filter
 	var categoryIDs []int32
-	if user, err := h.services.UserService.GetUserByID(ctx, update.GetUserID()); err == nil && user.Role == "volunteer" {
+	if user, err := h.services.UserService.GetUserByID(ctx, userID); err == nil && user.Role == "volunteer" {
 		if volunteer, err := h.services.VolunteerService.GetVolunteer(ctx, user.ID); err == nil {
 			categoryIDs = volunteer.CategoryIDs
 		}
 	}
 
-	var events []model.Event
-	var count int64
+	cursor := ""
+	if page > 1 {
+		cursor = cursors[page-1]
+	} else {
+		cursors = []string{""}
+	}
+
+	var events service.Page[model.Event]
 	if len(categoryIDs) > 0 {
-		events, _ = h.services.EventService.ListAvailableEventsForVolunteerWithCategories(ctx, update.GetUserID(), categoryIDs, limit, offset)
-		count, err = h.services.EventService.CountAvailableEventsForVolunteerWithCategories(ctx, update.GetUserID(), categoryIDs)
+		events, err = h.services.EventService.ListAvailableEventsForVolunteerWithCategories(ctx, userID, categoryIDs, limit, cursor)
 	} else {
-		events, _ = h.services.EventService.ListAvailableEventsForVolunteer(ctx, update.GetUserID(), limit, offset)
-		count, err = h.services.EventService.CountAvailableEventsForVolunteer(ctx, update.GetUserID())
+		events, err = h.services.EventService.ListAvailableEventsForVolunteer(ctx, userID, limit, cursor)
+	}
+	// Курсор мог устареть вместе с фильтром категорий — начинаем с первой страницы.
+	if errors.Is(err, service.ErrInvalidCursor) {
+		return h.EnterState(ctx, update, transition, map[string]string{"page": "1"})
 	}
 	if err != nil {
 		return err
 	}
 
-	for _, event := range events {
+	if events.NextCursor != "" {
+		cursors = append(cursors[:page], events.NextCursor)
+	}
+	rawCursors, err := json.Marshal(cursors)
+	if err != nil {
+		return err
+	}
+	if err := h.services.UserService.SetUserStateParams(ctx, userID, map[string]string{"cursors": string(rawCursors)}); err != nil {
+		return err
+	}
+
+	for _, event := range events.Items {
 		id := strconv.Itoa(int(event.ID))
 		eventPayload := EncodePayload(fsm.EventsToEvent, map[string]string{"id": id})
 		keyboard.AddRow().AddCallback(event.Title, schemes.DEFAULT, eventPayload)
 	}
 
 	keyboard.AddRow().
-		AddCallback("Фильтр категорий", schemes.DEFAULT, fsm.EventsToCategoriesFilter.String()).
-		AddCallback("Фильтр геолокации", schemes.DEFAULT, fsm.EventsToGeoFilter.String())
-
-	totalPages := int((count + int64(limit) - 1) / int64(limit))
+		AddCallback("Фильтр категорий", schemes.DEFAULT, EncodePayload(fsm.EventsToCategoriesFilter, nil)).
+		AddCallback("Фильтр геолокации", schemes.DEFAULT, EncodePayload(fsm.EventsToGeoFilter, nil))
 
 	row := keyboard.AddRow()
 
@@ -73,9 +105,9 @@ func (h *EventsHandler) EnterState(ctx context.Context, update schemes.UpdateInt
 		row.AddCallback("<<", schemes.DEFAULT, previousPayload)
 	}
 
-	row.AddCallback(params["page"], schemes.DEFAULT, fsm.Loop.String())
+	row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, nil))
 
-	if page < totalPages {
+	if events.NextCursor != "" {
 		nextPageStr := strconv.Itoa(page + 1)
 		nextPayload := EncodePayload(fsm.Loop, map[string]string{"page": nextPageStr})
 		row.AddCallback(">>", schemes.DEFAULT, nextPayload)
@@ -84,16 +116,16 @@ func (h *EventsHandler) EnterState(ctx context.Context, update schemes.UpdateInt
 	// Добавляем кнопку "Назад" в зависимости от того, откуда пришли
 	switch transition {
 	case fsm.PersonalEventsToEvents:
-		keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, fsm.EventsToPersonalEvents.String())
+		keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, EncodePayload(fsm.EventsToPersonalEvents, nil))
 	case fsm.CategoriesFilterToEvents, fsm.GeoFilterToEvents:
 		// Возвращаемся на ту же страницу Events, показывая кнопки фильтров выше
 		// Не добавляем дополнительную кнопку "Назад"
 	case fsm.MainMenuToEvents:
-		keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, fsm.EventsToMainMenu.String())
+		keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, EncodePayload(fsm.EventsToMainMenu, nil))
 	}
 
 	msg := maxbot.NewMessage().
-		SetUser(update.GetUserID()).
+		SetUser(userID).
 		SetText("События:").
 		AddKeyboard(keyboard)
 
@@ -107,7 +139,7 @@ func (h *EventsHandler) LeaveState(ctx context.Context, update schemes.UpdateInt
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if event == fsm.Loop {
 			return fsm.Loop, params, nil
@@ -23,7 +23,7 @@ func (h *GeoFilterHandler) EnterState(ctx context.Context, update schemes.Update
 	keyboard := &maxbot.Keyboard{}
 	eventsPayload := EncodePayload(fsm.GeoFilterToEvents, map[string]string{"page": "1"})
 	keyboard.AddRow().AddGeolocation("Отправить геолокацию", true)
-	keyboard.AddRow().AddCallback("Изменить радиус поиска", schemes.DEFAULT, fsm.GeoFilterToEditGeoFilter.String())
+	keyboard.AddRow().AddCallback("Изменить радиус поиска", schemes.DEFAULT, EncodePayload(fsm.GeoFilterToEditGeoFilter, nil))
 	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, eventsPayload)
 
 	vol, err := h.services.VolunteerService.GetVolunteer(ctx, update.GetUserID())
@@ -57,7 +57,7 @@ func (h *GeoFilterHandler) LeaveState(ctx context.Context, update schemes.Update
 		// Обработка callback кнопок
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !containsTransition(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("действие недоступно")
@@ -22,19 +22,32 @@ func NewMainMenuHandler(services *di.Services) *MainMenuHandler {
 func (h *MainMenuHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
 	keyboard := &maxbot.Keyboard{}
 
-	keyboard.AddRow().AddCallback("Мои события", schemes.DEFAULT, fsm.MainMenuToEvents.String())
+	keyboard.AddRow().AddCallback("Мои события", schemes.DEFAULT, EncodePayload(fsm.MainMenuToPersonalEvents, nil))
 
 	events := EncodePayload(fsm.MainMenuToEvents, map[string]string{"page": "1"})
 	keyboard.AddRow().AddCallback("События", schemes.DEFAULT, events)
 
-	keyboard.AddRow().AddCallback("Заявки", schemes.DEFAULT, fsm.MainMenuToApplications.String())
-	keyboard.AddRow().AddCallback("О себе", schemes.DEFAULT, fsm.MainMenuToAbout.String())
-	keyboard.AddRow().AddCallback("Верификация", schemes.DEFAULT, fsm.MainMenuToVerifications.String())
-	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, fsm.MainMenuToSelectRole.String())
+	keyboard.AddRow().AddCallback("Заявки", schemes.DEFAULT, EncodePayload(fsm.MainMenuToApplications, nil))
+	keyboard.AddRow().AddCallback("О себе", schemes.DEFAULT, EncodePayload(fsm.MainMenuToAbout, nil))
+	keyboard.AddRow().AddCallback("Верификация", schemes.DEFAULT, EncodePayload(fsm.MainMenuToVerifications, nil))
+	if isOrganizer(ctx, h.services, update.GetUserID()) {
+		keyboard.AddRow().AddCallback("Создать событие", schemes.POSITIVE, EncodePayload(fsm.MainMenuToCreateEvent, nil))
+		keyboard.AddRow().AddCallback("Заявки волонтёров", schemes.DEFAULT, EncodePayload(fsm.MainMenuToReviewEvents, nil))
+		keyboard.AddRow().AddCallback("Отметка участников", schemes.DEFAULT, EncodePayload(fsm.MainMenuToOrganizerEvents, nil))
+	}
+	if isAdmin(ctx, h.services, update.GetUserID()) {
+		keyboard.AddRow().AddCallback("Проверка организаций", schemes.DEFAULT, EncodePayload(fsm.MainMenuToAdminVerifications, nil))
+	}
+	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, EncodePayload(fsm.MainMenuToSelectRole, nil))
+
+	text := "Главное меню:"
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
 
 	msg := maxbot.NewMessage().
 		SetUser(update.GetUserID()).
-		SetText("Главное меню:").
+		SetText(text).
 		AddKeyboard(keyboard)
 
 	err := h.services.API.Messages.EditMessage(ctx, update.(*schemes.MessageCallbackUpdate).Message.Body.Mid, msg)
@@ -46,11 +59,23 @@ func (h *MainMenuHandler) LeaveState(ctx context.Context, update schemes.UpdateI
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !slices.Contains(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("неверный ответ, воспользуйтесь кнопками")
 		}
+		if event == fsm.MainMenuToCreateEvent && !isOrganizer(ctx, h.services, update.GetUserID()) {
+			return fsm.Error, nil, fmt.Errorf("создавать события могут только организаторы")
+		}
+		if event == fsm.MainMenuToReviewEvents && !isOrganizer(ctx, h.services, update.GetUserID()) {
+			return fsm.Error, nil, fmt.Errorf("рассматривать заявки могут только организаторы")
+		}
+		if event == fsm.MainMenuToOrganizerEvents && !isOrganizer(ctx, h.services, update.GetUserID()) {
+			return fsm.Error, nil, fmt.Errorf("отмечать участников могут только организаторы")
+		}
+		if event == fsm.MainMenuToAdminVerifications && !isAdmin(ctx, h.services, update.GetUserID()) {
+			return fsm.Error, nil, fmt.Errorf("раздел доступен только администраторам")
+		}
 		return event, params, nil
 	}
 	return fsm.Error, nil, fmt.Errorf("неверный ответ")
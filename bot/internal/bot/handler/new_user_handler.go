@@ -42,7 +42,7 @@ func (h *NewUserHandler) LeaveState(ctx context.Context, update schemes.UpdateIn
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неправильный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		return event, params, nil
 	}
@@ -1,35 +1,92 @@
 package handler
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"maxBot/internal/fsm"
 )
 
-// EncodePayload кодирует transition и параметры в строку формата "<transition>?<params>"
-// Например: "5?user_id=123&role=organizer"
-func EncodePayload(transition fsm.Transition, params map[string]string) string {
-	transitionStr := transition.String()
+const (
+	// DefaultPayloadTTL — сколько действует кнопка, если срок не задан в настройках.
+	DefaultPayloadTTL = 24 * time.Hour
+	// payloadMACSize — длина усечённой подписи HMAC-SHA256 в байтах.
+	payloadMACSize = 16
+)
 
-	if len(params) == 0 {
-		return transitionStr
+var (
+	// ErrPayloadSignature payload подделан или подписан другим ключом.
+	ErrPayloadSignature = errors.New("invalid payload signature")
+	// ErrPayloadExpired срок действия кнопки истёк.
+	ErrPayloadExpired = errors.New("payload expired")
+)
+
+// payloadSigner подписывает callback-payload, чтобы клиент не мог подставить свои параметры.
+type payloadSigner struct {
+	mu  sync.RWMutex
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// signer использует случайный ключ, пока бот не задаст постоянный через ConfigurePayloads:
+// со случайным ключом кнопки не переживают рестарт и не принимаются другими экземплярами.
+var signer = newPayloadSigner()
+
+func newPayloadSigner() *payloadSigner {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("generate payload key: %v", err))
 	}
+	return &payloadSigner{key: key, ttl: DefaultPayloadTTL, now: time.Now}
+}
 
-	values := url.Values{}
-	for key, value := range params {
-		values.Add(key, value)
+// ConfigurePayloads задаёт ключ подписи и срок действия кнопок. Пустой secret оставляет случайный ключ.
+func ConfigurePayloads(secret string, ttl time.Duration) {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+	if secret != "" {
+		key := sha256.Sum256([]byte(secret))
+		signer.key = key[:]
+	}
+	if ttl > 0 {
+		signer.ttl = ttl
 	}
+}
 
-	return transitionStr + "?" + values.Encode()
+// EncodePayload кодирует transition и параметры в строку формата "<transition>?<params>.<expires>.<mac>"
+// Например: "5?user_id=123&role=organizer.t3k2xq.<подпись>"
+func EncodePayload(transition fsm.Transition, params map[string]string) string {
+	body := transition.String()
+	if len(params) > 0 {
+		values := url.Values{}
+		for key, value := range params {
+			values.Add(key, value)
+		}
+		body += "?" + values.Encode()
+	}
+	return signer.sign(body)
 }
 
-// DecodePayload декодирует строку формата "<transition>?<params>" в transition и параметры
+// DecodePayload проверяет подпись и срок действия payload и декодирует его в transition и параметры
 // Возвращает Transition, map параметров и ошибку, если декодирование не удалось
 func DecodePayload(payload string) (fsm.Transition, map[string]string, error) {
+	body, err := signer.verify(payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	// Разделяем на transition и параметры
-	parts := strings.SplitN(payload, "?", 2)
+	parts := strings.SplitN(body, "?", 2)
 
 	// Парсим transition
 	transition, err := fsm.ParseTransition(parts[0])
@@ -58,3 +115,56 @@ func DecodePayload(payload string) (fsm.Transition, map[string]string, error) {
 
 	return transition, params, nil
 }
+
+func (s *payloadSigner) sign(body string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	signed := body + "." + strconv.FormatInt(s.now().Add(s.ttl).Unix(), 36)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(s.mac(signed))
+}
+
+// verify возвращает тело payload без срока и подписи. Точка в теле допустима:
+// срок и подпись отделяются по двум последним точкам.
+func (s *payloadSigner) verify(payload string) (string, error) {
+	i := strings.LastIndexByte(payload, '.')
+	if i < 0 {
+		return "", ErrPayloadSignature
+	}
+	signed, sig := payload[:i], payload[i+1:]
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", ErrPayloadSignature
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !hmac.Equal(mac, s.mac(signed)) {
+		return "", ErrPayloadSignature
+	}
+	j := strings.LastIndexByte(signed, '.')
+	if j < 0 {
+		return "", ErrPayloadSignature
+	}
+	expires, err := strconv.ParseInt(signed[j+1:], 36, 64)
+	if err != nil {
+		return "", ErrPayloadSignature
+	}
+	if s.now().Unix() > expires {
+		return "", ErrPayloadExpired
+	}
+	return signed[:j], nil
+}
+
+func (s *payloadSigner) mac(signed string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(signed))
+	return h.Sum(nil)[:payloadMACSize]
+}
+
+// callbackError переводит ошибку DecodePayload в сообщение для пользователя.
+func callbackError(err error) error {
+	if errors.Is(err, ErrPayloadExpired) {
+		return fmt.Errorf("кнопка устарела, воспользуйтесь новым сообщением")
+	}
+	return fmt.Errorf("неверный callback")
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"maxBot/internal/fsm"
+)
+
+func TestPayloadRoundTrip(t *testing.T) {
+	payload := EncodePayload(fsm.SelectRoleToMainMenu, map[string]string{"role": "volunteer", "note": "a.b?c=d"})
+
+	transition, params, err := DecodePayload(payload)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if transition != fsm.SelectRoleToMainMenu || params["role"] != "volunteer" || params["note"] != "a.b?c=d" {
+		t.Fatalf("unexpected payload %v %v", transition, params)
+	}
+
+	if _, params, err := DecodePayload(EncodePayload(fsm.Loop, nil)); err != nil || len(params) != 0 {
+		t.Fatalf("payload without params: %v %v", params, err)
+	}
+}
+
+func TestPayloadRejectsTampering(t *testing.T) {
+	payload := EncodePayload(fsm.SelectRoleToMainMenu, map[string]string{"role": "volunteer"})
+	forged := strings.Replace(payload, "role=volunteer", "role=admin", 1)
+
+	cases := map[string]string{
+		"forged params": forged,
+		"unsigned":      fsm.SelectRoleToMainMenu.String(),
+		"raw":           fsm.SelectRoleToMainMenu.String() + "?role=admin",
+		"bad mac":       payload[:len(payload)-2] + "!!",
+	}
+	for name, payload := range cases {
+		if _, _, err := DecodePayload(payload); !errors.Is(err, ErrPayloadSignature) {
+			t.Errorf("%s: got %v, want ErrPayloadSignature", name, err)
+		}
+	}
+}
+
+func TestPayloadExpires(t *testing.T) {
+	now := time.Now()
+	s := newPayloadSigner()
+	s.now = func() time.Time { return now }
+
+	payload := s.sign("5")
+	if _, err := s.verify(payload); err != nil {
+		t.Fatalf("fresh payload: %v", err)
+	}
+	now = now.Add(DefaultPayloadTTL + time.Second)
+	if _, err := s.verify(payload); !errors.Is(err, ErrPayloadExpired) {
+		t.Fatalf("got %v, want ErrPayloadExpired", err)
+	}
+}
@@ -3,9 +3,11 @@ package handler
 import (
 	"context"
 	"fmt"
+	"math"
 	"maxBot/internal/di"
 	"maxBot/internal/fsm"
 	"slices"
+	"strconv"
 
 	maxbot "github.com/rectid/max-bot-api-client-go"
 	"github.com/rectid/max-bot-api-client-go/schemes"
@@ -22,24 +24,34 @@ func NewPersonalEventsHandler(services *di.Services) *PersonalEventsHandler {
 // EnterState обрабатывает вход в состояние (вызывается после перехода)
 func (h *PersonalEventsHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
 	keyboard := &maxbot.Keyboard{}
-	activePayload := EncodePayload(fsm.PersonalEventsToEvents, map[string]string{"filter": "active"})
-	completedPayload := EncodePayload(fsm.Loop, map[string]string{"filter": "completed"})
-	canceledPayload := EncodePayload(fsm.Loop, map[string]string{"filter": "canceled"})
-	rejectedPayload := EncodePayload(fsm.Loop, map[string]string{"filter": "rejected"})
+	activePayload := EncodePayload(fsm.PersonalEventsToApplications, map[string]string{"status": "approved", "scope": applicationScopeActive, "from": "personal"})
+	completedPayload := EncodePayload(fsm.PersonalEventsToApplications, map[string]string{"status": "approved", "scope": applicationScopeCompleted, "from": "personal"})
+	canceledPayload := EncodePayload(fsm.PersonalEventsToApplications, map[string]string{"scope": applicationScopeCancelled, "from": "personal"})
+	rejectedPayload := EncodePayload(fsm.PersonalEventsToApplications, map[string]string{"status": "rejected", "from": "personal"})
 
 	keyboard.AddRow().AddCallback("Активные события", schemes.DEFAULT, activePayload)
 	keyboard.AddRow().AddCallback("Завершенные события", schemes.DEFAULT, completedPayload)
 	keyboard.AddRow().AddCallback("Отмененные события", schemes.DEFAULT, canceledPayload)
 	keyboard.AddRow().AddCallback("Участие отклонено", schemes.DEFAULT, rejectedPayload)
-	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, fsm.PersonalEventsToMainMenu.String())
+	keyboard.AddRow().AddCallback("Назад", schemes.DEFAULT, EncodePayload(fsm.PersonalEventsToMainMenu, nil))
+
+	text := "Мои события:"
+	if hours, err := h.services.AttendanceService.GetVolunteerHours(ctx, update.GetUserID()); err == nil && hours.EventsAttended > 0 {
+		text = fmt.Sprintf("Посещено событий: %d\nОтработано часов: %s\n\n%s", hours.EventsAttended, formatHours(hours.HoursServed), text)
+	}
 
 	msg := maxbot.NewMessage().
 		SetUser(update.GetUserID()).
-		SetText("Мои события:").
+		SetText(text).
 		AddKeyboard(keyboard)
 
-	err := h.services.API.Messages.EditMessage(ctx, update.(*schemes.MessageCallbackUpdate).Message.Body.Mid, msg)
-	return err
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		return h.services.API.Messages.EditMessage(ctx, upd.Message.Body.Mid, msg)
+	default:
+		_, err := h.services.API.Messages.Send(ctx, msg)
+		return err
+	}
 }
 
 // LeaveState проверяет апдейт и возвращает событие для выхода из состояния, параметры и опциональную ошибку
@@ -48,7 +60,7 @@ func (h *PersonalEventsHandler) LeaveState(ctx context.Context, update schemes.U
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !slices.Contains(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("неверный ответ, воспользуйтесь кнопками")
@@ -57,3 +69,8 @@ func (h *PersonalEventsHandler) LeaveState(ctx context.Context, update schemes.U
 	}
 	return fsm.Error, nil, fmt.Errorf("неверный ответ")
 }
+
+// formatHours округляет отработанные часы до десятых.
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(math.Round(hours*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// Редактируемые поля профиля.
+const (
+	profileFieldVolunteerAbout   = "volunteer_about"
+	profileFieldSearchRadius     = "search_radius"
+	profileFieldOrganizationName = "organization_name"
+	profileFieldOrganizerAbout   = "organizer_about"
+)
+
+const (
+	maxProfileAboutLength     = 1000
+	maxOrganizationNameLength = 200
+	minSearchRadiusKm         = 1
+	maxSearchRadiusKm         = 100
+)
+
+// ProfileEditHandler принимает новое значение одного поля профиля.
+type ProfileEditHandler struct {
+	services *di.Services
+}
+
+func NewProfileEditHandler(services *di.Services) *ProfileEditHandler {
+	return &ProfileEditHandler{services: services}
+}
+
+func (h *ProfileEditHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	// Новое значение придёт текстом без payload, поэтому запоминаем редактируемое поле.
+	field := params["field"]
+	if field != "" {
+		if err := h.services.UserService.SetUserStateParams(ctx, update.GetUserID(), map[string]string{"field": field}); err != nil {
+			return err
+		}
+	} else {
+		field = h.savedField(ctx, update.GetUserID())
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	var text string
+	switch field {
+	case profileFieldVolunteerAbout:
+		text = fmt.Sprintf("Расскажите о себе (до %d символов): опыт, навыки, чем хотите помогать. Организаторы увидят это в вашей заявке.", maxProfileAboutLength)
+		keyboard.AddRow().AddCallback("Очистить", schemes.NEGATIVE, EncodePayload(fsm.ProfileEditToAbout, map[string]string{"action": "clear"}))
+	case profileFieldSearchRadius:
+		text = fmt.Sprintf("Введите радиус поиска событий в км (число от %d до %d):", minSearchRadiusKm, maxSearchRadiusKm)
+	case profileFieldOrganizationName:
+		text = fmt.Sprintf("Введите название организации (до %d символов):", maxOrganizationNameLength)
+	case profileFieldOrganizerAbout:
+		text = fmt.Sprintf("Опишите организацию (до %d символов): чем занимаетесь, контакты, ссылки.", maxProfileAboutLength)
+		keyboard.AddRow().AddCallback("Очистить", schemes.NEGATIVE, EncodePayload(fsm.ProfileEditToAbout, map[string]string{"action": "clear"}))
+	default:
+		text = "Поле не выбрано, вернитесь в профиль."
+	}
+	keyboard.AddRow().AddCallback("← Назад", schemes.DEFAULT, EncodePayload(fsm.ProfileEditToAbout, nil))
+
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *ProfileEditHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		value := strings.TrimSpace(upd.Message.Body.Text)
+		if value == "" {
+			return fsm.Error, nil, fmt.Errorf("значение не может быть пустым")
+		}
+		notice, err := h.save(ctx, update.GetUserID(), h.savedField(ctx, update.GetUserID()), &value)
+		if err != nil {
+			return fsm.Error, nil, err
+		}
+		return fsm.ProfileEditToAbout, map[string]string{"notice": notice}, nil
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		if params["action"] == "clear" {
+			notice, err := h.save(ctx, update.GetUserID(), h.savedField(ctx, update.GetUserID()), nil)
+			if err != nil {
+				return fsm.Error, nil, err
+			}
+			return event, map[string]string{"notice": notice}, nil
+		}
+		return event, nil, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите значение текстом или воспользуйтесь кнопками")
+	}
+}
+
+// save проверяет и сохраняет значение поля; nil очищает необязательное поле.
+func (h *ProfileEditHandler) save(ctx context.Context, userID int64, field string, value *string) (string, error) {
+	switch field {
+	case profileFieldVolunteerAbout:
+		if value != nil && utf8.RuneCountInString(*value) > maxProfileAboutLength {
+			return "", fmt.Errorf("текст не должен превышать %d символов", maxProfileAboutLength)
+		}
+		volunteer, err := h.services.VolunteerService.GetVolunteer(ctx, userID)
+		if err != nil {
+			return "", fmt.Errorf("раздел доступен только волонтёрам")
+		}
+		if _, err := h.services.VolunteerService.UpdateVolunteerProfile(ctx, userID, value, volunteer.SearchRadius); err != nil {
+			return "", fmt.Errorf("не удалось сохранить профиль")
+		}
+		return "Профиль обновлён", nil
+	case profileFieldSearchRadius:
+		if value == nil {
+			return "", fmt.Errorf("введите радиус числом")
+		}
+		radius, err := strconv.Atoi(*value)
+		if err != nil {
+			return "", fmt.Errorf("радиус должен быть числом")
+		}
+		if radius < minSearchRadiusKm || radius > maxSearchRadiusKm {
+			return "", fmt.Errorf("радиус должен быть от %d до %d км", minSearchRadiusKm, maxSearchRadiusKm)
+		}
+		if _, err := h.services.VolunteerService.GetVolunteer(ctx, userID); err != nil {
+			return "", fmt.Errorf("раздел доступен только волонтёрам")
+		}
+		radius32 := int32(radius)
+		if _, err := h.services.VolunteerService.UpdateVolunteerSearchRadius(ctx, userID, &radius32); err != nil {
+			return "", fmt.Errorf("не удалось обновить радиус поиска")
+		}
+		return fmt.Sprintf("Радиус поиска обновлён до %d км", radius), nil
+	case profileFieldOrganizationName, profileFieldOrganizerAbout:
+		organizer, err := h.services.OrganizerService.GetOrganizer(ctx, userID)
+		if err != nil {
+			return "", fmt.Errorf("раздел доступен только организаторам")
+		}
+		name, about := organizer.OrganizationName, organizer.About
+		if field == profileFieldOrganizationName {
+			if value == nil {
+				return "", fmt.Errorf("название организации обязательно")
+			}
+			if utf8.RuneCountInString(*value) > maxOrganizationNameLength {
+				return "", fmt.Errorf("название не должно превышать %d символов", maxOrganizationNameLength)
+			}
+			name = *value
+		} else {
+			if value != nil && utf8.RuneCountInString(*value) > maxProfileAboutLength {
+				return "", fmt.Errorf("текст не должен превышать %d символов", maxProfileAboutLength)
+			}
+			about = value
+		}
+		if _, err := h.services.OrganizerService.UpdateOrganizerProfile(ctx, userID, name, about); err != nil {
+			return "", fmt.Errorf("не удалось сохранить профиль организации")
+		}
+		return "Профиль организации обновлён", nil
+	default:
+		return "", fmt.Errorf("поле не выбрано, вернитесь в профиль")
+	}
+}
+
+func (h *ProfileEditHandler) savedField(ctx context.Context, userID int64) string {
+	saved, err := h.services.UserService.GetUserStateParams(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	return saved["field"]
+}
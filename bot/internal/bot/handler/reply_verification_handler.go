@@ -60,7 +60,7 @@ func (h *ReplyVerificationHandler) LeaveState(ctx context.Context, update scheme
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !containsTransition(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("действие недоступно")
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+	"maxBot/internal/model"
+	"maxBot/internal/service"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// ReviewApplicationsHandler показывает заявки волонтёров на событие и позволяет их одобрить или отклонить.
+type ReviewApplicationsHandler struct {
+	services *di.Services
+}
+
+func NewReviewApplicationsHandler(services *di.Services) *ReviewApplicationsHandler {
+	return &ReviewApplicationsHandler{services: services}
+}
+
+func (h *ReviewApplicationsHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	organizerID := update.GetUserID()
+	params = h.withSavedParams(ctx, organizerID, params)
+
+	eventID, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		return h.sendBack(ctx, update, "Событие не выбрано.")
+	}
+	event, err := h.services.EventService.GetEventByID(ctx, int32(eventID))
+	if err != nil || event.OrganizerID == nil || *event.OrganizerID != organizerID {
+		return h.sendBack(ctx, update, "Событие не найдено.")
+	}
+
+	page, err := strconv.Atoi(params["page"])
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := int32(5)
+	offset := int32(page-1) * limit
+
+	count, err := h.services.ApplicationService.CountPendingApplicationsByEvent(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	totalPages := int((count + int64(limit) - 1) / int64(limit))
+	// После одобрения последней заявки на странице возвращаемся на предыдущую.
+	if totalPages > 0 && page > totalPages {
+		page = totalPages
+		offset = int32(page-1) * limit
+	}
+
+	applicants, err := h.services.ApplicationService.ListPendingApplicants(ctx, event.ID, limit, offset)
+	if err != nil {
+		return err
+	}
+	applicationIDs := make([]int32, 0, len(applicants))
+	for _, applicant := range applicants {
+		applicationIDs = append(applicationIDs, applicant.ApplicationID)
+	}
+	answers, err := h.services.EventQuestionService.ListApplicationAnswers(ctx, applicationIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := h.services.UserService.SetUserStateParams(ctx, organizerID, map[string]string{
+		"event_id": strconv.Itoa(int(event.ID)),
+		"page":     strconv.Itoa(page),
+	}); err != nil {
+		log.Printf("failed to save review params for organizer %d: %v", organizerID, err)
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	for i, applicant := range applicants {
+		number := strconv.Itoa(int(offset) + i + 1)
+		applicationID := strconv.Itoa(int(applicant.ApplicationID))
+		approvePayload := EncodePayload(fsm.Loop, map[string]string{
+			"action":         "approve",
+			"application_id": applicationID,
+			"event_id":       strconv.Itoa(int(event.ID)),
+			"page":           strconv.Itoa(page),
+		})
+		rejectPayload := EncodePayload(fsm.ReviewApplicationsToReviewRejectReason, map[string]string{
+			"application_id": applicationID,
+			"event_id":       strconv.Itoa(int(event.ID)),
+		})
+		keyboard.AddRow().
+			AddCallback("✅ "+number, schemes.POSITIVE, approvePayload).
+			AddCallback("❌ "+number, schemes.NEGATIVE, rejectPayload)
+	}
+
+	if totalPages > 1 {
+		row := keyboard.AddRow()
+		if page > 1 {
+			row.AddCallback("<<", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"event_id": strconv.Itoa(int(event.ID)), "page": strconv.Itoa(page - 1)}))
+		}
+		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"event_id": strconv.Itoa(int(event.ID)), "page": strconv.Itoa(page)}))
+		if page < totalPages {
+			row.AddCallback(">>", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"event_id": strconv.Itoa(int(event.ID)), "page": strconv.Itoa(page + 1)}))
+		}
+	}
+	keyboard.AddRow().AddCallback("← К событиям", schemes.NEGATIVE, EncodePayload(fsm.ReviewApplicationsToReviewEvents, nil))
+
+	text := formatReviewApplications(event, applicants, answers, int(offset))
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *ReviewApplicationsHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if event == fsm.Loop {
+			if params["action"] != "approve" {
+				return fsm.Loop, params, nil
+			}
+			applicationID, err := strconv.Atoi(params["application_id"])
+			if err != nil {
+				return fsm.Error, params, fmt.Errorf("неверный ID заявки")
+			}
+			if _, err := h.services.ReviewService.ApproveApplication(ctx, int32(applicationID), update.GetUserID()); err != nil {
+				return fsm.Error, params, reviewError(err)
+			}
+			return fsm.Loop, map[string]string{
+				"event_id": params["event_id"],
+				"page":     params["page"],
+				"notice":   "Заявка одобрена, волонтёр добавлен в участники",
+			}, nil
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
+
+// withSavedParams дополняет параметры значениями, сохранёнными при предыдущем показе экрана.
+func (h *ReviewApplicationsHandler) withSavedParams(ctx context.Context, userID int64, params map[string]string) map[string]string {
+	result := make(map[string]string, len(params))
+	for key, value := range params {
+		result[key] = value
+	}
+	if result["event_id"] != "" {
+		return result
+	}
+	saved, err := h.services.UserService.GetUserStateParams(ctx, userID)
+	if err != nil {
+		return result
+	}
+	result["event_id"] = saved["event_id"]
+	if result["page"] == "" {
+		result["page"] = saved["page"]
+	}
+	return result
+}
+
+func (h *ReviewApplicationsHandler) sendBack(ctx context.Context, update schemes.UpdateInterface, text string) error {
+	keyboard := &maxbot.Keyboard{}
+	keyboard.AddRow().AddCallback("← К событиям", schemes.NEGATIVE, EncodePayload(fsm.ReviewApplicationsToReviewEvents, nil))
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func formatReviewApplications(event model.Event, applicants []model.PendingApplicant, answers map[int32][]model.ApplicationAnswer, offset int) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Событие: %s\nДата: %s\n", event.Title, event.Date.Local().Format("02.01.2006 15:04")))
+	current := int32(0)
+	if event.CurrentVolunteers != nil {
+		current = *event.CurrentVolunteers
+	}
+	builder.WriteString(fmt.Sprintf("Участников: %d из %d\n\n", current, event.MaxVolunteers))
+
+	if len(applicants) == 0 {
+		builder.WriteString("Все заявки рассмотрены.")
+		return builder.String()
+	}
+
+	for i, applicant := range applicants {
+		builder.WriteString(fmt.Sprintf("%d. %s", offset+i+1, applicant.Name))
+		if applicant.Username != nil && *applicant.Username != "" {
+			builder.WriteString(" (@")
+			builder.WriteString(*applicant.Username)
+			builder.WriteString(")")
+		}
+		builder.WriteString(fmt.Sprintf(", заявка от %s\n", applicant.AppliedAt.Local().Format("02.01.2006")))
+		builder.WriteString("   О себе: ")
+		builder.WriteString(valueOrDash(applicant.About))
+		builder.WriteString("\n")
+		if applicant.Message != nil {
+			builder.WriteString("   Сообщение: ")
+			builder.WriteString(*applicant.Message)
+			builder.WriteString("\n")
+		}
+		for _, answer := range answers[applicant.ApplicationID] {
+			builder.WriteString(fmt.Sprintf("   %s — %s\n", answer.QuestionText, formatAnswerValues(answer)))
+		}
+	}
+	builder.WriteString("\n✅ — одобрить, ❌ — отклонить")
+	return builder.String()
+}
+
+// reviewError переводит ошибки рассмотрения заявки в сообщения для организатора.
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrApplicationNotFound):
+		return fmt.Errorf("заявка не найдена")
+	case errors.Is(err, service.ErrApplicationNotPending):
+		return fmt.Errorf("заявка уже рассмотрена")
+	case errors.Is(err, service.ErrNotEventOwner):
+		return fmt.Errorf("действие недоступно")
+	case errors.Is(err, service.ErrEventFull):
+		return fmt.Errorf("на событии не осталось свободных мест")
+	case errors.Is(err, service.ErrEventNotOpen):
+		return fmt.Errorf("событие уже началось, завершено или отменено")
+	default:
+		log.Printf("application review failed: %v", err)
+		return fmt.Errorf("не удалось обработать заявку. Попробуйте позже")
+	}
+}
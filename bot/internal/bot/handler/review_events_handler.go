@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// ReviewEventsHandler показывает организатору его события с заявками на рассмотрении.
+type ReviewEventsHandler struct {
+	services *di.Services
+}
+
+func NewReviewEventsHandler(services *di.Services) *ReviewEventsHandler {
+	return &ReviewEventsHandler{services: services}
+}
+
+func (h *ReviewEventsHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	organizerID := update.GetUserID()
+	if _, err := h.services.OrganizerService.GetOrganizer(ctx, organizerID); err != nil {
+		keyboard := &maxbot.Keyboard{}
+		keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, EncodePayload(fsm.ReviewEventsToMainMenu, nil))
+		return sendOrEditMessage(ctx, h.services, update, "Раздел доступен только организаторам.", keyboard)
+	}
+
+	page, err := strconv.Atoi(params["page"])
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := int32(8)
+	offset := int32(page-1) * limit
+
+	events, err := h.services.EventService.ListOrganizerEventsWithPendingApplications(ctx, organizerID, limit, offset)
+	if err != nil {
+		return err
+	}
+	count, err := h.services.EventService.CountOrganizerEventsWithPendingApplications(ctx, organizerID)
+	if err != nil {
+		return err
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	for _, event := range events {
+		buttonText := fmt.Sprintf("%s (%s) — %d", event.Title, event.Date.Local().Format("02.01"), event.PendingCount)
+		payload := EncodePayload(fsm.ReviewEventsToReviewApplications, map[string]string{
+			"event_id": strconv.Itoa(int(event.ID)),
+		})
+		keyboard.AddRow().AddCallback(buttonText, schemes.DEFAULT, payload)
+	}
+
+	totalPages := int((count + int64(limit) - 1) / int64(limit))
+	if totalPages > 1 {
+		row := keyboard.AddRow()
+		if page > 1 {
+			row.AddCallback("<<", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page - 1)}))
+		}
+		row.AddCallback(strconv.Itoa(page), schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page)}))
+		if page < totalPages {
+			row.AddCallback(">>", schemes.DEFAULT, EncodePayload(fsm.Loop, map[string]string{"page": strconv.Itoa(page + 1)}))
+		}
+	}
+	keyboard.AddRow().AddCallback("← Назад", schemes.NEGATIVE, EncodePayload(fsm.ReviewEventsToMainMenu, nil))
+
+	text := "Выберите событие, чтобы рассмотреть заявки волонтёров.\nВ скобках — дата, после тире — число новых заявок."
+	if count == 0 {
+		text = "Новых заявок нет."
+	}
+	if notice := params["notice"]; notice != "" {
+		text = notice + "\n\n" + text
+	}
+
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *ReviewEventsHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if event == fsm.Loop {
+			return fsm.Loop, params, nil
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		return event, params, nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("воспользуйтесь кнопками")
+	}
+}
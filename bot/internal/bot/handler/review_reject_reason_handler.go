@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"maxBot/internal/di"
+	"maxBot/internal/fsm"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+// ReviewRejectReasonHandler запрашивает у организатора необязательную причину отказа.
+type ReviewRejectReasonHandler struct {
+	services *di.Services
+}
+
+func NewReviewRejectReasonHandler(services *di.Services) *ReviewRejectReasonHandler {
+	return &ReviewRejectReasonHandler{services: services}
+}
+
+func (h *ReviewRejectReasonHandler) EnterState(ctx context.Context, update schemes.UpdateInterface, transition fsm.Transition, params map[string]string) error {
+	// Текстовый ответ придёт без payload, поэтому запоминаем выбранную заявку.
+	if params["application_id"] != "" {
+		if err := h.services.UserService.SetUserStateParams(ctx, update.GetUserID(), map[string]string{
+			"application_id": params["application_id"],
+			"event_id":       params["event_id"],
+		}); err != nil {
+			return err
+		}
+	}
+
+	keyboard := &maxbot.Keyboard{}
+	keyboard.AddRow().AddCallback("Отклонить без причины", schemes.NEGATIVE, EncodePayload(fsm.ReviewRejectReasonToReviewApplications, map[string]string{"action": "reject"}))
+	keyboard.AddRow().AddCallback("← Назад", schemes.DEFAULT, EncodePayload(fsm.ReviewRejectReasonToReviewApplications, nil))
+
+	text := "Напишите причину отказа (до 500 символов) — волонтёр увидит её в своих заявках."
+	return sendOrEditMessage(ctx, h.services, update, text, keyboard)
+}
+
+func (h *ReviewRejectReasonHandler) LeaveState(ctx context.Context, update schemes.UpdateInterface, availableTransitions []string) (fsm.Transition, map[string]string, error) {
+	switch upd := update.(type) {
+	case *schemes.MessageCreatedUpdate:
+		reason := strings.TrimSpace(upd.Message.Body.Text)
+		if reason == "" {
+			return fsm.Error, nil, fmt.Errorf("причина не может быть пустой")
+		}
+		if utf8.RuneCountInString(reason) > 500 {
+			return fsm.Error, nil, fmt.Errorf("причина не должна превышать 500 символов")
+		}
+		return h.reject(ctx, update.GetUserID(), &reason)
+	case *schemes.MessageCallbackUpdate:
+		event, params, err := DecodePayload(upd.Callback.Payload)
+		if err != nil {
+			return fsm.Error, nil, callbackError(err)
+		}
+		if !containsTransition(availableTransitions, event.String()) {
+			return fsm.Error, nil, fmt.Errorf("действие недоступно")
+		}
+		if params["action"] == "reject" {
+			return h.reject(ctx, update.GetUserID(), nil)
+		}
+		return event, h.backParams(ctx, update.GetUserID()), nil
+	default:
+		return fsm.Error, nil, fmt.Errorf("введите причину текстом или воспользуйтесь кнопками")
+	}
+}
+
+func (h *ReviewRejectReasonHandler) reject(ctx context.Context, organizerID int64, reason *string) (fsm.Transition, map[string]string, error) {
+	saved, err := h.services.UserService.GetUserStateParams(ctx, organizerID)
+	if err != nil {
+		return fsm.Error, nil, fmt.Errorf("не удалось загрузить заявку. Попробуйте позже")
+	}
+	applicationID, err := strconv.Atoi(saved["application_id"])
+	if err != nil {
+		return fsm.Error, nil, fmt.Errorf("заявка не выбрана, вернитесь к списку")
+	}
+
+	if _, err := h.services.ReviewService.RejectApplication(ctx, int32(applicationID), organizerID, reason); err != nil {
+		return fsm.Error, nil, reviewError(err)
+	}
+
+	params := h.backParams(ctx, organizerID)
+	params["notice"] = "Заявка отклонена"
+	return fsm.ReviewRejectReasonToReviewApplications, params, nil
+}
+
+// backParams возвращает параметры для возврата к списку заявок события.
+func (h *ReviewRejectReasonHandler) backParams(ctx context.Context, organizerID int64) map[string]string {
+	saved, err := h.services.UserService.GetUserStateParams(ctx, organizerID)
+	if err != nil {
+		log.Printf("failed to load review params for organizer %d: %v", organizerID, err)
+		return map[string]string{}
+	}
+	return map[string]string{"event_id": saved["event_id"]}
+}
@@ -2,11 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 
 	"maxBot/internal/di"
 	"maxBot/internal/fsm"
+	"maxBot/internal/service"
 
 	maxbot "github.com/rectid/max-bot-api-client-go"
 	"github.com/rectid/max-bot-api-client-go/schemes"
@@ -51,7 +53,7 @@ func (h *SelectRoleHandler) LeaveState(ctx context.Context, update schemes.Updat
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !slices.Contains(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("неверный ответ, воспользуйтесь кнопками")
@@ -60,8 +62,14 @@ func (h *SelectRoleHandler) LeaveState(ctx context.Context, update schemes.Updat
 		if role == "" {
 			return fsm.Error, nil, fmt.Errorf("роль не указана в параметрах")
 		}
+		// Права проверяет UserService: роль администратора доступна только при записи в admins.
 		_, err = h.services.UserService.UpdateUserRole(ctx, update.GetUserID(), role)
-		if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotAdmin):
+			return fsm.Error, nil, fmt.Errorf("роль администратора вам недоступна")
+		case errors.Is(err, service.ErrUnknownRole):
+			return fsm.Error, nil, fmt.Errorf("неизвестная роль, воспользуйтесь кнопками")
+		case err != nil:
 			return fsm.Error, nil, fmt.Errorf("failed to update user role: %w", err)
 		}
 		return event, params, nil
@@ -1,5 +1,14 @@
 package handler
 
+import (
+	"context"
+
+	"maxBot/internal/di"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
 func containsTransition(list []string, target string) bool {
 	for _, item := range list {
 		if item == target {
@@ -8,3 +17,34 @@ func containsTransition(list []string, target string) bool {
 	}
 	return false
 }
+
+// sendOrEditMessage редактирует сообщение с кнопками для callback-апдейтов и отправляет новое для текстовых.
+func sendOrEditMessage(ctx context.Context, services *di.Services, update schemes.UpdateInterface, text string, keyboard *maxbot.Keyboard) error {
+	msg := maxbot.NewMessage().
+		SetUser(update.GetUserID()).
+		SetText(text)
+	if keyboard != nil {
+		msg.AddKeyboard(keyboard)
+	}
+
+	switch upd := update.(type) {
+	case *schemes.MessageCallbackUpdate:
+		return services.API.Messages.EditMessage(ctx, upd.Message.Body.Mid, msg)
+	default:
+		_, err := services.API.Messages.Send(ctx, msg)
+		return err
+	}
+}
+
+// extractLocation достаёт координаты из геолокации, приложенной к сообщению.
+func extractLocation(body schemes.MessageBody) (float64, float64, bool) {
+	for _, attachment := range body.Attachments {
+		switch a := attachment.(type) {
+		case *schemes.LocationAttachment:
+			return a.Latitude, a.Longitude, true
+		case schemes.LocationAttachment:
+			return a.Latitude, a.Longitude, true
+		}
+	}
+	return 0, 0, false
+}
@@ -45,9 +45,8 @@ func (h *VerificationHandler) EnterState(ctx context.Context, update schemes.Upd
 		builder.WriteString("\n\n")
 	}
 
-	//status := translateVerificationStatusPtr(or.VerificationStatus)
-	builder.WriteString("Текущий статус: ")
-	//builder.WriteString(status)
+	builder.WriteString("Текущий статус: верификация ")
+	builder.WriteString(formatOrganizerVerified(or))
 	builder.WriteString("\n\n")
 
 	var latest *model.OrganizerVerificationRequest
@@ -62,7 +61,7 @@ func (h *VerificationHandler) EnterState(ctx context.Context, update schemes.Upd
 			builder.WriteString("У вас уже есть заявка на проверке. Вы можете обновить данные через кнопку “Заполнить данные”.\n\n")
 		}
 		builder.WriteString("Последняя заявка от ")
-		builder.WriteString(latest.SubmittedAt.Format("02.01.2006 15:04"))
+		builder.WriteString(latest.SubmittedAt.Local().Format("02.01.2006 15:04"))
 		builder.WriteString("\nСтатус: ")
 		builder.WriteString(translateVerificationStatus(latest.Status))
 		if latest.AdminComment != nil && *latest.AdminComment != "" {
@@ -92,7 +91,7 @@ func (h *VerificationHandler) LeaveState(ctx context.Context, update schemes.Upd
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !containsTransition(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("действие недоступно")
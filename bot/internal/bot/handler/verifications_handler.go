@@ -35,11 +35,9 @@ func (h *VerificationsHandler) EnterState(ctx context.Context, update schemes.Up
 		return h.sendMessage(ctx, update, "Не удалось загрузить историю заявок. Попробуйте позже.", nil)
 	}
 
-	//status := translateVerificationStatusPtr(organizer.VerificationStatus)
-
 	var builder strings.Builder
-	builder.WriteString("Статус организации: ")
-	//builder.WriteString(status)
+	builder.WriteString("Статус организации: верификация ")
+	builder.WriteString(formatOrganizerVerified(organizer))
 	builder.WriteString("\n\n")
 	// if organizer.RejectionReason != nil && *organizer.RejectionReason != "" {
 	// 	builder.WriteString("Причина отклонения: ")
@@ -59,6 +57,7 @@ func (h *VerificationsHandler) EnterState(ctx context.Context, update schemes.Up
 		payloadNew := EncodePayload(fsm.VerificationsToVerification, map[string]string{"action": "new"})
 		keyboard.AddRow().AddCallback("Новая заявка", schemes.POSITIVE, payloadNew)
 	}
+	keyboard.AddRow().AddCallback("← Главное меню", schemes.NEGATIVE, EncodePayload(fsm.VerificationsToMainMenu, nil))
 
 	return h.sendMessage(ctx, update, builder.String(), keyboard)
 }
@@ -68,7 +67,7 @@ func (h *VerificationsHandler) LeaveState(ctx context.Context, update schemes.Up
 	case *schemes.MessageCallbackUpdate:
 		event, params, err := DecodePayload(upd.Callback.Payload)
 		if err != nil {
-			return fsm.Error, nil, fmt.Errorf("неверный callback")
+			return fsm.Error, nil, callbackError(err)
 		}
 		if !containsTransition(availableTransitions, event.String()) {
 			return fsm.Error, nil, fmt.Errorf("действие недоступно")
@@ -97,7 +96,7 @@ func formatVerificationHistory(history []model.OrganizerVerificationRequest) str
 	var builder strings.Builder
 	builder.WriteString("Последние заявки:\n")
 	for i, item := range history {
-		builder.WriteString(fmt.Sprintf("%d. %s — %s\n", i+1, item.SubmittedAt.Format("02.01.2006 15:04"), translateVerificationStatus(item.Status)))
+		builder.WriteString(fmt.Sprintf("%d. %s — %s\n", i+1, item.SubmittedAt.Local().Format("02.01.2006 15:04"), translateVerificationStatus(item.Status)))
 		if item.OrganizerComment != nil && *item.OrganizerComment != "" {
 			builder.WriteString("   ✏️ ")
 			builder.WriteString(*item.OrganizerComment)
@@ -15,18 +15,44 @@ import (
 
 // Router управляет маршрутизацией между FSM и хендлерами
 type Router struct {
-	services              *di.Services
-	handlers              map[fsm.State]handler.Handler
-	emptyHandler          *handler.EmptyHandler
-	newUserHandler        *handler.NewUserHandler
-	selectRoleHandler     *handler.SelectRoleHandler
-	mainMenuHandler       *handler.MainMenuHandler
-	eventsHandler         *handler.EventsHandler
-	eventHandler          *handler.EventHandler
-	personalEventsHandler *handler.PersonalEventsHandler
-	categoryFilterHandler *handler.CategoryFilterHandler
-	geoFilterHandler      *handler.GeoFilterHandler
-	editGeoFilterHandler  *handler.EditGeoFilterHandler
+	services                        *di.Services
+	handlers                        map[fsm.State]handler.Handler
+	emptyHandler                    *handler.EmptyHandler
+	newUserHandler                  *handler.NewUserHandler
+	selectRoleHandler               *handler.SelectRoleHandler
+	mainMenuHandler                 *handler.MainMenuHandler
+	eventsHandler                   *handler.EventsHandler
+	eventHandler                    *handler.EventHandler
+	personalEventsHandler           *handler.PersonalEventsHandler
+	categoryFilterHandler           *handler.CategoryFilterHandler
+	geoFilterHandler                *handler.GeoFilterHandler
+	editGeoFilterHandler            *handler.EditGeoFilterHandler
+	createEventTitleHandler         *handler.CreateEventTitleHandler
+	createEventDescriptionHandler   *handler.CreateEventDescriptionHandler
+	createEventDateHandler          *handler.CreateEventDateHandler
+	createEventDurationHandler      *handler.CreateEventDurationHandler
+	createEventLocationHandler      *handler.CreateEventLocationHandler
+	createEventCategoryHandler      *handler.CreateEventCategoryHandler
+	createEventMaxVolunteersHandler *handler.CreateEventMaxVolunteersHandler
+	createEventContactsHandler      *handler.CreateEventContactsHandler
+	createEventConfirmHandler       *handler.CreateEventConfirmHandler
+	reviewEventsHandler             *handler.ReviewEventsHandler
+	reviewApplicationsHandler       *handler.ReviewApplicationsHandler
+	reviewRejectReasonHandler       *handler.ReviewRejectReasonHandler
+	verificationsHandler            *handler.VerificationsHandler
+	verificationHandler             *handler.VerificationHandler
+	replyVerificationHandler        *handler.ReplyVerificationHandler
+	editVerificationHandler         *handler.EditVerificationHandler
+	adminVerificationsHandler       *handler.AdminVerificationsHandler
+	adminVerificationHandler        *handler.AdminVerificationHandler
+	adminVerificationCommentHandler *handler.AdminVerificationCommentHandler
+	organizerEventsHandler          *handler.OrganizerEventsHandler
+	eventAttendanceHandler          *handler.EventAttendanceHandler
+	checkInHandler                  *handler.CheckInHandler
+	aboutHandler                    *handler.AboutHandler
+	profileEditHandler              *handler.ProfileEditHandler
+	applicationsHandler             *handler.ApplicationsHandler
+	applicationQuestionnaireHandler *handler.ApplicationQuestionnaireHandler
 }
 
 // NewRouter создаёт новый роутер с инициализированными хендлерами
@@ -46,6 +72,32 @@ func NewRouter(services *di.Services) *Router {
 	r.categoryFilterHandler = handler.NewCategoryFilterHandler(services)
 	r.geoFilterHandler = handler.NewGeoFilterHandler(services)
 	r.editGeoFilterHandler = handler.NewEditGeoFilterHandler(services)
+	r.createEventTitleHandler = handler.NewCreateEventTitleHandler(services)
+	r.createEventDescriptionHandler = handler.NewCreateEventDescriptionHandler(services)
+	r.createEventDateHandler = handler.NewCreateEventDateHandler(services)
+	r.createEventDurationHandler = handler.NewCreateEventDurationHandler(services)
+	r.createEventLocationHandler = handler.NewCreateEventLocationHandler(services)
+	r.createEventCategoryHandler = handler.NewCreateEventCategoryHandler(services)
+	r.createEventMaxVolunteersHandler = handler.NewCreateEventMaxVolunteersHandler(services)
+	r.createEventContactsHandler = handler.NewCreateEventContactsHandler(services)
+	r.createEventConfirmHandler = handler.NewCreateEventConfirmHandler(services)
+	r.reviewEventsHandler = handler.NewReviewEventsHandler(services)
+	r.reviewApplicationsHandler = handler.NewReviewApplicationsHandler(services)
+	r.reviewRejectReasonHandler = handler.NewReviewRejectReasonHandler(services)
+	r.verificationsHandler = handler.NewVerificationsHandler(services)
+	r.verificationHandler = handler.NewVerificationHandler(services)
+	r.replyVerificationHandler = handler.NewReplyVerificationHandler(services)
+	r.editVerificationHandler = handler.NewEditVerificationHandler(services)
+	r.adminVerificationsHandler = handler.NewAdminVerificationsHandler(services)
+	r.adminVerificationHandler = handler.NewAdminVerificationHandler(services)
+	r.adminVerificationCommentHandler = handler.NewAdminVerificationCommentHandler(services)
+	r.organizerEventsHandler = handler.NewOrganizerEventsHandler(services)
+	r.eventAttendanceHandler = handler.NewEventAttendanceHandler(services)
+	r.checkInHandler = handler.NewCheckInHandler(services)
+	r.aboutHandler = handler.NewAboutHandler(services)
+	r.profileEditHandler = handler.NewProfileEditHandler(services)
+	r.applicationsHandler = handler.NewApplicationsHandler(services)
+	r.applicationQuestionnaireHandler = handler.NewApplicationQuestionnaireHandler(services)
 
 	r.handlers[fsm.Empty] = r.emptyHandler
 	r.handlers[fsm.NewUser] = r.newUserHandler
@@ -57,6 +109,32 @@ func NewRouter(services *di.Services) *Router {
 	r.handlers[fsm.CategoriesFilter] = r.categoryFilterHandler
 	r.handlers[fsm.GeoFilter] = r.geoFilterHandler
 	r.handlers[fsm.EditGeoFilter] = r.editGeoFilterHandler
+	r.handlers[fsm.CreateEventTitle] = r.createEventTitleHandler
+	r.handlers[fsm.CreateEventDescription] = r.createEventDescriptionHandler
+	r.handlers[fsm.CreateEventDate] = r.createEventDateHandler
+	r.handlers[fsm.CreateEventDuration] = r.createEventDurationHandler
+	r.handlers[fsm.CreateEventLocation] = r.createEventLocationHandler
+	r.handlers[fsm.CreateEventCategory] = r.createEventCategoryHandler
+	r.handlers[fsm.CreateEventMaxVolunteers] = r.createEventMaxVolunteersHandler
+	r.handlers[fsm.CreateEventContacts] = r.createEventContactsHandler
+	r.handlers[fsm.CreateEventConfirm] = r.createEventConfirmHandler
+	r.handlers[fsm.ReviewEvents] = r.reviewEventsHandler
+	r.handlers[fsm.ReviewApplications] = r.reviewApplicationsHandler
+	r.handlers[fsm.ReviewRejectReason] = r.reviewRejectReasonHandler
+	r.handlers[fsm.Verifications] = r.verificationsHandler
+	r.handlers[fsm.Verification] = r.verificationHandler
+	r.handlers[fsm.ReplyVerification] = r.replyVerificationHandler
+	r.handlers[fsm.EditVerification] = r.editVerificationHandler
+	r.handlers[fsm.AdminVerifications] = r.adminVerificationsHandler
+	r.handlers[fsm.AdminVerification] = r.adminVerificationHandler
+	r.handlers[fsm.AdminVerificationComment] = r.adminVerificationCommentHandler
+	r.handlers[fsm.OrganizerEvents] = r.organizerEventsHandler
+	r.handlers[fsm.EventAttendance] = r.eventAttendanceHandler
+	r.handlers[fsm.CheckIn] = r.checkInHandler
+	r.handlers[fsm.About] = r.aboutHandler
+	r.handlers[fsm.ProfileEdit] = r.profileEditHandler
+	r.handlers[fsm.Applications] = r.applicationsHandler
+	r.handlers[fsm.ApplicationQuestionnaire] = r.applicationQuestionnaireHandler
 	return r
 }
 
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+const (
+	webhookSecretHeader = "X-Max-Bot-Api-Secret"
+	webhookMaxBodyBytes = 1 << 20
+)
+
+// webhookUpdateTypes — обновления, которые разбирает handleUpdate; остальные MAX не присылает.
+var webhookUpdateTypes = []string{
+	string(schemes.TypeMessageCreated),
+	string(schemes.TypeMessageCallback),
+	string(schemes.TypeBotStarted),
+}
+
+var errUnsupportedUpdate = errors.New("unsupported update type")
+
+// WebhookHandler принимает обновления от MAX, проверяет секрет подписки и отправляет их
+// в тот же конвейер, что и long polling. Ответ 200 уходит сразу, обработка идёт в фоне.
+func (b *Bot) WebhookHandler() http.Handler {
+	secret := b.cfg.WebhookSecret
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get(webhookSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			log.Printf("webhook: rejected update with invalid secret from %s", r.RemoteAddr)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBodyBytes))
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		update, err := decodeUpdate(body)
+		if errors.Is(err, errUnsupportedUpdate) {
+			// Подтверждаем, чтобы MAX не повторял доставку обновления, которое бот не обрабатывает.
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err != nil {
+			log.Printf("webhook: decode update: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := b.dispatch(update); err != nil {
+			// Бот останавливается: MAX повторит доставку, возможно на другой экземпляр.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// decodeUpdate разбирает тело webhook в конкретный тип обновления по полю update_type.
+// Типы — указатели, как у GetUpdates, чтобы обработчики работали одинаково в обоих режимах.
+func decodeUpdate(data []byte) (schemes.UpdateInterface, error) {
+	var head schemes.Update
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("parse update: %w", err)
+	}
+
+	switch head.UpdateType {
+	case schemes.TypeMessageCreated:
+		upd := &schemes.MessageCreatedUpdate{}
+		if err := json.Unmarshal(data, upd); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", head.UpdateType, err)
+		}
+		if err := decodeAttachments(&upd.Message.Body); err != nil {
+			return nil, err
+		}
+		return upd, nil
+	case schemes.TypeMessageCallback:
+		upd := &schemes.MessageCallbackUpdate{}
+		if err := json.Unmarshal(data, upd); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", head.UpdateType, err)
+		}
+		if upd.Message != nil {
+			if err := decodeAttachments(&upd.Message.Body); err != nil {
+				return nil, err
+			}
+		}
+		return upd, nil
+	case schemes.TypeBotStarted:
+		upd := &schemes.BotStartedUpdate{}
+		if err := json.Unmarshal(data, upd); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", head.UpdateType, err)
+		}
+		return upd, nil
+	default:
+		return nil, errUnsupportedUpdate
+	}
+}
+
+// decodeAttachments заполняет Attachments из сырых вложений. Бот читает только геолокацию,
+// остальные вложения пропускаются.
+func decodeAttachments(body *schemes.MessageBody) error {
+	for _, raw := range body.RawAttachments {
+		var attachment schemes.Attachment
+		if err := json.Unmarshal(raw, &attachment); err != nil {
+			return fmt.Errorf("parse attachment: %w", err)
+		}
+		if attachment.Type != "location" {
+			continue
+		}
+		location := &schemes.LocationAttachment{}
+		if err := json.Unmarshal(raw, location); err != nil {
+			return fmt.Errorf("parse location: %w", err)
+		}
+		body.Attachments = append(body.Attachments, location)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rectid/max-bot-api-client-go/schemes"
+)
+
+const (
+	testWebhookSecret = "webhook-secret"
+
+	messageUpdateJSON = `{
+		"update_type": "message_created",
+		"timestamp": 1700000000000,
+		"message": {
+			"sender": {"user_id": 42, "name": "Иван"},
+			"recipient": {"chat_id": 7, "chat_type": "dialog"},
+			"body": {
+				"mid": "mid.1",
+				"text": "привет",
+				"attachments": [
+					{"type": "image", "payload": {"url": "https://example.com/a.png"}},
+					{"type": "location", "latitude": 55.75, "longitude": 37.62}
+				]
+			}
+		}
+	}`
+	callbackUpdateJSON = `{
+		"update_type": "message_callback",
+		"timestamp": 1700000000000,
+		"callback": {"callback_id": "cb.1", "payload": "5.t3k2xq.sig", "user": {"user_id": 42, "name": "Иван"}},
+		"message": {"sender": {"user_id": 1, "name": "bot", "is_bot": true}, "recipient": {"chat_id": 7}, "body": {"mid": "mid.2"}}
+	}`
+	botStartedUpdateJSON = `{"update_type": "bot_started", "timestamp": 1700000000000, "chat_id": 7, "user": {"user_id": 42, "name": "Иван"}}`
+)
+
+// newWebhookTestBot возвращает бота с закрытой очередью: принятое обновление до обработчиков не доходит,
+// а webhook отвечает 503, как при остановке.
+func newWebhookTestBot() *Bot {
+	d := newDispatcher(1, 0)
+	d.Close()
+	return &Bot{cfg: Config{WebhookSecret: testWebhookSecret}, dispatcher: d}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	cases := map[string]struct {
+		secret string
+		body   string
+		want   int
+	}{
+		"missing secret":       {secret: "", body: messageUpdateJSON, want: http.StatusUnauthorized},
+		"wrong secret":         {secret: "other-secret", body: messageUpdateJSON, want: http.StatusUnauthorized},
+		"unsupported type":     {secret: testWebhookSecret, body: `{"update_type": "message_edited", "timestamp": 1}`, want: http.StatusOK},
+		"malformed json":       {secret: testWebhookSecret, body: `{"update_type":`, want: http.StatusBadRequest},
+		"bad attachment":       {secret: testWebhookSecret, body: `{"update_type": "message_created", "message": {"body": {"attachments": [{"type": "location", "latitude": "north"}]}}}`, want: http.StatusBadRequest},
+		"too large":            {secret: testWebhookSecret, body: `{"update_type": "message_created", "pad": "` + strings.Repeat("x", webhookMaxBodyBytes) + `"}`, want: http.StatusRequestEntityTooLarge},
+		"message on shutdown":  {secret: testWebhookSecret, body: messageUpdateJSON, want: http.StatusServiceUnavailable},
+		"callback on shutdown": {secret: testWebhookSecret, body: callbackUpdateJSON, want: http.StatusServiceUnavailable},
+	}
+	handler := newWebhookTestBot().WebhookHandler()
+	for name, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/bot/webhook", strings.NewReader(tc.body))
+		if tc.secret != "" {
+			req.Header.Set(webhookSecretHeader, tc.secret)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", name, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestDecodeUpdate(t *testing.T) {
+	cases := map[string]struct {
+		body     string
+		wantType schemes.UpdateType
+		wantUser int64
+	}{
+		"message":     {body: messageUpdateJSON, wantType: schemes.TypeMessageCreated, wantUser: 42},
+		"callback":    {body: callbackUpdateJSON, wantType: schemes.TypeMessageCallback, wantUser: 42},
+		"bot started": {body: botStartedUpdateJSON, wantType: schemes.TypeBotStarted, wantUser: 42},
+	}
+	for name, tc := range cases {
+		update, err := decodeUpdate([]byte(tc.body))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if update.GetUpdateType() != tc.wantType || update.GetUserID() != tc.wantUser {
+			t.Errorf("%s: got %s from %d, want %s from %d", name, update.GetUpdateType(), update.GetUserID(), tc.wantType, tc.wantUser)
+		}
+	}
+
+	if _, err := decodeUpdate([]byte(`{"update_type": "chat_title_changed"}`)); !errors.Is(err, errUnsupportedUpdate) {
+		t.Errorf("unsupported type: got %v, want errUnsupportedUpdate", err)
+	}
+	if _, err := decodeUpdate([]byte(`[]`)); err == nil || errors.Is(err, errUnsupportedUpdate) {
+		t.Errorf("malformed body: got %v, want parse error", err)
+	}
+}
+
+func TestDecodeUpdatePayloads(t *testing.T) {
+	update, err := decodeUpdate([]byte(callbackUpdateJSON))
+	if err != nil {
+		t.Fatalf("decode callback: %v", err)
+	}
+	callback, ok := update.(*schemes.MessageCallbackUpdate)
+	if !ok {
+		t.Fatalf("callback decoded as %T", update)
+	}
+	if callback.Callback.Payload != "5.t3k2xq.sig" || callback.Message == nil || callback.Message.Body.Mid != "mid.2" {
+		t.Errorf("unexpected callback %+v", callback)
+	}
+
+	update, err = decodeUpdate([]byte(messageUpdateJSON))
+	if err != nil {
+		t.Fatalf("decode message: %v", err)
+	}
+	message, ok := update.(*schemes.MessageCreatedUpdate)
+	if !ok {
+		t.Fatalf("message decoded as %T", update)
+	}
+	if message.Message.Body.Text != "привет" || message.GetChatID() != 7 {
+		t.Errorf("unexpected message %+v", message.Message)
+	}
+	// Картинка пропускается, геолокация становится LocationAttachment, как у GetUpdates.
+	if len(message.Message.Body.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want only the location", len(message.Message.Body.Attachments))
+	}
+	location, ok := message.Message.Body.Attachments[0].(*schemes.LocationAttachment)
+	if !ok {
+		t.Fatalf("attachment decoded as %T", message.Message.Body.Attachments[0])
+	}
+	if location.Latitude != 55.75 || location.Longitude != 37.62 {
+		t.Errorf("location %v,%v, want 55.75,37.62", location.Latitude, location.Longitude)
+	}
+}
@@ -23,7 +23,7 @@ INSERT INTO event_participants (
     $3,
     COALESCE($4, NOW())
 )
-RETURNING id, event_id, volunteer_id, application_id, joined_chat_at
+RETURNING id, event_id, volunteer_id, application_id, joined_chat_at, checked_in_at, checked_out_at, check_in_method, attendance_marked_by
 `
 
 type AddEventParticipantParams struct {
@@ -47,6 +47,86 @@ func (q *Queries) AddEventParticipant(ctx context.Context, arg AddEventParticipa
 		&i.VolunteerID,
 		&i.ApplicationID,
 		&i.JoinedChatAt,
+		&i.CheckedInAt,
+		&i.CheckedOutAt,
+		&i.CheckInMethod,
+		&i.AttendanceMarkedBy,
+	)
+	return i, err
+}
+
+const checkInParticipant = `-- name: CheckInParticipant :one
+UPDATE event_participants
+SET checked_in_at = NOW(),
+    check_in_method = $1,
+    attendance_marked_by = $2
+WHERE event_id = $3
+  AND volunteer_id = $4
+  AND checked_in_at IS NULL
+RETURNING id, event_id, volunteer_id, application_id, joined_chat_at, checked_in_at, checked_out_at, check_in_method, attendance_marked_by
+`
+
+type CheckInParticipantParams struct {
+	CheckInMethod pgtype.Text `db:"check_in_method" json:"check_in_method"`
+	MarkedBy      pgtype.Int8 `db:"marked_by" json:"marked_by"`
+	EventID       pgtype.Int4 `db:"event_id" json:"event_id"`
+	VolunteerID   pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+}
+
+// Отмечает приход участника; повторная отметка не перезаписывает время.
+func (q *Queries) CheckInParticipant(ctx context.Context, arg CheckInParticipantParams) (EventParticipant, error) {
+	row := q.db.QueryRow(ctx, checkInParticipant,
+		arg.CheckInMethod,
+		arg.MarkedBy,
+		arg.EventID,
+		arg.VolunteerID,
+	)
+	var i EventParticipant
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VolunteerID,
+		&i.ApplicationID,
+		&i.JoinedChatAt,
+		&i.CheckedInAt,
+		&i.CheckedOutAt,
+		&i.CheckInMethod,
+		&i.AttendanceMarkedBy,
+	)
+	return i, err
+}
+
+const checkOutParticipant = `-- name: CheckOutParticipant :one
+UPDATE event_participants
+SET checked_out_at = NOW(),
+    attendance_marked_by = COALESCE($1, attendance_marked_by)
+WHERE event_id = $2
+  AND volunteer_id = $3
+  AND checked_in_at IS NOT NULL
+  AND checked_out_at IS NULL
+RETURNING id, event_id, volunteer_id, application_id, joined_chat_at, checked_in_at, checked_out_at, check_in_method, attendance_marked_by
+`
+
+type CheckOutParticipantParams struct {
+	MarkedBy    pgtype.Int8 `db:"marked_by" json:"marked_by"`
+	EventID     pgtype.Int4 `db:"event_id" json:"event_id"`
+	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+}
+
+// Отмечает уход участника, который ранее отметил приход.
+func (q *Queries) CheckOutParticipant(ctx context.Context, arg CheckOutParticipantParams) (EventParticipant, error) {
+	row := q.db.QueryRow(ctx, checkOutParticipant, arg.MarkedBy, arg.EventID, arg.VolunteerID)
+	var i EventParticipant
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VolunteerID,
+		&i.ApplicationID,
+		&i.JoinedChatAt,
+		&i.CheckedInAt,
+		&i.CheckedOutAt,
+		&i.CheckInMethod,
+		&i.AttendanceMarkedBy,
 	)
 	return i, err
 }
@@ -75,7 +155,7 @@ func (q *Queries) DeleteParticipantsByEvent(ctx context.Context, eventID pgtype.
 }
 
 const getEventParticipant = `-- name: GetEventParticipant :one
-SELECT id, event_id, volunteer_id, application_id, joined_chat_at
+SELECT id, event_id, volunteer_id, application_id, joined_chat_at, checked_in_at, checked_out_at, check_in_method, attendance_marked_by
 FROM event_participants
 WHERE event_id = $1
   AND volunteer_id = $2
@@ -95,12 +175,53 @@ func (q *Queries) GetEventParticipant(ctx context.Context, arg GetEventParticipa
 		&i.VolunteerID,
 		&i.ApplicationID,
 		&i.JoinedChatAt,
+		&i.CheckedInAt,
+		&i.CheckedOutAt,
+		&i.CheckInMethod,
+		&i.AttendanceMarkedBy,
 	)
 	return i, err
 }
 
+const getVolunteerHoursServed = `-- name: GetVolunteerHoursServed :one
+SELECT
+    (COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM (
+        COALESCE(
+            ep.checked_out_at,
+            CASE WHEN e.status = 'completed'
+                THEN e.date + COALESCE(make_interval(hours => e.duration_hours), make_interval(mins => $1::int))
+            END
+        ) - ep.checked_in_at
+    )), 0)), 0) / 3600)::float8 AS hours_served,
+    COUNT(*) AS events_attended
+FROM event_participants ep
+JOIN events e ON e.id = ep.event_id
+WHERE ep.volunteer_id = $2
+  AND ep.checked_in_at IS NOT NULL
+`
+
+type GetVolunteerHoursServedParams struct {
+	DefaultDurationMinutes int32       `db:"default_duration_minutes" json:"default_duration_minutes"`
+	VolunteerID            pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+}
+
+type GetVolunteerHoursServedRow struct {
+	HoursServed    float64 `db:"hours_served" json:"hours_served"`
+	EventsAttended int64   `db:"events_attended" json:"events_attended"`
+}
+
+// Суммирует время участия: от отметки прихода до ухода, а если уход не отмечен
+// и событие завершено — до запланированного окончания события. Для события без длительности
+// окончание считается так же, как в CompletePastEvents: через default_duration_minutes.
+func (q *Queries) GetVolunteerHoursServed(ctx context.Context, arg GetVolunteerHoursServedParams) (GetVolunteerHoursServedRow, error) {
+	row := q.db.QueryRow(ctx, getVolunteerHoursServed, arg.DefaultDurationMinutes, arg.VolunteerID)
+	var i GetVolunteerHoursServedRow
+	err := row.Scan(&i.HoursServed, &i.EventsAttended)
+	return i, err
+}
+
 const listEventParticipants = `-- name: ListEventParticipants :many
-SELECT id, event_id, volunteer_id, application_id, joined_chat_at
+SELECT id, event_id, volunteer_id, application_id, joined_chat_at, checked_in_at, checked_out_at, check_in_method, attendance_marked_by
 FROM event_participants
 WHERE event_id = $1
 ORDER BY joined_chat_at DESC, id DESC
@@ -129,6 +250,10 @@ func (q *Queries) ListEventParticipants(ctx context.Context, arg ListEventPartic
 			&i.VolunteerID,
 			&i.ApplicationID,
 			&i.JoinedChatAt,
+			&i.CheckedInAt,
+			&i.CheckedOutAt,
+			&i.CheckInMethod,
+			&i.AttendanceMarkedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -141,7 +266,7 @@ func (q *Queries) ListEventParticipants(ctx context.Context, arg ListEventPartic
 }
 
 const listEventParticipantsWithUsers = `-- name: ListEventParticipantsWithUsers :many
-SELECT ep.id, ep.event_id, ep.volunteer_id, ep.application_id, ep.joined_chat_at, u.username, u.name, u.state
+SELECT ep.id, ep.event_id, ep.volunteer_id, ep.application_id, ep.joined_chat_at, ep.checked_in_at, ep.checked_out_at, ep.check_in_method, ep.attendance_marked_by, u.username, u.name, u.state
 FROM event_participants ep
 JOIN users u ON u.id = ep.volunteer_id
 WHERE ep.event_id = $1
@@ -157,14 +282,18 @@ type ListEventParticipantsWithUsersParams struct {
 }
 
 type ListEventParticipantsWithUsersRow struct {
-	ID            int32            `db:"id" json:"id"`
-	EventID       pgtype.Int4      `db:"event_id" json:"event_id"`
-	VolunteerID   pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
-	ApplicationID pgtype.Int4      `db:"application_id" json:"application_id"`
-	JoinedChatAt  pgtype.Timestamp `db:"joined_chat_at" json:"joined_chat_at"`
-	Username      pgtype.Text      `db:"username" json:"username"`
-	Name          string           `db:"name" json:"name"`
-	State         string           `db:"state" json:"state"`
+	ID                 int32            `db:"id" json:"id"`
+	EventID            pgtype.Int4      `db:"event_id" json:"event_id"`
+	VolunteerID        pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	ApplicationID      pgtype.Int4      `db:"application_id" json:"application_id"`
+	JoinedChatAt       pgtype.Timestamp `db:"joined_chat_at" json:"joined_chat_at"`
+	CheckedInAt        pgtype.Timestamp `db:"checked_in_at" json:"checked_in_at"`
+	CheckedOutAt       pgtype.Timestamp `db:"checked_out_at" json:"checked_out_at"`
+	CheckInMethod      pgtype.Text      `db:"check_in_method" json:"check_in_method"`
+	AttendanceMarkedBy pgtype.Int8      `db:"attendance_marked_by" json:"attendance_marked_by"`
+	Username           pgtype.Text      `db:"username" json:"username"`
+	Name               string           `db:"name" json:"name"`
+	State              string           `db:"state" json:"state"`
 }
 
 func (q *Queries) ListEventParticipantsWithUsers(ctx context.Context, arg ListEventParticipantsWithUsersParams) ([]ListEventParticipantsWithUsersRow, error) {
@@ -182,6 +311,10 @@ func (q *Queries) ListEventParticipantsWithUsers(ctx context.Context, arg ListEv
 			&i.VolunteerID,
 			&i.ApplicationID,
 			&i.JoinedChatAt,
+			&i.CheckedInAt,
+			&i.CheckedOutAt,
+			&i.CheckInMethod,
+			&i.AttendanceMarkedBy,
 			&i.Username,
 			&i.Name,
 			&i.State,
@@ -197,7 +330,7 @@ func (q *Queries) ListEventParticipantsWithUsers(ctx context.Context, arg ListEv
 }
 
 const listParticipantEvents = `-- name: ListParticipantEvents :many
-SELECT ep.id, ep.event_id, ep.volunteer_id, ep.application_id, ep.joined_chat_at
+SELECT ep.id, ep.event_id, ep.volunteer_id, ep.application_id, ep.joined_chat_at, ep.checked_in_at, ep.checked_out_at, ep.check_in_method, ep.attendance_marked_by
 FROM event_participants ep
 WHERE ep.volunteer_id = $1
 ORDER BY ep.joined_chat_at DESC, ep.id DESC
@@ -226,6 +359,10 @@ func (q *Queries) ListParticipantEvents(ctx context.Context, arg ListParticipant
 			&i.VolunteerID,
 			&i.ApplicationID,
 			&i.JoinedChatAt,
+			&i.CheckedInAt,
+			&i.CheckedOutAt,
+			&i.CheckInMethod,
+			&i.AttendanceMarkedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -252,3 +389,36 @@ func (q *Queries) RemoveEventParticipant(ctx context.Context, arg RemoveEventPar
 	_, err := q.db.Exec(ctx, removeEventParticipant, arg.EventID, arg.VolunteerID)
 	return err
 }
+
+const resetParticipantAttendance = `-- name: ResetParticipantAttendance :one
+UPDATE event_participants
+SET checked_in_at = NULL,
+    checked_out_at = NULL,
+    check_in_method = NULL,
+    attendance_marked_by = NULL
+WHERE event_id = $1
+  AND volunteer_id = $2
+RETURNING id, event_id, volunteer_id, application_id, joined_chat_at, checked_in_at, checked_out_at, check_in_method, attendance_marked_by
+`
+
+type ResetParticipantAttendanceParams struct {
+	EventID     pgtype.Int4 `db:"event_id" json:"event_id"`
+	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+}
+
+func (q *Queries) ResetParticipantAttendance(ctx context.Context, arg ResetParticipantAttendanceParams) (EventParticipant, error) {
+	row := q.db.QueryRow(ctx, resetParticipantAttendance, arg.EventID, arg.VolunteerID)
+	var i EventParticipant
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VolunteerID,
+		&i.ApplicationID,
+		&i.JoinedChatAt,
+		&i.CheckedInAt,
+		&i.CheckedOutAt,
+		&i.CheckInMethod,
+		&i.AttendanceMarkedBy,
+	)
+	return i, err
+}
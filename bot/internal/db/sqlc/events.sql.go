@@ -18,16 +18,18 @@ SET
     cancelled_reason = $1,
     updated_at = NOW()
 WHERE id = $2
+  AND status = $3
 RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 `
 
 type CancelEventParams struct {
-	Reason pgtype.Text `db:"reason" json:"reason"`
-	ID     int32       `db:"id" json:"id"`
+	Reason     pgtype.Text `db:"reason" json:"reason"`
+	ID         int32       `db:"id" json:"id"`
+	FromStatus pgtype.Text `db:"from_status" json:"from_status"`
 }
 
 func (q *Queries) CancelEvent(ctx context.Context, arg CancelEventParams) (Event, error) {
-	row := q.db.QueryRow(ctx, cancelEvent, arg.Reason, arg.ID)
+	row := q.db.QueryRow(ctx, cancelEvent, arg.Reason, arg.ID, arg.FromStatus)
 	var i Event
 	err := row.Scan(
 		&i.ID,
@@ -60,11 +62,17 @@ SET
     completed_at = NOW(),
     updated_at = NOW()
 WHERE id = $1
+  AND status = $2
 RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 `
 
-func (q *Queries) CompleteEvent(ctx context.Context, id int32) (Event, error) {
-	row := q.db.QueryRow(ctx, completeEvent, id)
+type CompleteEventParams struct {
+	ID         int32       `db:"id" json:"id"`
+	FromStatus pgtype.Text `db:"from_status" json:"from_status"`
+}
+
+func (q *Queries) CompleteEvent(ctx context.Context, arg CompleteEventParams) (Event, error) {
+	row := q.db.QueryRow(ctx, completeEvent, arg.ID, arg.FromStatus)
 	var i Event
 	err := row.Scan(
 		&i.ID,
@@ -90,10 +98,62 @@ func (q *Queries) CompleteEvent(ctx context.Context, id int32) (Event, error) {
 	return i, err
 }
 
+const completePastEvents = `-- name: CompletePastEvents :many
+UPDATE events
+SET
+    status = 'completed',
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE status IN ('open', 'full', 'in_progress')
+  AND date + COALESCE(make_interval(hours => duration_hours), make_interval(mins => $1::int)) <= NOW()
+RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
+`
+
+// Завершает события, которые закончились. Если длительность не указана, используется default_duration_minutes.
+func (q *Queries) CompletePastEvents(ctx context.Context, defaultDurationMinutes int32) ([]Event, error) {
+	rows, err := q.db.Query(ctx, completePastEvents, defaultDurationMinutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Chat,
+			&i.Date,
+			&i.DurationHours,
+			&i.Location,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.OrganizerID,
+			&i.Contacts,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.Status,
+			&i.CancelledReason,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countAvailableEventsForVolunteer = `-- name: CountAvailableEventsForVolunteer :one
 SELECT COUNT(*)
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND NOT EXISTS (
     SELECT 1
     FROM volunteer_applications va
@@ -118,7 +178,7 @@ func (q *Queries) CountAvailableEventsForVolunteer(ctx context.Context, voluntee
 const countAvailableEventsForVolunteerWithCategories = `-- name: CountAvailableEventsForVolunteerWithCategories :one
 SELECT COUNT(*)
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND ($1 IS NULL OR array_length($1, 1) = 0 OR e.category_id = ANY($1))
   AND NOT EXISTS (
     SELECT 1
@@ -157,6 +217,177 @@ func (q *Queries) CountEvents(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countEventsByOrganizer = `-- name: CountEventsByOrganizer :one
+SELECT COUNT(*) AS count
+FROM events
+WHERE organizer_id = $1
+`
+
+func (q *Queries) CountEventsByOrganizer(ctx context.Context, organizerID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, countEventsByOrganizer, organizerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countEventsForMap = `-- name: CountEventsForMap :one
+SELECT COUNT(*)
+FROM events e
+WHERE e.status IN ('open', 'full')
+  AND (
+    $1::int[] IS NULL OR
+    e.category_id = ANY($1::int[])
+  )
+  AND earth_box(ll_to_earth($2::float8, $3::float8), $4::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth($2::float8, $3::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= $4::float8 * 1000
+`
+
+type CountEventsForMapParams struct {
+	CategoryIds []int32 `db:"category_ids" json:"category_ids"`
+	Lat         float64 `db:"lat" json:"lat"`
+	Lon         float64 `db:"lon" json:"lon"`
+	RadiusKm    float64 `db:"radius_km" json:"radius_km"`
+}
+
+func (q *Queries) CountEventsForMap(ctx context.Context, arg CountEventsForMapParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEventsForMap,
+		arg.CategoryIds,
+		arg.Lat,
+		arg.Lon,
+		arg.RadiusKm,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countEventsForMapByVolunteer = `-- name: CountEventsForMapByVolunteer :one
+SELECT COUNT(*)
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = $1
+WHERE (
+    $2::int[] IS NULL OR
+    e.category_id = ANY($2::int[])
+)
+  AND earth_box(ll_to_earth($3::float8, $4::float8), $5::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth($3::float8, $4::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= $5::float8 * 1000
+`
+
+type CountEventsForMapByVolunteerParams struct {
+	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+	CategoryIds []int32     `db:"category_ids" json:"category_ids"`
+	Lat         float64     `db:"lat" json:"lat"`
+	Lon         float64     `db:"lon" json:"lon"`
+	RadiusKm    float64     `db:"radius_km" json:"radius_km"`
+}
+
+func (q *Queries) CountEventsForMapByVolunteer(ctx context.Context, arg CountEventsForMapByVolunteerParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEventsForMapByVolunteer,
+		arg.VolunteerID,
+		arg.CategoryIds,
+		arg.Lat,
+		arg.Lon,
+		arg.RadiusKm,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countEventsForMapByVolunteerInBBox = `-- name: CountEventsForMapByVolunteerInBBox :one
+SELECT COUNT(*)
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = $1
+WHERE (
+    $2::int[] IS NULL OR
+    e.category_id = ANY($2::int[])
+)
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point($3::float8, $4::float8),
+        point($5::float8, $6::float8)
+      )
+`
+
+type CountEventsForMapByVolunteerInBBoxParams struct {
+	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+	CategoryIds []int32     `db:"category_ids" json:"category_ids"`
+	MinLon      float64     `db:"min_lon" json:"min_lon"`
+	MinLat      float64     `db:"min_lat" json:"min_lat"`
+	MaxLon      float64     `db:"max_lon" json:"max_lon"`
+	MaxLat      float64     `db:"max_lat" json:"max_lat"`
+}
+
+func (q *Queries) CountEventsForMapByVolunteerInBBox(ctx context.Context, arg CountEventsForMapByVolunteerInBBoxParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEventsForMapByVolunteerInBBox,
+		arg.VolunteerID,
+		arg.CategoryIds,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countEventsForMapInBBox = `-- name: CountEventsForMapInBBox :one
+SELECT COUNT(*)
+FROM events e
+WHERE e.status IN ('open', 'full')
+  AND (
+    $1::int[] IS NULL OR
+    e.category_id = ANY($1::int[])
+  )
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point($2::float8, $3::float8),
+        point($4::float8, $5::float8)
+      )
+`
+
+type CountEventsForMapInBBoxParams struct {
+	CategoryIds []int32 `db:"category_ids" json:"category_ids"`
+	MinLon      float64 `db:"min_lon" json:"min_lon"`
+	MinLat      float64 `db:"min_lat" json:"min_lat"`
+	MaxLon      float64 `db:"max_lon" json:"max_lon"`
+	MaxLat      float64 `db:"max_lat" json:"max_lat"`
+}
+
+func (q *Queries) CountEventsForMapInBBox(ctx context.Context, arg CountEventsForMapInBBoxParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEventsForMapInBBox,
+		arg.CategoryIds,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOrganizerEventsWithPendingApplications = `-- name: CountOrganizerEventsWithPendingApplications :one
+SELECT COUNT(DISTINCT e.id)
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.status = 'pending'
+WHERE e.organizer_id = $1
+`
+
+func (q *Queries) CountOrganizerEventsWithPendingApplications(ctx context.Context, organizerID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrganizerEventsWithPendingApplications, organizerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createEvent = `-- name: CreateEvent :one
 INSERT INTO events (
     title,
@@ -302,6 +533,40 @@ func (q *Queries) GetEventByID(ctx context.Context, id int32) (Event, error) {
 	return i, err
 }
 
+const getEventByIDForUpdate = `-- name: GetEventByIDForUpdate :one
+SELECT id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
+FROM events
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetEventByIDForUpdate(ctx context.Context, id int32) (Event, error) {
+	row := q.db.QueryRow(ctx, getEventByIDForUpdate, id)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Chat,
+		&i.Date,
+		&i.DurationHours,
+		&i.Location,
+		&i.LocationLat,
+		&i.LocationLon,
+		&i.CategoryID,
+		&i.OrganizerID,
+		&i.Contacts,
+		&i.MaxVolunteers,
+		&i.CurrentVolunteers,
+		&i.Status,
+		&i.CancelledReason,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getEventWithOrganizer = `-- name: GetEventWithOrganizer :one
 SELECT e.id, e.title, e.description, e.chat, e.date, e.duration_hours, e.location, e.location_lat, e.location_lon, e.category_id, e.organizer_id, e.contacts, e.max_volunteers, e.current_volunteers, e.status, e.cancelled_reason, e.completed_at, e.created_at, e.updated_at, o.organization_name
 FROM events e
@@ -364,6 +629,11 @@ const incrementEventVolunteers = `-- name: IncrementEventVolunteers :one
 UPDATE events
 SET
     current_volunteers = current_volunteers + $1,
+    status = CASE
+        WHEN status NOT IN ('open', 'full') THEN status
+        WHEN current_volunteers + $1 >= max_volunteers THEN 'full'
+        ELSE 'open'
+    END,
     updated_at = NOW()
 WHERE id = $2
 RETURNING current_volunteers
@@ -374,6 +644,7 @@ type IncrementEventVolunteersParams struct {
 	ID    int32       `db:"id" json:"id"`
 }
 
+// Вместе со счётчиком переводит событие между open и full.
 func (q *Queries) IncrementEventVolunteers(ctx context.Context, arg IncrementEventVolunteersParams) (pgtype.Int4, error) {
 	row := q.db.QueryRow(ctx, incrementEventVolunteers, arg.Delta, arg.ID)
 	var current_volunteers pgtype.Int4
@@ -384,7 +655,7 @@ func (q *Queries) IncrementEventVolunteers(ctx context.Context, arg IncrementEve
 const listAvailableEventsForVolunteer = `-- name: ListAvailableEventsForVolunteer :many
 SELECT id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND NOT EXISTS (
     SELECT 1
     FROM volunteer_applications va
@@ -397,19 +668,29 @@ WHERE e.status = 'open'
     WHERE ep.event_id = e.id
       AND ep.volunteer_id = $1
   )
+  AND (
+    $2::int IS NULL OR
+    (e.date, e.id) < ($3::timestamp, $2::int)
+  )
 ORDER BY e.date DESC, e.id DESC
-LIMIT $3::int
-OFFSET $2::int
+LIMIT $4::int
 `
 
 type ListAvailableEventsForVolunteerParams struct {
-	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
-	Offset      int32       `db:"offset" json:"offset"`
-	Limit       int32       `db:"limit" json:"limit"`
+	VolunteerID pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	AfterID     pgtype.Int4      `db:"after_id" json:"after_id"`
+	AfterDate   pgtype.Timestamp `db:"after_date" json:"after_date"`
+	Limit       int32            `db:"limit" json:"limit"`
 }
 
+// Keyset-пагинация: следующая страница начинается после (after_date, after_id) прошлой.
 func (q *Queries) ListAvailableEventsForVolunteer(ctx context.Context, arg ListAvailableEventsForVolunteerParams) ([]Event, error) {
-	rows, err := q.db.Query(ctx, listAvailableEventsForVolunteer, arg.VolunteerID, arg.Offset, arg.Limit)
+	rows, err := q.db.Query(ctx, listAvailableEventsForVolunteer,
+		arg.VolunteerID,
+		arg.AfterID,
+		arg.AfterDate,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -451,7 +732,7 @@ func (q *Queries) ListAvailableEventsForVolunteer(ctx context.Context, arg ListA
 const listAvailableEventsForVolunteerWithCategories = `-- name: ListAvailableEventsForVolunteerWithCategories :many
 SELECT id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 FROM events e
-WHERE e.status = 'open'
+WHERE e.status IN ('open', 'full')
   AND ($1 IS NULL OR array_length($1, 1) = 0 OR e.category_id = ANY($1))
   AND NOT EXISTS (
     SELECT 1
@@ -465,23 +746,28 @@ WHERE e.status = 'open'
     WHERE ep.event_id = e.id
       AND ep.volunteer_id = $2
   )
+  AND (
+    $3::int IS NULL OR
+    (e.date, e.id) < ($4::timestamp, $3::int)
+  )
 ORDER BY e.date DESC, e.id DESC
-LIMIT $4::int
-OFFSET $3::int
+LIMIT $5::int
 `
 
 type ListAvailableEventsForVolunteerWithCategoriesParams struct {
-	CategoryIds interface{} `db:"category_ids" json:"category_ids"`
-	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
-	Offset      int32       `db:"offset" json:"offset"`
-	Limit       int32       `db:"limit" json:"limit"`
+	CategoryIds interface{}      `db:"category_ids" json:"category_ids"`
+	VolunteerID pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	AfterID     pgtype.Int4      `db:"after_id" json:"after_id"`
+	AfterDate   pgtype.Timestamp `db:"after_date" json:"after_date"`
+	Limit       int32            `db:"limit" json:"limit"`
 }
 
 func (q *Queries) ListAvailableEventsForVolunteerWithCategories(ctx context.Context, arg ListAvailableEventsForVolunteerWithCategoriesParams) ([]Event, error) {
 	rows, err := q.db.Query(ctx, listAvailableEventsForVolunteerWithCategories,
 		arg.CategoryIds,
 		arg.VolunteerID,
-		arg.Offset,
+		arg.AfterID,
+		arg.AfterDate,
 		arg.Limit,
 	)
 	if err != nil {
@@ -522,6 +808,236 @@ func (q *Queries) ListAvailableEventsForVolunteerWithCategories(ctx context.Cont
 	return items, nil
 }
 
+const listCalendarEventsForVolunteer = `-- name: ListCalendarEventsForVolunteer :many
+SELECT e.id, e.title, e.description, e.chat, e.date, e.duration_hours, e.location, e.location_lat, e.location_lon, e.category_id, e.organizer_id, e.contacts, e.max_volunteers, e.current_volunteers, e.status, e.cancelled_reason, e.completed_at, e.created_at, e.updated_at
+FROM events e
+JOIN event_participants ep ON ep.event_id = e.id
+WHERE ep.volunteer_id = $1
+  AND e.date >= $2
+ORDER BY e.date, e.id
+LIMIT $3::int
+`
+
+type ListCalendarEventsForVolunteerParams struct {
+	VolunteerID pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	Since       pgtype.Timestamp `db:"since" json:"since"`
+	Limit       int32            `db:"limit" json:"limit"`
+}
+
+// Лента календаря волонтёра: мероприятия, где он участник, начиная с since.
+// Отменённые остаются в выборке, чтобы календарь пометил их у себя, а не потерял молча.
+func (q *Queries) ListCalendarEventsForVolunteer(ctx context.Context, arg ListCalendarEventsForVolunteerParams) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listCalendarEventsForVolunteer, arg.VolunteerID, arg.Since, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Chat,
+			&i.Date,
+			&i.DurationHours,
+			&i.Location,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.OrganizerID,
+			&i.Contacts,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.Status,
+			&i.CancelledReason,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventClustersInBBox = `-- name: ListEventClustersInBBox :many
+WITH cells AS (
+    SELECT
+        e.category_id,
+        e.location_lat::float8 AS lat,
+        e.location_lon::float8 AS lon,
+        LEAST(GREATEST(
+            floor((e.location_lon::float8 + 180) / 360 * $1::float8), 0
+        ), $1::float8 - 1)::int AS cell_x,
+        LEAST(GREATEST(
+            floor((1 - asinh(tan(radians(LEAST(GREATEST(e.location_lat::float8, -85.05112878), 85.05112878)))) / pi()) / 2 * $1::float8), 0
+        ), $1::float8 - 1)::int AS cell_y
+    FROM events e
+    WHERE e.status IN ('open', 'full')
+      AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+            point($2::float8, $3::float8),
+            point($4::float8, $5::float8)
+          )
+      AND (
+        $6::int[] IS NULL OR
+        e.category_id = ANY($6::int[])
+      )
+)
+SELECT
+    cells.cell_x::int AS cell_x,
+    cells.cell_y::int AS cell_y,
+    cells.category_id,
+    c.name AS category_name,
+    count(*) AS count,
+    sum(cells.lat)::float8 AS sum_lat,
+    sum(cells.lon)::float8 AS sum_lon
+FROM cells
+LEFT JOIN categories c ON c.id = cells.category_id
+GROUP BY cells.cell_x, cells.cell_y, cells.category_id, c.name
+ORDER BY cells.cell_x, cells.cell_y, cells.category_id
+`
+
+type ListEventClustersInBBoxParams struct {
+	GridSize    float64 `db:"grid_size" json:"grid_size"`
+	MinLon      float64 `db:"min_lon" json:"min_lon"`
+	MinLat      float64 `db:"min_lat" json:"min_lat"`
+	MaxLon      float64 `db:"max_lon" json:"max_lon"`
+	MaxLat      float64 `db:"max_lat" json:"max_lat"`
+	CategoryIds []int32 `db:"category_ids" json:"category_ids"`
+}
+
+type ListEventClustersInBBoxRow struct {
+	CellX        int32       `db:"cell_x" json:"cell_x"`
+	CellY        int32       `db:"cell_y" json:"cell_y"`
+	CategoryID   pgtype.Int4 `db:"category_id" json:"category_id"`
+	CategoryName pgtype.Text `db:"category_name" json:"category_name"`
+	Count        int64       `db:"count" json:"count"`
+	SumLat       float64     `db:"sum_lat" json:"sum_lat"`
+	SumLon       float64     `db:"sum_lon" json:"sum_lon"`
+}
+
+// Сетка кластеров считается в базе: ячейка — номер квадрата grid_size×grid_size в проекции Меркатора,
+// как у тайлов карты. Строка — одна категория одной ячейки, сумма координат нужна для центроида.
+func (q *Queries) ListEventClustersInBBox(ctx context.Context, arg ListEventClustersInBBoxParams) ([]ListEventClustersInBBoxRow, error) {
+	rows, err := q.db.Query(ctx, listEventClustersInBBox,
+		arg.GridSize,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+		arg.CategoryIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventClustersInBBoxRow
+	for rows.Next() {
+		var i ListEventClustersInBBoxRow
+		if err := rows.Scan(
+			&i.CellX,
+			&i.CellY,
+			&i.CategoryID,
+			&i.CategoryName,
+			&i.Count,
+			&i.SumLat,
+			&i.SumLon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventPointsInBBox = `-- name: ListEventPointsInBBox :many
+SELECT
+    e.id,
+    e.title,
+    e.date,
+    e.location_lat::float8 AS location_lat,
+    e.location_lon::float8 AS location_lon,
+    e.category_id,
+    c.name AS category_name
+FROM events e
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE e.status IN ('open', 'full')
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point($1::float8, $2::float8),
+        point($3::float8, $4::float8)
+      )
+  AND (
+    $5::int[] IS NULL OR
+    e.category_id = ANY($5::int[])
+  )
+ORDER BY e.id
+LIMIT $6::int
+`
+
+type ListEventPointsInBBoxParams struct {
+	MinLon      float64 `db:"min_lon" json:"min_lon"`
+	MinLat      float64 `db:"min_lat" json:"min_lat"`
+	MaxLon      float64 `db:"max_lon" json:"max_lon"`
+	MaxLat      float64 `db:"max_lat" json:"max_lat"`
+	CategoryIds []int32 `db:"category_ids" json:"category_ids"`
+	Limit       int32   `db:"limit" json:"limit"`
+}
+
+type ListEventPointsInBBoxRow struct {
+	ID           int32            `db:"id" json:"id"`
+	Title        string           `db:"title" json:"title"`
+	Date         pgtype.Timestamp `db:"date" json:"date"`
+	LocationLat  float64          `db:"location_lat" json:"location_lat"`
+	LocationLon  float64          `db:"location_lon" json:"location_lon"`
+	CategoryID   pgtype.Int4      `db:"category_id" json:"category_id"`
+	CategoryName pgtype.Text      `db:"category_name" json:"category_name"`
+}
+
+// Лёгкие точки для крупных масштабов карты: без описаний и расстояний, только координаты и категория.
+func (q *Queries) ListEventPointsInBBox(ctx context.Context, arg ListEventPointsInBBoxParams) ([]ListEventPointsInBBoxRow, error) {
+	rows, err := q.db.Query(ctx, listEventPointsInBBox,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+		arg.CategoryIds,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventPointsInBBoxRow
+	for rows.Next() {
+		var i ListEventPointsInBBoxRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEvents = `-- name: ListEvents :many
 SELECT id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 FROM events
@@ -700,20 +1216,299 @@ type ListEventsByStatusParams struct {
 	Limit  int32       `db:"limit" json:"limit"`
 }
 
-func (q *Queries) ListEventsByStatus(ctx context.Context, arg ListEventsByStatusParams) ([]Event, error) {
-	rows, err := q.db.Query(ctx, listEventsByStatus, arg.Status, arg.Offset, arg.Limit)
+func (q *Queries) ListEventsByStatus(ctx context.Context, arg ListEventsByStatusParams) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listEventsByStatus, arg.Status, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Chat,
+			&i.Date,
+			&i.DurationHours,
+			&i.Location,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.OrganizerID,
+			&i.Contacts,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.Status,
+			&i.CancelledReason,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsForMap = `-- name: ListEventsForMap :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km
+FROM events e
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE e.status IN ('open', 'full')
+  AND (
+    $3::int[] IS NULL OR
+    e.category_id = ANY($3::int[])
+  )
+  AND earth_box(ll_to_earth($1::float8, $2::float8), $4::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= $4::float8 * 1000
+  AND (
+    $5::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > ($6::float8, $7::timestamp, $5::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT $8::int
+`
+
+type ListEventsForMapParams struct {
+	Lat             float64          `db:"lat" json:"lat"`
+	Lon             float64          `db:"lon" json:"lon"`
+	CategoryIds     []int32          `db:"category_ids" json:"category_ids"`
+	RadiusKm        float64          `db:"radius_km" json:"radius_km"`
+	AfterID         pgtype.Int4      `db:"after_id" json:"after_id"`
+	AfterDistanceKm pgtype.Float8    `db:"after_distance_km" json:"after_distance_km"`
+	AfterDate       pgtype.Timestamp `db:"after_date" json:"after_date"`
+	Limit           int32            `db:"limit" json:"limit"`
+}
+
+type ListEventsForMapRow struct {
+	ID                int32            `db:"id" json:"id"`
+	Title             string           `db:"title" json:"title"`
+	Description       pgtype.Text      `db:"description" json:"description"`
+	Date              pgtype.Timestamp `db:"date" json:"date"`
+	DurationHours     pgtype.Int4      `db:"duration_hours" json:"duration_hours"`
+	Location          string           `db:"location" json:"location"`
+	LocationLat       pgtype.Numeric   `db:"location_lat" json:"location_lat"`
+	LocationLon       pgtype.Numeric   `db:"location_lon" json:"location_lon"`
+	CategoryID        pgtype.Int4      `db:"category_id" json:"category_id"`
+	OrganizerID       pgtype.Int8      `db:"organizer_id" json:"organizer_id"`
+	Contacts          pgtype.Text      `db:"contacts" json:"contacts"`
+	Chat              pgtype.Int8      `db:"chat" json:"chat"`
+	MaxVolunteers     int32            `db:"max_volunteers" json:"max_volunteers"`
+	CurrentVolunteers int32            `db:"current_volunteers" json:"current_volunteers"`
+	Status            pgtype.Text      `db:"status" json:"status"`
+	CancelledReason   pgtype.Text      `db:"cancelled_reason" json:"cancelled_reason"`
+	CompletedAt       pgtype.Timestamp `db:"completed_at" json:"completed_at"`
+	CreatedAt         pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	CategoryName      pgtype.Text      `db:"category_name" json:"category_name"`
+	SlotsLeft         int32            `db:"slots_left" json:"slots_left"`
+	DistanceKm        float64          `db:"distance_km" json:"distance_km"`
+}
+
+// earth_box отбирает кандидатов по GiST-индексу idx_events_location_earth, earth_distance отсекает углы куба.
+func (q *Queries) ListEventsForMap(ctx context.Context, arg ListEventsForMapParams) ([]ListEventsForMapRow, error) {
+	rows, err := q.db.Query(ctx, listEventsForMap,
+		arg.Lat,
+		arg.Lon,
+		arg.CategoryIds,
+		arg.RadiusKm,
+		arg.AfterID,
+		arg.AfterDistanceKm,
+		arg.AfterDate,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventsForMapRow
+	for rows.Next() {
+		var i ListEventsForMapRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Date,
+			&i.DurationHours,
+			&i.Location,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.OrganizerID,
+			&i.Contacts,
+			&i.Chat,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.Status,
+			&i.CancelledReason,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CategoryName,
+			&i.SlotsLeft,
+			&i.DistanceKm,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsForMapByVolunteer = `-- name: ListEventsForMapByVolunteer :many
+SELECT
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km,
+    va.status AS application_status
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = $3
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE (
+    $4::int[] IS NULL OR
+    e.category_id = ANY($4::int[])
+)
+  AND earth_box(ll_to_earth($1::float8, $2::float8), $5::float8 * 1000)
+      @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+      ) <= $5::float8 * 1000
+  AND (
+    $6::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > ($7::float8, $8::timestamp, $6::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT $9::int
+`
+
+type ListEventsForMapByVolunteerParams struct {
+	Lat             float64          `db:"lat" json:"lat"`
+	Lon             float64          `db:"lon" json:"lon"`
+	VolunteerID     pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	CategoryIds     []int32          `db:"category_ids" json:"category_ids"`
+	RadiusKm        float64          `db:"radius_km" json:"radius_km"`
+	AfterID         pgtype.Int4      `db:"after_id" json:"after_id"`
+	AfterDistanceKm pgtype.Float8    `db:"after_distance_km" json:"after_distance_km"`
+	AfterDate       pgtype.Timestamp `db:"after_date" json:"after_date"`
+	Limit           int32            `db:"limit" json:"limit"`
+}
+
+type ListEventsForMapByVolunteerRow struct {
+	ID                int32            `db:"id" json:"id"`
+	Title             string           `db:"title" json:"title"`
+	Description       pgtype.Text      `db:"description" json:"description"`
+	Date              pgtype.Timestamp `db:"date" json:"date"`
+	DurationHours     pgtype.Int4      `db:"duration_hours" json:"duration_hours"`
+	Location          string           `db:"location" json:"location"`
+	LocationLat       pgtype.Numeric   `db:"location_lat" json:"location_lat"`
+	LocationLon       pgtype.Numeric   `db:"location_lon" json:"location_lon"`
+	CategoryID        pgtype.Int4      `db:"category_id" json:"category_id"`
+	OrganizerID       pgtype.Int8      `db:"organizer_id" json:"organizer_id"`
+	Contacts          pgtype.Text      `db:"contacts" json:"contacts"`
+	Chat              pgtype.Int8      `db:"chat" json:"chat"`
+	MaxVolunteers     int32            `db:"max_volunteers" json:"max_volunteers"`
+	CurrentVolunteers int32            `db:"current_volunteers" json:"current_volunteers"`
+	Status            pgtype.Text      `db:"status" json:"status"`
+	CancelledReason   pgtype.Text      `db:"cancelled_reason" json:"cancelled_reason"`
+	CompletedAt       pgtype.Timestamp `db:"completed_at" json:"completed_at"`
+	CreatedAt         pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	CategoryName      pgtype.Text      `db:"category_name" json:"category_name"`
+	SlotsLeft         int32            `db:"slots_left" json:"slots_left"`
+	DistanceKm        float64          `db:"distance_km" json:"distance_km"`
+	ApplicationStatus pgtype.Text      `db:"application_status" json:"application_status"`
+}
+
+func (q *Queries) ListEventsForMapByVolunteer(ctx context.Context, arg ListEventsForMapByVolunteerParams) ([]ListEventsForMapByVolunteerRow, error) {
+	rows, err := q.db.Query(ctx, listEventsForMapByVolunteer,
+		arg.Lat,
+		arg.Lon,
+		arg.VolunteerID,
+		arg.CategoryIds,
+		arg.RadiusKm,
+		arg.AfterID,
+		arg.AfterDistanceKm,
+		arg.AfterDate,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Event
+	var items []ListEventsForMapByVolunteerRow
 	for rows.Next() {
-		var i Event
+		var i ListEventsForMapByVolunteerRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
 			&i.Description,
-			&i.Chat,
 			&i.Date,
 			&i.DurationHours,
 			&i.Location,
@@ -722,6 +1517,7 @@ func (q *Queries) ListEventsByStatus(ctx context.Context, arg ListEventsByStatus
 			&i.CategoryID,
 			&i.OrganizerID,
 			&i.Contacts,
+			&i.Chat,
 			&i.MaxVolunteers,
 			&i.CurrentVolunteers,
 			&i.Status,
@@ -729,6 +1525,10 @@ func (q *Queries) ListEventsByStatus(ctx context.Context, arg ListEventsByStatus
 			&i.CompletedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CategoryName,
+			&i.SlotsLeft,
+			&i.DistanceKm,
+			&i.ApplicationStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -740,106 +1540,72 @@ func (q *Queries) ListEventsByStatus(ctx context.Context, arg ListEventsByStatus
 	return items, nil
 }
 
-const listEventsForMap = `-- name: ListEventsForMap :many
-WITH candidate AS (
-    SELECT
-        e.id,
-        e.title,
-        e.description,
-        e.date,
-        e.duration_hours,
-        e.location,
-        e.location_lat,
-        e.location_lon,
-        e.category_id,
-        e.organizer_id,
-        e.contacts,
-        e.chat,
-        e.max_volunteers,
-        COALESCE(e.current_volunteers, 0) AS current_volunteers,
-        e.status,
-        e.cancelled_reason,
-        e.completed_at,
-        e.created_at,
-        e.updated_at,
-        c.name AS category_name,
-        GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
-        CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians($3)) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians($4)) +
-                    sin(radians($3)) * sin(radians(e.location_lat::float8))
-                )
-            )
-        ) AS double precision) AS distance_km
-    FROM events e
-    LEFT JOIN categories c ON c.id = e.category_id
-    WHERE e.status = 'open'
-      AND e.location_lat IS NOT NULL
-      AND e.location_lon IS NOT NULL
-      AND (
-            e.max_volunteers = 0 OR
-            e.current_volunteers IS NULL OR
-            e.current_volunteers < e.max_volunteers
-      )
-      AND (
-        $5::int[] IS NULL OR
-        e.category_id = ANY($5::int[])
-      )
-      AND CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians($3)) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians($4)) +
-                    sin(radians($3)) * sin(radians(e.location_lat::float8))
-                )
-            )
-    ) AS double precision) <= $6::double precision
-)
+const listEventsForMapByVolunteerInBBox = `-- name: ListEventsForMapByVolunteerInBBox :many
 SELECT
-    id,
-    title,
-    description,
-    date,
-    duration_hours,
-    location,
-    location_lat,
-    location_lon,
-    category_id,
-    organizer_id,
-    contacts,
-    chat,
-    max_volunteers,
-    current_volunteers,
-    status,
-    cancelled_reason,
-    completed_at,
-    created_at,
-    updated_at,
-    category_name,
-    slots_left::int4 AS slots_left,
-    distance_km::double precision AS distance_km
-FROM candidate
-ORDER BY distance_km ASC, date ASC
-LIMIT $2::int
-OFFSET $1::int
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km,
+    va.status AS application_status
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.volunteer_id = $3
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE (
+    $4::int[] IS NULL OR
+    e.category_id = ANY($4::int[])
+)
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point($5::float8, $6::float8),
+        point($7::float8, $8::float8)
+      )
+  AND (
+    $9::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > ($10::float8, $11::timestamp, $9::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT $12::int
 `
 
-type ListEventsForMapParams struct {
-	Offset      int32   `db:"offset" json:"offset"`
-	Limit       int32   `db:"limit" json:"limit"`
-	Lat         float64 `db:"lat" json:"lat"`
-	Lon         float64 `db:"lon" json:"lon"`
-	CategoryIds []int32 `db:"category_ids" json:"category_ids"`
-	RadiusKm    float64 `db:"radius_km" json:"radius_km"`
+type ListEventsForMapByVolunteerInBBoxParams struct {
+	Lat             float64          `db:"lat" json:"lat"`
+	Lon             float64          `db:"lon" json:"lon"`
+	VolunteerID     pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	CategoryIds     []int32          `db:"category_ids" json:"category_ids"`
+	MinLon          float64          `db:"min_lon" json:"min_lon"`
+	MinLat          float64          `db:"min_lat" json:"min_lat"`
+	MaxLon          float64          `db:"max_lon" json:"max_lon"`
+	MaxLat          float64          `db:"max_lat" json:"max_lat"`
+	AfterID         pgtype.Int4      `db:"after_id" json:"after_id"`
+	AfterDistanceKm pgtype.Float8    `db:"after_distance_km" json:"after_distance_km"`
+	AfterDate       pgtype.Timestamp `db:"after_date" json:"after_date"`
+	Limit           int32            `db:"limit" json:"limit"`
 }
 
-type ListEventsForMapRow struct {
+type ListEventsForMapByVolunteerInBBoxRow struct {
 	ID                int32            `db:"id" json:"id"`
 	Title             string           `db:"title" json:"title"`
 	Description       pgtype.Text      `db:"description" json:"description"`
@@ -862,24 +1628,31 @@ type ListEventsForMapRow struct {
 	CategoryName      pgtype.Text      `db:"category_name" json:"category_name"`
 	SlotsLeft         int32            `db:"slots_left" json:"slots_left"`
 	DistanceKm        float64          `db:"distance_km" json:"distance_km"`
+	ApplicationStatus pgtype.Text      `db:"application_status" json:"application_status"`
 }
 
-func (q *Queries) ListEventsForMap(ctx context.Context, arg ListEventsForMapParams) ([]ListEventsForMapRow, error) {
-	rows, err := q.db.Query(ctx, listEventsForMap,
-		arg.Offset,
-		arg.Limit,
+func (q *Queries) ListEventsForMapByVolunteerInBBox(ctx context.Context, arg ListEventsForMapByVolunteerInBBoxParams) ([]ListEventsForMapByVolunteerInBBoxRow, error) {
+	rows, err := q.db.Query(ctx, listEventsForMapByVolunteerInBBox,
 		arg.Lat,
 		arg.Lon,
+		arg.VolunteerID,
 		arg.CategoryIds,
-		arg.RadiusKm,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+		arg.AfterID,
+		arg.AfterDistanceKm,
+		arg.AfterDate,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListEventsForMapRow
+	var items []ListEventsForMapByVolunteerInBBoxRow
 	for rows.Next() {
-		var i ListEventsForMapRow
+		var i ListEventsForMapByVolunteerInBBoxRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
@@ -903,6 +1676,7 @@ func (q *Queries) ListEventsForMap(ctx context.Context, arg ListEventsForMapPara
 			&i.CategoryName,
 			&i.SlotsLeft,
 			&i.DistanceKm,
+			&i.ApplicationStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -914,111 +1688,70 @@ func (q *Queries) ListEventsForMap(ctx context.Context, arg ListEventsForMapPara
 	return items, nil
 }
 
-const listEventsForMapByVolunteer = `-- name: ListEventsForMapByVolunteer :many
-WITH user_apps AS (
-    SELECT
-        va.event_id,
-        va.status
-    FROM volunteer_applications va
-    WHERE va.volunteer_id = $3
-),
-candidate AS (
-    SELECT
-        e.id,
-        e.title,
-        e.description,
-        e.date,
-        e.duration_hours,
-        e.location,
-        e.location_lat,
-        e.location_lon,
-        e.category_id,
-        e.organizer_id,
-        e.contacts,
-        e.chat,
-        e.max_volunteers,
-        COALESCE(e.current_volunteers, 0) AS current_volunteers,
-        e.status,
-        e.cancelled_reason,
-        e.completed_at,
-        e.created_at,
-        e.updated_at,
-        c.name AS category_name,
-        GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
-        CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians($4)) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians($5)) +
-                    sin(radians($4)) * sin(radians(e.location_lat::float8))
-                )
-            )
-        ) AS double precision) AS distance_km,
-        ua.status AS application_status
-    FROM events e
-    JOIN user_apps ua ON ua.event_id = e.id
-    LEFT JOIN categories c ON c.id = e.category_id
-    WHERE e.location_lat IS NOT NULL
-      AND e.location_lon IS NOT NULL
-      AND (
-        $6::int[] IS NULL OR
-        e.category_id = ANY($6::int[])
-      )
-      AND CAST(6371 * acos(
-            LEAST(
-                1,
-                GREATEST(
-                    -1,
-                    cos(radians($4)) * cos(radians(e.location_lat::float8)) *
-                    cos(radians(e.location_lon::float8) - radians($5)) +
-                    sin(radians($4)) * sin(radians(e.location_lat::float8))
-                )
-            )
-    ) AS double precision) <= $7::double precision
-)
+const listEventsForMapInBBox = `-- name: ListEventsForMapInBBox :many
 SELECT
-    id,
-    title,
-    description,
-    date,
-    duration_hours,
-    location,
-    location_lat,
-    location_lon,
-    category_id,
-    organizer_id,
-    contacts,
-    chat,
-    max_volunteers,
-    current_volunteers,
-    status,
-    cancelled_reason,
-    completed_at,
-    created_at,
-    updated_at,
-    category_name,
-    slots_left::int4 AS slots_left,
-    distance_km::double precision AS distance_km,
-    application_status
-FROM candidate
-ORDER BY distance_km ASC, date ASC
-LIMIT $2::int
-OFFSET $1::int
+    e.id,
+    e.title,
+    e.description,
+    e.date,
+    e.duration_hours,
+    e.location,
+    e.location_lat,
+    e.location_lon,
+    e.category_id,
+    e.organizer_id,
+    e.contacts,
+    e.chat,
+    e.max_volunteers,
+    COALESCE(e.current_volunteers, 0) AS current_volunteers,
+    e.status,
+    e.cancelled_reason,
+    e.completed_at,
+    e.created_at,
+    e.updated_at,
+    c.name AS category_name,
+    GREATEST(e.max_volunteers - COALESCE(e.current_volunteers, 0), 0)::int4 AS slots_left,
+    (earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8 AS distance_km
+FROM events e
+LEFT JOIN categories c ON c.id = e.category_id
+WHERE e.status IN ('open', 'full')
+  AND (
+    $3::int[] IS NULL OR
+    e.category_id = ANY($3::int[])
+  )
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(
+        point($4::float8, $5::float8),
+        point($6::float8, $7::float8)
+      )
+  AND (
+    $8::int IS NULL OR
+    ((earth_distance(
+        ll_to_earth($1::float8, $2::float8),
+        ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+    ) / 1000)::float8, e.date, e.id) > ($9::float8, $10::timestamp, $8::int)
+  )
+ORDER BY distance_km ASC, e.date ASC, e.id ASC
+LIMIT $11::int
 `
 
-type ListEventsForMapByVolunteerParams struct {
-	Offset      int32       `db:"offset" json:"offset"`
-	Limit       int32       `db:"limit" json:"limit"`
-	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
-	Lat         float64     `db:"lat" json:"lat"`
-	Lon         float64     `db:"lon" json:"lon"`
-	CategoryIds []int32     `db:"category_ids" json:"category_ids"`
-	RadiusKm    float64     `db:"radius_km" json:"radius_km"`
+type ListEventsForMapInBBoxParams struct {
+	Lat             float64          `db:"lat" json:"lat"`
+	Lon             float64          `db:"lon" json:"lon"`
+	CategoryIds     []int32          `db:"category_ids" json:"category_ids"`
+	MinLon          float64          `db:"min_lon" json:"min_lon"`
+	MinLat          float64          `db:"min_lat" json:"min_lat"`
+	MaxLon          float64          `db:"max_lon" json:"max_lon"`
+	MaxLat          float64          `db:"max_lat" json:"max_lat"`
+	AfterID         pgtype.Int4      `db:"after_id" json:"after_id"`
+	AfterDistanceKm pgtype.Float8    `db:"after_distance_km" json:"after_distance_km"`
+	AfterDate       pgtype.Timestamp `db:"after_date" json:"after_date"`
+	Limit           int32            `db:"limit" json:"limit"`
 }
 
-type ListEventsForMapByVolunteerRow struct {
+type ListEventsForMapInBBoxRow struct {
 	ID                int32            `db:"id" json:"id"`
 	Title             string           `db:"title" json:"title"`
 	Description       pgtype.Text      `db:"description" json:"description"`
@@ -1041,26 +1774,30 @@ type ListEventsForMapByVolunteerRow struct {
 	CategoryName      pgtype.Text      `db:"category_name" json:"category_name"`
 	SlotsLeft         int32            `db:"slots_left" json:"slots_left"`
 	DistanceKm        float64          `db:"distance_km" json:"distance_km"`
-	ApplicationStatus pgtype.Text      `db:"application_status" json:"application_status"`
 }
 
-func (q *Queries) ListEventsForMapByVolunteer(ctx context.Context, arg ListEventsForMapByVolunteerParams) ([]ListEventsForMapByVolunteerRow, error) {
-	rows, err := q.db.Query(ctx, listEventsForMapByVolunteer,
-		arg.Offset,
-		arg.Limit,
-		arg.VolunteerID,
+// Область карты ищется по GiST-индексу idx_events_location_point; distance_km считается от lat/lon (центра области).
+func (q *Queries) ListEventsForMapInBBox(ctx context.Context, arg ListEventsForMapInBBoxParams) ([]ListEventsForMapInBBoxRow, error) {
+	rows, err := q.db.Query(ctx, listEventsForMapInBBox,
 		arg.Lat,
 		arg.Lon,
 		arg.CategoryIds,
-		arg.RadiusKm,
+		arg.MinLon,
+		arg.MinLat,
+		arg.MaxLon,
+		arg.MaxLat,
+		arg.AfterID,
+		arg.AfterDistanceKm,
+		arg.AfterDate,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListEventsForMapByVolunteerRow
+	var items []ListEventsForMapInBBoxRow
 	for rows.Next() {
-		var i ListEventsForMapByVolunteerRow
+		var i ListEventsForMapInBBoxRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
@@ -1084,7 +1821,6 @@ func (q *Queries) ListEventsForMapByVolunteer(ctx context.Context, arg ListEvent
 			&i.CategoryName,
 			&i.SlotsLeft,
 			&i.DistanceKm,
-			&i.ApplicationStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -1157,19 +1893,25 @@ SELECT id, title, description, chat, date, duration_hours, location, location_la
 FROM events
 WHERE status = 'open'
   AND date >= $1
-  AND ABS(location_lat - $2) <= $3
-  AND ABS(location_lon - $4) <= $5
-ORDER BY POWER(location_lat - $2, 2) + POWER(location_lon - $4, 2)
-LIMIT $7::int
-OFFSET $6::int
+  AND earth_box(ll_to_earth($2::float8, $3::float8), $4::float8 * 1000)
+      @> ll_to_earth(location_lat::float8, location_lon::float8)
+  AND earth_distance(
+        ll_to_earth($2::float8, $3::float8),
+        ll_to_earth(location_lat::float8, location_lon::float8)
+      ) <= $4::float8 * 1000
+ORDER BY earth_distance(
+    ll_to_earth($2::float8, $3::float8),
+    ll_to_earth(location_lat::float8, location_lon::float8)
+)
+LIMIT $6::int
+OFFSET $5::int
 `
 
 type ListEventsNearLocationParams struct {
 	StartDate pgtype.Timestamp `db:"start_date" json:"start_date"`
-	TargetLat pgtype.Numeric   `db:"target_lat" json:"target_lat"`
-	LatDelta  pgtype.Numeric   `db:"lat_delta" json:"lat_delta"`
-	TargetLon pgtype.Numeric   `db:"target_lon" json:"target_lon"`
-	LonDelta  pgtype.Numeric   `db:"lon_delta" json:"lon_delta"`
+	Lat       float64          `db:"lat" json:"lat"`
+	Lon       float64          `db:"lon" json:"lon"`
+	RadiusKm  float64          `db:"radius_km" json:"radius_km"`
 	Offset    int32            `db:"offset" json:"offset"`
 	Limit     int32            `db:"limit" json:"limit"`
 }
@@ -1177,10 +1919,9 @@ type ListEventsNearLocationParams struct {
 func (q *Queries) ListEventsNearLocation(ctx context.Context, arg ListEventsNearLocationParams) ([]Event, error) {
 	rows, err := q.db.Query(ctx, listEventsNearLocation,
 		arg.StartDate,
-		arg.TargetLat,
-		arg.LatDelta,
-		arg.TargetLon,
-		arg.LonDelta,
+		arg.Lat,
+		arg.Lon,
+		arg.RadiusKm,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -1281,6 +2022,65 @@ func (q *Queries) ListEventsWithPendingApplications(ctx context.Context, arg Lis
 	return items, nil
 }
 
+const listOrganizerEventsWithPendingApplications = `-- name: ListOrganizerEventsWithPendingApplications :many
+SELECT
+    e.id,
+    e.title,
+    e.date,
+    e.max_volunteers,
+    e.current_volunteers,
+    COUNT(va.id)::bigint AS pending_count
+FROM events e
+JOIN volunteer_applications va ON va.event_id = e.id AND va.status = 'pending'
+WHERE e.organizer_id = $1
+GROUP BY e.id
+ORDER BY MIN(va.applied_at) ASC, e.id ASC
+LIMIT $3::int
+OFFSET $2::int
+`
+
+type ListOrganizerEventsWithPendingApplicationsParams struct {
+	OrganizerID pgtype.Int8 `db:"organizer_id" json:"organizer_id"`
+	Offset      int32       `db:"offset" json:"offset"`
+	Limit       int32       `db:"limit" json:"limit"`
+}
+
+type ListOrganizerEventsWithPendingApplicationsRow struct {
+	ID                int32            `db:"id" json:"id"`
+	Title             string           `db:"title" json:"title"`
+	Date              pgtype.Timestamp `db:"date" json:"date"`
+	MaxVolunteers     int32            `db:"max_volunteers" json:"max_volunteers"`
+	CurrentVolunteers pgtype.Int4      `db:"current_volunteers" json:"current_volunteers"`
+	PendingCount      int64            `db:"pending_count" json:"pending_count"`
+}
+
+func (q *Queries) ListOrganizerEventsWithPendingApplications(ctx context.Context, arg ListOrganizerEventsWithPendingApplicationsParams) ([]ListOrganizerEventsWithPendingApplicationsRow, error) {
+	rows, err := q.db.Query(ctx, listOrganizerEventsWithPendingApplications, arg.OrganizerID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrganizerEventsWithPendingApplicationsRow
+	for rows.Next() {
+		var i ListOrganizerEventsWithPendingApplicationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.PendingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUpcomingEvents = `-- name: ListUpcomingEvents :many
 SELECT id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 FROM events
@@ -1342,6 +2142,11 @@ UPDATE events
 SET
     current_volunteers = $1,
     max_volunteers = $2,
+    status = CASE
+        WHEN status NOT IN ('open', 'full') THEN status
+        WHEN $1 >= $2 THEN 'full'
+        ELSE 'open'
+    END,
     updated_at = NOW()
 WHERE id = $3
 RETURNING current_volunteers, max_volunteers
@@ -1365,6 +2170,107 @@ func (q *Queries) SetEventVolunteerCounts(ctx context.Context, arg SetEventVolun
 	return i, err
 }
 
+const startDueEvents = `-- name: StartDueEvents :many
+UPDATE events
+SET
+    status = 'in_progress',
+    updated_at = NOW()
+WHERE status IN ('open', 'full')
+  AND date <= NOW()
+RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
+`
+
+func (q *Queries) StartDueEvents(ctx context.Context) ([]Event, error) {
+	rows, err := q.db.Query(ctx, startDueEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Chat,
+			&i.Date,
+			&i.DurationHours,
+			&i.Location,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.OrganizerID,
+			&i.Contacts,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.Status,
+			&i.CancelledReason,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncEventCapacityStatuses = `-- name: SyncEventCapacityStatuses :many
+UPDATE events
+SET
+    status = CASE WHEN COALESCE(current_volunteers, 0) >= max_volunteers THEN 'full' ELSE 'open' END,
+    updated_at = NOW()
+WHERE status IN ('open', 'full')
+  AND status <> CASE WHEN COALESCE(current_volunteers, 0) >= max_volunteers THEN 'full' ELSE 'open' END
+RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
+`
+
+// Приводит статус open/full в соответствие со счётчиком участников.
+func (q *Queries) SyncEventCapacityStatuses(ctx context.Context) ([]Event, error) {
+	rows, err := q.db.Query(ctx, syncEventCapacityStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Chat,
+			&i.Date,
+			&i.DurationHours,
+			&i.Location,
+			&i.LocationLat,
+			&i.LocationLon,
+			&i.CategoryID,
+			&i.OrganizerID,
+			&i.Contacts,
+			&i.MaxVolunteers,
+			&i.CurrentVolunteers,
+			&i.Status,
+			&i.CancelledReason,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateEvent = `-- name: UpdateEvent :one
 UPDATE events
 SET
@@ -1379,6 +2285,11 @@ SET
     category_id = $9,
     contacts = $10,
     max_volunteers = $11,
+    status = CASE
+        WHEN status NOT IN ('open', 'full') THEN status
+        WHEN COALESCE(current_volunteers, 0) >= $11 THEN 'full'
+        ELSE 'open'
+    END,
     updated_at = NOW()
 WHERE id = $12
 RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
@@ -1443,18 +2354,22 @@ const updateEventStatus = `-- name: UpdateEventStatus :one
 UPDATE events
 SET
     status = $1,
+    completed_at = CASE WHEN $1 = 'completed' THEN NOW() ELSE completed_at END,
     updated_at = NOW()
 WHERE id = $2
+  AND status = $3
 RETURNING id, title, description, chat, date, duration_hours, location, location_lat, location_lon, category_id, organizer_id, contacts, max_volunteers, current_volunteers, status, cancelled_reason, completed_at, created_at, updated_at
 `
 
 type UpdateEventStatusParams struct {
-	Status pgtype.Text `db:"status" json:"status"`
-	ID     int32       `db:"id" json:"id"`
+	Status     pgtype.Text `db:"status" json:"status"`
+	ID         int32       `db:"id" json:"id"`
+	FromStatus pgtype.Text `db:"from_status" json:"from_status"`
 }
 
+// Меняет статус, только если событие всё ещё в статусе from_status (проверка перехода в сервисе).
 func (q *Queries) UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (Event, error) {
-	row := q.db.QueryRow(ctx, updateEventStatus, arg.Status, arg.ID)
+	row := q.db.QueryRow(ctx, updateEventStatus, arg.Status, arg.ID, arg.FromStatus)
 	var i Event
 	err := row.Scan(
 		&i.ID,
@@ -13,6 +13,42 @@ type Admin struct {
 	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
 }
 
+type ApplicationAnswer struct {
+	ApplicationID int32            `db:"application_id" json:"application_id"`
+	QuestionID    int32            `db:"question_id" json:"question_id"`
+	Answer        []string         `db:"answer" json:"answer"`
+	CreatedAt     pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type AuthInitDataUse struct {
+	Key       string           `db:"key" json:"key"`
+	ExpiresAt pgtype.Timestamp `db:"expires_at" json:"expires_at"`
+}
+
+type AuthRefreshToken struct {
+	TokenHash string           `db:"token_hash" json:"token_hash"`
+	SessionID string           `db:"session_id" json:"session_id"`
+	ExpiresAt pgtype.Timestamp `db:"expires_at" json:"expires_at"`
+	UsedAt    pgtype.Timestamp `db:"used_at" json:"used_at"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type AuthSession struct {
+	ID        string           `db:"id" json:"id"`
+	UserID    int64            `db:"user_id" json:"user_id"`
+	UserData  []byte           `db:"user_data" json:"user_data"`
+	ExpiresAt pgtype.Timestamp `db:"expires_at" json:"expires_at"`
+	RevokedAt pgtype.Timestamp `db:"revoked_at" json:"revoked_at"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type CalendarFeedToken struct {
+	UserID     int64            `db:"user_id" json:"user_id"`
+	TokenHash  string           `db:"token_hash" json:"token_hash"`
+	LastUsedAt pgtype.Timestamp `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
 type Category struct {
 	ID          int32            `db:"id" json:"id"`
 	Name        string           `db:"name" json:"name"`
@@ -43,6 +79,29 @@ type Event struct {
 	UpdatedAt         pgtype.Timestamp `db:"updated_at" json:"updated_at"`
 }
 
+type EventCheckInCode struct {
+	EventID   int32            `db:"event_id" json:"event_id"`
+	Code      string           `db:"code" json:"code"`
+	ExpiresAt pgtype.Timestamp `db:"expires_at" json:"expires_at"`
+	CreatedBy pgtype.Int8      `db:"created_by" json:"created_by"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type EventDraft struct {
+	OrganizerID   int64            `db:"organizer_id" json:"organizer_id"`
+	Title         pgtype.Text      `db:"title" json:"title"`
+	Description   pgtype.Text      `db:"description" json:"description"`
+	Date          pgtype.Timestamp `db:"date" json:"date"`
+	DurationHours pgtype.Int4      `db:"duration_hours" json:"duration_hours"`
+	Location      pgtype.Text      `db:"location" json:"location"`
+	LocationLat   pgtype.Numeric   `db:"location_lat" json:"location_lat"`
+	LocationLon   pgtype.Numeric   `db:"location_lon" json:"location_lon"`
+	CategoryID    pgtype.Int4      `db:"category_id" json:"category_id"`
+	MaxVolunteers pgtype.Int4      `db:"max_volunteers" json:"max_volunteers"`
+	Contacts      pgtype.Text      `db:"contacts" json:"contacts"`
+	UpdatedAt     pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
 type EventMedium struct {
 	ID         int32            `db:"id" json:"id"`
 	EventID    pgtype.Int4      `db:"event_id" json:"event_id"`
@@ -52,11 +111,42 @@ type EventMedium struct {
 }
 
 type EventParticipant struct {
-	ID            int32            `db:"id" json:"id"`
+	ID                 int32            `db:"id" json:"id"`
+	EventID            pgtype.Int4      `db:"event_id" json:"event_id"`
+	VolunteerID        pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	ApplicationID      pgtype.Int4      `db:"application_id" json:"application_id"`
+	JoinedChatAt       pgtype.Timestamp `db:"joined_chat_at" json:"joined_chat_at"`
+	CheckedInAt        pgtype.Timestamp `db:"checked_in_at" json:"checked_in_at"`
+	CheckedOutAt       pgtype.Timestamp `db:"checked_out_at" json:"checked_out_at"`
+	CheckInMethod      pgtype.Text      `db:"check_in_method" json:"check_in_method"`
+	AttendanceMarkedBy pgtype.Int8      `db:"attendance_marked_by" json:"attendance_marked_by"`
+}
+
+type EventQuestion struct {
+	ID        int32            `db:"id" json:"id"`
+	EventID   int32            `db:"event_id" json:"event_id"`
+	Position  int32            `db:"position" json:"position"`
+	Type      string           `db:"type" json:"type"`
+	Text      string           `db:"text" json:"text"`
+	Options   []string         `db:"options" json:"options"`
+	Required  bool             `db:"required" json:"required"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type Notification struct {
+	ID            int64            `db:"id" json:"id"`
+	UserID        int64            `db:"user_id" json:"user_id"`
+	Kind          string           `db:"kind" json:"kind"`
 	EventID       pgtype.Int4      `db:"event_id" json:"event_id"`
-	VolunteerID   pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
 	ApplicationID pgtype.Int4      `db:"application_id" json:"application_id"`
-	JoinedChatAt  pgtype.Timestamp `db:"joined_chat_at" json:"joined_chat_at"`
+	DedupKey      string           `db:"dedup_key" json:"dedup_key"`
+	Status        string           `db:"status" json:"status"`
+	Attempts      int32            `db:"attempts" json:"attempts"`
+	LastError     pgtype.Text      `db:"last_error" json:"last_error"`
+	ScheduledAt   pgtype.Timestamp `db:"scheduled_at" json:"scheduled_at"`
+	LockedAt      pgtype.Timestamp `db:"locked_at" json:"locked_at"`
+	SentAt        pgtype.Timestamp `db:"sent_at" json:"sent_at"`
+	CreatedAt     pgtype.Timestamp `db:"created_at" json:"created_at"`
 }
 
 type Organizer struct {
@@ -93,6 +183,12 @@ type User struct {
 	LocationLon pgtype.Numeric   `db:"location_lon" json:"location_lon"`
 }
 
+type UserStateParam struct {
+	UserID    int64            `db:"user_id" json:"user_id"`
+	Params    []byte           `db:"params" json:"params"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
 type Volunteer struct {
 	ID           int64       `db:"id" json:"id"`
 	About        pgtype.Text `db:"about" json:"about"`
@@ -109,4 +205,5 @@ type VolunteerApplication struct {
 	ReviewedBy      pgtype.Int8      `db:"reviewed_by" json:"reviewed_by"`
 	AppliedAt       pgtype.Timestamp `db:"applied_at" json:"applied_at"`
 	ReviewedAt      pgtype.Timestamp `db:"reviewed_at" json:"reviewed_at"`
+	Message         pgtype.Text      `db:"message" json:"message"`
 }
@@ -11,6 +11,19 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countPendingOrganizerVerificationRequests = `-- name: CountPendingOrganizerVerificationRequests :one
+SELECT COUNT(*)
+FROM organizer_verification_requests
+WHERE status = 'pending'
+`
+
+func (q *Queries) CountPendingOrganizerVerificationRequests(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countPendingOrganizerVerificationRequests)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createOrganizerVerificationRequest = `-- name: CreateOrganizerVerificationRequest :one
 INSERT INTO organizer_verification_requests (
     organizer_id,
@@ -87,6 +100,28 @@ func (q *Queries) GetLatestPendingOrganizerVerificationRequest(ctx context.Conte
 	return i, err
 }
 
+const getOrganizerVerificationRequestByID = `-- name: GetOrganizerVerificationRequestByID :one
+SELECT id, organizer_id, status, organizer_comment, admin_comment, reviewed_by, submitted_at, reviewed_at
+FROM organizer_verification_requests
+WHERE id = $1
+`
+
+func (q *Queries) GetOrganizerVerificationRequestByID(ctx context.Context, id int32) (OrganizerVerificationRequest, error) {
+	row := q.db.QueryRow(ctx, getOrganizerVerificationRequestByID, id)
+	var i OrganizerVerificationRequest
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizerID,
+		&i.Status,
+		&i.OrganizerComment,
+		&i.AdminComment,
+		&i.ReviewedBy,
+		&i.SubmittedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
 const listOrganizerVerificationRequests = `-- name: ListOrganizerVerificationRequests :many
 SELECT id, organizer_id, status, organizer_comment, admin_comment, reviewed_by, submitted_at, reviewed_at
 FROM organizer_verification_requests
@@ -131,6 +166,104 @@ func (q *Queries) ListOrganizerVerificationRequests(ctx context.Context, arg Lis
 	return items, nil
 }
 
+const listPendingOrganizerVerificationRequests = `-- name: ListPendingOrganizerVerificationRequests :many
+SELECT
+    ovr.id,
+    ovr.organizer_id,
+    ovr.submitted_at,
+    o.organization_name,
+    u.name,
+    u.username
+FROM organizer_verification_requests ovr
+JOIN organizers o ON o.id = ovr.organizer_id
+JOIN users u ON u.id = ovr.organizer_id
+WHERE ovr.status = 'pending'
+ORDER BY ovr.submitted_at ASC, ovr.id ASC
+LIMIT $2::int
+OFFSET $1::int
+`
+
+type ListPendingOrganizerVerificationRequestsParams struct {
+	Offset int32 `db:"offset" json:"offset"`
+	Limit  int32 `db:"limit" json:"limit"`
+}
+
+type ListPendingOrganizerVerificationRequestsRow struct {
+	ID               int32            `db:"id" json:"id"`
+	OrganizerID      int64            `db:"organizer_id" json:"organizer_id"`
+	SubmittedAt      pgtype.Timestamp `db:"submitted_at" json:"submitted_at"`
+	OrganizationName string           `db:"organization_name" json:"organization_name"`
+	Name             string           `db:"name" json:"name"`
+	Username         pgtype.Text      `db:"username" json:"username"`
+}
+
+func (q *Queries) ListPendingOrganizerVerificationRequests(ctx context.Context, arg ListPendingOrganizerVerificationRequestsParams) ([]ListPendingOrganizerVerificationRequestsRow, error) {
+	rows, err := q.db.Query(ctx, listPendingOrganizerVerificationRequests, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingOrganizerVerificationRequestsRow
+	for rows.Next() {
+		var i ListPendingOrganizerVerificationRequestsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizerID,
+			&i.SubmittedAt,
+			&i.OrganizationName,
+			&i.Name,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reviewOrganizerVerificationRequest = `-- name: ReviewOrganizerVerificationRequest :one
+UPDATE organizer_verification_requests
+SET
+    status = $1,
+    admin_comment = $2,
+    reviewed_by = $3,
+    reviewed_at = NOW()
+WHERE id = $4
+  AND status = 'pending'
+RETURNING id, organizer_id, status, organizer_comment, admin_comment, reviewed_by, submitted_at, reviewed_at
+`
+
+type ReviewOrganizerVerificationRequestParams struct {
+	Status       string      `db:"status" json:"status"`
+	AdminComment pgtype.Text `db:"admin_comment" json:"admin_comment"`
+	ReviewedBy   pgtype.Int8 `db:"reviewed_by" json:"reviewed_by"`
+	ID           int32       `db:"id" json:"id"`
+}
+
+func (q *Queries) ReviewOrganizerVerificationRequest(ctx context.Context, arg ReviewOrganizerVerificationRequestParams) (OrganizerVerificationRequest, error) {
+	row := q.db.QueryRow(ctx, reviewOrganizerVerificationRequest,
+		arg.Status,
+		arg.AdminComment,
+		arg.ReviewedBy,
+		arg.ID,
+	)
+	var i OrganizerVerificationRequest
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizerID,
+		&i.Status,
+		&i.OrganizerComment,
+		&i.AdminComment,
+		&i.ReviewedBy,
+		&i.SubmittedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
 const updateOrganizerVerificationRequestComment = `-- name: UpdateOrganizerVerificationRequestComment :one
 UPDATE organizer_verification_requests
 SET organizer_comment = $1,
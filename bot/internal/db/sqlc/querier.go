@@ -15,75 +15,144 @@ type Querier interface {
 	AddEventParticipant(ctx context.Context, arg AddEventParticipantParams) (EventParticipant, error)
 	BlockUser(ctx context.Context, id int64) error
 	CancelEvent(ctx context.Context, arg CancelEventParams) (Event, error)
-	CompleteEvent(ctx context.Context, id int32) (Event, error)
+	CheckInParticipant(ctx context.Context, arg CheckInParticipantParams) (EventParticipant, error)
+	CheckOutParticipant(ctx context.Context, arg CheckOutParticipantParams) (EventParticipant, error)
+	ClaimDueNotifications(ctx context.Context, arg ClaimDueNotificationsParams) ([]Notification, error)
+	// Отмечает ключ initData использованным. Возвращает 0 строк, если ключ уже занят и ещё не истёк.
+	ClaimInitDataKey(ctx context.Context, arg ClaimInitDataKeyParams) (int64, error)
+	CompleteEvent(ctx context.Context, arg CompleteEventParams) (Event, error)
+	CompletePastEvents(ctx context.Context, defaultDurationMinutes int32) ([]Event, error)
+	// Заявки, которые пропадут при удалении события: ожидающие, в листе ожидания и одобренные.
+	CountActiveApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error)
 	CountActiveCategories(ctx context.Context) (int64, error)
+	CountApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error)
+	CountApplicationsByVolunteerGroupedByStatus(ctx context.Context, volunteerID pgtype.Int8) ([]CountApplicationsByVolunteerGroupedByStatusRow, error)
 	CountAvailableEventsForVolunteer(ctx context.Context, volunteerID pgtype.Int8) (int64, error)
 	CountAvailableEventsForVolunteerWithCategories(ctx context.Context, arg CountAvailableEventsForVolunteerWithCategoriesParams) (int64, error)
 	CountEvents(ctx context.Context) (int64, error)
+	CountEventsByOrganizer(ctx context.Context, organizerID pgtype.Int8) (int64, error)
+	CountEventsForMap(ctx context.Context, arg CountEventsForMapParams) (int64, error)
+	CountEventsForMapByVolunteer(ctx context.Context, arg CountEventsForMapByVolunteerParams) (int64, error)
+	CountEventsForMapByVolunteerInBBox(ctx context.Context, arg CountEventsForMapByVolunteerInBBoxParams) (int64, error)
+	CountEventsForMapInBBox(ctx context.Context, arg CountEventsForMapInBBoxParams) (int64, error)
+	CountOrganizerEventsWithPendingApplications(ctx context.Context, organizerID pgtype.Int8) (int64, error)
 	CountParticipantsForEvent(ctx context.Context, eventID pgtype.Int4) (int64, error)
+	CountPendingApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error)
+	CountPendingOrganizerVerificationRequests(ctx context.Context) (int64, error)
+	CountVolunteerApplicationsWithEvents(ctx context.Context, arg CountVolunteerApplicationsWithEventsParams) (int64, error)
+	CountWaitlistedApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error)
 	CreateAdmin(ctx context.Context, id int64) (Admin, error)
+	CreateApplicationAnswer(ctx context.Context, arg CreateApplicationAnswerParams) error
+	CreateAuthSession(ctx context.Context, arg CreateAuthSessionParams) (AuthSession, error)
 	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	CreateEventQuestion(ctx context.Context, arg CreateEventQuestionParams) (EventQuestion, error)
 	CreateOrganizer(ctx context.Context, arg CreateOrganizerParams) (Organizer, error)
 	CreateOrganizerVerificationRequest(ctx context.Context, arg CreateOrganizerVerificationRequestParams) (OrganizerVerificationRequest, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVolunteer(ctx context.Context, arg CreateVolunteerParams) (Volunteer, error)
 	CreateVolunteerApplication(ctx context.Context, arg CreateVolunteerApplicationParams) (VolunteerApplication, error)
 	DeleteAdmin(ctx context.Context, id int64) error
+	DeleteCalendarFeedToken(ctx context.Context, userID int64) error
 	DeleteEvent(ctx context.Context, id int32) error
+	DeleteEventCheckInCode(ctx context.Context, eventID int32) error
+	DeleteEventDraft(ctx context.Context, organizerID int64) error
 	DeleteEventMedia(ctx context.Context, id int32) error
 	DeleteEventMediaByEvent(ctx context.Context, eventID pgtype.Int4) error
+	DeleteEventQuestions(ctx context.Context, eventID int32) error
+	DeleteExpiredInitDataKeys(ctx context.Context, now pgtype.Timestamp) error
 	DeleteOrganizer(ctx context.Context, id int64) error
 	DeleteParticipantsByEvent(ctx context.Context, eventID pgtype.Int4) error
 	DeleteUser(ctx context.Context, id int64) error
+	DeleteUserStateParams(ctx context.Context, userID int64) error
 	DeleteVolunteer(ctx context.Context, id int64) error
 	DeleteVolunteerApplication(ctx context.Context, id int32) error
+	EnqueueApplicationStatusNotifications(ctx context.Context, lookbackMinutes int32) (int64, error)
+	EnqueueEventReminders(ctx context.Context, arg EnqueueEventRemindersParams) (int64, error)
+	EnqueueNewApplicationNotifications(ctx context.Context, lookbackMinutes int32) (int64, error)
+	EnqueueNotification(ctx context.Context, arg EnqueueNotificationParams) error
+	ExtendAuthSession(ctx context.Context, arg ExtendAuthSessionParams) (AuthSession, error)
 	GetAdmin(ctx context.Context, id int64) (Admin, error)
+	GetAuthSessionForUpdate(ctx context.Context, id string) (AuthSession, error)
 	GetCategory(ctx context.Context, id int32) (Category, error)
 	GetCategoryByName(ctx context.Context, name string) (Category, error)
 	GetEventByID(ctx context.Context, id int32) (Event, error)
+	GetEventByIDForUpdate(ctx context.Context, id int32) (Event, error)
+	GetEventCheckInCode(ctx context.Context, eventID int32) (EventCheckInCode, error)
+	GetEventDraft(ctx context.Context, organizerID int64) (EventDraft, error)
 	GetEventMediaByID(ctx context.Context, id int32) (EventMedium, error)
 	GetEventMediaByToken(ctx context.Context, token string) (EventMedium, error)
 	GetEventParticipant(ctx context.Context, arg GetEventParticipantParams) (EventParticipant, error)
 	GetEventWithOrganizer(ctx context.Context, id int32) (GetEventWithOrganizerRow, error)
 	GetLatestPendingOrganizerVerificationRequest(ctx context.Context, organizerID int64) (OrganizerVerificationRequest, error)
+	GetNextWaitlistedApplication(ctx context.Context, eventID pgtype.Int4) (VolunteerApplication, error)
 	GetOrganizer(ctx context.Context, id int64) (Organizer, error)
+	GetOrganizerVerificationRequestByID(ctx context.Context, id int32) (OrganizerVerificationRequest, error)
 	GetOrganizerWithUser(ctx context.Context, id int64) (GetOrganizerWithUserRow, error)
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (AuthRefreshToken, error)
 	GetUserByID(ctx context.Context, id int64) (User, error)
 	GetUserByUsername(ctx context.Context, username pgtype.Text) (User, error)
+	GetUserStateParams(ctx context.Context, userID int64) ([]byte, error)
 	GetVolunteer(ctx context.Context, id int64) (Volunteer, error)
 	GetVolunteerApplication(ctx context.Context, arg GetVolunteerApplicationParams) (VolunteerApplication, error)
 	GetVolunteerApplicationByID(ctx context.Context, id int32) (VolunteerApplication, error)
+	// Суммирует время участия: от отметки прихода до ухода, а если уход не отмечен
+	// и событие завершено — до запланированного окончания события. Для события без длительности
+	// окончание считается так же, как в CompletePastEvents: через default_duration_minutes.
+	GetVolunteerHoursServed(ctx context.Context, arg GetVolunteerHoursServedParams) (GetVolunteerHoursServedRow, error)
 	GetVolunteerWithUser(ctx context.Context, id int64) (GetVolunteerWithUserRow, error)
+	GetWaitlistPosition(ctx context.Context, arg GetWaitlistPositionParams) (int64, error)
 	IncrementEventVolunteers(ctx context.Context, arg IncrementEventVolunteersParams) (pgtype.Int4, error)
+	// Сессия активна, пока не отозвана и не истекла. Время сравнивается в UTC, как его пишет сервис.
+	IsAuthSessionActive(ctx context.Context, arg IsAuthSessionActiveParams) (bool, error)
 	ListActiveCategories(ctx context.Context, arg ListActiveCategoriesParams) ([]Category, error)
 	ListAdmins(ctx context.Context, arg ListAdminsParams) ([]Admin, error)
 	ListAdminsWithUsers(ctx context.Context, arg ListAdminsWithUsersParams) ([]ListAdminsWithUsersRow, error)
+	// Ответы на анкету для набора заявок в порядке вопросов.
+	ListApplicationAnswers(ctx context.Context, applicationIds []int32) ([]ListApplicationAnswersRow, error)
 	ListApplicationsByEvent(ctx context.Context, arg ListApplicationsByEventParams) ([]VolunteerApplication, error)
 	ListApplicationsByStatus(ctx context.Context, arg ListApplicationsByStatusParams) ([]VolunteerApplication, error)
 	ListApplicationsByVolunteer(ctx context.Context, arg ListApplicationsByVolunteerParams) ([]VolunteerApplication, error)
 	ListApplicationsForOrganizer(ctx context.Context, arg ListApplicationsForOrganizerParams) ([]VolunteerApplication, error)
+	// Keyset-пагинация: следующая страница начинается после (after_date, after_id) прошлой.
 	ListAvailableEventsForVolunteer(ctx context.Context, arg ListAvailableEventsForVolunteerParams) ([]Event, error)
 	ListAvailableEventsForVolunteerWithCategories(ctx context.Context, arg ListAvailableEventsForVolunteerWithCategoriesParams) ([]Event, error)
 	ListBlockedUsers(ctx context.Context, arg ListBlockedUsersParams) ([]User, error)
+	// Лента календаря волонтёра: мероприятия, где он участник, начиная с since.
+	// Отменённые остаются в выборке, чтобы календарь пометил их у себя, а не потерял молча.
+	ListCalendarEventsForVolunteer(ctx context.Context, arg ListCalendarEventsForVolunteerParams) ([]Event, error)
 	ListCategories(ctx context.Context, arg ListCategoriesParams) ([]Category, error)
+	// Сетка кластеров считается в базе: ячейка — номер квадрата grid_size×grid_size в проекции Меркатора,
+	// как у тайлов карты. Строка — одна категория одной ячейки, сумма координат нужна для центроида.
+	ListEventClustersInBBox(ctx context.Context, arg ListEventClustersInBBoxParams) ([]ListEventClustersInBBoxRow, error)
 	ListEventMedia(ctx context.Context, arg ListEventMediaParams) ([]EventMedium, error)
 	ListEventMediaByUploader(ctx context.Context, arg ListEventMediaByUploaderParams) ([]EventMedium, error)
 	ListEventParticipants(ctx context.Context, arg ListEventParticipantsParams) ([]EventParticipant, error)
 	ListEventParticipantsWithUsers(ctx context.Context, arg ListEventParticipantsWithUsersParams) ([]ListEventParticipantsWithUsersRow, error)
+	// Лёгкие точки для крупных масштабов карты: без описаний и расстояний, только координаты и категория.
+	ListEventPointsInBBox(ctx context.Context, arg ListEventPointsInBBoxParams) ([]ListEventPointsInBBoxRow, error)
+	ListEventQuestions(ctx context.Context, eventID int32) ([]EventQuestion, error)
 	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
 	ListEventsByCategory(ctx context.Context, arg ListEventsByCategoryParams) ([]Event, error)
 	ListEventsByOrganizer(ctx context.Context, arg ListEventsByOrganizerParams) ([]Event, error)
 	ListEventsByStatus(ctx context.Context, arg ListEventsByStatusParams) ([]Event, error)
+	// earth_box отбирает кандидатов по GiST-индексу idx_events_location_earth, earth_distance отсекает углы куба.
 	ListEventsForMap(ctx context.Context, arg ListEventsForMapParams) ([]ListEventsForMapRow, error)
 	ListEventsForMapByVolunteer(ctx context.Context, arg ListEventsForMapByVolunteerParams) ([]ListEventsForMapByVolunteerRow, error)
+	ListEventsForMapByVolunteerInBBox(ctx context.Context, arg ListEventsForMapByVolunteerInBBoxParams) ([]ListEventsForMapByVolunteerInBBoxRow, error)
+	// Область карты ищется по GiST-индексу idx_events_location_point; distance_km считается от lat/lon (центра области).
+	ListEventsForMapInBBox(ctx context.Context, arg ListEventsForMapInBBoxParams) ([]ListEventsForMapInBBoxRow, error)
 	ListEventsForVolunteer(ctx context.Context, arg ListEventsForVolunteerParams) ([]Event, error)
 	ListEventsNearLocation(ctx context.Context, arg ListEventsNearLocationParams) ([]Event, error)
 	ListEventsWithPendingApplications(ctx context.Context, arg ListEventsWithPendingApplicationsParams) ([]Event, error)
+	ListOrganizerEventsWithPendingApplications(ctx context.Context, arg ListOrganizerEventsWithPendingApplicationsParams) ([]ListOrganizerEventsWithPendingApplicationsRow, error)
 	ListOrganizerVerificationRequests(ctx context.Context, arg ListOrganizerVerificationRequestsParams) ([]OrganizerVerificationRequest, error)
 	ListOrganizers(ctx context.Context, arg ListOrganizersParams) ([]Organizer, error)
 	ListParticipantEvents(ctx context.Context, arg ListParticipantEventsParams) ([]EventParticipant, error)
+	ListPendingApplicantsByEvent(ctx context.Context, arg ListPendingApplicantsByEventParams) ([]ListPendingApplicantsByEventRow, error)
 	ListPendingApplicationsByEvent(ctx context.Context, arg ListPendingApplicationsByEventParams) ([]VolunteerApplication, error)
+	ListPendingOrganizerVerificationRequests(ctx context.Context, arg ListPendingOrganizerVerificationRequestsParams) ([]ListPendingOrganizerVerificationRequestsRow, error)
 	ListUnverifiedOrganizers(ctx context.Context, arg ListUnverifiedOrganizersParams) ([]Organizer, error)
 	ListUpcomingEvents(ctx context.Context, arg ListUpcomingEventsParams) ([]Event, error)
 	ListUsersByIDs(ctx context.Context, ids []int64) ([]User, error)
@@ -91,19 +160,32 @@ type Querier interface {
 	ListUsersByState(ctx context.Context, arg ListUsersByStateParams) ([]User, error)
 	ListUsersNearLocation(ctx context.Context, arg ListUsersNearLocationParams) ([]User, error)
 	ListVerifiedOrganizers(ctx context.Context, arg ListVerifiedOrganizersParams) ([]Organizer, error)
+	// Заявки волонтёра вместе с событием, сгруппированные по статусу заявки.
+	ListVolunteerApplicationsWithEvents(ctx context.Context, arg ListVolunteerApplicationsWithEventsParams) ([]ListVolunteerApplicationsWithEventsRow, error)
 	ListVolunteers(ctx context.Context, arg ListVolunteersParams) ([]Volunteer, error)
 	ListVolunteersByCategory(ctx context.Context, arg ListVolunteersByCategoryParams) ([]Volunteer, error)
 	ListVolunteersByCategoryWithUsers(ctx context.Context, arg ListVolunteersByCategoryWithUsersParams) ([]ListVolunteersByCategoryWithUsersRow, error)
 	ListVolunteersByIDs(ctx context.Context, ids []int64) ([]Volunteer, error)
 	ListVolunteersNearLocation(ctx context.Context, arg ListVolunteersNearLocationParams) ([]ListVolunteersNearLocationRow, error)
 	ListVolunteersWithUsers(ctx context.Context, arg ListVolunteersWithUsersParams) ([]ListVolunteersWithUsersRow, error)
+	MarkNotificationCancelled(ctx context.Context, arg MarkNotificationCancelledParams) error
+	MarkNotificationFailed(ctx context.Context, arg MarkNotificationFailedParams) error
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkRefreshTokenUsed(ctx context.Context, tokenHash string) error
 	RemoveEventParticipant(ctx context.Context, arg RemoveEventParticipantParams) error
+	ResetParticipantAttendance(ctx context.Context, arg ResetParticipantAttendanceParams) (EventParticipant, error)
 	ResetVolunteerApplicationReview(ctx context.Context, id int32) (VolunteerApplication, error)
+	ReviewOrganizerVerificationRequest(ctx context.Context, arg ReviewOrganizerVerificationRequestParams) (OrganizerVerificationRequest, error)
+	RevokeAuthSession(ctx context.Context, id string) error
+	RevokeUserAuthSessions(ctx context.Context, userID int64) error
 	SearchCategories(ctx context.Context, arg SearchCategoriesParams) ([]Category, error)
 	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
 	SetCategoryActive(ctx context.Context, arg SetCategoryActiveParams) (Category, error)
 	SetEventVolunteerCounts(ctx context.Context, arg SetEventVolunteerCountsParams) (SetEventVolunteerCountsRow, error)
 	SetOrganizerVerification(ctx context.Context, arg SetOrganizerVerificationParams) (Organizer, error)
+	SetUserStateParams(ctx context.Context, arg SetUserStateParamsParams) error
+	StartDueEvents(ctx context.Context) ([]Event, error)
+	SyncEventCapacityStatuses(ctx context.Context) ([]Event, error)
 	UnblockUser(ctx context.Context, id int64) error
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
 	UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event, error)
@@ -118,10 +200,16 @@ type Querier interface {
 	UpdateVolunteerCategories(ctx context.Context, arg UpdateVolunteerCategoriesParams) (Volunteer, error)
 	UpdateVolunteerProfile(ctx context.Context, arg UpdateVolunteerProfileParams) (Volunteer, error)
 	UpdateVolunteerSearchRadius(ctx context.Context, arg UpdateVolunteerSearchRadiusParams) (Volunteer, error)
+	// Новый токен сразу заменяет прежний, старая ссылка на календарь перестаёт работать.
+	UpsertCalendarFeedToken(ctx context.Context, arg UpsertCalendarFeedTokenParams) error
+	UpsertEventCheckInCode(ctx context.Context, arg UpsertEventCheckInCodeParams) (EventCheckInCode, error)
+	UpsertEventDraft(ctx context.Context, arg UpsertEventDraftParams) (EventDraft, error)
 	UpsertOrganizer(ctx context.Context, arg UpsertOrganizerParams) (Organizer, error)
 	UpsertUser(ctx context.Context, arg UpsertUserParams) (User, error)
 	UpsertVolunteer(ctx context.Context, arg UpsertVolunteerParams) (Volunteer, error)
 	UpsertVolunteerApplication(ctx context.Context, arg UpsertVolunteerApplicationParams) (VolunteerApplication, error)
+	// Возвращает владельца токена и отмечает обращение календаря к ленте.
+	UseCalendarFeedToken(ctx context.Context, tokenHash string) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
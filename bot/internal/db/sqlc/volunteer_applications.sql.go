@@ -11,6 +11,117 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countActiveApplicationsByEvent = `-- name: CountActiveApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = $1
+  AND COALESCE(status, 'pending') IN ('pending', 'waitlisted', 'approved')
+`
+
+// Заявки, которые пропадут при удалении события: ожидающие, в листе ожидания и одобренные.
+func (q *Queries) CountActiveApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveApplicationsByEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countApplicationsByEvent = `-- name: CountApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = $1
+`
+
+func (q *Queries) CountApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error) {
+	row := q.db.QueryRow(ctx, countApplicationsByEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countApplicationsByVolunteerGroupedByStatus = `-- name: CountApplicationsByVolunteerGroupedByStatus :many
+SELECT COALESCE(status, 'pending')::text AS status, COUNT(*) AS count
+FROM volunteer_applications
+WHERE volunteer_id = $1
+GROUP BY 1
+ORDER BY 1
+`
+
+type CountApplicationsByVolunteerGroupedByStatusRow struct {
+	Status string `db:"status" json:"status"`
+	Count  int64  `db:"count" json:"count"`
+}
+
+func (q *Queries) CountApplicationsByVolunteerGroupedByStatus(ctx context.Context, volunteerID pgtype.Int8) ([]CountApplicationsByVolunteerGroupedByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countApplicationsByVolunteerGroupedByStatus, volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountApplicationsByVolunteerGroupedByStatusRow
+	for rows.Next() {
+		var i CountApplicationsByVolunteerGroupedByStatusRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countPendingApplicationsByEvent = `-- name: CountPendingApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = $1
+  AND status = 'pending'
+`
+
+func (q *Queries) CountPendingApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error) {
+	row := q.db.QueryRow(ctx, countPendingApplicationsByEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVolunteerApplicationsWithEvents = `-- name: CountVolunteerApplicationsWithEvents :one
+SELECT COUNT(*)
+FROM volunteer_applications va
+JOIN events e ON e.id = va.event_id
+WHERE va.volunteer_id = $1
+  AND ($2::text IS NULL OR va.status = $2::text)
+  AND ($3::text[] IS NULL OR e.status = ANY($3::text[]))
+`
+
+type CountVolunteerApplicationsWithEventsParams struct {
+	VolunteerID   pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+	Status        pgtype.Text `db:"status" json:"status"`
+	EventStatuses []string    `db:"event_statuses" json:"event_statuses"`
+}
+
+func (q *Queries) CountVolunteerApplicationsWithEvents(ctx context.Context, arg CountVolunteerApplicationsWithEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countVolunteerApplicationsWithEvents, arg.VolunteerID, arg.Status, arg.EventStatuses)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWaitlistedApplicationsByEvent = `-- name: CountWaitlistedApplicationsByEvent :one
+SELECT COUNT(*)
+FROM volunteer_applications
+WHERE event_id = $1
+  AND status = 'waitlisted'
+`
+
+func (q *Queries) CountWaitlistedApplicationsByEvent(ctx context.Context, eventID pgtype.Int4) (int64, error) {
+	row := q.db.QueryRow(ctx, countWaitlistedApplicationsByEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createVolunteerApplication = `-- name: CreateVolunteerApplication :one
 INSERT INTO volunteer_applications (
     event_id,
@@ -18,16 +129,18 @@ INSERT INTO volunteer_applications (
     status,
     rejection_reason,
     reviewed_by,
-    reviewed_at
+    reviewed_at,
+    message
 ) VALUES (
     $1,
     $2,
     COALESCE($3, 'pending'),
     $4,
     $5,
-    $6
+    $6,
+    $7
 )
-RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 `
 
 type CreateVolunteerApplicationParams struct {
@@ -37,6 +150,7 @@ type CreateVolunteerApplicationParams struct {
 	RejectionReason pgtype.Text      `db:"rejection_reason" json:"rejection_reason"`
 	ReviewedBy      pgtype.Int8      `db:"reviewed_by" json:"reviewed_by"`
 	ReviewedAt      pgtype.Timestamp `db:"reviewed_at" json:"reviewed_at"`
+	Message         pgtype.Text      `db:"message" json:"message"`
 }
 
 func (q *Queries) CreateVolunteerApplication(ctx context.Context, arg CreateVolunteerApplicationParams) (VolunteerApplication, error) {
@@ -47,6 +161,7 @@ func (q *Queries) CreateVolunteerApplication(ctx context.Context, arg CreateVolu
 		arg.RejectionReason,
 		arg.ReviewedBy,
 		arg.ReviewedAt,
+		arg.Message,
 	)
 	var i VolunteerApplication
 	err := row.Scan(
@@ -58,6 +173,7 @@ func (q *Queries) CreateVolunteerApplication(ctx context.Context, arg CreateVolu
 		&i.ReviewedBy,
 		&i.AppliedAt,
 		&i.ReviewedAt,
+		&i.Message,
 	)
 	return i, err
 }
@@ -72,8 +188,35 @@ func (q *Queries) DeleteVolunteerApplication(ctx context.Context, id int32) erro
 	return err
 }
 
+const getNextWaitlistedApplication = `-- name: GetNextWaitlistedApplication :one
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
+FROM volunteer_applications
+WHERE event_id = $1
+  AND status = 'waitlisted'
+ORDER BY applied_at ASC, id ASC
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetNextWaitlistedApplication(ctx context.Context, eventID pgtype.Int4) (VolunteerApplication, error) {
+	row := q.db.QueryRow(ctx, getNextWaitlistedApplication, eventID)
+	var i VolunteerApplication
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VolunteerID,
+		&i.Status,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.AppliedAt,
+		&i.ReviewedAt,
+		&i.Message,
+	)
+	return i, err
+}
+
 const getVolunteerApplication = `-- name: GetVolunteerApplication :one
-SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 FROM volunteer_applications
 WHERE event_id = $1
   AND volunteer_id = $2
@@ -96,12 +239,13 @@ func (q *Queries) GetVolunteerApplication(ctx context.Context, arg GetVolunteerA
 		&i.ReviewedBy,
 		&i.AppliedAt,
 		&i.ReviewedAt,
+		&i.Message,
 	)
 	return i, err
 }
 
 const getVolunteerApplicationByID = `-- name: GetVolunteerApplicationByID :one
-SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 FROM volunteer_applications
 WHERE id = $1
 `
@@ -118,12 +262,37 @@ func (q *Queries) GetVolunteerApplicationByID(ctx context.Context, id int32) (Vo
 		&i.ReviewedBy,
 		&i.AppliedAt,
 		&i.ReviewedAt,
+		&i.Message,
 	)
 	return i, err
 }
 
+const getWaitlistPosition = `-- name: GetWaitlistPosition :one
+SELECT COUNT(*)
+FROM volunteer_applications w
+JOIN volunteer_applications me ON me.event_id = w.event_id
+WHERE me.event_id = $1
+  AND me.volunteer_id = $2
+  AND me.status = 'waitlisted'
+  AND w.status = 'waitlisted'
+  AND (w.applied_at, w.id) <= (me.applied_at, me.id)
+`
+
+type GetWaitlistPositionParams struct {
+	EventID     pgtype.Int4 `db:"event_id" json:"event_id"`
+	VolunteerID pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+}
+
+// Позиция заявки в листе ожидания, начиная с 1. Для заявки не из листа ожидания возвращает 0.
+func (q *Queries) GetWaitlistPosition(ctx context.Context, arg GetWaitlistPositionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getWaitlistPosition, arg.EventID, arg.VolunteerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const listApplicationsByEvent = `-- name: ListApplicationsByEvent :many
-SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 FROM volunteer_applications
 WHERE event_id = $1
 ORDER BY applied_at DESC, id DESC
@@ -155,6 +324,7 @@ func (q *Queries) ListApplicationsByEvent(ctx context.Context, arg ListApplicati
 			&i.ReviewedBy,
 			&i.AppliedAt,
 			&i.ReviewedAt,
+			&i.Message,
 		); err != nil {
 			return nil, err
 		}
@@ -167,7 +337,7 @@ func (q *Queries) ListApplicationsByEvent(ctx context.Context, arg ListApplicati
 }
 
 const listApplicationsByStatus = `-- name: ListApplicationsByStatus :many
-SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 FROM volunteer_applications
 WHERE status = $1
 ORDER BY applied_at DESC, id DESC
@@ -199,6 +369,7 @@ func (q *Queries) ListApplicationsByStatus(ctx context.Context, arg ListApplicat
 			&i.ReviewedBy,
 			&i.AppliedAt,
 			&i.ReviewedAt,
+			&i.Message,
 		); err != nil {
 			return nil, err
 		}
@@ -211,7 +382,7 @@ func (q *Queries) ListApplicationsByStatus(ctx context.Context, arg ListApplicat
 }
 
 const listApplicationsByVolunteer = `-- name: ListApplicationsByVolunteer :many
-SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 FROM volunteer_applications
 WHERE volunteer_id = $1
 ORDER BY applied_at DESC, id DESC
@@ -243,6 +414,7 @@ func (q *Queries) ListApplicationsByVolunteer(ctx context.Context, arg ListAppli
 			&i.ReviewedBy,
 			&i.AppliedAt,
 			&i.ReviewedAt,
+			&i.Message,
 		); err != nil {
 			return nil, err
 		}
@@ -288,6 +460,73 @@ func (q *Queries) ListApplicationsForOrganizer(ctx context.Context, arg ListAppl
 			&i.ReviewedBy,
 			&i.AppliedAt,
 			&i.ReviewedAt,
+			&i.Message,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingApplicantsByEvent = `-- name: ListPendingApplicantsByEvent :many
+SELECT
+    va.id,
+    va.event_id,
+    va.volunteer_id,
+    va.applied_at,
+    va.message,
+    u.name,
+    u.username,
+    v.about
+FROM volunteer_applications va
+JOIN users u ON u.id = va.volunteer_id
+LEFT JOIN volunteers v ON v.id = va.volunteer_id
+WHERE va.event_id = $1
+  AND va.status = 'pending'
+ORDER BY va.applied_at ASC, va.id ASC
+LIMIT $3::int
+OFFSET $2::int
+`
+
+type ListPendingApplicantsByEventParams struct {
+	EventID pgtype.Int4 `db:"event_id" json:"event_id"`
+	Offset  int32       `db:"offset" json:"offset"`
+	Limit   int32       `db:"limit" json:"limit"`
+}
+
+type ListPendingApplicantsByEventRow struct {
+	ID          int32            `db:"id" json:"id"`
+	EventID     pgtype.Int4      `db:"event_id" json:"event_id"`
+	VolunteerID pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	AppliedAt   pgtype.Timestamp `db:"applied_at" json:"applied_at"`
+	Message     pgtype.Text      `db:"message" json:"message"`
+	Name        string           `db:"name" json:"name"`
+	Username    pgtype.Text      `db:"username" json:"username"`
+	About       pgtype.Text      `db:"about" json:"about"`
+}
+
+func (q *Queries) ListPendingApplicantsByEvent(ctx context.Context, arg ListPendingApplicantsByEventParams) ([]ListPendingApplicantsByEventRow, error) {
+	rows, err := q.db.Query(ctx, listPendingApplicantsByEvent, arg.EventID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingApplicantsByEventRow
+	for rows.Next() {
+		var i ListPendingApplicantsByEventRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VolunteerID,
+			&i.AppliedAt,
+			&i.Message,
+			&i.Name,
+			&i.Username,
+			&i.About,
 		); err != nil {
 			return nil, err
 		}
@@ -300,7 +539,7 @@ func (q *Queries) ListApplicationsForOrganizer(ctx context.Context, arg ListAppl
 }
 
 const listPendingApplicationsByEvent = `-- name: ListPendingApplicationsByEvent :many
-SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+SELECT id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 FROM volunteer_applications
 WHERE event_id = $1
   AND status = 'pending'
@@ -333,6 +572,91 @@ func (q *Queries) ListPendingApplicationsByEvent(ctx context.Context, arg ListPe
 			&i.ReviewedBy,
 			&i.AppliedAt,
 			&i.ReviewedAt,
+			&i.Message,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVolunteerApplicationsWithEvents = `-- name: ListVolunteerApplicationsWithEvents :many
+SELECT va.id, va.event_id, va.volunteer_id, va.status, va.rejection_reason, va.reviewed_by, va.applied_at, va.reviewed_at, va.message, e.title AS event_title, e.date AS event_date, e.status AS event_status
+FROM volunteer_applications va
+JOIN events e ON e.id = va.event_id
+WHERE va.volunteer_id = $1
+  AND ($2::text IS NULL OR va.status = $2::text)
+  AND ($3::text[] IS NULL OR e.status = ANY($3::text[]))
+ORDER BY
+    CASE va.status
+        WHEN 'pending' THEN 0
+        WHEN 'waitlisted' THEN 1
+        WHEN 'approved' THEN 2
+        WHEN 'rejected' THEN 3
+        ELSE 4
+    END,
+    va.applied_at DESC,
+    va.id DESC
+LIMIT $4::int
+OFFSET $5::int
+`
+
+type ListVolunteerApplicationsWithEventsParams struct {
+	VolunteerID   pgtype.Int8 `db:"volunteer_id" json:"volunteer_id"`
+	Status        pgtype.Text `db:"status" json:"status"`
+	EventStatuses []string    `db:"event_statuses" json:"event_statuses"`
+	Limit         int32       `db:"limit" json:"limit"`
+	Offset        int32       `db:"offset" json:"offset"`
+}
+
+type ListVolunteerApplicationsWithEventsRow struct {
+	ID              int32            `db:"id" json:"id"`
+	EventID         pgtype.Int4      `db:"event_id" json:"event_id"`
+	VolunteerID     pgtype.Int8      `db:"volunteer_id" json:"volunteer_id"`
+	Status          pgtype.Text      `db:"status" json:"status"`
+	RejectionReason pgtype.Text      `db:"rejection_reason" json:"rejection_reason"`
+	ReviewedBy      pgtype.Int8      `db:"reviewed_by" json:"reviewed_by"`
+	AppliedAt       pgtype.Timestamp `db:"applied_at" json:"applied_at"`
+	ReviewedAt      pgtype.Timestamp `db:"reviewed_at" json:"reviewed_at"`
+	Message         pgtype.Text      `db:"message" json:"message"`
+	EventTitle      string           `db:"event_title" json:"event_title"`
+	EventDate       pgtype.Timestamp `db:"event_date" json:"event_date"`
+	EventStatus     pgtype.Text      `db:"event_status" json:"event_status"`
+}
+
+// Заявки волонтёра вместе с событием, сгруппированные по статусу заявки.
+func (q *Queries) ListVolunteerApplicationsWithEvents(ctx context.Context, arg ListVolunteerApplicationsWithEventsParams) ([]ListVolunteerApplicationsWithEventsRow, error) {
+	rows, err := q.db.Query(ctx, listVolunteerApplicationsWithEvents,
+		arg.VolunteerID,
+		arg.Status,
+		arg.EventStatuses,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVolunteerApplicationsWithEventsRow
+	for rows.Next() {
+		var i ListVolunteerApplicationsWithEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VolunteerID,
+			&i.Status,
+			&i.RejectionReason,
+			&i.ReviewedBy,
+			&i.AppliedAt,
+			&i.ReviewedAt,
+			&i.Message,
+			&i.EventTitle,
+			&i.EventDate,
+			&i.EventStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -352,7 +676,7 @@ SET
     reviewed_by = NULL,
     reviewed_at = NULL
 WHERE id = $1
-RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 `
 
 func (q *Queries) ResetVolunteerApplicationReview(ctx context.Context, id int32) (VolunteerApplication, error) {
@@ -367,6 +691,7 @@ func (q *Queries) ResetVolunteerApplicationReview(ctx context.Context, id int32)
 		&i.ReviewedBy,
 		&i.AppliedAt,
 		&i.ReviewedAt,
+		&i.Message,
 	)
 	return i, err
 }
@@ -379,7 +704,7 @@ SET
     reviewed_by = $3,
     reviewed_at = COALESCE($4, NOW())
 WHERE id = $5
-RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 `
 
 type UpdateVolunteerApplicationStatusParams struct {
@@ -408,6 +733,7 @@ func (q *Queries) UpdateVolunteerApplicationStatus(ctx context.Context, arg Upda
 		&i.ReviewedBy,
 		&i.AppliedAt,
 		&i.ReviewedAt,
+		&i.Message,
 	)
 	return i, err
 }
@@ -434,7 +760,7 @@ SET
     rejection_reason = EXCLUDED.rejection_reason,
     reviewed_by = EXCLUDED.reviewed_by,
     reviewed_at = EXCLUDED.reviewed_at
-RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at
+RETURNING id, event_id, volunteer_id, status, rejection_reason, reviewed_by, applied_at, reviewed_at, message
 `
 
 type UpsertVolunteerApplicationParams struct {
@@ -465,6 +791,7 @@ func (q *Queries) UpsertVolunteerApplication(ctx context.Context, arg UpsertVolu
 		&i.ReviewedBy,
 		&i.AppliedAt,
 		&i.ReviewedAt,
+		&i.Message,
 	)
 	return i, err
 }
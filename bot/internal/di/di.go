@@ -11,15 +11,25 @@ import (
 
 // Services контейнер зависимостей для всех сервисов и внешних зависимостей
 type Services struct {
-	AdminService       service.AdminService
-	ApplicationService service.VolunteerApplicationService
-	CategoryService    service.CategoryService
-	EventService       service.EventService
-	ImageService       service.EventMediaService
-	OrganizerService   service.OrganizerService
-	UserService        service.UserService
-	VolunteerService   service.VolunteerService
-	API                *maxbot.Api
+	AdminService              service.AdminService
+	AttendanceService         service.AttendanceService
+	CalendarService           service.CalendarService
+	ApplicationService        service.VolunteerApplicationService
+	ReviewService             service.ApplicationReviewService
+	CategoryService           service.CategoryService
+	EventService              service.EventService
+	EventHub                  *service.EventHub
+	EventDraftService         service.EventDraftService
+	EventQuestionService      service.EventQuestionService
+	ImageService              service.EventMediaService
+	NotificationService       service.NotificationService
+	OrganizerService          service.OrganizerService
+	ParticipantService        service.EventParticipantService
+	SessionService            service.SessionService
+	UserService               service.UserService
+	VolunteerService          service.VolunteerService
+	VerificationReviewService service.VerificationReviewService
+	API                       *maxbot.Api
 }
 
 // NewServices инициализирует все сервисы и возвращает контейнер зависимостей
@@ -29,25 +39,45 @@ func NewServices(api *maxbot.Api, repo *repository.Repository) *Services {
 	}
 
 	queries := repo.Queries()
+	eventHub := service.NewEventHub()
 
 	adminService := service.NewAdminService(queries)
+	attendanceService := service.NewAttendanceService(queries, service.LoadAttendanceConfigFromEnv())
+	calendarService := service.NewCalendarService(queries)
 	applicationService := service.NewVolunteerApplicationService(queries)
+	reviewService := service.NewApplicationReviewService(repo, eventHub)
 	categoryService := service.NewCategoryService(queries)
-	eventService := service.NewEventService(queries)
+	eventService := service.NewEventService(queries, repo, eventHub)
+	eventDraftService := service.NewEventDraftService(queries)
+	eventQuestionService := service.NewEventQuestionService(queries, repo)
 	imageService := service.NewEventMediaService(queries)
+	notificationService := service.NewNotificationService(queries)
 	organizerService := service.NewOrganizerService(queries, api)
-	userService := service.NewUserService(queries)
+	participantService := service.NewEventParticipantService(queries)
+	sessionService := service.NewSessionService(queries, repo)
+	userService := service.NewUserService(queries, repo)
 	volunteerService := service.NewVolunteerService(queries)
+	verificationReviewService := service.NewVerificationReviewService(repo, api)
 
 	return &Services{
-		AdminService:       adminService,
-		ApplicationService: applicationService,
-		CategoryService:    categoryService,
-		EventService:       eventService,
-		ImageService:       imageService,
-		OrganizerService:   organizerService,
-		UserService:        userService,
-		VolunteerService:   volunteerService,
-		API:                api,
+		AdminService:              adminService,
+		AttendanceService:         attendanceService,
+		CalendarService:           calendarService,
+		ApplicationService:        applicationService,
+		ReviewService:             reviewService,
+		CategoryService:           categoryService,
+		EventService:              eventService,
+		EventHub:                  eventHub,
+		EventDraftService:         eventDraftService,
+		EventQuestionService:      eventQuestionService,
+		ImageService:              imageService,
+		NotificationService:       notificationService,
+		OrganizerService:          organizerService,
+		ParticipantService:        participantService,
+		SessionService:            sessionService,
+		UserService:               userService,
+		VolunteerService:          volunteerService,
+		VerificationReviewService: verificationReviewService,
+		API:                       api,
 	}
 }
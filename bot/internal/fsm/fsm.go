@@ -41,12 +41,16 @@ func NewUserFSM(
 				{Name: MainMenuToEvents.String(), Src: []string{MainMenu.String()}, Dst: Events.String()},
 				{Name: MainMenuToPersonalEvents.String(), Src: []string{MainMenu.String()}, Dst: PersonalEvents.String()},
 				{Name: MainMenuToVerifications.String(), Src: []string{MainMenu.String()}, Dst: Verifications.String()},
+				{Name: MainMenuToCreateEvent.String(), Src: []string{MainMenu.String()}, Dst: CreateEventTitle.String()},
+				{Name: MainMenuToReviewEvents.String(), Src: []string{MainMenu.String()}, Dst: ReviewEvents.String()},
+				{Name: MainMenuToAdminVerifications.String(), Src: []string{MainMenu.String()}, Dst: AdminVerifications.String()},
 				{Name: VerificationsToVerification.String(), Src: []string{Verifications.String()}, Dst: Verification.String()},
 				{Name: VerificationToVerifications.String(), Src: []string{Verification.String()}, Dst: Verifications.String()},
 				{Name: VerificationToReplyVerification.String(), Src: []string{Verification.String()}, Dst: ReplyVerification.String()},
 				{Name: ReplyVerificationToVerification.String(), Src: []string{ReplyVerification.String()}, Dst: Verification.String()},
 				{Name: VerificationToEditVerification.String(), Src: []string{Verification.String()}, Dst: EditVerification.String()},
 				{Name: EditVerificationToVerification.String(), Src: []string{EditVerification.String()}, Dst: Verification.String()},
+				{Name: VerificationsToMainMenu.String(), Src: []string{Verifications.String()}, Dst: MainMenu.String()},
 				{Name: EventsToCategoriesFilter.String(), Src: []string{Events.String()}, Dst: CategoriesFilter.String()},
 				{Name: CategoriesFilterToEvents.String(), Src: []string{CategoriesFilter.String()}, Dst: Events.String()},
 				{Name: EventsToGeoFilter.String(), Src: []string{Events.String()}, Dst: GeoFilter.String()},
@@ -56,6 +60,57 @@ func NewUserFSM(
 				{Name: EditGeoFilterToGeoFilter.String(), Src: []string{EditGeoFilter.String()}, Dst: GeoFilter.String()},
 				{Name: EventToEvents.String(), Src: []string{Event.String()}, Dst: Events.String()},
 				{Name: EventsToEvent.String(), Src: []string{Events.String()}, Dst: Event.String()},
+				{Name: CreateEventTitleToDescription.String(), Src: []string{CreateEventTitle.String()}, Dst: CreateEventDescription.String()},
+				{Name: CreateEventDescriptionToDate.String(), Src: []string{CreateEventDescription.String()}, Dst: CreateEventDate.String()},
+				{Name: CreateEventDateToDuration.String(), Src: []string{CreateEventDate.String()}, Dst: CreateEventDuration.String()},
+				{Name: CreateEventDurationToLocation.String(), Src: []string{CreateEventDuration.String()}, Dst: CreateEventLocation.String()},
+				{Name: CreateEventLocationToCategory.String(), Src: []string{CreateEventLocation.String()}, Dst: CreateEventCategory.String()},
+				{Name: CreateEventCategoryToMaxVolunteers.String(), Src: []string{CreateEventCategory.String()}, Dst: CreateEventMaxVolunteers.String()},
+				{Name: CreateEventMaxVolunteersToContacts.String(), Src: []string{CreateEventMaxVolunteers.String()}, Dst: CreateEventContacts.String()},
+				{Name: CreateEventContactsToConfirm.String(), Src: []string{CreateEventContacts.String()}, Dst: CreateEventConfirm.String()},
+				{Name: CreateEventConfirmToTitle.String(), Src: []string{CreateEventConfirm.String()}, Dst: CreateEventTitle.String()},
+				{Name: CreateEventConfirmToMainMenu.String(), Src: []string{CreateEventConfirm.String()}, Dst: MainMenu.String()},
+				{Name: CreateEventToMainMenu.String(), Src: []string{
+					CreateEventTitle.String(),
+					CreateEventDescription.String(),
+					CreateEventDate.String(),
+					CreateEventDuration.String(),
+					CreateEventLocation.String(),
+					CreateEventCategory.String(),
+					CreateEventMaxVolunteers.String(),
+					CreateEventContacts.String(),
+					CreateEventConfirm.String(),
+				}, Dst: MainMenu.String()},
+				{Name: ReviewEventsToReviewApplications.String(), Src: []string{ReviewEvents.String()}, Dst: ReviewApplications.String()},
+				{Name: ReviewEventsToMainMenu.String(), Src: []string{ReviewEvents.String()}, Dst: MainMenu.String()},
+				{Name: ReviewApplicationsToReviewEvents.String(), Src: []string{ReviewApplications.String()}, Dst: ReviewEvents.String()},
+				{Name: ReviewApplicationsToReviewRejectReason.String(), Src: []string{ReviewApplications.String()}, Dst: ReviewRejectReason.String()},
+				{Name: ReviewRejectReasonToReviewApplications.String(), Src: []string{ReviewRejectReason.String()}, Dst: ReviewApplications.String()},
+				{Name: AdminVerificationsToAdminVerification.String(), Src: []string{AdminVerifications.String()}, Dst: AdminVerification.String()},
+				{Name: AdminVerificationsToMainMenu.String(), Src: []string{AdminVerifications.String()}, Dst: MainMenu.String()},
+				{Name: AdminVerificationToAdminVerifications.String(), Src: []string{AdminVerification.String()}, Dst: AdminVerifications.String()},
+				{Name: AdminVerificationToAdminVerificationComment.String(), Src: []string{AdminVerification.String()}, Dst: AdminVerificationComment.String()},
+				{Name: AdminVerificationCommentToAdminVerification.String(), Src: []string{AdminVerificationComment.String()}, Dst: AdminVerification.String()},
+				{Name: AdminVerificationCommentToAdminVerifications.String(), Src: []string{AdminVerificationComment.String()}, Dst: AdminVerifications.String()},
+				{Name: MainMenuToOrganizerEvents.String(), Src: []string{MainMenu.String()}, Dst: OrganizerEvents.String()},
+				{Name: OrganizerEventsToEventAttendance.String(), Src: []string{OrganizerEvents.String()}, Dst: EventAttendance.String()},
+				{Name: OrganizerEventsToMainMenu.String(), Src: []string{OrganizerEvents.String()}, Dst: MainMenu.String()},
+				{Name: EventAttendanceToOrganizerEvents.String(), Src: []string{EventAttendance.String()}, Dst: OrganizerEvents.String()},
+				{Name: EventToCheckIn.String(), Src: []string{Event.String()}, Dst: CheckIn.String()},
+				{Name: CheckInToEvent.String(), Src: []string{CheckIn.String()}, Dst: Event.String()},
+				{Name: AboutToMainMenu.String(), Src: []string{About.String()}, Dst: MainMenu.String()},
+				{Name: AboutToProfileEdit.String(), Src: []string{About.String()}, Dst: ProfileEdit.String()},
+				{Name: AboutToCategoriesFilter.String(), Src: []string{About.String()}, Dst: CategoriesFilter.String()},
+				{Name: ProfileEditToAbout.String(), Src: []string{ProfileEdit.String()}, Dst: About.String()},
+				{Name: CategoriesFilterToAbout.String(), Src: []string{CategoriesFilter.String()}, Dst: About.String()},
+				{Name: PersonalEventsToMainMenu.String(), Src: []string{PersonalEvents.String()}, Dst: MainMenu.String()},
+				{Name: ApplicationsToMainMenu.String(), Src: []string{Applications.String()}, Dst: MainMenu.String()},
+				{Name: ApplicationsToPersonalEvents.String(), Src: []string{Applications.String()}, Dst: PersonalEvents.String()},
+				{Name: ApplicationsToEvent.String(), Src: []string{Applications.String()}, Dst: Event.String()},
+				{Name: EventToApplications.String(), Src: []string{Event.String()}, Dst: Applications.String()},
+				{Name: PersonalEventsToApplications.String(), Src: []string{PersonalEvents.String()}, Dst: Applications.String()},
+				{Name: EventToApplicationQuestionnaire.String(), Src: []string{Event.String()}, Dst: ApplicationQuestionnaire.String()},
+				{Name: ApplicationQuestionnaireToEvent.String(), Src: []string{ApplicationQuestionnaire.String()}, Dst: Event.String()},
 				{Name: Reset.String(), Src: []string{"*"}, Dst: Empty.String()},
 				{Name: Error.String(), Src: []string{"*"}, Dst: Empty.String()},
 			},
@@ -22,6 +22,26 @@ const (
 	Verification
 	ReplyVerification
 	EditVerification
+	CreateEventTitle
+	CreateEventDescription
+	CreateEventDate
+	CreateEventDuration
+	CreateEventLocation
+	CreateEventCategory
+	CreateEventMaxVolunteers
+	CreateEventContacts
+	CreateEventConfirm
+	ReviewEvents
+	ReviewApplications
+	ReviewRejectReason
+	AdminVerifications
+	AdminVerification
+	AdminVerificationComment
+	OrganizerEvents
+	EventAttendance
+	CheckIn
+	ProfileEdit
+	ApplicationQuestionnaire
 )
 
 const (
@@ -62,6 +82,61 @@ const (
 	Reset
 	Error
 	Loop
+
+	// Значения переходов попадают в payload кнопок, поэтому новые переходы добавляются
+	// только в конец списка, чтобы не сдвигать номера уже существующих.
+
+	MainMenuToCreateEvent
+	MainMenuToReviewEvents
+	MainMenuToAdminVerifications
+	VerificationsToMainMenu
+
+	CreateEventTitleToDescription
+	CreateEventDescriptionToDate
+	CreateEventDateToDuration
+	CreateEventDurationToLocation
+	CreateEventLocationToCategory
+	CreateEventCategoryToMaxVolunteers
+	CreateEventMaxVolunteersToContacts
+	CreateEventContactsToConfirm
+	CreateEventConfirmToTitle
+	CreateEventConfirmToMainMenu
+	CreateEventToMainMenu
+
+	ReviewEventsToReviewApplications
+	ReviewEventsToMainMenu
+	ReviewApplicationsToReviewEvents
+	ReviewApplicationsToReviewRejectReason
+	ReviewRejectReasonToReviewApplications
+
+	AdminVerificationsToAdminVerification
+	AdminVerificationsToMainMenu
+	AdminVerificationToAdminVerifications
+	AdminVerificationToAdminVerificationComment
+	AdminVerificationCommentToAdminVerification
+	AdminVerificationCommentToAdminVerifications
+
+	MainMenuToOrganizerEvents
+	OrganizerEventsToEventAttendance
+	OrganizerEventsToMainMenu
+	EventAttendanceToOrganizerEvents
+	EventToCheckIn
+	CheckInToEvent
+
+	AboutToMainMenu
+	AboutToProfileEdit
+	AboutToCategoriesFilter
+	ProfileEditToAbout
+	CategoriesFilterToAbout
+
+	ApplicationsToMainMenu
+	ApplicationsToPersonalEvents
+	ApplicationsToEvent
+	EventToApplications
+	PersonalEventsToApplications
+
+	EventToApplicationQuestionnaire
+	ApplicationQuestionnaireToEvent
 )
 
 func (s State) String() string {
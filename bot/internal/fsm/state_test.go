@@ -0,0 +1,23 @@
+package fsm
+
+import "testing"
+
+// Номера переходов хранятся в payload уже отправленных кнопок и не должны меняться.
+func TestTransitionValuesAreStable(t *testing.T) {
+	cases := map[Transition]int{
+		EmptyToNewUser:                 0,
+		SelectRoleToMainMenu:           2,
+		MainMenuToVerifications:        8,
+		EventToEvents:                  16,
+		EditVerificationToVerification: 26,
+		Reset:                          27,
+		Error:                          28,
+		Loop:                           29,
+		MainMenuToCreateEvent:          30,
+	}
+	for transition, want := range cases {
+		if int(transition) != want {
+			t.Errorf("transition %d, want %d", transition, want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+// ApplicationAnswer ответ волонтёра на вопрос анкеты. Для текстового вопроса и «да/нет»
+// Values содержит одно значение, для вопросов с выбором — выбранные варианты.
+// Соответствует таблице application_answers.
+type ApplicationAnswer struct {
+	ApplicationID int32
+	QuestionID    int32
+	QuestionType  string
+	QuestionText  string
+	Values        []string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// AuthSession сессия веб-приложения, к которой привязаны access и refresh токены.
+// Соответствует таблице auth_sessions.
+type AuthSession struct {
+	ID     string
+	UserID int64
+	// UserData данные пользователя из initData в JSON, из них перевыпускается access-токен.
+	UserData  []byte
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
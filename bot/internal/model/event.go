@@ -25,3 +25,12 @@ type Event struct {
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
+
+// Статусы события. Переходы между ними проверяет сервис событий.
+const (
+	EventStatusOpen       = "open"
+	EventStatusFull       = "full"
+	EventStatusInProgress = "in_progress"
+	EventStatusCompleted  = "completed"
+	EventStatusCancelled  = "cancelled"
+)
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// EventCheckInCode is a short-lived code for volunteer self check-in.
+// Соответствует таблице event_check_in_codes.
+type EventCheckInCode struct {
+	EventID   int32
+	Code      string
+	ExpiresAt time.Time
+	CreatedBy *int64
+	CreatedAt time.Time
+}
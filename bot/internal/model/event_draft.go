@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// EventDraft хранит промежуточные данные мастера создания события.
+// Соответствует таблице event_drafts.
+type EventDraft struct {
+	OrganizerID   int64
+	Title         *string
+	Description   *string
+	Date          *time.Time
+	DurationHours *int32
+	Location      *string
+	LocationLat   *float64
+	LocationLon   *float64
+	CategoryID    *int32
+	MaxVolunteers *int32
+	Contacts      *string
+	UpdatedAt     time.Time
+}
@@ -2,12 +2,23 @@ package model
 
 import "time"
 
+// Способы отметки присутствия участника.
+const (
+	CheckInMethodCode      = "code"
+	CheckInMethodGeo       = "geo"
+	CheckInMethodOrganizer = "organizer"
+)
+
 // EventParticipant represents a volunteer assigned to an event.
 // Соответствует таблице event_participants.
 type EventParticipant struct {
-	ID            int32
-	EventID       *int32
-	VolunteerID   *int64
-	ApplicationID *int32
-	JoinedChatAt  time.Time
+	ID                 int32
+	EventID            *int32
+	VolunteerID        *int64
+	ApplicationID      *int32
+	JoinedChatAt       time.Time
+	CheckedInAt        *time.Time
+	CheckedOutAt       *time.Time
+	CheckInMethod      *string
+	AttendanceMarkedBy *int64
 }
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// EventPendingSummary краткая сводка о событии организатора с заявками на рассмотрении.
+type EventPendingSummary struct {
+	ID                int32
+	Title             string
+	Date              time.Time
+	MaxVolunteers     int32
+	CurrentVolunteers *int32
+	PendingCount      int64
+}
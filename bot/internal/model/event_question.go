@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// EventQuestion вопрос анкеты, которую волонтёр заполняет при подаче заявки.
+// Соответствует таблице event_questions.
+type EventQuestion struct {
+	ID        int32
+	EventID   int32
+	Position  int32
+	Type      string
+	Text      string
+	Options   []string
+	Required  bool
+	CreatedAt time.Time
+}
+
+// Типы вопросов анкеты.
+const (
+	QuestionTypeText           = "text"
+	QuestionTypeSingleChoice   = "single_choice"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeYesNo          = "yes_no"
+)
+
+// Значения ответа на вопрос «да/нет».
+const (
+	AnswerYes = "yes"
+	AnswerNo  = "no"
+)
@@ -0,0 +1,19 @@
+package model
+
+// Типы сообщений потока изменений мероприятий.
+const (
+	EventUpdateCreated   = "created"
+	EventUpdateUpdated   = "updated"
+	EventUpdateCancelled = "cancelled"
+	EventUpdateDeleted   = "deleted"
+	EventUpdateSlots     = "slots"
+)
+
+// EventUpdate — сообщение потока изменений мероприятий для карты. Seq растёт монотонно и служит id
+// для возобновления по Last-Event-ID. Event пуст только у удалённых мероприятий.
+type EventUpdate struct {
+	Seq     uint64    `json:"-"`
+	Type    string    `json:"type"`
+	EventID int32     `json:"eventId"`
+	Event   *MapEvent `json:"event,omitempty"`
+}
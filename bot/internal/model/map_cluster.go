@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// MapBBox описывает видимую область карты в градусах.
+type MapBBox struct {
+	MinLat float64 `json:"minLat"`
+	MinLon float64 `json:"minLon"`
+	MaxLat float64 `json:"maxLat"`
+	MaxLon float64 `json:"maxLon"`
+}
+
+// MapPoint — отдельное мероприятие на карте без подробностей; детали запрашиваются по id.
+type MapPoint struct {
+	ID           int32     `json:"id"`
+	Title        string    `json:"title"`
+	Date         time.Time `json:"date"`
+	LocationLat  float64   `json:"locationLat"`
+	LocationLon  float64   `json:"locationLon"`
+	CategoryID   *int32    `json:"categoryId,omitempty"`
+	CategoryName *string   `json:"categoryName,omitempty"`
+}
+
+// MapClusterCategory — число мероприятий категории внутри кластера. CategoryID пуст для мероприятий без категории.
+type MapClusterCategory struct {
+	CategoryID   *int32  `json:"categoryId"`
+	CategoryName *string `json:"categoryName,omitempty"`
+	Count        int     `json:"count"`
+}
+
+// MapCluster — группа мероприятий одной ячейки сетки с центроидом и разбивкой по категориям.
+type MapCluster struct {
+	ID          string               `json:"id"`
+	Count       int                  `json:"count"`
+	LocationLat float64              `json:"locationLat"`
+	LocationLon float64              `json:"locationLon"`
+	Categories  []MapClusterCategory `json:"categories"`
+}
+
+// MapClusters — ответ кластеризации: на мелких масштабах заполнены Clusters, на крупных — Points.
+type MapClusters struct {
+	Zoom      int          `json:"zoom"`
+	Total     int          `json:"total"`
+	Truncated bool         `json:"truncated"`
+	Clusters  []MapCluster `json:"clusters"`
+	Points    []MapPoint   `json:"points"`
+}
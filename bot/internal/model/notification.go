@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Notification задание планировщика на отправку сообщения пользователю.
+// Соответствует таблице notifications.
+type Notification struct {
+	ID            int64
+	UserID        int64
+	Kind          string
+	EventID       *int32
+	ApplicationID *int32
+	Status        string
+	Attempts      int32
+	LastError     *string
+	ScheduledAt   time.Time
+	SentAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// Виды уведомлений планировщика.
+const (
+	NotificationEventReminder24h   = "event_reminder_24h"
+	NotificationEventReminder2h    = "event_reminder_2h"
+	NotificationApplicationStatus  = "application_status"
+	NotificationApplicationCreated = "application_created"
+	NotificationWaitlistPromoted   = "waitlist_promoted"
+)
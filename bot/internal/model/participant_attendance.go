@@ -0,0 +1,9 @@
+package model
+
+// ParticipantAttendance is an event participant together with user info
+// for the organizer attendance screen.
+type ParticipantAttendance struct {
+	Participant EventParticipant
+	Username    *string
+	Name        string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// PendingApplicant заявка на рассмотрении вместе с профилем волонтёра.
+type PendingApplicant struct {
+	ApplicationID int32
+	EventID       *int32
+	VolunteerID   *int64
+	Name          string
+	Username      *string
+	About         *string
+	Message       *string
+	AppliedAt     time.Time
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// PendingVerificationRequest заявка на верификацию в очереди администратора.
+type PendingVerificationRequest struct {
+	ID               int32
+	OrganizerID      int64
+	OrganizationName string
+	Name             string
+	Username         *string
+	SubmittedAt      time.Time
+}
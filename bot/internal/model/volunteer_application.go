@@ -12,4 +12,5 @@ type VolunteerApplication struct {
 	ReviewedBy      *int64
 	AppliedAt       time.Time
 	ReviewedAt      *time.Time
+	Message         *string
 }
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// VolunteerApplicationWithEvent is a volunteer's application together with
+// the event summary for the "My applications" screen.
+type VolunteerApplicationWithEvent struct {
+	Application VolunteerApplication
+	EventTitle  string
+	EventDate   time.Time
+	EventStatus *string
+}
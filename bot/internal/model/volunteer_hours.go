@@ -0,0 +1,7 @@
+package model
+
+// VolunteerHours aggregates attendance of a volunteer across events.
+type VolunteerHours struct {
+	HoursServed    float64
+	EventsAttended int64
+}
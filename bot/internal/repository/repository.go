@@ -24,7 +24,15 @@ func New(ctx context.Context, dsn, migrationsPath string) (*Repository, error) {
 		return nil, fmt.Errorf("database dsn is required")
 	}
 
-	pool, err := pgxpool.New(ctx, dsn)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse dsn: %w", err)
+	}
+	// Колонки времени хранятся без часового пояса и содержат UTC, поэтому NOW() в запросах
+	// тоже должен возвращать UTC независимо от настроек сервера.
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create pool: %w", err)
 	}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+)
+
+const (
+	// defaultTxMaxRetries количество повторов транзакции после конфликта сериализации.
+	defaultTxMaxRetries = 3
+	// txRetryBaseDelay базовая задержка перед повтором, растёт экспоненциально.
+	txRetryBaseDelay = 20 * time.Millisecond
+)
+
+// TxOptions describes how WithTx runs a transaction.
+type TxOptions struct {
+	IsoLevel   pgx.TxIsoLevel
+	AccessMode pgx.TxAccessMode
+	MaxRetries int
+}
+
+// TxOption customizes TxOptions for a single WithTx call.
+type TxOption func(*TxOptions)
+
+// WithIsolation sets the transaction isolation level.
+func WithIsolation(level pgx.TxIsoLevel) TxOption {
+	return func(o *TxOptions) {
+		o.IsoLevel = level
+	}
+}
+
+// WithReadOnly marks the transaction as read-only.
+func WithReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.AccessMode = pgx.ReadOnly
+	}
+}
+
+// WithMaxRetries sets how many times a transaction is retried after a serialization failure or deadlock.
+func WithMaxRetries(n int) TxOption {
+	return func(o *TxOptions) {
+		if n < 0 {
+			n = 0
+		}
+		o.MaxRetries = n
+	}
+}
+
+// WithTx runs fn inside a database transaction and commits it when fn returns nil.
+// The transaction is rolled back on error and retried on serialization failures and deadlocks,
+// so fn must be safe to call several times and must not keep side effects outside the database.
+func (r *Repository) WithTx(ctx context.Context, fn func(q dbsqlc.Querier) error, opts ...TxOption) error {
+	options := TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadWrite,
+		MaxRetries: defaultTxMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return retryTx(ctx, options.MaxRetries, func() error {
+		return r.runTx(ctx, options, fn)
+	})
+}
+
+// retryTx вызывает run и повторяет его после конфликта сериализации или дедлока, но не больше maxRetries раз.
+func retryTx(ctx context.Context, maxRetries int, run func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := run()
+		if err == nil || !isRetryableTxError(err) || attempt >= maxRetries {
+			return err
+		}
+
+		// Экспоненциальная задержка с джиттером, чтобы конкурирующие транзакции разошлись.
+		delay := txRetryBaseDelay << attempt
+		delay += time.Duration(rand.Int64N(int64(delay)))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *Repository) runTx(ctx context.Context, options TxOptions, fn func(q dbsqlc.Querier) error) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   options.IsoLevel,
+		AccessMode: options.AccessMode,
+	})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(r.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// isRetryableTxError сообщает, можно ли повторить транзакцию: 40001 serialization_failure, 40P01 deadlock_detected.
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
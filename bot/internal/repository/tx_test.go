@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsRetryableTxError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"serialization failure": {&pgconn.PgError{Code: "40001"}, true},
+		"deadlock":              {&pgconn.PgError{Code: "40P01"}, true},
+		"wrapped":               {fmt.Errorf("commit tx: %w", &pgconn.PgError{Code: "40001"}), true},
+		"unique violation":      {&pgconn.PgError{Code: "23505"}, false},
+		"lock not available":    {&pgconn.PgError{Code: "55P03"}, false},
+		"no rows":               {pgx.ErrNoRows, false},
+		"plain":                 {errors.New("boom"), false},
+		"nil":                   {nil, false},
+	}
+	for name, tc := range cases {
+		if got := isRetryableTxError(tc.err); got != tc.want {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestRetryTxRetriesConflicts(t *testing.T) {
+	conflict := &pgconn.PgError{Code: "40001"}
+	cases := map[string]struct {
+		maxRetries int
+		failures   int
+		err        error
+		wantCalls  int
+		wantErr    error
+	}{
+		"success":                {maxRetries: 3, failures: 0, wantCalls: 1},
+		"recovers after retries": {maxRetries: 3, failures: 2, err: conflict, wantCalls: 3},
+		"gives up":               {maxRetries: 2, failures: 10, err: conflict, wantCalls: 3, wantErr: conflict},
+		"no retries":             {maxRetries: 0, failures: 10, err: conflict, wantCalls: 1, wantErr: conflict},
+		"not retryable":          {maxRetries: 3, failures: 10, err: pgx.ErrNoRows, wantCalls: 1, wantErr: pgx.ErrNoRows},
+	}
+	for name, tc := range cases {
+		calls := 0
+		err := retryTx(context.Background(), tc.maxRetries, func() error {
+			calls++
+			if calls <= tc.failures {
+				return tc.err
+			}
+			return nil
+		})
+		if calls != tc.wantCalls {
+			t.Errorf("%s: %d calls, want %d", name, calls, tc.wantCalls)
+		}
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRetryTxStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retryTx(ctx, 5, func() error {
+		calls++
+		cancel()
+		return &pgconn.PgError{Code: "40P01"}
+	})
+	if calls != 1 {
+		t.Fatalf("%d calls after cancel, want 1", calls)
+	}
+	if !errors.Is(err, context.Canceled) || !isRetryableTxError(err) {
+		t.Fatalf("got %v, want the conflict joined with context.Canceled", err)
+	}
+}
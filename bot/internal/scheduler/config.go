@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInterval    = time.Minute
+	defaultBatchSize   = 50
+	defaultMaxAttempts = 5
+	defaultRetryDelay  = 5 * time.Minute
+	defaultLockTimeout = 10 * time.Minute
+	defaultLookback    = 24 * time.Hour
+	// defaultEventDuration считается длительностью события, если организатор её не указал.
+	defaultEventDuration = 3 * time.Hour
+)
+
+// Config описывает настройки планировщика уведомлений.
+type Config struct {
+	// Enabled позволяет отключить планировщик, например на втором экземпляре бота.
+	Enabled bool
+	// Interval период между проходами планировщика.
+	Interval time.Duration
+	// BatchSize сколько уведомлений отправляется за один проход.
+	BatchSize int32
+	// MaxAttempts после стольких неудачных отправок уведомление помечается failed.
+	MaxAttempts int32
+	// RetryDelay задержка перед повторной отправкой.
+	RetryDelay time.Duration
+	// LockTimeout через сколько зависшее в статусе sending уведомление снова берётся в работу.
+	LockTimeout time.Duration
+	// Lookback за какой период ищутся новые и рассмотренные заявки.
+	Lookback time.Duration
+	// DefaultEventDuration через сколько после начала завершается событие без указанной длительности.
+	DefaultEventDuration time.Duration
+}
+
+// LoadConfigFromEnv читает настройки из переменных окружения.
+func LoadConfigFromEnv() Config {
+	cfg := Config{
+		Enabled:              boolOrDefault(os.Getenv("SCHEDULER_ENABLED"), true),
+		Interval:             durationOrDefault(os.Getenv("SCHEDULER_INTERVAL"), defaultInterval),
+		BatchSize:            int32OrDefault(os.Getenv("SCHEDULER_BATCH_SIZE"), defaultBatchSize),
+		MaxAttempts:          int32OrDefault(os.Getenv("SCHEDULER_MAX_ATTEMPTS"), defaultMaxAttempts),
+		RetryDelay:           durationOrDefault(os.Getenv("SCHEDULER_RETRY_DELAY"), defaultRetryDelay),
+		LockTimeout:          durationOrDefault(os.Getenv("SCHEDULER_LOCK_TIMEOUT"), defaultLockTimeout),
+		Lookback:             durationOrDefault(os.Getenv("SCHEDULER_LOOKBACK"), defaultLookback),
+		DefaultEventDuration: durationOrDefault(os.Getenv("SCHEDULER_DEFAULT_EVENT_DURATION"), defaultEventDuration),
+	}
+	return cfg
+}
+
+func durationOrDefault(val string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(val))
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+func int32OrDefault(val string, def int32) int32 {
+	n, err := strconv.ParseInt(strings.TrimSpace(val), 10, 32)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return int32(n)
+}
+
+func boolOrDefault(val string, def bool) bool {
+	b, err := strconv.ParseBool(strings.TrimSpace(val))
+	if err != nil {
+		return def
+	}
+	return b
+}
@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"maxBot/internal/model"
+)
+
+// render собирает текст уведомления по актуальным данным. Возвращает errObsolete,
+// если событие отменено, заявку отозвали или волонтёр больше не участвует.
+func (s *Scheduler) render(ctx context.Context, notification model.Notification) (string, error) {
+	switch notification.Kind {
+	case model.NotificationEventReminder24h, model.NotificationEventReminder2h:
+		return s.renderEventReminder(ctx, notification)
+	case model.NotificationApplicationStatus:
+		return s.renderApplicationStatus(ctx, notification)
+	case model.NotificationApplicationCreated:
+		return s.renderApplicationCreated(ctx, notification)
+	case model.NotificationWaitlistPromoted:
+		return s.renderWaitlistPromoted(ctx, notification)
+	default:
+		return "", fmt.Errorf("%w: unknown kind %q", errObsolete, notification.Kind)
+	}
+}
+
+func (s *Scheduler) renderEventReminder(ctx context.Context, notification model.Notification) (string, error) {
+	event, err := s.activeEvent(ctx, notification.EventID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.services.ParticipantService.GetEventParticipant(ctx, &event.ID, &notification.UserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w: user is not a participant", errObsolete)
+		}
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Напоминание: %s начинается событие «%s».\n\n", reminderWhen(event.Date, time.Now()), event.Title))
+	builder.WriteString(fmt.Sprintf("Когда: %s\n", event.Date.Local().Format("02.01.2006 15:04")))
+	builder.WriteString(fmt.Sprintf("Где: %s\n", event.Location))
+	if event.Contacts != nil && *event.Contacts != "" {
+		builder.WriteString(fmt.Sprintf("Контакты организатора: %s\n", *event.Contacts))
+	}
+	return builder.String(), nil
+}
+
+// reminderWhen описывает начало события относительно момента отправки. Напоминание за сутки ставится
+// и на события, до которых осталось меньше суток, а отправка может задержаться, поэтому текст
+// строится по фактическому времени, а не по виду напоминания.
+func reminderWhen(start, now time.Time) string {
+	start, now = start.Local(), now.Local()
+	if start.Sub(now) < time.Hour {
+		return "меньше чем через час"
+	}
+	year, month, day := now.Date()
+	tomorrow := time.Date(year, month, day+1, 0, 0, 0, 0, time.Local)
+	switch {
+	case start.Before(tomorrow):
+		return "сегодня в " + start.Format("15:04")
+	case start.Before(tomorrow.AddDate(0, 0, 1)):
+		return "завтра в " + start.Format("15:04")
+	default:
+		return start.Format("02.01.2006 в 15:04")
+	}
+}
+
+func (s *Scheduler) renderApplicationStatus(ctx context.Context, notification model.Notification) (string, error) {
+	application, err := s.application(ctx, notification.ApplicationID)
+	if err != nil {
+		return "", err
+	}
+	event, err := s.activeEvent(ctx, application.EventID)
+	if err != nil {
+		return "", err
+	}
+
+	status := ""
+	if application.Status != nil {
+		status = *application.Status
+	}
+	switch status {
+	case "approved":
+		return fmt.Sprintf("Ваша заявка на событие «%s» одобрена! Ждём вас %s по адресу: %s.",
+			event.Title, event.Date.Local().Format("02.01.2006 15:04"), event.Location), nil
+	case "rejected":
+		text := fmt.Sprintf("К сожалению, ваша заявка на событие «%s» отклонена.", event.Title)
+		if application.RejectionReason != nil && *application.RejectionReason != "" {
+			text += "\n\nПричина: " + *application.RejectionReason
+		}
+		return text, nil
+	default:
+		// Решение успели отменить, пока уведомление ждало отправки.
+		return "", fmt.Errorf("%w: application status is %q", errObsolete, status)
+	}
+}
+
+func (s *Scheduler) renderApplicationCreated(ctx context.Context, notification model.Notification) (string, error) {
+	application, err := s.application(ctx, notification.ApplicationID)
+	if err != nil {
+		return "", err
+	}
+	if application.Status == nil || *application.Status != "pending" {
+		return "", fmt.Errorf("%w: application is already reviewed", errObsolete)
+	}
+	event, err := s.activeEvent(ctx, application.EventID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Новая заявка на событие «%s». Откройте «Заявки волонтёров» в главном меню, чтобы её рассмотреть.", event.Title), nil
+}
+
+func (s *Scheduler) renderWaitlistPromoted(ctx context.Context, notification model.Notification) (string, error) {
+	application, err := s.application(ctx, notification.ApplicationID)
+	if err != nil {
+		return "", err
+	}
+	if application.Status == nil || *application.Status != "approved" {
+		return "", fmt.Errorf("%w: application is no longer approved", errObsolete)
+	}
+	event, err := s.activeEvent(ctx, application.EventID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Освободилось место! Вы переведены из листа ожидания в участники события «%s». Ждём вас %s по адресу: %s.",
+		event.Title, event.Date.Local().Format("02.01.2006 15:04"), event.Location), nil
+}
+
+// activeEvent загружает событие и проверяет, что оно ещё не отменено и не завершено.
+func (s *Scheduler) activeEvent(ctx context.Context, eventID *int32) (model.Event, error) {
+	if eventID == nil {
+		return model.Event{}, fmt.Errorf("%w: event is not set", errObsolete)
+	}
+	event, err := s.services.EventService.GetEventByID(ctx, *eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Event{}, fmt.Errorf("%w: event not found", errObsolete)
+	}
+	if err != nil {
+		return model.Event{}, err
+	}
+	if event.Status != nil && (*event.Status == "cancelled" || *event.Status == "completed") {
+		return model.Event{}, fmt.Errorf("%w: event is %s", errObsolete, *event.Status)
+	}
+	return event, nil
+}
+
+func (s *Scheduler) application(ctx context.Context, applicationID *int32) (model.VolunteerApplication, error) {
+	if applicationID == nil {
+		return model.VolunteerApplication{}, fmt.Errorf("%w: application is not set", errObsolete)
+	}
+	application, err := s.services.ApplicationService.GetVolunteerApplicationByID(ctx, *applicationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.VolunteerApplication{}, fmt.Errorf("%w: application not found", errObsolete)
+	}
+	if err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	return application, nil
+}
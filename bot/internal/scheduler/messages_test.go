@@ -0,0 +1,26 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderWhen(t *testing.T) {
+	now := time.Date(2025, 11, 20, 18, 0, 0, 0, time.Local)
+	cases := map[string]struct {
+		start time.Time
+		want  string
+	}{
+		"in half an hour":    {now.Add(30 * time.Minute), "меньше чем через час"},
+		"later today":        {time.Date(2025, 11, 20, 21, 30, 0, 0, time.Local), "сегодня в 21:30"},
+		"after midnight":     {time.Date(2025, 11, 21, 1, 0, 0, 0, time.Local), "завтра в 01:00"},
+		"tomorrow same time": {now.Add(24 * time.Hour), "завтра в 18:00"},
+		"day after tomorrow": {time.Date(2025, 11, 22, 9, 0, 0, 0, time.Local), "22.11.2025 в 09:00"},
+		"stored in utc":      {time.Date(2025, 11, 20, 21, 30, 0, 0, time.Local).UTC(), "сегодня в 21:30"},
+	}
+	for name, tc := range cases {
+		if got := reminderWhen(tc.start, now); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+}
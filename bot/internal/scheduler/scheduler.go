@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	maxbot "github.com/rectid/max-bot-api-client-go"
+
+	"maxBot/internal/di"
+	"maxBot/internal/model"
+)
+
+const (
+	reminder24hLead = 24 * time.Hour
+	reminder2hLead  = 2 * time.Hour
+	// releaseTimeout время на запись результата отправки после остановки планировщика.
+	releaseTimeout = 5 * time.Second
+)
+
+// errObsolete означает, что уведомление больше не актуально и отправлять его не нужно.
+var errObsolete = errors.New("notification is obsolete")
+
+// Scheduler периодически переводит события по жизненному циклу, ставит уведомления
+// в очередь (таблица notifications) и рассылает их.
+// Очередь хранится в БД, поэтому после перезапуска бота уведомления не теряются и не дублируются.
+type Scheduler struct {
+	cfg      Config
+	services *di.Services
+}
+
+// New создаёт планировщик уведомлений.
+func New(cfg Config, services *di.Services) (*Scheduler, error) {
+	if services == nil {
+		return nil, fmt.Errorf("services are required")
+	}
+	if services.API == nil {
+		return nil, fmt.Errorf("api client is required")
+	}
+	return &Scheduler{cfg: cfg, services: services}, nil
+}
+
+// Run выполняет проходы планировщика раз в Interval, пока не отменён ctx.
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Scheduler started, interval %s", s.cfg.Interval)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick обновляет статусы событий, ставит новые уведомления в очередь и отправляет готовые.
+func (s *Scheduler) tick(ctx context.Context) {
+	if _, err := s.services.EventService.AdvanceEventStatuses(ctx, s.cfg.DefaultEventDuration); err != nil && ctx.Err() == nil {
+		log.Printf("scheduler: advance event statuses: %v", err)
+	}
+	if err := s.enqueue(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("scheduler: enqueue notifications: %v", err)
+	}
+	if err := s.dispatch(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("scheduler: dispatch notifications: %v", err)
+	}
+}
+
+func (s *Scheduler) enqueue(ctx context.Context) error {
+	notifications := s.services.NotificationService
+
+	if _, err := notifications.EnqueueEventReminders(ctx, model.NotificationEventReminder24h, reminder2hLead, reminder24hLead); err != nil {
+		return fmt.Errorf("24h reminders: %w", err)
+	}
+	if _, err := notifications.EnqueueEventReminders(ctx, model.NotificationEventReminder2h, 0, reminder2hLead); err != nil {
+		return fmt.Errorf("2h reminders: %w", err)
+	}
+	if _, err := notifications.EnqueueApplicationStatusNotifications(ctx, s.cfg.Lookback); err != nil {
+		return fmt.Errorf("application status: %w", err)
+	}
+	if _, err := notifications.EnqueueNewApplicationNotifications(ctx, s.cfg.Lookback); err != nil {
+		return fmt.Errorf("new applications: %w", err)
+	}
+	return nil
+}
+
+// dispatch отправляет одну пачку уведомлений. Уведомления, которые не успели отправить до остановки,
+// остаются в статусе sending и будут взяты снова после LockTimeout.
+func (s *Scheduler) dispatch(ctx context.Context) error {
+	items, err := s.services.NotificationService.ClaimDueNotifications(ctx, s.cfg.BatchSize, s.cfg.LockTimeout)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return nil
+		}
+		s.deliver(ctx, item)
+	}
+	return nil
+}
+
+// deliver отправляет уведомление и сохраняет результат отправки.
+func (s *Scheduler) deliver(ctx context.Context, notification model.Notification) {
+	text, err := s.render(ctx, notification)
+	if err == nil {
+		msg := maxbot.NewMessage().
+			SetUser(notification.UserID).
+			SetText(text)
+		_, err = s.services.API.Messages.Send(ctx, msg)
+	}
+
+	// Результат записываем даже если ctx уже отменён, иначе успешная отправка повторится.
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	switch {
+	case err == nil:
+		err = s.services.NotificationService.MarkNotificationSent(saveCtx, notification.ID)
+	case errors.Is(err, errObsolete):
+		err = s.services.NotificationService.MarkNotificationCancelled(saveCtx, notification.ID, err.Error())
+	default:
+		log.Printf("scheduler: send notification %d (%s) to user %d: %v", notification.ID, notification.Kind, notification.UserID, err)
+		err = s.services.NotificationService.MarkNotificationFailed(saveCtx, notification.ID, err.Error(), s.cfg.MaxAttempts, s.cfg.RetryDelay)
+	}
+	if err != nil {
+		log.Printf("scheduler: save notification %d result: %v", notification.ID, err)
+	}
+}
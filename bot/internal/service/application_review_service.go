@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+var (
+	// ErrApplicationNotFound заявка не найдена.
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrApplicationNotPending заявка уже рассмотрена.
+	ErrApplicationNotPending = errors.New("application is not pending")
+	// ErrNotEventOwner пользователь не является организатором события.
+	ErrNotEventOwner = errors.New("user is not the event organizer")
+	// ErrEventFull на событии не осталось свободных мест.
+	ErrEventFull = errors.New("event has no free slots")
+	// ErrEventNotOpen событие уже началось, завершено или отменено.
+	ErrEventNotOpen = errors.New("event is not accepting volunteers")
+	// ErrEventHasFreeSlots на событии есть места, лист ожидания не нужен.
+	ErrEventHasFreeSlots = errors.New("event still has free slots")
+	// ErrAlreadyApplied волонтёр уже подал заявку на событие.
+	ErrAlreadyApplied = errors.New("volunteer has already applied to the event")
+	// ErrParticipantNotFound волонтёр не участвует в событии.
+	ErrParticipantNotFound = errors.New("participant not found")
+	// ErrApplicationNotWithdrawable заявка уже закрыта или событие уже началось.
+	ErrApplicationNotWithdrawable = errors.New("application cannot be withdrawn")
+)
+
+// ApplicationReviewService handles organizer decisions on volunteer applications, their withdrawal
+// and the waitlist of full events.
+type ApplicationReviewService interface {
+	ApproveApplication(ctx context.Context, applicationID int32, organizerID int64) (model.VolunteerApplication, error)
+	RejectApplication(ctx context.Context, applicationID int32, organizerID int64, reason *string) (model.VolunteerApplication, error)
+	WithdrawApplication(ctx context.Context, applicationID int32, volunteerID int64) error
+	ApplyToEvent(ctx context.Context, eventID int32, volunteerID int64, message *string, answers []model.ApplicationAnswer) (model.VolunteerApplication, error)
+	JoinWaitlist(ctx context.Context, eventID int32, volunteerID int64) (model.VolunteerApplication, error)
+	RemoveParticipant(ctx context.Context, eventID int32, volunteerID, organizerID int64) error
+}
+
+type applicationReviewService struct {
+	tx  TxRunner
+	hub *EventHub
+}
+
+// NewApplicationReviewService создаёт сервис заявок. Изменение числа мест публикуется в hub после коммита.
+func NewApplicationReviewService(tx TxRunner, hub *EventHub) ApplicationReviewService {
+	return &applicationReviewService{tx: tx, hub: hub}
+}
+
+// ApproveApplication одобряет заявку, добавляет волонтёра в участники и увеличивает счётчик в одной транзакции.
+// Строка события блокируется, поэтому параллельные одобрения не переполнят событие.
+func (s *applicationReviewService) ApproveApplication(ctx context.Context, applicationID int32, organizerID int64) (model.VolunteerApplication, error) {
+	var (
+		approved model.VolunteerApplication
+		changed  model.Event
+	)
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		application, event, err := lockApplicationForReview(ctx, q, applicationID, organizerID)
+		if err != nil {
+			return err
+		}
+		if !acceptsVolunteers(event) {
+			return ErrEventNotOpen
+		}
+		if !hasFreeSlots(event) {
+			return ErrEventFull
+		}
+
+		approved, err = NewVolunteerApplicationService(q).UpdateVolunteerApplicationStatus(ctx, application.ID, "approved", nil, &organizerID)
+		if err != nil {
+			return fmt.Errorf("update application: %w", err)
+		}
+		if _, err := NewEventParticipantService(q).AddEventParticipant(ctx, application.EventID, application.VolunteerID, &application.ID, nil); err != nil {
+			return fmt.Errorf("add participant: %w", err)
+		}
+		if _, err := NewEventService(q, nil, nil).IncrementEventVolunteers(ctx, event.ID, 1); err != nil {
+			return fmt.Errorf("increment volunteers: %w", err)
+		}
+		changed, err = NewEventService(q, nil, nil).GetEventByID(ctx, event.ID)
+		return err
+	})
+	if err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	s.hub.publishEvent(model.EventUpdateSlots, changed)
+	return approved, nil
+}
+
+// RejectApplication отклоняет заявку с необязательной причиной.
+func (s *applicationReviewService) RejectApplication(ctx context.Context, applicationID int32, organizerID int64, reason *string) (model.VolunteerApplication, error) {
+	var rejected model.VolunteerApplication
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		application, _, err := lockApplicationForReview(ctx, q, applicationID, organizerID)
+		if err != nil {
+			return err
+		}
+		rejected, err = NewVolunteerApplicationService(q).UpdateVolunteerApplicationStatus(ctx, application.ID, "rejected", reason, &organizerID)
+		return err
+	})
+	if err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	return rejected, nil
+}
+
+// WithdrawApplication отзывает заявку волонтёра. Если заявка уже была одобрена, волонтёр удаляется
+// из участников, а на освободившееся место переводится первый из листа ожидания.
+// Закрытую заявку и участие в начавшемся событии отозвать нельзя — возвращается ErrApplicationNotWithdrawable.
+func (s *applicationReviewService) WithdrawApplication(ctx context.Context, applicationID int32, volunteerID int64) error {
+	var changed *model.Event
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		application, err := getApplicationForReview(ctx, q, applicationID)
+		if err != nil {
+			return err
+		}
+		if *application.VolunteerID != volunteerID {
+			return ErrApplicationNotFound
+		}
+		event, err := lockEvent(ctx, q, *application.EventID)
+		if err != nil {
+			return err
+		}
+		if !CanWithdrawApplication(application.Status, event.Status) {
+			return ErrApplicationNotWithdrawable
+		}
+
+		removed, err := removeParticipant(ctx, q, event.ID, volunteerID)
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteVolunteerApplication(ctx, application.ID); err != nil {
+			return fmt.Errorf("delete application: %w", err)
+		}
+		if !removed {
+			return nil
+		}
+		if err := promoteFromWaitlist(ctx, q, event.ID); err != nil {
+			return err
+		}
+		snapshot, err := NewEventService(q, nil, nil).GetEventByID(ctx, event.ID)
+		changed = &snapshot
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if changed != nil {
+		s.hub.publishEvent(model.EventUpdateSlots, *changed)
+	}
+	return nil
+}
+
+// CanWithdrawApplication сообщает, можно ли отозвать заявку: ожидающую рассмотрения или место
+// в листе ожидания — всегда, одобренную — пока событие открыто или заполнено. Отклонённая заявка
+// не отзывается, иначе её можно было бы подать заново.
+func CanWithdrawApplication(applicationStatus, currentEventStatus *string) bool {
+	if applicationStatus == nil {
+		return true
+	}
+	switch *applicationStatus {
+	case "pending", "waitlisted":
+		return true
+	case "approved":
+		return acceptsVolunteers(model.Event{Status: currentEventStatus})
+	default:
+		return false
+	}
+}
+
+// ApplyToEvent подаёт заявку волонтёра на событие со свободными местами. message — необязательное
+// сопроводительное сообщение, answers — ответы на анкету события; они проверяются по вопросам анкеты
+// и сохраняются вместе с заявкой. Если мест нет, возвращается ErrEventFull и нужно встать в лист ожидания.
+func (s *applicationReviewService) ApplyToEvent(ctx context.Context, eventID int32, volunteerID int64, message *string, answers []model.ApplicationAnswer) (model.VolunteerApplication, error) {
+	var applied model.VolunteerApplication
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		event, err := lockEvent(ctx, q, eventID)
+		if err != nil {
+			return err
+		}
+		if !acceptsVolunteers(event) {
+			return ErrEventNotOpen
+		}
+		if !hasFreeSlots(event) {
+			return ErrEventFull
+		}
+		if err := ensureNotApplied(ctx, q, eventID, volunteerID); err != nil {
+			return err
+		}
+		questions, err := q.ListEventQuestions(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("list questions: %w", err)
+		}
+		validAnswers, err := validateAnswers(mapEventQuestions(questions), answers)
+		if err != nil {
+			return err
+		}
+
+		row, err := q.CreateVolunteerApplication(ctx, dbsqlc.CreateVolunteerApplicationParams{
+			EventID:     int32ToInt4(eventID),
+			VolunteerID: int64ToInt8(volunteerID),
+			Status:      nil, // будет "pending" по умолчанию
+			Message:     stringPtrToText(message),
+		})
+		if err != nil {
+			return fmt.Errorf("create application: %w", err)
+		}
+		for _, answer := range validAnswers {
+			if err := q.CreateApplicationAnswer(ctx, dbsqlc.CreateApplicationAnswerParams{
+				ApplicationID: row.ID,
+				QuestionID:    answer.QuestionID,
+				Answer:        answer.Values,
+			}); err != nil {
+				return fmt.Errorf("create answer: %w", err)
+			}
+		}
+		applied = mapVolunteerApplication(row)
+		return nil
+	})
+	if err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	return applied, nil
+}
+
+// JoinWaitlist ставит волонтёра в лист ожидания события, на котором не осталось мест.
+func (s *applicationReviewService) JoinWaitlist(ctx context.Context, eventID int32, volunteerID int64) (model.VolunteerApplication, error) {
+	var waitlisted model.VolunteerApplication
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		event, err := lockEvent(ctx, q, eventID)
+		if err != nil {
+			return err
+		}
+		if !acceptsVolunteers(event) {
+			return ErrEventNotOpen
+		}
+		if hasFreeSlots(event) {
+			return ErrEventHasFreeSlots
+		}
+
+		if err := ensureNotApplied(ctx, q, eventID, volunteerID); err != nil {
+			return err
+		}
+
+		row, err := q.CreateVolunteerApplication(ctx, dbsqlc.CreateVolunteerApplicationParams{
+			EventID:     int32ToInt4(eventID),
+			VolunteerID: int64ToInt8(volunteerID),
+			Status:      "waitlisted",
+		})
+		if err != nil {
+			return fmt.Errorf("create waitlisted application: %w", err)
+		}
+		waitlisted = mapVolunteerApplication(row)
+		return nil
+	})
+	if err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	return waitlisted, nil
+}
+
+// RemoveParticipant исключает волонтёра из участников события по решению организатора.
+// Заявка волонтёра получает статус cancelled, освободившееся место занимает первый из листа ожидания.
+func (s *applicationReviewService) RemoveParticipant(ctx context.Context, eventID int32, volunteerID, organizerID int64) error {
+	var changed model.Event
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		event, err := lockEvent(ctx, q, eventID)
+		if err != nil {
+			return err
+		}
+		if event.OrganizerID == nil || *event.OrganizerID != organizerID {
+			return ErrNotEventOwner
+		}
+
+		removed, err := removeParticipant(ctx, q, eventID, volunteerID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrParticipantNotFound
+		}
+
+		application, err := q.GetVolunteerApplication(ctx, dbsqlc.GetVolunteerApplicationParams{
+			EventID:     int32ToInt4(eventID),
+			VolunteerID: int64ToInt8(volunteerID),
+		})
+		switch {
+		case err == nil:
+			if _, err := NewVolunteerApplicationService(q).UpdateVolunteerApplicationStatus(ctx, application.ID, "cancelled", nil, &organizerID); err != nil {
+				return fmt.Errorf("cancel application: %w", err)
+			}
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		}
+
+		if err := promoteFromWaitlist(ctx, q, eventID); err != nil {
+			return err
+		}
+		changed, err = NewEventService(q, nil, nil).GetEventByID(ctx, eventID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.hub.publishEvent(model.EventUpdateSlots, changed)
+	return nil
+}
+
+// removeParticipant удаляет участника и уменьшает счётчик события. Событие должно быть заблокировано.
+// Возвращает false, если волонтёр не был участником.
+func removeParticipant(ctx context.Context, q dbsqlc.Querier, eventID int32, volunteerID int64) (bool, error) {
+	participants := NewEventParticipantService(q)
+	if _, err := participants.GetEventParticipant(ctx, &eventID, &volunteerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get participant: %w", err)
+	}
+	if err := participants.RemoveEventParticipant(ctx, &eventID, &volunteerID); err != nil {
+		return false, fmt.Errorf("remove participant: %w", err)
+	}
+	if _, err := NewEventService(q, nil, nil).IncrementEventVolunteers(ctx, eventID, -1); err != nil {
+		return false, fmt.Errorf("decrement volunteers: %w", err)
+	}
+	return true, nil
+}
+
+// promoteFromWaitlist переводит волонтёров из листа ожидания в участники, пока на событии есть места.
+// Уведомление ставится в очередь планировщика и уйдёт только после коммита. Событие должно быть заблокировано.
+func promoteFromWaitlist(ctx context.Context, q dbsqlc.Querier, eventID int32) error {
+	for {
+		event, err := NewEventService(q, nil, nil).GetEventByID(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("get event: %w", err)
+		}
+		if !acceptsVolunteers(event) {
+			return nil
+		}
+		if !hasFreeSlots(event) {
+			return nil
+		}
+
+		row, err := q.GetNextWaitlistedApplication(ctx, int32ToInt4(eventID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get next waitlisted: %w", err)
+		}
+		next := mapVolunteerApplication(row)
+
+		if _, err := NewVolunteerApplicationService(q).UpdateVolunteerApplicationStatus(ctx, next.ID, "approved", nil, nil); err != nil {
+			return fmt.Errorf("promote application: %w", err)
+		}
+		if _, err := NewEventParticipantService(q).AddEventParticipant(ctx, next.EventID, next.VolunteerID, &next.ID, nil); err != nil {
+			return fmt.Errorf("add participant: %w", err)
+		}
+		if _, err := NewEventService(q, nil, nil).IncrementEventVolunteers(ctx, eventID, 1); err != nil {
+			return fmt.Errorf("increment volunteers: %w", err)
+		}
+		if err := q.EnqueueNotification(ctx, dbsqlc.EnqueueNotificationParams{
+			UserID:        *next.VolunteerID,
+			Kind:          model.NotificationWaitlistPromoted,
+			EventID:       int32ToInt4(eventID),
+			ApplicationID: int32ToInt4(next.ID),
+			DedupKey:      model.NotificationWaitlistPromoted + ":" + strconv.Itoa(int(next.ID)),
+		}); err != nil {
+			return fmt.Errorf("enqueue notification: %w", err)
+		}
+	}
+}
+
+// lockEvent читает событие с блокировкой строки (SELECT ... FOR UPDATE).
+func lockEvent(ctx context.Context, q dbsqlc.Querier, eventID int32) (model.Event, error) {
+	row, err := q.GetEventByIDForUpdate(ctx, eventID)
+	if err != nil {
+		return model.Event{}, fmt.Errorf("lock event: %w", err)
+	}
+	return mapEvent(row)
+}
+
+// ensureNotApplied возвращает ErrAlreadyApplied, если у волонтёра уже есть заявка на событие.
+func ensureNotApplied(ctx context.Context, q dbsqlc.Querier, eventID int32, volunteerID int64) error {
+	_, err := q.GetVolunteerApplication(ctx, dbsqlc.GetVolunteerApplicationParams{
+		EventID:     int32ToInt4(eventID),
+		VolunteerID: int64ToInt8(volunteerID),
+	})
+	if err == nil {
+		return ErrAlreadyApplied
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+func hasFreeSlots(event model.Event) bool {
+	return event.CurrentVolunteers == nil || *event.CurrentVolunteers < event.MaxVolunteers
+}
+
+// acceptsVolunteers сообщает, что событие ещё набирает волонтёров: оно открыто или заполнено.
+func acceptsVolunteers(event model.Event) bool {
+	status := eventStatus(event)
+	return status == model.EventStatusOpen || status == model.EventStatusFull
+}
+
+// CanApplyToEvent сообщает, что на событие можно подать заявку: те же проверки выполняет ApplyToEvent.
+func CanApplyToEvent(event model.Event) bool {
+	return acceptsVolunteers(event) && hasFreeSlots(event)
+}
+
+// CanJoinWaitlist сообщает, что можно встать в лист ожидания: те же проверки выполняет JoinWaitlist.
+func CanJoinWaitlist(event model.Event) bool {
+	return acceptsVolunteers(event) && !hasFreeSlots(event)
+}
+
+// lockApplicationForReview блокирует строку события заявки (SELECT ... FOR UPDATE), перечитывает заявку
+// под блокировкой и проверяет, что организатор может её рассмотреть.
+func lockApplicationForReview(ctx context.Context, q dbsqlc.Querier, applicationID int32, organizerID int64) (model.VolunteerApplication, model.Event, error) {
+	application, err := getApplicationForReview(ctx, q, applicationID)
+	if err != nil {
+		return model.VolunteerApplication{}, model.Event{}, err
+	}
+
+	event, err := lockEvent(ctx, q, *application.EventID)
+	if err != nil {
+		return model.VolunteerApplication{}, model.Event{}, err
+	}
+	if event.OrganizerID == nil || *event.OrganizerID != organizerID {
+		return model.VolunteerApplication{}, model.Event{}, ErrNotEventOwner
+	}
+
+	// Пока ждали блокировку, заявку могли рассмотреть в другой транзакции.
+	application, err = getApplicationForReview(ctx, q, applicationID)
+	if err != nil {
+		return model.VolunteerApplication{}, model.Event{}, err
+	}
+	if application.Status == nil || *application.Status != "pending" {
+		return model.VolunteerApplication{}, model.Event{}, ErrApplicationNotPending
+	}
+	return application, event, nil
+}
+
+func getApplicationForReview(ctx context.Context, q dbsqlc.Querier, applicationID int32) (model.VolunteerApplication, error) {
+	row, err := q.GetVolunteerApplicationByID(ctx, applicationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.VolunteerApplication{}, ErrApplicationNotFound
+	}
+	if err != nil {
+		return model.VolunteerApplication{}, err
+	}
+	application := mapVolunteerApplication(row)
+	if application.EventID == nil || application.VolunteerID == nil {
+		return model.VolunteerApplication{}, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+var _ ApplicationReviewService = (*applicationReviewService)(nil)
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"maxBot/internal/model"
+)
+
+func TestCanWithdrawApplication(t *testing.T) {
+	status := func(s string) *string { return &s }
+	cases := []struct {
+		application, event *string
+		want               bool
+	}{
+		{nil, nil, true},
+		{status("pending"), status(model.EventStatusInProgress), true},
+		{status("waitlisted"), status(model.EventStatusFull), true},
+		{status("approved"), nil, true},
+		{status("approved"), status(model.EventStatusFull), true},
+		{status("approved"), status(model.EventStatusInProgress), false},
+		{status("approved"), status(model.EventStatusCompleted), false},
+		{status("approved"), status(model.EventStatusCancelled), false},
+		{status("rejected"), status(model.EventStatusOpen), false},
+		{status("cancelled"), status(model.EventStatusOpen), false},
+	}
+	for _, tc := range cases {
+		if got := CanWithdrawApplication(tc.application, tc.event); got != tc.want {
+			t.Errorf("CanWithdrawApplication(%v, %v) = %v, want %v", deref(tc.application), deref(tc.event), got, tc.want)
+		}
+	}
+}
+
+func TestCanApplyOrJoinWaitlist(t *testing.T) {
+	event := func(status string, current int32) model.Event {
+		return model.Event{Status: &status, MaxVolunteers: 2, CurrentVolunteers: &current}
+	}
+	cases := []struct {
+		event           model.Event
+		apply, waitlist bool
+	}{
+		{model.Event{MaxVolunteers: 2}, true, false},
+		{event(model.EventStatusOpen, 1), true, false},
+		{event(model.EventStatusOpen, 2), false, true},
+		{event(model.EventStatusFull, 2), false, true},
+		// Статус ещё не пересчитан после отзыва заявки: место есть, поэтому заявка, а не лист ожидания.
+		{event(model.EventStatusFull, 1), true, false},
+		{event(model.EventStatusInProgress, 0), false, false},
+		{event(model.EventStatusInProgress, 2), false, false},
+		{event(model.EventStatusCompleted, 0), false, false},
+		{event(model.EventStatusCancelled, 2), false, false},
+	}
+	for _, tc := range cases {
+		if got := CanApplyToEvent(tc.event); got != tc.apply {
+			t.Errorf("CanApplyToEvent(%+v) = %v, want %v", tc.event, got, tc.apply)
+		}
+		if got := CanJoinWaitlist(tc.event); got != tc.waitlist {
+			t.Errorf("CanJoinWaitlist(%+v) = %v, want %v", tc.event, got, tc.waitlist)
+		}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCheckInRadiusMeters = 200
+	defaultCheckInCodeTTL      = 30 * time.Minute
+	defaultCheckInOpensBefore  = time.Hour
+	defaultEventDuration       = 3 * time.Hour
+)
+
+// AttendanceConfig описывает правила самостоятельной отметки волонтёров.
+type AttendanceConfig struct {
+	// CheckInRadiusMeters максимальное расстояние до места события для отметки по геолокации.
+	CheckInRadiusMeters float64
+	// CheckInCodeTTL сколько действует код отметки, выданный организатором.
+	CheckInCodeTTL time.Duration
+	// CheckInOpensBefore за сколько до начала события открывается отметка.
+	CheckInOpensBefore time.Duration
+	// DefaultEventDuration длительность события без duration_hours при подсчёте часов волонтёра.
+	// Берётся из той же переменной, что и у планировщика, который по ней завершает такие события.
+	DefaultEventDuration time.Duration
+}
+
+// LoadAttendanceConfigFromEnv читает настройки отметки из переменных окружения.
+func LoadAttendanceConfigFromEnv() AttendanceConfig {
+	return AttendanceConfig{
+		CheckInRadiusMeters:  floatOrDefault(os.Getenv("CHECK_IN_RADIUS_METERS"), defaultCheckInRadiusMeters),
+		CheckInCodeTTL:       durationOrDefault(os.Getenv("CHECK_IN_CODE_TTL"), defaultCheckInCodeTTL),
+		CheckInOpensBefore:   durationOrDefault(os.Getenv("CHECK_IN_OPENS_BEFORE"), defaultCheckInOpensBefore),
+		DefaultEventDuration: durationOrDefault(os.Getenv("SCHEDULER_DEFAULT_EVENT_DURATION"), defaultEventDuration),
+	}
+}
+
+func floatOrDefault(val string, def float64) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil || f <= 0 {
+		return def
+	}
+	return f
+}
+
+func durationOrDefault(val string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(val))
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+const (
+	checkInCodeDigits = 6
+	earthRadiusMeters = 6371000
+)
+
+var (
+	// ErrCheckInClosed отметка на событии ещё не открыта или уже закрыта.
+	ErrCheckInClosed = errors.New("check-in is closed for the event")
+	// ErrInvalidCheckInCode код не совпадает с выданным организатором или истёк.
+	ErrInvalidCheckInCode = errors.New("invalid or expired check-in code")
+	// ErrTooFarFromEvent волонтёр находится дальше допустимого радиуса от места события.
+	ErrTooFarFromEvent = errors.New("volunteer is too far from the event location")
+	// ErrEventLocationUnknown у события не указаны координаты.
+	ErrEventLocationUnknown = errors.New("event location is unknown")
+	// ErrAlreadyCheckedIn участник уже отметил приход.
+	ErrAlreadyCheckedIn = errors.New("participant has already checked in")
+	// ErrNotCheckedIn участник не отмечал приход или уже отметил уход.
+	ErrNotCheckedIn = errors.New("participant has not checked in")
+)
+
+// AttendanceService отвечает за отметку присутствия участников и подсчёт часов волонтёра.
+type AttendanceService interface {
+	GenerateCheckInCode(ctx context.Context, eventID int32, organizerID int64) (model.EventCheckInCode, error)
+	CheckInWithCode(ctx context.Context, eventID int32, volunteerID int64, code string) (model.EventParticipant, error)
+	CheckInWithLocation(ctx context.Context, eventID int32, volunteerID int64, lat, lon float64) (model.EventParticipant, error)
+	CheckOut(ctx context.Context, eventID int32, volunteerID int64) (model.EventParticipant, error)
+	MarkCheckIn(ctx context.Context, eventID int32, volunteerID, organizerID int64) (model.EventParticipant, error)
+	MarkCheckOut(ctx context.Context, eventID int32, volunteerID, organizerID int64) (model.EventParticipant, error)
+	ResetAttendance(ctx context.Context, eventID int32, volunteerID, organizerID int64) (model.EventParticipant, error)
+	ListAttendance(ctx context.Context, eventID int32, organizerID int64, limit, offset int32) ([]model.ParticipantAttendance, error)
+	GetVolunteerHours(ctx context.Context, volunteerID int64) (model.VolunteerHours, error)
+}
+
+type attendanceService struct {
+	q   dbsqlc.Querier
+	cfg AttendanceConfig
+}
+
+func NewAttendanceService(q dbsqlc.Querier, cfg AttendanceConfig) AttendanceService {
+	return &attendanceService{q: q, cfg: cfg}
+}
+
+// GenerateCheckInCode выдаёт новый код отметки; предыдущий код события перестаёт действовать.
+func (s *attendanceService) GenerateCheckInCode(ctx context.Context, eventID int32, organizerID int64) (model.EventCheckInCode, error) {
+	event, err := s.ownedEvent(ctx, eventID, organizerID)
+	if err != nil {
+		return model.EventCheckInCode{}, err
+	}
+	if !attendanceOpen(eventStatus(event)) {
+		return model.EventCheckInCode{}, ErrCheckInClosed
+	}
+
+	code, err := randomDigits(checkInCodeDigits)
+	if err != nil {
+		return model.EventCheckInCode{}, fmt.Errorf("generate code: %w", err)
+	}
+	expiresAt := time.Now().Add(s.cfg.CheckInCodeTTL)
+	row, err := s.q.UpsertEventCheckInCode(ctx, dbsqlc.UpsertEventCheckInCodeParams{
+		EventID:   eventID,
+		Code:      code,
+		ExpiresAt: timePtrToTimestamp(&expiresAt),
+		CreatedBy: int64ToInt8(organizerID),
+	})
+	if err != nil {
+		return model.EventCheckInCode{}, err
+	}
+	return mapEventCheckInCode(row), nil
+}
+
+// CheckInWithCode отмечает приход волонтёра по коду, который показал организатор.
+func (s *attendanceService) CheckInWithCode(ctx context.Context, eventID int32, volunteerID int64, code string) (model.EventParticipant, error) {
+	if _, err := s.checkInEvent(ctx, eventID, volunteerID); err != nil {
+		return model.EventParticipant{}, err
+	}
+
+	row, err := s.q.GetEventCheckInCode(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.EventParticipant{}, ErrInvalidCheckInCode
+	}
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	stored := mapEventCheckInCode(row)
+	code = strings.TrimSpace(code)
+	if time.Now().After(stored.ExpiresAt) || subtle.ConstantTimeCompare([]byte(code), []byte(stored.Code)) != 1 {
+		return model.EventParticipant{}, ErrInvalidCheckInCode
+	}
+	return s.checkIn(ctx, eventID, volunteerID, model.CheckInMethodCode, nil)
+}
+
+// CheckInWithLocation отмечает приход волонтёра, если он находится в радиусе CheckInRadiusMeters от места события.
+func (s *attendanceService) CheckInWithLocation(ctx context.Context, eventID int32, volunteerID int64, lat, lon float64) (model.EventParticipant, error) {
+	event, err := s.checkInEvent(ctx, eventID, volunteerID)
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	if event.LocationLat == 0 && event.LocationLon == 0 {
+		return model.EventParticipant{}, ErrEventLocationUnknown
+	}
+	if distanceMeters(lat, lon, event.LocationLat, event.LocationLon) > s.cfg.CheckInRadiusMeters {
+		return model.EventParticipant{}, ErrTooFarFromEvent
+	}
+	return s.checkIn(ctx, eventID, volunteerID, model.CheckInMethodGeo, nil)
+}
+
+// CheckOut отмечает уход волонтёра с события.
+func (s *attendanceService) CheckOut(ctx context.Context, eventID int32, volunteerID int64) (model.EventParticipant, error) {
+	if _, err := s.participant(ctx, eventID, volunteerID); err != nil {
+		return model.EventParticipant{}, err
+	}
+	return s.checkOut(ctx, eventID, volunteerID, nil)
+}
+
+// MarkCheckIn отмечает приход участника от имени организатора.
+func (s *attendanceService) MarkCheckIn(ctx context.Context, eventID int32, volunteerID, organizerID int64) (model.EventParticipant, error) {
+	event, err := s.ownedEvent(ctx, eventID, organizerID)
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	if eventStatus(event) == model.EventStatusCancelled {
+		return model.EventParticipant{}, ErrCheckInClosed
+	}
+	if _, err := s.participant(ctx, eventID, volunteerID); err != nil {
+		return model.EventParticipant{}, err
+	}
+	return s.checkIn(ctx, eventID, volunteerID, model.CheckInMethodOrganizer, &organizerID)
+}
+
+// MarkCheckOut отмечает уход участника от имени организатора.
+func (s *attendanceService) MarkCheckOut(ctx context.Context, eventID int32, volunteerID, organizerID int64) (model.EventParticipant, error) {
+	if _, err := s.ownedEvent(ctx, eventID, organizerID); err != nil {
+		return model.EventParticipant{}, err
+	}
+	if _, err := s.participant(ctx, eventID, volunteerID); err != nil {
+		return model.EventParticipant{}, err
+	}
+	return s.checkOut(ctx, eventID, volunteerID, &organizerID)
+}
+
+// ResetAttendance снимает отметки участника, например если организатор ошибся.
+func (s *attendanceService) ResetAttendance(ctx context.Context, eventID int32, volunteerID, organizerID int64) (model.EventParticipant, error) {
+	if _, err := s.ownedEvent(ctx, eventID, organizerID); err != nil {
+		return model.EventParticipant{}, err
+	}
+	row, err := s.q.ResetParticipantAttendance(ctx, dbsqlc.ResetParticipantAttendanceParams{
+		EventID:     int32ToInt4(eventID),
+		VolunteerID: int64ToInt8(volunteerID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.EventParticipant{}, ErrParticipantNotFound
+	}
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	return mapEventParticipant(row), nil
+}
+
+// ListAttendance возвращает участников события с отметками для экрана организатора.
+func (s *attendanceService) ListAttendance(ctx context.Context, eventID int32, organizerID int64, limit, offset int32) ([]model.ParticipantAttendance, error) {
+	if _, err := s.ownedEvent(ctx, eventID, organizerID); err != nil {
+		return nil, err
+	}
+	rows, err := s.q.ListEventParticipantsWithUsers(ctx, dbsqlc.ListEventParticipantsWithUsersParams{
+		EventID: int32ToInt4(eventID),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapParticipantAttendances(rows), nil
+}
+
+// GetVolunteerHours возвращает количество отработанных часов и посещённых событий.
+func (s *attendanceService) GetVolunteerHours(ctx context.Context, volunteerID int64) (model.VolunteerHours, error) {
+	row, err := s.q.GetVolunteerHoursServed(ctx, dbsqlc.GetVolunteerHoursServedParams{
+		DefaultDurationMinutes: int32(s.cfg.DefaultEventDuration / time.Minute),
+		VolunteerID:            int64ToInt8(volunteerID),
+	})
+	if err != nil {
+		return model.VolunteerHours{}, err
+	}
+	return model.VolunteerHours{HoursServed: row.HoursServed, EventsAttended: row.EventsAttended}, nil
+}
+
+// checkInEvent проверяет, что волонтёр участвует в событии и отметка сейчас открыта.
+func (s *attendanceService) checkInEvent(ctx context.Context, eventID int32, volunteerID int64) (model.Event, error) {
+	event, err := s.event(ctx, eventID)
+	if err != nil {
+		return model.Event{}, err
+	}
+	if _, err := s.participant(ctx, eventID, volunteerID); err != nil {
+		return model.Event{}, err
+	}
+	if !attendanceOpen(eventStatus(event)) || time.Now().Before(event.Date.Add(-s.cfg.CheckInOpensBefore)) {
+		return model.Event{}, ErrCheckInClosed
+	}
+	return event, nil
+}
+
+func (s *attendanceService) checkIn(ctx context.Context, eventID int32, volunteerID int64, method string, markedBy *int64) (model.EventParticipant, error) {
+	row, err := s.q.CheckInParticipant(ctx, dbsqlc.CheckInParticipantParams{
+		CheckInMethod: stringToText(method),
+		MarkedBy:      int64PtrToInt8(markedBy),
+		EventID:       int32ToInt4(eventID),
+		VolunteerID:   int64ToInt8(volunteerID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.EventParticipant{}, ErrAlreadyCheckedIn
+	}
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	return mapEventParticipant(row), nil
+}
+
+func (s *attendanceService) checkOut(ctx context.Context, eventID int32, volunteerID int64, markedBy *int64) (model.EventParticipant, error) {
+	row, err := s.q.CheckOutParticipant(ctx, dbsqlc.CheckOutParticipantParams{
+		MarkedBy:    int64PtrToInt8(markedBy),
+		EventID:     int32ToInt4(eventID),
+		VolunteerID: int64ToInt8(volunteerID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.EventParticipant{}, ErrNotCheckedIn
+	}
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	return mapEventParticipant(row), nil
+}
+
+func (s *attendanceService) participant(ctx context.Context, eventID int32, volunteerID int64) (model.EventParticipant, error) {
+	row, err := s.q.GetEventParticipant(ctx, dbsqlc.GetEventParticipantParams{
+		EventID:     int32ToInt4(eventID),
+		VolunteerID: int64ToInt8(volunteerID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.EventParticipant{}, ErrParticipantNotFound
+	}
+	if err != nil {
+		return model.EventParticipant{}, err
+	}
+	return mapEventParticipant(row), nil
+}
+
+func (s *attendanceService) event(ctx context.Context, eventID int32) (model.Event, error) {
+	row, err := s.q.GetEventByID(ctx, eventID)
+	if err != nil {
+		return model.Event{}, fmt.Errorf("get event: %w", err)
+	}
+	return mapEvent(row)
+}
+
+func (s *attendanceService) ownedEvent(ctx context.Context, eventID int32, organizerID int64) (model.Event, error) {
+	event, err := s.event(ctx, eventID)
+	if err != nil {
+		return model.Event{}, err
+	}
+	if event.OrganizerID == nil || *event.OrganizerID != organizerID {
+		return model.Event{}, ErrNotEventOwner
+	}
+	return event, nil
+}
+
+// attendanceOpen отметка доступна, пока событие не завершено и не отменено.
+func attendanceOpen(status string) bool {
+	return status != model.EventStatusCompleted && status != model.EventStatusCancelled
+}
+
+func randomDigits(n int) (string, error) {
+	max := big.NewInt(int64(math.Pow10(n)))
+	v, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", n, v.Int64()), nil
+}
+
+// distanceMeters расстояние между двумя точками по формуле гаверсинусов.
+func distanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+var _ AttendanceService = (*attendanceService)(nil)
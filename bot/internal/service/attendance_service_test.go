@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+)
+
+// hoursQuerier запоминает длительность по умолчанию, с которой считаются часы волонтёра.
+type hoursQuerier struct {
+	dbsqlc.Querier
+	defaultMinutes int32
+}
+
+func (q *hoursQuerier) GetVolunteerHoursServed(_ context.Context, arg dbsqlc.GetVolunteerHoursServedParams) (dbsqlc.GetVolunteerHoursServedRow, error) {
+	q.defaultMinutes = arg.DefaultDurationMinutes
+	return dbsqlc.GetVolunteerHoursServedRow{HoursServed: 3, EventsAttended: 1}, nil
+}
+
+func TestGetVolunteerHoursUsesSchedulerDefaultDuration(t *testing.T) {
+	t.Setenv("SCHEDULER_DEFAULT_EVENT_DURATION", "90m")
+	cfg := LoadAttendanceConfigFromEnv()
+	if cfg.DefaultEventDuration != 90*time.Minute {
+		t.Fatalf("default duration %v, want 90m", cfg.DefaultEventDuration)
+	}
+
+	q := &hoursQuerier{}
+	hours, err := NewAttendanceService(q, cfg).GetVolunteerHours(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("get hours: %v", err)
+	}
+	if q.defaultMinutes != 90 || hours.HoursServed != 3 || hours.EventsAttended != 1 {
+		t.Fatalf("got %+v with %d default minutes", hours, q.defaultMinutes)
+	}
+
+	t.Setenv("SCHEDULER_DEFAULT_EVENT_DURATION", "")
+	if got := LoadAttendanceConfigFromEnv().DefaultEventDuration; got != 3*time.Hour {
+		t.Fatalf("unset default duration %v, want 3h", got)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+const (
+	calendarFeedTokenBytes = 32
+	// calendarFeedLookback — сколько прошедших мероприятий остаётся в ленте, чтобы они не пропадали из календаря сразу.
+	calendarFeedLookback  = 30 * 24 * time.Hour
+	calendarFeedMaxEvents = 500
+)
+
+// ErrInvalidFeedToken токен ленты не найден: его не выдавали, перевыпустили или отозвали.
+var ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+
+// CalendarService выдаёт персональные токены iCalendar-ленты и отдаёт по ним мероприятия волонтёра.
+// Календари не умеют передавать заголовки, поэтому токен живёт в ссылке на ленту и отзывается отдельно от сессий.
+type CalendarService interface {
+	IssueFeedToken(ctx context.Context, userID int64) (string, error)
+	RevokeFeedToken(ctx context.Context, userID int64) error
+	ListFeedEvents(ctx context.Context, token string) (int64, []model.Event, error)
+}
+
+type calendarService struct {
+	q dbsqlc.Querier
+}
+
+func NewCalendarService(q dbsqlc.Querier) CalendarService {
+	return &calendarService{q: q}
+}
+
+// IssueFeedToken выпускает новый токен ленты; прежний токен пользователя перестаёт действовать.
+func (s *calendarService) IssueFeedToken(ctx context.Context, userID int64) (string, error) {
+	token, err := randomToken(calendarFeedTokenBytes)
+	if err != nil {
+		return "", fmt.Errorf("generate feed token: %w", err)
+	}
+	if err := s.q.UpsertCalendarFeedToken(ctx, dbsqlc.UpsertCalendarFeedTokenParams{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+	}); err != nil {
+		return "", fmt.Errorf("store feed token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeFeedToken(ctx context.Context, userID int64) error {
+	return s.q.DeleteCalendarFeedToken(ctx, userID)
+}
+
+// ListFeedEvents возвращает владельца токена и мероприятия, где он участник: за последний месяц и предстоящие.
+func (s *calendarService) ListFeedEvents(ctx context.Context, token string) (int64, []model.Event, error) {
+	userID, err := s.q.UseCalendarFeedToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil, ErrInvalidFeedToken
+		}
+		return 0, nil, err
+	}
+	since := time.Now().UTC().Add(-calendarFeedLookback)
+	rows, err := s.q.ListCalendarEventsForVolunteer(ctx, dbsqlc.ListCalendarEventsForVolunteerParams{
+		VolunteerID: int64ToInt8(userID),
+		Since:       timePtrToTimestamp(&since),
+		Limit:       calendarFeedMaxEvents,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	events, err := mapEvents(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	return userID, events, nil
+}
+
+var _ CalendarService = (*calendarService)(nil)
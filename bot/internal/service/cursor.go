@@ -0,0 +1,93 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrInvalidCursor — курсор повреждён или выдан для других фильтров.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page — страница keyset-пагинации. NextCursor пуст на последней странице,
+// TotalEstimate заполняется по запросу и может разойтись с фактом, пока пользователь листает.
+type Page[T any] struct {
+	Items         []T
+	NextCursor    string
+	TotalEstimate *int64
+}
+
+// pageCursor хранит ключ сортировки последнего элемента страницы. Filter — отпечаток фильтров:
+// курсор от одной выборки не применим к другой, иначе страница начнётся с произвольного места.
+type pageCursor struct {
+	Filter     string    `json:"f"`
+	Date       time.Time `json:"d"`
+	ID         int32     `json:"i"`
+	DistanceKm *float64  `json:"k,omitempty"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor возвращает nil для пустого курсора, то есть для первой страницы.
+func decodeCursor(token, filter string) (*pageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 || c.Filter != filter {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+func cursorFilter(parts ...any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(parts...)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (c *pageCursor) afterID() pgtype.Int4 {
+	if c == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: c.ID, Valid: true}
+}
+
+func (c *pageCursor) afterDate() pgtype.Timestamp {
+	if c == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: c.Date, Valid: true}
+}
+
+func (c *pageCursor) afterDistanceKm() pgtype.Float8 {
+	if c == nil || c.DistanceKm == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *c.DistanceKm, Valid: true}
+}
+
+// newPage обрезает выборку из limit+1 элементов до limit и строит курсор по последнему элементу.
+func newPage[T any](items []T, limit int32, key func(T) pageCursor) Page[T] {
+	page := Page[T]{Items: items}
+	if int32(len(items)) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = encodeCursor(key(page.Items[limit-1]))
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	filter := cursorFilter("events", int32(3), "open")
+	distance := 12.5
+	want := pageCursor{
+		Filter:     filter,
+		Date:       time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC),
+		ID:         17,
+		DistanceKm: &distance,
+	}
+
+	got, err := decodeCursor(encodeCursor(want), filter)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Filter != want.Filter || !got.Date.Equal(want.Date) || got.ID != want.ID || got.DistanceKm == nil || *got.DistanceKm != distance {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got.afterID().Int32 != 17 || !got.afterDate().Time.Equal(want.Date) || got.afterDistanceKm().Float64 != distance {
+		t.Fatalf("unexpected query params from %+v", got)
+	}
+
+	first, err := decodeCursor("", filter)
+	if err != nil || first != nil {
+		t.Fatalf("empty cursor: got %+v, %v", first, err)
+	}
+	if first.afterID().Valid || first.afterDate().Valid || first.afterDistanceKm().Valid {
+		t.Fatal("first page must not filter by cursor")
+	}
+}
+
+func TestDecodeCursorRejectsForeignOrBrokenTokens(t *testing.T) {
+	filter := cursorFilter("events", int32(3), "open")
+	valid := encodeCursor(pageCursor{Filter: filter, Date: time.Now(), ID: 5})
+
+	cases := map[string]string{
+		"other filter":  encodeCursor(pageCursor{Filter: cursorFilter("events", int32(4), "open"), ID: 5}),
+		"no id":         encodeCursor(pageCursor{Filter: filter}),
+		"not base64":    "!!!",
+		"not json":      base64.RawURLEncoding.EncodeToString([]byte("cursor")),
+		"truncated":     valid[:len(valid)/2],
+		"padded base64": base64.URLEncoding.EncodeToString([]byte(`{"f":"`+filter+`","i":5}`)) + "=",
+	}
+	for name, token := range cases {
+		if _, err := decodeCursor(token, filter); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: got %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
+
+func TestCursorFilterFingerprint(t *testing.T) {
+	base := cursorFilter("map", 55.75, 37.62, 10.0, []int32{1, 2})
+	if base != cursorFilter("map", 55.75, 37.62, 10.0, []int32{1, 2}) {
+		t.Fatal("same filters must give the same fingerprint")
+	}
+	for name, other := range map[string]string{
+		"radius":     cursorFilter("map", 55.75, 37.62, 20.0, []int32{1, 2}),
+		"categories": cursorFilter("map", 55.75, 37.62, 10.0, []int32{1}),
+		"scope":      cursorFilter("events", 55.75, 37.62, 10.0, []int32{1, 2}),
+	} {
+		if other == base {
+			t.Errorf("%s change keeps the fingerprint", name)
+		}
+	}
+	if len(base) != 16 || strings.Trim(base, "0123456789abcdef") != "" {
+		t.Fatalf("fingerprint %q is not 8 hex bytes", base)
+	}
+}
+
+func TestNewPageLimitBoundary(t *testing.T) {
+	key := func(id int32) pageCursor { return pageCursor{Filter: "f", ID: id} }
+	cases := map[string]struct {
+		items      []int32
+		limit      int32
+		wantItems  int
+		wantCursor int32
+	}{
+		"empty":          {items: nil, limit: 3, wantItems: 0},
+		"short page":     {items: []int32{1, 2}, limit: 3, wantItems: 2},
+		"exactly limit":  {items: []int32{1, 2, 3}, limit: 3, wantItems: 3},
+		"limit plus one": {items: []int32{1, 2, 3, 4}, limit: 3, wantItems: 3, wantCursor: 3},
+	}
+	for name, tc := range cases {
+		page := newPage(tc.items, tc.limit, key)
+		if page.Items == nil || len(page.Items) != tc.wantItems {
+			t.Errorf("%s: got items %v, want %d", name, page.Items, tc.wantItems)
+			continue
+		}
+		if tc.wantCursor == 0 {
+			if page.NextCursor != "" {
+				t.Errorf("%s: last page has cursor %q", name, page.NextCursor)
+			}
+			continue
+		}
+		next, err := decodeCursor(page.NextCursor, "f")
+		if err != nil || next.ID != tc.wantCursor {
+			t.Errorf("%s: cursor %+v, %v, want after %d", name, next, err, tc.wantCursor)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+// EventDraftService stores organizer drafts for the event-creation wizard.
+type EventDraftService interface {
+	GetEventDraft(ctx context.Context, organizerID int64) (model.EventDraft, error)
+	SaveEventDraft(ctx context.Context, draft model.EventDraft) (model.EventDraft, error)
+	DeleteEventDraft(ctx context.Context, organizerID int64) error
+}
+
+type eventDraftService struct {
+	q dbsqlc.Querier
+}
+
+func NewEventDraftService(q dbsqlc.Querier) EventDraftService {
+	return &eventDraftService{q: q}
+}
+
+// GetEventDraft возвращает черновик организатора или пустой черновик, если он ещё не создан.
+func (s *eventDraftService) GetEventDraft(ctx context.Context, organizerID int64) (model.EventDraft, error) {
+	d, err := s.q.GetEventDraft(ctx, organizerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.EventDraft{OrganizerID: organizerID}, nil
+	}
+	if err != nil {
+		return model.EventDraft{}, err
+	}
+	return mapEventDraft(d)
+}
+
+func (s *eventDraftService) SaveEventDraft(ctx context.Context, draft model.EventDraft) (model.EventDraft, error) {
+	params := dbsqlc.UpsertEventDraftParams{
+		OrganizerID:   draft.OrganizerID,
+		Title:         stringPtrToText(draft.Title),
+		Description:   stringPtrToText(draft.Description),
+		Date:          timePtrToTimestamp(draft.Date),
+		DurationHours: int32PtrToInt4(draft.DurationHours),
+		Location:      stringPtrToText(draft.Location),
+		LocationLat:   float64PtrToNumeric(draft.LocationLat),
+		LocationLon:   float64PtrToNumeric(draft.LocationLon),
+		CategoryID:    int32PtrToInt4(draft.CategoryID),
+		MaxVolunteers: int32PtrToInt4(draft.MaxVolunteers),
+		Contacts:      stringPtrToText(draft.Contacts),
+	}
+	d, err := s.q.UpsertEventDraft(ctx, params)
+	if err != nil {
+		return model.EventDraft{}, err
+	}
+	return mapEventDraft(d)
+}
+
+func (s *eventDraftService) DeleteEventDraft(ctx context.Context, organizerID int64) error {
+	return s.q.DeleteEventDraft(ctx, organizerID)
+}
+
+var _ EventDraftService = (*eventDraftService)(nil)
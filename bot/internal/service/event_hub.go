@@ -0,0 +1,177 @@
+package service
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"maxBot/internal/model"
+)
+
+const (
+	// eventHubHistory — сколько последних сообщений хранится для возобновления по Last-Event-ID.
+	eventHubHistory = 1024
+	// eventHubBuffer — очередь подписчика; кто не успевает её разбирать, отключается и переподключается с Last-Event-ID.
+	eventHubBuffer = 64
+)
+
+// EventStreamFilter ограничивает поток подписчика областью карты и категориями.
+// Если задан BBox, Lat/Lon — точка отсчёта distanceKm, а RadiusKm не используется; нулевой RadiusKm без BBox — без ограничения по месту.
+type EventStreamFilter struct {
+	BBox        *model.MapBBox
+	Lat         float64
+	Lon         float64
+	RadiusKm    float64
+	CategoryIDs []int32
+}
+
+// Match сообщает, относится ли сообщение к подписчику. Удаления рассылаются всем: координат у них уже нет.
+func (f EventStreamFilter) Match(update model.EventUpdate) bool {
+	if update.Event == nil {
+		return true
+	}
+	event := update.Event
+	if len(f.CategoryIDs) > 0 && (event.CategoryID == nil || !slices.Contains(f.CategoryIDs, *event.CategoryID)) {
+		return false
+	}
+	if f.BBox != nil {
+		return event.LocationLat >= f.BBox.MinLat && event.LocationLat <= f.BBox.MaxLat &&
+			event.LocationLon >= f.BBox.MinLon && event.LocationLon <= f.BBox.MaxLon
+	}
+	if f.RadiusKm > 0 {
+		return DistanceKm(f.Lat, f.Lon, event.LocationLat, event.LocationLon) <= f.RadiusKm
+	}
+	return true
+}
+
+// EventSubscription — подписка на поток. Канал Updates закрывается при отписке, переполнении очереди
+// или остановке хаба.
+type EventSubscription struct {
+	filter EventStreamFilter
+	ch     chan model.EventUpdate
+}
+
+func (s *EventSubscription) Updates() <-chan model.EventUpdate {
+	return s.ch
+}
+
+// EventHub — внутрипроцессная шина изменений мероприятий: сервисы публикуют в неё изменения
+// после успешной записи, SSE-подписчики получают их по своим фильтрам. Nil-хаб ничего не рассылает.
+type EventHub struct {
+	mu      sync.Mutex
+	seq     uint64
+	history []model.EventUpdate
+	subs    map[*EventSubscription]struct{}
+	closed  bool
+}
+
+// NewEventHub создаёт хаб. Нумерация начинается с текущего времени в микросекундах,
+// чтобы id сообщений не повторялись после перезапуска и старый Last-Event-ID не принимался за свежий.
+func NewEventHub() *EventHub {
+	return &EventHub{
+		seq:  uint64(time.Now().UnixMicro()),
+		subs: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// Subscribe регистрирует подписчика. lastSeq — Last-Event-ID клиента или 0 для нового подключения:
+// пропущенные сообщения из истории возвращаются в replay. complete=false означает, что часть пропущенного
+// уже вытеснена из истории и клиенту нужно перечитать карту целиком.
+func (h *EventHub) Subscribe(filter EventStreamFilter, lastSeq uint64) (sub *EventSubscription, replay []model.EventUpdate, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub = &EventSubscription{filter: filter, ch: make(chan model.EventUpdate, eventHubBuffer)}
+	if h.closed {
+		close(sub.ch)
+		return sub, nil, true
+	}
+	h.subs[sub] = struct{}{}
+
+	if lastSeq == 0 {
+		return sub, nil, true
+	}
+	oldest := h.seq + 1
+	if len(h.history) > 0 {
+		oldest = h.history[0].Seq
+	}
+	if lastSeq+1 < oldest || lastSeq > h.seq {
+		return sub, nil, false
+	}
+	for _, update := range h.history {
+		if update.Seq > lastSeq && filter.Match(update) {
+			replay = append(replay, update)
+		}
+	}
+	return sub, replay, true
+}
+
+// Unsubscribe отключает подписчика; повторный вызов безопасен.
+func (h *EventHub) Unsubscribe(sub *EventSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Publish присваивает сообщению номер, сохраняет его в истории и рассылает подписчикам.
+// Публикация не блокируется: подписчик с переполненной очередью отключается.
+func (h *EventHub) Publish(update model.EventUpdate) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+
+	h.seq++
+	update.Seq = h.seq
+	if len(h.history) == eventHubHistory {
+		h.history = slices.Delete(h.history, 0, 1)
+	}
+	h.history = append(h.history, update)
+
+	for sub := range h.subs {
+		if !sub.filter.Match(update) {
+			continue
+		}
+		select {
+		case sub.ch <- update:
+		default:
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Close отключает всех подписчиков, чтобы долгие SSE-запросы не мешали остановке HTTP-сервера.
+func (h *EventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for sub := range h.subs {
+		close(sub.ch)
+	}
+	h.subs = nil
+}
+
+// DistanceKm — расстояние между точками в километрах, как distanceKm в выдаче карты.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	return distanceMeters(lat1, lon1, lat2, lon2) / 1000
+}
+
+// publishEvent рассылает снимок мероприятия после изменения.
+func (h *EventHub) publishEvent(kind string, event model.Event) {
+	if h == nil {
+		return
+	}
+	snapshot := mapEventSnapshot(event)
+	h.Publish(model.EventUpdate{Type: kind, EventID: event.ID, Event: &snapshot})
+}
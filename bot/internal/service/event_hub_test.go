@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"maxBot/internal/model"
+)
+
+func hubEvent(id int32, lat, lon float64, category int32) model.EventUpdate {
+	return model.EventUpdate{
+		Type:    model.EventUpdateSlots,
+		EventID: id,
+		Event:   &model.MapEvent{ID: id, LocationLat: lat, LocationLon: lon, CategoryID: &category},
+	}
+}
+
+func TestEventHubFiltersAndReplays(t *testing.T) {
+	hub := NewEventHub()
+	moscow := EventStreamFilter{Lat: 55.75, Lon: 37.62, RadiusKm: 30, CategoryIDs: []int32{1}}
+	sub, _, _ := hub.Subscribe(moscow, 0)
+
+	hub.Publish(hubEvent(1, 55.76, 37.60, 1)) // подходит
+	hub.Publish(hubEvent(2, 59.93, 30.33, 1)) // другой город
+	hub.Publish(hubEvent(3, 55.76, 37.60, 2)) // другая категория
+	hub.Publish(model.EventUpdate{Type: model.EventUpdateDeleted, EventID: 4})
+
+	first := <-sub.Updates()
+	deleted := <-sub.Updates()
+	if first.EventID != 1 || deleted.EventID != 4 || len(sub.Updates()) != 0 {
+		t.Fatalf("unexpected delivery: %d, %d, pending %d", first.EventID, deleted.EventID, len(sub.Updates()))
+	}
+	hub.Unsubscribe(sub)
+
+	resumed, replay, complete := hub.Subscribe(moscow, first.Seq)
+	defer hub.Unsubscribe(resumed)
+	if !complete || len(replay) != 1 || replay[0].Seq != deleted.Seq {
+		t.Fatalf("unexpected replay after %d: complete=%v %+v", first.Seq, complete, replay)
+	}
+}
+
+func TestEventHubReportsGapAndDropsSlowSubscriber(t *testing.T) {
+	hub := NewEventHub()
+	all := EventStreamFilter{}
+
+	start := hub.seq
+	slow, _, _ := hub.Subscribe(all, 0)
+	for i := 0; i < eventHubHistory+1; i++ {
+		hub.Publish(hubEvent(int32(i+1), 55.75, 37.62, 1))
+	}
+	received := 0
+	for range slow.Updates() {
+		received++
+	}
+	if received != eventHubBuffer {
+		t.Fatalf("slow subscriber must be closed after %d updates, got %d", eventHubBuffer, received)
+	}
+
+	// Клиент не видел ни одного сообщения, а первое уже вытеснено из истории.
+	sub, replay, complete := hub.Subscribe(all, start)
+	defer hub.Unsubscribe(sub)
+	if complete || len(replay) != 0 {
+		t.Fatalf("expected gap, got complete=%v with %d updates", complete, len(replay))
+	}
+}
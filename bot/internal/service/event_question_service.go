@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+var (
+	// ErrInvalidQuestion вопрос анкеты составлен некорректно.
+	ErrInvalidQuestion = errors.New("invalid questionnaire question")
+	// ErrInvalidAnswer ответ на вопрос анкеты не подходит к вопросу.
+	ErrInvalidAnswer = errors.New("invalid questionnaire answer")
+	// ErrQuestionnaireLocked на событие уже подавали заявки, анкету менять нельзя.
+	ErrQuestionnaireLocked = errors.New("questionnaire cannot be changed after applications were submitted")
+)
+
+// Ограничения анкеты события.
+const (
+	MaxEventQuestions       = 20
+	MaxQuestionTextLength   = 500
+	MaxQuestionOptions      = 10
+	MaxQuestionOptionLength = 100
+	MaxTextAnswerLength     = 1000
+	minQuestionOptions      = 2
+)
+
+// QuestionnaireError описывает ошибку в конкретном вопросе или ответе анкеты.
+// Reason предназначен для пользователя, Err — ErrInvalidQuestion или ErrInvalidAnswer.
+type QuestionnaireError struct {
+	Err error
+	// Position номер вопроса в анкете, начиная с 1.
+	Position   int
+	QuestionID int32
+	Reason     string
+}
+
+func (e *QuestionnaireError) Error() string {
+	return fmt.Sprintf("%v: question %d: %s", e.Err, e.Position, e.Reason)
+}
+
+func (e *QuestionnaireError) Unwrap() error {
+	return e.Err
+}
+
+// EventQuestionService управляет анкетами событий и ответами волонтёров на них.
+type EventQuestionService interface {
+	ListEventQuestions(ctx context.Context, eventID int32) ([]model.EventQuestion, error)
+	ReplaceEventQuestions(ctx context.Context, eventID int32, organizerID int64, questions []model.EventQuestion) ([]model.EventQuestion, error)
+	ListApplicationAnswers(ctx context.Context, applicationIDs []int32) (map[int32][]model.ApplicationAnswer, error)
+}
+
+type eventQuestionService struct {
+	q  dbsqlc.Querier
+	tx TxRunner
+}
+
+func NewEventQuestionService(q dbsqlc.Querier, tx TxRunner) EventQuestionService {
+	return &eventQuestionService{q: q, tx: tx}
+}
+
+func (s *eventQuestionService) ListEventQuestions(ctx context.Context, eventID int32) ([]model.EventQuestion, error) {
+	rows, err := s.q.ListEventQuestions(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return mapEventQuestions(rows), nil
+}
+
+// ReplaceEventQuestions заменяет анкету события целиком. После первой заявки анкету менять нельзя,
+// иначе ответы уже поданных заявок потеряют смысл.
+func (s *eventQuestionService) ReplaceEventQuestions(ctx context.Context, eventID int32, organizerID int64, questions []model.EventQuestion) ([]model.EventQuestion, error) {
+	normalized, err := normalizeQuestions(questions)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []model.EventQuestion
+	err = s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		event, err := lockEvent(ctx, q, eventID)
+		if err != nil {
+			return err
+		}
+		if event.OrganizerID == nil || *event.OrganizerID != organizerID {
+			return ErrNotEventOwner
+		}
+		applications, err := q.CountApplicationsByEvent(ctx, int32ToInt4(eventID))
+		if err != nil {
+			return err
+		}
+		if applications > 0 {
+			return ErrQuestionnaireLocked
+		}
+
+		if err := q.DeleteEventQuestions(ctx, eventID); err != nil {
+			return fmt.Errorf("delete questions: %w", err)
+		}
+		saved = make([]model.EventQuestion, 0, len(normalized))
+		for i, question := range normalized {
+			row, err := q.CreateEventQuestion(ctx, dbsqlc.CreateEventQuestionParams{
+				EventID:  eventID,
+				Position: int32(i + 1),
+				Type:     question.Type,
+				Text:     question.Text,
+				Options:  question.Options,
+				Required: question.Required,
+			})
+			if err != nil {
+				return fmt.Errorf("create question: %w", err)
+			}
+			saved = append(saved, mapEventQuestion(row))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ListApplicationAnswers возвращает ответы на анкету, сгруппированные по id заявки.
+func (s *eventQuestionService) ListApplicationAnswers(ctx context.Context, applicationIDs []int32) (map[int32][]model.ApplicationAnswer, error) {
+	result := make(map[int32][]model.ApplicationAnswer)
+	if len(applicationIDs) == 0 {
+		return result, nil
+	}
+	rows, err := s.q.ListApplicationAnswers(ctx, applicationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, answer := range mapApplicationAnswers(rows) {
+		result[answer.ApplicationID] = append(result[answer.ApplicationID], answer)
+	}
+	return result, nil
+}
+
+// ValidateAnswer проверяет ответ на один вопрос и возвращает нормализованные значения.
+// Пустой ответ на необязательный вопрос возвращается как nil без ошибки.
+func ValidateAnswer(question model.EventQuestion, values []string) ([]string, error) {
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	fail := func(reason string) ([]string, error) {
+		return nil, &QuestionnaireError{Err: ErrInvalidAnswer, Position: int(question.Position), QuestionID: question.ID, Reason: reason}
+	}
+
+	if len(trimmed) == 0 {
+		if question.Required {
+			return fail("ответ обязателен")
+		}
+		return nil, nil
+	}
+
+	switch question.Type {
+	case model.QuestionTypeText:
+		if len(trimmed) != 1 {
+			return fail("нужен один текстовый ответ")
+		}
+		if utf8.RuneCountInString(trimmed[0]) > MaxTextAnswerLength {
+			return fail(fmt.Sprintf("ответ не должен превышать %d символов", MaxTextAnswerLength))
+		}
+	case model.QuestionTypeYesNo:
+		if len(trimmed) != 1 || (trimmed[0] != model.AnswerYes && trimmed[0] != model.AnswerNo) {
+			return fail("ответьте «да» или «нет»")
+		}
+	case model.QuestionTypeSingleChoice:
+		if len(trimmed) != 1 || !slices.Contains(question.Options, trimmed[0]) {
+			return fail("выберите один из вариантов")
+		}
+	case model.QuestionTypeMultipleChoice:
+		selected := make([]string, 0, len(trimmed))
+		for _, value := range trimmed {
+			if !slices.Contains(question.Options, value) {
+				return fail("выберите варианты из списка")
+			}
+			if !slices.Contains(selected, value) {
+				selected = append(selected, value)
+			}
+		}
+		trimmed = selected
+	default:
+		return fail("неизвестный тип вопроса")
+	}
+	return trimmed, nil
+}
+
+// validateAnswers проверяет ответы на всю анкету и возвращает только непустые ответы.
+func validateAnswers(questions []model.EventQuestion, answers []model.ApplicationAnswer) ([]model.ApplicationAnswer, error) {
+	byQuestion := make(map[int32][]string, len(answers))
+	for _, answer := range answers {
+		if !slices.ContainsFunc(questions, func(q model.EventQuestion) bool { return q.ID == answer.QuestionID }) {
+			return nil, &QuestionnaireError{Err: ErrInvalidAnswer, QuestionID: answer.QuestionID, Reason: "такого вопроса нет в анкете"}
+		}
+		byQuestion[answer.QuestionID] = append(byQuestion[answer.QuestionID], answer.Values...)
+	}
+
+	result := make([]model.ApplicationAnswer, 0, len(questions))
+	for _, question := range questions {
+		values, err := ValidateAnswer(question, byQuestion[question.ID])
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			continue
+		}
+		result = append(result, model.ApplicationAnswer{
+			QuestionID:   question.ID,
+			QuestionType: question.Type,
+			QuestionText: question.Text,
+			Values:       values,
+		})
+	}
+	return result, nil
+}
+
+// normalizeQuestions проверяет вопросы анкеты перед сохранением.
+func normalizeQuestions(questions []model.EventQuestion) ([]model.EventQuestion, error) {
+	if len(questions) > MaxEventQuestions {
+		return nil, &QuestionnaireError{Err: ErrInvalidQuestion, Reason: fmt.Sprintf("в анкете может быть не больше %d вопросов", MaxEventQuestions)}
+	}
+
+	result := make([]model.EventQuestion, 0, len(questions))
+	for i, question := range questions {
+		fail := func(reason string) ([]model.EventQuestion, error) {
+			return nil, &QuestionnaireError{Err: ErrInvalidQuestion, Position: i + 1, Reason: reason}
+		}
+
+		question.Text = strings.TrimSpace(question.Text)
+		if question.Text == "" {
+			return fail("текст вопроса обязателен")
+		}
+		if utf8.RuneCountInString(question.Text) > MaxQuestionTextLength {
+			return fail(fmt.Sprintf("текст вопроса не должен превышать %d символов", MaxQuestionTextLength))
+		}
+
+		switch question.Type {
+		case model.QuestionTypeText, model.QuestionTypeYesNo:
+			if len(question.Options) > 0 {
+				return fail("варианты ответа задаются только для вопросов с выбором")
+			}
+			question.Options = []string{}
+		case model.QuestionTypeSingleChoice, model.QuestionTypeMultipleChoice:
+			options := make([]string, 0, len(question.Options))
+			for _, option := range question.Options {
+				option = strings.TrimSpace(option)
+				if option == "" {
+					return fail("вариант ответа не может быть пустым")
+				}
+				if utf8.RuneCountInString(option) > MaxQuestionOptionLength {
+					return fail(fmt.Sprintf("вариант ответа не должен превышать %d символов", MaxQuestionOptionLength))
+				}
+				if slices.Contains(options, option) {
+					return fail("варианты ответа не должны повторяться")
+				}
+				options = append(options, option)
+			}
+			if len(options) < minQuestionOptions || len(options) > MaxQuestionOptions {
+				return fail(fmt.Sprintf("у вопроса с выбором должно быть от %d до %d вариантов", minQuestionOptions, MaxQuestionOptions))
+			}
+			question.Options = options
+		default:
+			return fail("тип вопроса должен быть text, single_choice, multiple_choice или yes_no")
+		}
+
+		question.Position = int32(i + 1)
+		result = append(result, question)
+	}
+	return result, nil
+}
+
+var _ EventQuestionService = (*eventQuestionService)(nil)
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+func TestValidateAnswer(t *testing.T) {
+	text := model.EventQuestion{ID: 1, Position: 1, Type: model.QuestionTypeText, Required: true}
+	optionalText := model.EventQuestion{ID: 2, Position: 2, Type: model.QuestionTypeText}
+	yesNo := model.EventQuestion{ID: 3, Position: 3, Type: model.QuestionTypeYesNo, Required: true}
+	single := model.EventQuestion{ID: 4, Position: 4, Type: model.QuestionTypeSingleChoice, Options: []string{"утро", "вечер"}}
+	multiple := model.EventQuestion{ID: 5, Position: 5, Type: model.QuestionTypeMultipleChoice, Options: []string{"a", "b", "c"}}
+
+	cases := map[string]struct {
+		question model.EventQuestion
+		values   []string
+		want     []string
+		wantErr  bool
+	}{
+		"text trimmed":              {question: text, values: []string{"  есть опыт  "}, want: []string{"есть опыт"}},
+		"text required":             {question: text, values: []string{" ", ""}, wantErr: true},
+		"text two values":           {question: text, values: []string{"a", "b"}, wantErr: true},
+		"text at limit":             {question: text, values: []string{strings.Repeat("я", MaxTextAnswerLength)}, want: []string{strings.Repeat("я", MaxTextAnswerLength)}},
+		"text over limit":           {question: text, values: []string{strings.Repeat("я", MaxTextAnswerLength+1)}, wantErr: true},
+		"optional skipped":          {question: optionalText, values: nil, want: nil},
+		"optional blank":            {question: optionalText, values: []string{"  "}, want: nil},
+		"yes":                       {question: yesNo, values: []string{model.AnswerYes}, want: []string{model.AnswerYes}},
+		"no":                        {question: yesNo, values: []string{" no "}, want: []string{model.AnswerNo}},
+		"yes no other value":        {question: yesNo, values: []string{"да"}, wantErr: true},
+		"yes no both":               {question: yesNo, values: []string{model.AnswerYes, model.AnswerNo}, wantErr: true},
+		"yes no required":           {question: yesNo, values: nil, wantErr: true},
+		"single option":             {question: single, values: []string{"вечер"}, want: []string{"вечер"}},
+		"single unknown option":     {question: single, values: []string{"ночь"}, wantErr: true},
+		"single two options":        {question: single, values: []string{"утро", "вечер"}, wantErr: true},
+		"single optional skipped":   {question: single, values: []string{}, want: nil},
+		"multiple options":          {question: multiple, values: []string{"c", "a"}, want: []string{"c", "a"}},
+		"multiple duplicates":       {question: multiple, values: []string{"a", " a", "b", "a"}, want: []string{"a", "b"}},
+		"multiple unknown option":   {question: multiple, values: []string{"a", "d"}, wantErr: true},
+		"unknown question type":     {question: model.EventQuestion{ID: 6, Type: "rating"}, values: []string{"5"}, wantErr: true},
+		"unknown type when skipped": {question: model.EventQuestion{ID: 6, Type: "rating"}, values: nil, want: nil},
+	}
+	for name, tc := range cases {
+		got, err := ValidateAnswer(tc.question, tc.values)
+		if tc.wantErr {
+			var qErr *QuestionnaireError
+			if !errors.As(err, &qErr) || !errors.Is(err, ErrInvalidAnswer) || qErr.QuestionID != tc.question.ID || qErr.Reason == "" {
+				t.Errorf("%s: got %v, want answer error for question %d", name, err, tc.question.ID)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %q, %v, want %q", name, got, err, tc.want)
+		}
+	}
+}
+
+func TestValidateAnswers(t *testing.T) {
+	questions := []model.EventQuestion{
+		{ID: 10, Position: 1, Type: model.QuestionTypeText, Text: "Опыт", Required: true},
+		{ID: 11, Position: 2, Type: model.QuestionTypeMultipleChoice, Text: "Дни", Options: []string{"сб", "вс"}},
+		{ID: 12, Position: 3, Type: model.QuestionTypeYesNo, Text: "Есть машина?"},
+	}
+
+	// Значения одного вопроса из нескольких ответов объединяются, пустые необязательные ответы пропадают.
+	got, err := validateAnswers(questions, []model.ApplicationAnswer{
+		{QuestionID: 11, Values: []string{"сб"}},
+		{QuestionID: 10, Values: []string{"два года"}},
+		{QuestionID: 11, Values: []string{"вс", "сб"}},
+	})
+	if err != nil {
+		t.Fatalf("valid answers: %v", err)
+	}
+	if len(got) != 2 || got[0].QuestionID != 10 || got[0].QuestionText != "Опыт" ||
+		got[1].QuestionID != 11 || got[1].QuestionType != model.QuestionTypeMultipleChoice || !slices.Equal(got[1].Values, []string{"сб", "вс"}) {
+		t.Fatalf("unexpected answers %+v", got)
+	}
+
+	cases := map[string]struct {
+		answers      []model.ApplicationAnswer
+		wantQuestion int32
+	}{
+		"unknown question":  {answers: []model.ApplicationAnswer{{QuestionID: 10, Values: []string{"да"}}, {QuestionID: 99, Values: []string{"x"}}}, wantQuestion: 99},
+		"required missing":  {answers: []model.ApplicationAnswer{{QuestionID: 12, Values: []string{model.AnswerYes}}}, wantQuestion: 10},
+		"invalid in second": {answers: []model.ApplicationAnswer{{QuestionID: 10, Values: []string{"да"}}, {QuestionID: 11, Values: []string{"пн"}}}, wantQuestion: 11},
+	}
+	for name, tc := range cases {
+		var qErr *QuestionnaireError
+		if _, err := validateAnswers(questions, tc.answers); !errors.As(err, &qErr) || !errors.Is(err, ErrInvalidAnswer) || qErr.QuestionID != tc.wantQuestion {
+			t.Errorf("%s: got %v, want answer error for question %d", name, err, tc.wantQuestion)
+		}
+	}
+
+	if got, err := validateAnswers(nil, nil); err != nil || len(got) != 0 {
+		t.Errorf("no questionnaire: got %+v, %v", got, err)
+	}
+}
+
+func TestNormalizeQuestions(t *testing.T) {
+	got, err := normalizeQuestions([]model.EventQuestion{
+		{Type: model.QuestionTypeText, Text: "  Расскажите о себе  ", Required: true},
+		{Type: model.QuestionTypeSingleChoice, Text: "Смена", Options: []string{" утро ", "вечер"}},
+		{Type: model.QuestionTypeYesNo, Text: "Есть медкнижка?"},
+	})
+	if err != nil {
+		t.Fatalf("valid questions: %v", err)
+	}
+	if len(got) != 3 || got[0].Text != "Расскажите о себе" || got[0].Options == nil || len(got[0].Options) != 0 ||
+		!slices.Equal(got[1].Options, []string{"утро", "вечер"}) || got[2].Position != 3 {
+		t.Fatalf("unexpected questions %+v", got)
+	}
+
+	options := func(n int) []string {
+		result := make([]string, n)
+		for i := range result {
+			result[i] = strings.Repeat("x", i+1)
+		}
+		return result
+	}
+	cases := map[string]struct {
+		question model.EventQuestion
+		wantErr  bool
+	}{
+		"empty text":          {question: model.EventQuestion{Type: model.QuestionTypeText, Text: "  "}, wantErr: true},
+		"long text":           {question: model.EventQuestion{Type: model.QuestionTypeText, Text: strings.Repeat("я", MaxQuestionTextLength+1)}, wantErr: true},
+		"text with options":   {question: model.EventQuestion{Type: model.QuestionTypeText, Text: "Опыт", Options: []string{"a", "b"}}, wantErr: true},
+		"yes no with options": {question: model.EventQuestion{Type: model.QuestionTypeYesNo, Text: "Машина?", Options: []string{"да", "нет"}}, wantErr: true},
+		"one option":          {question: model.EventQuestion{Type: model.QuestionTypeSingleChoice, Text: "Смена", Options: options(1)}, wantErr: true},
+		"min options":         {question: model.EventQuestion{Type: model.QuestionTypeSingleChoice, Text: "Смена", Options: options(minQuestionOptions)}},
+		"max options":         {question: model.EventQuestion{Type: model.QuestionTypeMultipleChoice, Text: "Дни", Options: options(MaxQuestionOptions)}},
+		"too many options":    {question: model.EventQuestion{Type: model.QuestionTypeMultipleChoice, Text: "Дни", Options: options(MaxQuestionOptions + 1)}, wantErr: true},
+		"blank option":        {question: model.EventQuestion{Type: model.QuestionTypeSingleChoice, Text: "Смена", Options: []string{"утро", " "}}, wantErr: true},
+		"long option":         {question: model.EventQuestion{Type: model.QuestionTypeSingleChoice, Text: "Смена", Options: []string{"утро", strings.Repeat("я", MaxQuestionOptionLength+1)}}, wantErr: true},
+		"duplicate options":   {question: model.EventQuestion{Type: model.QuestionTypeMultipleChoice, Text: "Дни", Options: []string{"сб", " сб"}}, wantErr: true},
+		"unknown type":        {question: model.EventQuestion{Type: "rating", Text: "Оценка"}, wantErr: true},
+	}
+	for name, tc := range cases {
+		// Ошибочный вопрос идёт вторым, чтобы проверить номер позиции в ошибке.
+		questions := []model.EventQuestion{{Type: model.QuestionTypeYesNo, Text: "Готовы?"}, tc.question}
+		_, err := normalizeQuestions(questions)
+		if !tc.wantErr {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+			continue
+		}
+		var qErr *QuestionnaireError
+		if !errors.As(err, &qErr) || !errors.Is(err, ErrInvalidQuestion) || qErr.Position != 2 {
+			t.Errorf("%s: got %v, want question error at position 2", name, err)
+		}
+	}
+
+	tooMany := make([]model.EventQuestion, MaxEventQuestions+1)
+	for i := range tooMany {
+		tooMany[i] = model.EventQuestion{Type: model.QuestionTypeYesNo, Text: "Вопрос"}
+	}
+	var qErr *QuestionnaireError
+	if _, err := normalizeQuestions(tooMany); !errors.As(err, &qErr) || qErr.Position != 0 {
+		t.Errorf("too many questions: got %v, want questionnaire-level error", err)
+	}
+}
+
+// questionnaireQuerier — событие организатора 7 с applications заявками.
+type questionnaireQuerier struct {
+	lockQuerier
+	applications int64
+	created      []dbsqlc.CreateEventQuestionParams
+}
+
+func (q *questionnaireQuerier) CountApplicationsByEvent(context.Context, pgtype.Int4) (int64, error) {
+	return q.applications, nil
+}
+
+func (q *questionnaireQuerier) DeleteEventQuestions(context.Context, int32) error {
+	q.created = nil
+	return nil
+}
+
+func (q *questionnaireQuerier) CreateEventQuestion(_ context.Context, arg dbsqlc.CreateEventQuestionParams) (dbsqlc.EventQuestion, error) {
+	q.created = append(q.created, arg)
+	return dbsqlc.EventQuestion{ID: int32(len(q.created)), EventID: arg.EventID, Position: arg.Position, Type: arg.Type, Text: arg.Text, Options: arg.Options, Required: arg.Required}, nil
+}
+
+func TestReplaceEventQuestions(t *testing.T) {
+	questions := []model.EventQuestion{{Type: model.QuestionTypeYesNo, Text: " Готовы? ", Required: true}}
+	newQuerier := func(applications int64) *questionnaireQuerier {
+		event := lockedEvent("open", 0)
+		event.OrganizerID = int64ToInt8(7)
+		return &questionnaireQuerier{lockQuerier: lockQuerier{event: event}, applications: applications}
+	}
+
+	q := newQuerier(0)
+	saved, err := NewEventQuestionService(q, inlineTx{q: q}).ReplaceEventQuestions(context.Background(), 1, 7, questions)
+	if err != nil || len(saved) != 1 || saved[0].Text != "Готовы?" || saved[0].Position != 1 || len(q.created) != 1 {
+		t.Fatalf("replace: got %+v, %v", saved, err)
+	}
+
+	q = newQuerier(1)
+	if _, err := NewEventQuestionService(q, inlineTx{q: q}).ReplaceEventQuestions(context.Background(), 1, 7, questions); !errors.Is(err, ErrQuestionnaireLocked) || q.created != nil {
+		t.Errorf("after applications: got %v, created %v, want ErrQuestionnaireLocked", err, q.created)
+	}
+
+	q = newQuerier(0)
+	if _, err := NewEventQuestionService(q, inlineTx{q: q}).ReplaceEventQuestions(context.Background(), 1, 8, questions); !errors.Is(err, ErrNotEventOwner) {
+		t.Errorf("foreign event: got %v, want ErrNotEventOwner", err)
+	}
+
+	// Некорректная анкета отклоняется до транзакции.
+	q = newQuerier(0)
+	invalid := []model.EventQuestion{{Type: model.QuestionTypeText, Text: ""}}
+	if _, err := NewEventQuestionService(q, inlineTx{q: q}).ReplaceEventQuestions(context.Background(), 1, 7, invalid); !errors.Is(err, ErrInvalidQuestion) || q.lockedFirst {
+		t.Errorf("invalid questionnaire: got %v, locked %v", err, q.lockedFirst)
+	}
+}
@@ -2,15 +2,29 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	dbsqlc "maxBot/internal/db/sqlc"
 	"maxBot/internal/model"
 )
 
+var (
+	// ErrEventFinished завершённое или отменённое событие нельзя изменить.
+	ErrEventFinished = errors.New("event is completed or cancelled")
+	// ErrMaxVolunteersBelowCurrent лимит мест меньше числа уже одобренных волонтёров.
+	ErrMaxVolunteersBelowCurrent = errors.New("max volunteers is below approved volunteers")
+	// ErrEventHasApplications у события есть участники или открытые заявки: удаление стёрло бы их без уведомления.
+	ErrEventHasApplications = errors.New("event has participants or open applications")
+)
+
 // EventService aggregates event-related database operations.
 type EventService interface {
-	CreateEvent(ctx context.Context, title string, date time.Time, location string, locationLat, locationLon float64, maxVolunteers int32, organizerID int64) (model.Event, error)
+	CreateEvent(ctx context.Context, title string, description *string, chat *int64, date time.Time, durationHours *int32, location string, locationLat, locationLon float64, categoryID *int32, contacts *string, maxVolunteers int32, organizerID int64) (model.Event, error)
 	UpdateEvent(ctx context.Context, id int32, title string, description *string, chat *int64, date time.Time, durationHours *int32, location string, locationLat, locationLon float64, categoryID *int32, contacts *string, maxVolunteers int32) (model.Event, error)
 	UpdateEventStatus(ctx context.Context, id int32, status string) (model.Event, error)
 	CancelEvent(ctx context.Context, id int32, reason *string) (model.Event, error)
@@ -18,33 +32,44 @@ type EventService interface {
 	DeleteEvent(ctx context.Context, id int32) error
 	GetEventByID(ctx context.Context, id int32) (model.Event, error)
 	IncrementEventVolunteers(ctx context.Context, id int32, delta int32) (int32, error)
+	AdvanceEventStatuses(ctx context.Context, defaultDuration time.Duration) (int64, error)
 	CountAvailableEventsForVolunteer(ctx context.Context, volunteerID int64) (int64, error)
-	ListAvailableEventsForVolunteer(ctx context.Context, volunteerID int64, limit, offset int32) ([]model.Event, error)
-	ListAvailableEventsForVolunteerWithCategories(ctx context.Context, volunteerID int64, categoryIDs []int32, limit, offset int32) ([]model.Event, error)
+	ListAvailableEventsForVolunteer(ctx context.Context, volunteerID int64, limit int32, cursor string) (Page[model.Event], error)
+	ListAvailableEventsForVolunteerWithCategories(ctx context.Context, volunteerID int64, categoryIDs []int32, limit int32, cursor string) (Page[model.Event], error)
 	CountAvailableEventsForVolunteerWithCategories(ctx context.Context, volunteerID int64, categoryIDs []int32) (int64, error)
 	ListEvents(ctx context.Context, limit, offset int32) ([]model.Event, error)
 	ListEventsByOrganizer(ctx context.Context, organizerID int64, limit, offset int32) ([]model.Event, error)
+	CountEventsByOrganizer(ctx context.Context, organizerID int64) (int64, error)
 	ListEventsByStatus(ctx context.Context, status string, limit, offset int32) ([]model.Event, error)
 	ListUpcomingEvents(ctx context.Context, limit, offset int32) ([]model.Event, error)
 	ListEventsByCategory(ctx context.Context, categoryID int32, limit, offset int32) ([]model.Event, error)
 	ListEventsNearLocation(ctx context.Context, lat, lon, radiusKm float64, limit, offset int32) ([]model.Event, error)
 	ListEventsForVolunteer(ctx context.Context, volunteerID int64, limit, offset int32) ([]model.Event, error)
 	ListEventsWithPendingApplications(ctx context.Context, limit, offset int32) ([]model.Event, error)
-	ListEventsForMap(ctx context.Context, params ListMapEventsParams) ([]model.MapEvent, error)
-	ListEventsForMapByVolunteer(ctx context.Context, params ListMapEventsForVolunteerParams) ([]model.MapEvent, error)
+	ListOrganizerEventsWithPendingApplications(ctx context.Context, organizerID int64, limit, offset int32) ([]model.EventPendingSummary, error)
+	CountOrganizerEventsWithPendingApplications(ctx context.Context, organizerID int64) (int64, error)
+	ListEventsForMap(ctx context.Context, params ListMapEventsParams) (Page[model.MapEvent], error)
+	ListEventsForMapByVolunteer(ctx context.Context, params ListMapEventsForVolunteerParams) (Page[model.MapEvent], error)
+	ListMapClusters(ctx context.Context, params ListMapClustersParams) (model.MapClusters, error)
 }
 
 type eventService struct {
-	q dbsqlc.Querier
+	q   dbsqlc.Querier
+	tx  TxRunner
+	hub *EventHub
 }
 
 // ListMapEventsParams описывает фильтры для REST API карты волонтёров.
+// Если задан BBox, поиск идёт по области, а Lat/Lon — точка отсчёта distanceKm; RadiusKm тогда не используется.
+// Cursor — NextCursor предыдущей страницы, WithTotal дополнительно считает TotalEstimate.
 type ListMapEventsParams struct {
-	Offset      int32
+	Cursor      string
+	WithTotal   bool
 	Limit       int32
 	Lat         float64
 	Lon         float64
 	RadiusKm    float64
+	BBox        *model.MapBBox
 	CategoryIDs []int32
 }
 
@@ -53,23 +78,46 @@ type ListMapEventsForVolunteerParams struct {
 	VolunteerID int64
 }
 
-func NewEventService(q dbsqlc.Querier) EventService {
-	return &eventService{q: q}
+func (p ListMapEventsParams) cursorFilter(volunteerID int64) string {
+	var bbox model.MapBBox
+	if p.BBox != nil {
+		bbox = *p.BBox
+	}
+	return cursorFilter("map", volunteerID, p.Lat, p.Lon, p.RadiusKm, bbox, p.CategoryIDs)
+}
+
+func mapEventCursorKey(filter string) func(model.MapEvent) pageCursor {
+	return func(e model.MapEvent) pageCursor {
+		distance := e.DistanceKm
+		return pageCursor{Filter: filter, Date: e.Date, ID: e.ID, DistanceKm: &distance}
+	}
+}
+
+func eventCursorKey(filter string) func(model.Event) pageCursor {
+	return func(e model.Event) pageCursor {
+		return pageCursor{Filter: filter, Date: e.Date, ID: e.ID}
+	}
 }
 
-func (s *eventService) CreateEvent(ctx context.Context, title string, date time.Time, location string, locationLat, locationLon float64, maxVolunteers int32, organizerID int64) (model.Event, error) {
+// NewEventService создаёт сервис событий. Изменения публикуются в hub; внутри транзакций tx и hub передают nil,
+// а публикуют уже после коммита. UpdateEvent и DeleteEvent без tx недоступны.
+func NewEventService(q dbsqlc.Querier, tx TxRunner, hub *EventHub) EventService {
+	return &eventService{q: q, tx: tx, hub: hub}
+}
+
+func (s *eventService) CreateEvent(ctx context.Context, title string, description *string, chat *int64, date time.Time, durationHours *int32, location string, locationLat, locationLon float64, categoryID *int32, contacts *string, maxVolunteers int32, organizerID int64) (model.Event, error) {
 	params := dbsqlc.CreateEventParams{
 		Title:             title,
-		Description:       stringPtrToText(nil),
-		Chat:              int64PtrToInt8(nil),
+		Description:       stringPtrToText(description),
+		Chat:              int64PtrToInt8(chat),
 		Date:              timePtrToTimestamp(&date),
-		DurationHours:     int32PtrToInt4(nil),
+		DurationHours:     int32PtrToInt4(durationHours),
 		Location:          location,
 		LocationLat:       float64ToNumeric(locationLat),
 		LocationLon:       float64ToNumeric(locationLon),
-		CategoryID:        int32PtrToInt4(nil),
+		CategoryID:        int32PtrToInt4(categoryID),
 		OrganizerID:       int64ToInt8(organizerID),
-		Contacts:          stringPtrToText(nil),
+		Contacts:          stringPtrToText(contacts),
 		MaxVolunteers:     maxVolunteers,
 		CurrentVolunteers: nil,
 		Status:            nil, // будет "planned" по умолчанию
@@ -80,7 +128,12 @@ func (s *eventService) CreateEvent(ctx context.Context, title string, date time.
 	if err != nil {
 		return model.Event{}, err
 	}
-	return mapEvent(e)
+	event, err := mapEvent(e)
+	if err != nil {
+		return model.Event{}, err
+	}
+	s.hub.publishEvent(model.EventUpdateCreated, event)
+	return event, nil
 }
 
 func (s *eventService) UpdateEvent(ctx context.Context, id int32, title string, description *string, chat *int64, date time.Time, durationHours *int32, location string, locationLat, locationLon float64, categoryID *int32, contacts *string, maxVolunteers int32) (model.Event, error) {
@@ -98,47 +151,121 @@ func (s *eventService) UpdateEvent(ctx context.Context, id int32, title string,
 		Contacts:      stringPtrToText(contacts),
 		MaxVolunteers: maxVolunteers,
 	}
-	e, err := s.q.UpdateEvent(ctx, params)
+	// Проверки идут под блокировкой строки: одобрение заявки, закоммиченное между проверкой и update,
+	// иначе оставило бы лимит мест меньше числа участников.
+	var event model.Event
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		current, err := lockEvent(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if status := eventStatus(current); status == model.EventStatusCompleted || status == model.EventStatusCancelled {
+			return ErrEventFinished
+		}
+		if current.CurrentVolunteers != nil && maxVolunteers < *current.CurrentVolunteers {
+			return ErrMaxVolunteersBelowCurrent
+		}
+		e, err := q.UpdateEvent(ctx, params)
+		if err != nil {
+			return err
+		}
+		event, err = mapEvent(e)
+		return err
+	})
 	if err != nil {
 		return model.Event{}, err
 	}
-	return mapEvent(e)
+	s.hub.publishEvent(model.EventUpdateUpdated, event)
+	return event, nil
 }
 
+// UpdateEventStatus переводит событие в новый статус, если такой переход разрешён.
 func (s *eventService) UpdateEventStatus(ctx context.Context, id int32, status string) (model.Event, error) {
-	params := dbsqlc.UpdateEventStatusParams{
-		ID:     id,
-		Status: stringToText(status),
-	}
-	e, err := s.q.UpdateEventStatus(ctx, params)
+	return s.transitionEventStatus(ctx, id, status, func(from string) (dbsqlc.Event, error) {
+		return s.q.UpdateEventStatus(ctx, dbsqlc.UpdateEventStatusParams{
+			ID:         id,
+			Status:     stringToText(status),
+			FromStatus: stringToText(from),
+		})
+	})
+}
+
+func (s *eventService) CancelEvent(ctx context.Context, id int32, reason *string) (model.Event, error) {
+	return s.transitionEventStatus(ctx, id, model.EventStatusCancelled, func(from string) (dbsqlc.Event, error) {
+		return s.q.CancelEvent(ctx, dbsqlc.CancelEventParams{
+			ID:         id,
+			Reason:     stringPtrToText(reason),
+			FromStatus: stringToText(from),
+		})
+	})
+}
+
+func (s *eventService) CompleteEvent(ctx context.Context, id int32) (model.Event, error) {
+	return s.transitionEventStatus(ctx, id, model.EventStatusCompleted, func(from string) (dbsqlc.Event, error) {
+		return s.q.CompleteEvent(ctx, dbsqlc.CompleteEventParams{
+			ID:         id,
+			FromStatus: stringToText(from),
+		})
+	})
+}
+
+// transitionEventStatus проверяет переход из текущего статуса и выполняет update. Update срабатывает,
+// только если статус не изменился с момента чтения, иначе возвращается ErrEventStatusChanged.
+func (s *eventService) transitionEventStatus(ctx context.Context, id int32, to string, update func(from string) (dbsqlc.Event, error)) (model.Event, error) {
+	current, err := s.GetEventByID(ctx, id)
 	if err != nil {
 		return model.Event{}, err
 	}
-	return mapEvent(e)
-}
+	from := eventStatus(current)
+	if err := validateEventStatusTransition(from, to); err != nil {
+		return model.Event{}, err
+	}
 
-func (s *eventService) CancelEvent(ctx context.Context, id int32, reason *string) (model.Event, error) {
-	params := dbsqlc.CancelEventParams{
-		ID:     id,
-		Reason: stringPtrToText(reason),
+	e, err := update(from)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Event{}, ErrEventStatusChanged
 	}
-	e, err := s.q.CancelEvent(ctx, params)
 	if err != nil {
 		return model.Event{}, err
 	}
-	return mapEvent(e)
-}
-
-func (s *eventService) CompleteEvent(ctx context.Context, id int32) (model.Event, error) {
-	e, err := s.q.CompleteEvent(ctx, id)
+	event, err := mapEvent(e)
 	if err != nil {
 		return model.Event{}, err
 	}
-	return mapEvent(e)
+	kind := model.EventUpdateUpdated
+	if to == model.EventStatusCancelled {
+		kind = model.EventUpdateCancelled
+	}
+	s.hub.publishEvent(kind, event)
+	return event, nil
 }
 
+// DeleteEvent удаляет событие без участников и открытых заявок. Удаление каскадно стирает заявки
+// без уведомлений, поэтому такое событие нужно отменять. Строка события блокируется так же, как при
+// подаче и одобрении заявок, поэтому заявка, поданная после проверки, не пропадёт молча.
 func (s *eventService) DeleteEvent(ctx context.Context, id int32) error {
-	return s.q.DeleteEvent(ctx, id)
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		event, err := lockEvent(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if event.CurrentVolunteers != nil && *event.CurrentVolunteers > 0 {
+			return ErrEventHasApplications
+		}
+		active, err := q.CountActiveApplicationsByEvent(ctx, int32ToInt4(id))
+		if err != nil {
+			return fmt.Errorf("count applications: %w", err)
+		}
+		if active > 0 {
+			return ErrEventHasApplications
+		}
+		return q.DeleteEvent(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+	s.hub.Publish(model.EventUpdate{Type: model.EventUpdateDeleted, EventID: id})
+	return nil
 }
 
 func (s *eventService) GetEventByID(ctx context.Context, id int32) (model.Event, error) {
@@ -161,35 +288,89 @@ func (s *eventService) IncrementEventVolunteers(ctx context.Context, id int32, d
 	return int4Value(val), nil
 }
 
+// AdvanceEventStatuses выполняет автоматические переходы: open/full по заполненности,
+// начавшиеся события в in_progress, закончившиеся в completed. Возвращает число изменённых событий.
+func (s *eventService) AdvanceEventStatuses(ctx context.Context, defaultDuration time.Duration) (int64, error) {
+	synced, err := s.q.SyncEventCapacityStatuses(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.publishEvents(synced)
+	started, err := s.q.StartDueEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.publishEvents(started)
+	completed, err := s.q.CompletePastEvents(ctx, int32(defaultDuration/time.Minute))
+	if err != nil {
+		return 0, err
+	}
+	s.publishEvents(completed)
+	return int64(len(synced) + len(started) + len(completed)), nil
+}
+
+// publishEvents рассылает смену статусов, сделанную планировщиком.
+func (s *eventService) publishEvents(rows []dbsqlc.Event) {
+	if s.hub == nil {
+		return
+	}
+	for _, row := range rows {
+		if event, err := mapEvent(row); err == nil {
+			s.hub.publishEvent(model.EventUpdateUpdated, event)
+		}
+	}
+}
+
 func (s *eventService) CountAvailableEventsForVolunteer(ctx context.Context, volunteerID int64) (int64, error) {
 	return s.q.CountAvailableEventsForVolunteer(ctx, int64ToInt8(volunteerID))
 }
 
-func (s *eventService) ListAvailableEventsForVolunteer(ctx context.Context, volunteerID int64, limit, offset int32) ([]model.Event, error) {
+// ListAvailableEventsForVolunteer отдаёт страницу от новых к старым; cursor — NextCursor прошлой страницы.
+func (s *eventService) ListAvailableEventsForVolunteer(ctx context.Context, volunteerID int64, limit int32, cursor string) (Page[model.Event], error) {
+	filter := cursorFilter("available", volunteerID)
+	after, err := decodeCursor(cursor, filter)
+	if err != nil {
+		return Page[model.Event]{}, err
+	}
 	params := dbsqlc.ListAvailableEventsForVolunteerParams{
 		VolunteerID: int64ToInt8(volunteerID),
-		Limit:       limit,
-		Offset:      offset,
+		AfterID:     after.afterID(),
+		AfterDate:   after.afterDate(),
+		Limit:       limit + 1,
 	}
-	items, err := s.q.ListAvailableEventsForVolunteer(ctx, params)
+	rows, err := s.q.ListAvailableEventsForVolunteer(ctx, params)
 	if err != nil {
-		return nil, err
+		return Page[model.Event]{}, err
 	}
-	return mapEvents(items)
+	items, err := mapEvents(rows)
+	if err != nil {
+		return Page[model.Event]{}, err
+	}
+	return newPage(items, limit, eventCursorKey(filter)), nil
 }
 
-func (s *eventService) ListAvailableEventsForVolunteerWithCategories(ctx context.Context, volunteerID int64, categoryIDs []int32, limit, offset int32) ([]model.Event, error) {
+func (s *eventService) ListAvailableEventsForVolunteerWithCategories(ctx context.Context, volunteerID int64, categoryIDs []int32, limit int32, cursor string) (Page[model.Event], error) {
+	filter := cursorFilter("available", volunteerID, categoryIDs)
+	after, err := decodeCursor(cursor, filter)
+	if err != nil {
+		return Page[model.Event]{}, err
+	}
 	params := dbsqlc.ListAvailableEventsForVolunteerWithCategoriesParams{
 		VolunteerID: int64ToInt8(volunteerID),
 		CategoryIds: categoryIDs,
-		Limit:       limit,
-		Offset:      offset,
+		AfterID:     after.afterID(),
+		AfterDate:   after.afterDate(),
+		Limit:       limit + 1,
 	}
-	items, err := s.q.ListAvailableEventsForVolunteerWithCategories(ctx, params)
+	rows, err := s.q.ListAvailableEventsForVolunteerWithCategories(ctx, params)
 	if err != nil {
-		return nil, err
+		return Page[model.Event]{}, err
 	}
-	return mapEvents(items)
+	items, err := mapEvents(rows)
+	if err != nil {
+		return Page[model.Event]{}, err
+	}
+	return newPage(items, limit, eventCursorKey(filter)), nil
 }
 
 func (s *eventService) CountAvailableEventsForVolunteerWithCategories(ctx context.Context, volunteerID int64, categoryIDs []int32) (int64, error) {
@@ -225,6 +406,10 @@ func (s *eventService) ListEventsByOrganizer(ctx context.Context, organizerID in
 	return mapEvents(items)
 }
 
+func (s *eventService) CountEventsByOrganizer(ctx context.Context, organizerID int64) (int64, error) {
+	return s.q.CountEventsByOrganizer(ctx, int64ToInt8(organizerID))
+}
+
 func (s *eventService) ListEventsByStatus(ctx context.Context, status string, limit, offset int32) ([]model.Event, error) {
 	params := dbsqlc.ListEventsByStatusParams{
 		Status: stringPtrToText(&status),
@@ -264,14 +449,14 @@ func (s *eventService) ListEventsByCategory(ctx context.Context, categoryID int3
 }
 
 func (s *eventService) ListEventsNearLocation(ctx context.Context, lat, lon, radiusKm float64, limit, offset int32) ([]model.Event, error) {
-	delta := radiusKm / 111.0
+	now := time.Now().UTC()
 	params := dbsqlc.ListEventsNearLocationParams{
-		TargetLat: float64ToNumeric(lat),
-		LatDelta:  float64ToNumeric(delta),
-		TargetLon: float64ToNumeric(lon),
-		LonDelta:  float64ToNumeric(delta),
-		Limit:     limit,
+		StartDate: timePtrToTimestamp(&now),
+		Lat:       lat,
+		Lon:       lon,
+		RadiusKm:  radiusKm,
 		Offset:    offset,
+		Limit:     limit,
 	}
 	items, err := s.q.ListEventsNearLocation(ctx, params)
 	if err != nil {
@@ -305,35 +490,237 @@ func (s *eventService) ListEventsWithPendingApplications(ctx context.Context, li
 	return mapEvents(items)
 }
 
-func (s *eventService) ListEventsForMap(ctx context.Context, params ListMapEventsParams) ([]model.MapEvent, error) {
-	rows, err := s.q.ListEventsForMap(ctx, dbsqlc.ListEventsForMapParams{
-		Offset:      params.Offset,
-		Limit:       params.Limit,
-		Lat:         params.Lat,
-		Lon:         params.Lon,
-		CategoryIds: params.CategoryIDs,
-		RadiusKm:    params.RadiusKm,
-	})
+func (s *eventService) ListOrganizerEventsWithPendingApplications(ctx context.Context, organizerID int64, limit, offset int32) ([]model.EventPendingSummary, error) {
+	params := dbsqlc.ListOrganizerEventsWithPendingApplicationsParams{
+		OrganizerID: int64ToInt8(organizerID),
+		Limit:       limit,
+		Offset:      offset,
+	}
+	items, err := s.q.ListOrganizerEventsWithPendingApplications(ctx, params)
 	if err != nil {
 		return nil, err
 	}
-	return mapMapEvents(rows)
+	return mapEventPendingSummaries(items), nil
 }
 
-func (s *eventService) ListEventsForMapByVolunteer(ctx context.Context, params ListMapEventsForVolunteerParams) ([]model.MapEvent, error) {
-	rows, err := s.q.ListEventsForMapByVolunteer(ctx, dbsqlc.ListEventsForMapByVolunteerParams{
-		Offset:      params.Offset,
-		Limit:       params.Limit,
-		VolunteerID: int64ToInt8(params.VolunteerID),
-		Lat:         params.Lat,
-		Lon:         params.Lon,
-		CategoryIds: params.CategoryIDs,
-		RadiusKm:    params.RadiusKm,
-	})
+func (s *eventService) CountOrganizerEventsWithPendingApplications(ctx context.Context, organizerID int64) (int64, error) {
+	return s.q.CountOrganizerEventsWithPendingApplications(ctx, int64ToInt8(organizerID))
+}
+
+func (s *eventService) ListEventsForMap(ctx context.Context, params ListMapEventsParams) (Page[model.MapEvent], error) {
+	filter := params.cursorFilter(0)
+	after, err := decodeCursor(params.Cursor, filter)
 	if err != nil {
-		return nil, err
+		return Page[model.MapEvent]{}, err
+	}
+
+	var items []model.MapEvent
+	var total int64
+	if params.BBox != nil {
+		rows, err := s.q.ListEventsForMapInBBox(ctx, dbsqlc.ListEventsForMapInBBoxParams{
+			Lat:             params.Lat,
+			Lon:             params.Lon,
+			CategoryIds:     params.CategoryIDs,
+			MinLon:          params.BBox.MinLon,
+			MinLat:          params.BBox.MinLat,
+			MaxLon:          params.BBox.MaxLon,
+			MaxLat:          params.BBox.MaxLat,
+			AfterID:         after.afterID(),
+			AfterDistanceKm: after.afterDistanceKm(),
+			AfterDate:       after.afterDate(),
+			Limit:           params.Limit + 1,
+		})
+		if err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if items, err = mapMapEventsInBBox(rows); err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if params.WithTotal {
+			if total, err = s.q.CountEventsForMapInBBox(ctx, dbsqlc.CountEventsForMapInBBoxParams{
+				CategoryIds: params.CategoryIDs,
+				MinLon:      params.BBox.MinLon,
+				MinLat:      params.BBox.MinLat,
+				MaxLon:      params.BBox.MaxLon,
+				MaxLat:      params.BBox.MaxLat,
+			}); err != nil {
+				return Page[model.MapEvent]{}, err
+			}
+		}
+	} else {
+		rows, err := s.q.ListEventsForMap(ctx, dbsqlc.ListEventsForMapParams{
+			Lat:             params.Lat,
+			Lon:             params.Lon,
+			CategoryIds:     params.CategoryIDs,
+			RadiusKm:        params.RadiusKm,
+			AfterID:         after.afterID(),
+			AfterDistanceKm: after.afterDistanceKm(),
+			AfterDate:       after.afterDate(),
+			Limit:           params.Limit + 1,
+		})
+		if err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if items, err = mapMapEvents(rows); err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if params.WithTotal {
+			if total, err = s.q.CountEventsForMap(ctx, dbsqlc.CountEventsForMapParams{
+				CategoryIds: params.CategoryIDs,
+				Lat:         params.Lat,
+				Lon:         params.Lon,
+				RadiusKm:    params.RadiusKm,
+			}); err != nil {
+				return Page[model.MapEvent]{}, err
+			}
+		}
+	}
+
+	page := newPage(items, params.Limit, mapEventCursorKey(filter))
+	if params.WithTotal {
+		page.TotalEstimate = &total
 	}
-	return mapMapEventsWithStatus(rows)
+	return page, nil
+}
+
+func (s *eventService) ListEventsForMapByVolunteer(ctx context.Context, params ListMapEventsForVolunteerParams) (Page[model.MapEvent], error) {
+	filter := params.cursorFilter(params.VolunteerID)
+	after, err := decodeCursor(params.Cursor, filter)
+	if err != nil {
+		return Page[model.MapEvent]{}, err
+	}
+
+	var items []model.MapEvent
+	var total int64
+	if params.BBox != nil {
+		rows, err := s.q.ListEventsForMapByVolunteerInBBox(ctx, dbsqlc.ListEventsForMapByVolunteerInBBoxParams{
+			Lat:             params.Lat,
+			Lon:             params.Lon,
+			VolunteerID:     int64ToInt8(params.VolunteerID),
+			CategoryIds:     params.CategoryIDs,
+			MinLon:          params.BBox.MinLon,
+			MinLat:          params.BBox.MinLat,
+			MaxLon:          params.BBox.MaxLon,
+			MaxLat:          params.BBox.MaxLat,
+			AfterID:         after.afterID(),
+			AfterDistanceKm: after.afterDistanceKm(),
+			AfterDate:       after.afterDate(),
+			Limit:           params.Limit + 1,
+		})
+		if err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if items, err = mapMapEventsWithStatusInBBox(rows); err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if params.WithTotal {
+			if total, err = s.q.CountEventsForMapByVolunteerInBBox(ctx, dbsqlc.CountEventsForMapByVolunteerInBBoxParams{
+				VolunteerID: int64ToInt8(params.VolunteerID),
+				CategoryIds: params.CategoryIDs,
+				MinLon:      params.BBox.MinLon,
+				MinLat:      params.BBox.MinLat,
+				MaxLon:      params.BBox.MaxLon,
+				MaxLat:      params.BBox.MaxLat,
+			}); err != nil {
+				return Page[model.MapEvent]{}, err
+			}
+		}
+	} else {
+		rows, err := s.q.ListEventsForMapByVolunteer(ctx, dbsqlc.ListEventsForMapByVolunteerParams{
+			Lat:             params.Lat,
+			Lon:             params.Lon,
+			VolunteerID:     int64ToInt8(params.VolunteerID),
+			CategoryIds:     params.CategoryIDs,
+			RadiusKm:        params.RadiusKm,
+			AfterID:         after.afterID(),
+			AfterDistanceKm: after.afterDistanceKm(),
+			AfterDate:       after.afterDate(),
+			Limit:           params.Limit + 1,
+		})
+		if err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if items, err = mapMapEventsWithStatus(rows); err != nil {
+			return Page[model.MapEvent]{}, err
+		}
+		if params.WithTotal {
+			if total, err = s.q.CountEventsForMapByVolunteer(ctx, dbsqlc.CountEventsForMapByVolunteerParams{
+				VolunteerID: int64ToInt8(params.VolunteerID),
+				CategoryIds: params.CategoryIDs,
+				Lat:         params.Lat,
+				Lon:         params.Lon,
+				RadiusKm:    params.RadiusKm,
+			}); err != nil {
+				return Page[model.MapEvent]{}, err
+			}
+		}
+	}
+
+	page := newPage(items, params.Limit, mapEventCursorKey(filter))
+	if params.WithTotal {
+		page.TotalEstimate = &total
+	}
+	return page, nil
+}
+
+// ListMapClusters группирует мероприятия видимой области в базе по сетке масштаба; с масштаба
+// MapPointsMinZoom возвращает отдельные точки. Область через 180-й меридиан запрашивается двумя частями.
+func (s *eventService) ListMapClusters(ctx context.Context, params ListMapClustersParams) (model.MapClusters, error) {
+	result := model.MapClusters{
+		Zoom:     params.Zoom,
+		Clusters: []model.MapCluster{},
+		Points:   []model.MapPoint{},
+	}
+	boxes := splitAntimeridian(params.BBox)
+
+	if params.Zoom >= MapPointsMinZoom {
+		var rows []dbsqlc.ListEventPointsInBBoxRow
+		for _, box := range boxes {
+			part, err := s.q.ListEventPointsInBBox(ctx, dbsqlc.ListEventPointsInBBoxParams{
+				MinLon:      box.MinLon,
+				MinLat:      box.MinLat,
+				MaxLon:      box.MaxLon,
+				MaxLat:      box.MaxLat,
+				CategoryIds: params.CategoryIDs,
+				Limit:       mapMaxPoints,
+			})
+			if err != nil {
+				return model.MapClusters{}, err
+			}
+			rows = append(rows, part...)
+		}
+		if len(boxes) > 1 {
+			sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+		}
+		result.Truncated = len(rows) >= mapMaxPoints
+		if len(rows) > mapMaxPoints {
+			rows = rows[:mapMaxPoints]
+		}
+		result.Points = mapMapPoints(rows)
+		result.Total = len(result.Points)
+		return result, nil
+	}
+
+	var rows []dbsqlc.ListEventClustersInBBoxRow
+	for _, box := range boxes {
+		part, err := s.q.ListEventClustersInBBox(ctx, dbsqlc.ListEventClustersInBBoxParams{
+			GridSize:    clusterGridSize(params.Zoom),
+			MinLon:      box.MinLon,
+			MinLat:      box.MinLat,
+			MaxLon:      box.MaxLon,
+			MaxLat:      box.MaxLat,
+			CategoryIds: params.CategoryIDs,
+		})
+		if err != nil {
+			return model.MapClusters{}, err
+		}
+		rows = append(rows, part...)
+	}
+	result.Clusters = buildMapClusters(rows, params.Zoom)
+	for _, cluster := range result.Clusters {
+		result.Total += cluster.Count
+	}
+	return result, nil
 }
 
 var _ EventService = (*eventService)(nil)
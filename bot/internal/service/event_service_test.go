@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/repository"
+)
+
+// lockQuerier отдаёт одно событие под «блокировкой» и запоминает, дошло ли дело до update или delete.
+type lockQuerier struct {
+	dbsqlc.Querier
+	event       dbsqlc.Event
+	active      int64
+	updated     bool
+	deleted     bool
+	lockedFirst bool
+}
+
+func (q *lockQuerier) GetEventByIDForUpdate(_ context.Context, id int32) (dbsqlc.Event, error) {
+	if id != q.event.ID {
+		return dbsqlc.Event{}, pgx.ErrNoRows
+	}
+	q.lockedFirst = !q.updated && !q.deleted
+	return q.event, nil
+}
+
+func (q *lockQuerier) CountActiveApplicationsByEvent(context.Context, pgtype.Int4) (int64, error) {
+	return q.active, nil
+}
+
+func (q *lockQuerier) UpdateEvent(_ context.Context, arg dbsqlc.UpdateEventParams) (dbsqlc.Event, error) {
+	q.updated = true
+	event := q.event
+	event.MaxVolunteers = arg.MaxVolunteers
+	return event, nil
+}
+
+func (q *lockQuerier) DeleteEvent(context.Context, int32) error {
+	q.deleted = true
+	return nil
+}
+
+// inlineTx выполняет функцию сразу на переданном Querier, как WithTx без повторов.
+type inlineTx struct {
+	q dbsqlc.Querier
+}
+
+func (tx inlineTx) WithTx(_ context.Context, fn func(q dbsqlc.Querier) error, _ ...repository.TxOption) error {
+	return fn(tx.q)
+}
+
+func lockedEvent(status string, current int32) dbsqlc.Event {
+	return dbsqlc.Event{
+		ID:                1,
+		MaxVolunteers:     10,
+		CurrentVolunteers: int32ToInt4(current),
+		Status:            stringToText(status),
+		LocationLat:       float64ToNumeric(55.75),
+		LocationLon:       float64ToNumeric(37.62),
+	}
+}
+
+func TestUpdateEventChecksLockedRow(t *testing.T) {
+	cases := map[string]struct {
+		event         dbsqlc.Event
+		maxVolunteers int32
+		wantErr       error
+	}{
+		"shrink to participants": {event: lockedEvent("open", 4), maxVolunteers: 4},
+		"below participants":     {event: lockedEvent("full", 4), maxVolunteers: 3, wantErr: ErrMaxVolunteersBelowCurrent},
+		"in progress":            {event: lockedEvent("in_progress", 0), maxVolunteers: 5},
+		"completed":              {event: lockedEvent("completed", 0), maxVolunteers: 5, wantErr: ErrEventFinished},
+		"cancelled":              {event: lockedEvent("cancelled", 0), maxVolunteers: 5, wantErr: ErrEventFinished},
+	}
+	for name, tc := range cases {
+		q := &lockQuerier{event: tc.event}
+		svc := NewEventService(nil, inlineTx{q: q}, nil)
+		_, err := svc.UpdateEvent(context.Background(), 1, "Уборка", nil, nil, time.Now(), nil, "Парк", 55.75, 37.62, nil, nil, tc.maxVolunteers)
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.wantErr)
+			continue
+		}
+		if q.updated != (tc.wantErr == nil) || !q.lockedFirst {
+			t.Errorf("%s: updated=%v lockedFirst=%v", name, q.updated, q.lockedFirst)
+		}
+	}
+
+	svc := NewEventService(nil, inlineTx{q: &lockQuerier{event: lockedEvent("open", 0)}}, nil)
+	if _, err := svc.UpdateEvent(context.Background(), 2, "Уборка", nil, nil, time.Now(), nil, "Парк", 55.75, 37.62, nil, nil, 5); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("missing event: got %v, want pgx.ErrNoRows", err)
+	}
+}
+
+func TestDeleteEventKeepsApplications(t *testing.T) {
+	cases := map[string]struct {
+		event   dbsqlc.Event
+		active  int64
+		wantErr error
+	}{
+		"empty":               {event: lockedEvent("open", 0)},
+		"with participants":   {event: lockedEvent("open", 2), wantErr: ErrEventHasApplications},
+		"pending or waitlist": {event: lockedEvent("open", 0), active: 1, wantErr: ErrEventHasApplications},
+	}
+	for name, tc := range cases {
+		q := &lockQuerier{event: tc.event, active: tc.active}
+		err := NewEventService(nil, inlineTx{q: q}, nil).DeleteEvent(context.Background(), 1)
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.wantErr)
+		}
+		if q.deleted != (tc.wantErr == nil) || !q.lockedFirst {
+			t.Errorf("%s: deleted=%v lockedFirst=%v", name, q.deleted, q.lockedFirst)
+		}
+	}
+}
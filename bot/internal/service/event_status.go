@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"maxBot/internal/model"
+)
+
+var (
+	// ErrInvalidEventStatus неизвестный статус события.
+	ErrInvalidEventStatus = errors.New("invalid event status")
+	// ErrEventStatusTransition переход между статусами события запрещён.
+	ErrEventStatusTransition = errors.New("event status transition is not allowed")
+	// ErrEventStatusChanged статус события изменился, пока выполнялся переход.
+	ErrEventStatusChanged = errors.New("event status was changed concurrently")
+)
+
+// eventStatusTransitions описывает допустимые переходы: open <-> full -> in_progress -> completed,
+// отменить можно любое ещё не завершённое событие. completed и cancelled — конечные статусы.
+var eventStatusTransitions = map[string][]string{
+	model.EventStatusOpen:       {model.EventStatusFull, model.EventStatusInProgress, model.EventStatusCompleted, model.EventStatusCancelled},
+	model.EventStatusFull:       {model.EventStatusOpen, model.EventStatusInProgress, model.EventStatusCompleted, model.EventStatusCancelled},
+	model.EventStatusInProgress: {model.EventStatusCompleted, model.EventStatusCancelled},
+	model.EventStatusCompleted:  {},
+	model.EventStatusCancelled:  {},
+}
+
+// CanTransitionEventStatus сообщает, можно ли перевести событие из статуса from в статус to.
+func CanTransitionEventStatus(from, to string) bool {
+	for _, allowed := range eventStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func validateEventStatusTransition(from, to string) error {
+	if _, ok := eventStatusTransitions[to]; !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidEventStatus, to)
+	}
+	if !CanTransitionEventStatus(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrEventStatusTransition, from, to)
+	}
+	return nil
+}
+
+// eventStatus возвращает статус события, пустой статус считается open.
+func eventStatus(event model.Event) string {
+	if event.Status == nil || *event.Status == "" {
+		return model.EventStatusOpen
+	}
+	return *event.Status
+}
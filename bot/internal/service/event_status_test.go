@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+func TestCanTransitionEventStatus(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{model.EventStatusOpen, model.EventStatusFull, true},
+		{model.EventStatusOpen, model.EventStatusInProgress, true},
+		{model.EventStatusOpen, model.EventStatusCompleted, true},
+		{model.EventStatusOpen, model.EventStatusCancelled, true},
+		{model.EventStatusOpen, model.EventStatusOpen, false},
+		{model.EventStatusFull, model.EventStatusOpen, true},
+		{model.EventStatusFull, model.EventStatusCancelled, true},
+		{model.EventStatusInProgress, model.EventStatusCompleted, true},
+		{model.EventStatusInProgress, model.EventStatusCancelled, true},
+		{model.EventStatusInProgress, model.EventStatusOpen, false},
+		{model.EventStatusInProgress, model.EventStatusFull, false},
+		{model.EventStatusCompleted, model.EventStatusOpen, false},
+		{model.EventStatusCompleted, model.EventStatusCancelled, false},
+		{model.EventStatusCancelled, model.EventStatusOpen, false},
+		{model.EventStatusCancelled, model.EventStatusCompleted, false},
+		{"draft", model.EventStatusOpen, false},
+		{model.EventStatusOpen, "draft", false},
+	}
+	for _, tc := range cases {
+		if got := CanTransitionEventStatus(tc.from, tc.to); got != tc.want {
+			t.Errorf("CanTransitionEventStatus(%q, %q) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestValidateEventStatusTransition(t *testing.T) {
+	if err := validateEventStatusTransition(model.EventStatusOpen, "draft"); !errors.Is(err, ErrInvalidEventStatus) {
+		t.Errorf("unknown status: got %v, want ErrInvalidEventStatus", err)
+	}
+	if err := validateEventStatusTransition(model.EventStatusCompleted, model.EventStatusOpen); !errors.Is(err, ErrEventStatusTransition) {
+		t.Errorf("final status: got %v, want ErrEventStatusTransition", err)
+	}
+	if err := validateEventStatusTransition(model.EventStatusFull, model.EventStatusOpen); err != nil {
+		t.Errorf("full -> open: %v", err)
+	}
+}
+
+// advanceQuerier отвечает только на запросы AdvanceEventStatuses; остальные методы вызывать нельзя.
+type advanceQuerier struct {
+	dbsqlc.Querier
+	defaultMinutes int32
+}
+
+func (q *advanceQuerier) SyncEventCapacityStatuses(context.Context) ([]dbsqlc.Event, error) {
+	return nil, nil
+}
+
+func (q *advanceQuerier) StartDueEvents(context.Context) ([]dbsqlc.Event, error) {
+	return nil, nil
+}
+
+func (q *advanceQuerier) CompletePastEvents(_ context.Context, defaultDurationMinutes int32) ([]dbsqlc.Event, error) {
+	q.defaultMinutes = defaultDurationMinutes
+	return nil, nil
+}
+
+func TestAdvanceEventStatusesKeepsSubHourDefaultDuration(t *testing.T) {
+	cases := map[time.Duration]int32{
+		30 * time.Minute:             30,
+		90 * time.Minute:             90,
+		3 * time.Hour:                180,
+		2*time.Hour + 15*time.Second: 120,
+	}
+	for duration, want := range cases {
+		q := &advanceQuerier{}
+		if _, err := NewEventService(q, nil, nil).AdvanceEventStatuses(context.Background(), duration); err != nil {
+			t.Fatalf("advance %v: %v", duration, err)
+		}
+		if q.defaultMinutes != want {
+			t.Errorf("default duration %v passed as %d minutes, want %d", duration, q.defaultMinutes, want)
+		}
+	}
+}
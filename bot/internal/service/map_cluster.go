@@ -0,0 +1,112 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+const (
+	// MapMaxZoom — максимальный масштаб карты, как у тайлов OSM.
+	MapMaxZoom = 20
+	// MapPointsMinZoom — начиная с этого масштаба вместо кластеров отдаются отдельные точки.
+	MapPointsMinZoom = 15
+	// mapClusterCellsPerTile задаёт размер ячейки: 4 ячейки на тайл 256px, то есть 64px на экране.
+	mapClusterCellsPerTile = 4
+	// mapMaxPoints ограничивает число отдельных точек в ответе; на крупных масштабах в экран столько не помещается.
+	mapMaxPoints = 10_000
+)
+
+// ListMapClustersParams описывает видимую область и масштаб карты.
+type ListMapClustersParams struct {
+	BBox        model.MapBBox
+	Zoom        int
+	CategoryIDs []int32
+}
+
+// clusterGridSize — число ячеек сетки по каждой оси на масштабе zoom.
+func clusterGridSize(zoom int) float64 {
+	return float64(uint32(1)<<uint(zoom)) * mapClusterCellsPerTile
+}
+
+// splitAntimeridian делит область, пересекающую 180-й меридиан (MinLon > MaxLon), на две части
+// по обе стороны от него: box() в Postgres молча поменял бы углы местами и взял бы не ту половину мира.
+func splitAntimeridian(bbox model.MapBBox) []model.MapBBox {
+	if bbox.MinLon <= bbox.MaxLon {
+		return []model.MapBBox{bbox}
+	}
+	east, west := bbox, bbox
+	east.MaxLon = 180
+	west.MinLon = -180
+	return []model.MapBBox{east, west}
+}
+
+type clusterCell struct {
+	x, y       int32
+	count      int
+	sumLat     float64
+	sumLon     float64
+	categories []model.MapClusterCategory
+}
+
+// buildMapClusters собирает кластеры из строк «ячейка × категория», посчитанных в базе.
+// Ячейки сетки глобальные, поэтому строки двух половин области за 180-м меридианом не пересекаются.
+func buildMapClusters(rows []dbsqlc.ListEventClustersInBBoxRow, zoom int) []model.MapCluster {
+	index := make(map[uint64]int)
+	var cells []clusterCell
+	for _, row := range rows {
+		key := uint64(uint32(row.CellX))<<32 | uint64(uint32(row.CellY))
+		i, ok := index[key]
+		if !ok {
+			i = len(cells)
+			index[key] = i
+			cells = append(cells, clusterCell{x: row.CellX, y: row.CellY})
+		}
+		cell := &cells[i]
+		cell.count += int(row.Count)
+		cell.sumLat += row.SumLat
+		cell.sumLon += row.SumLon
+		cell.categories = append(cell.categories, model.MapClusterCategory{
+			CategoryID:   int4ToPtr(row.CategoryID),
+			CategoryName: textToPtr(row.CategoryName),
+			Count:        int(row.Count),
+		})
+	}
+
+	zoomPrefix := strconv.Itoa(zoom) + "/"
+	clusters := make([]model.MapCluster, 0, len(cells))
+	for i := range cells {
+		cell := &cells[i]
+		sort.Slice(cell.categories, func(i, j int) bool {
+			if cell.categories[i].Count != cell.categories[j].Count {
+				return cell.categories[i].Count > cell.categories[j].Count
+			}
+			return categoryKey(cell.categories[i].CategoryID) < categoryKey(cell.categories[j].CategoryID)
+		})
+		clusters = append(clusters, model.MapCluster{
+			ID:          zoomPrefix + strconv.Itoa(int(cell.x)) + "/" + strconv.Itoa(int(cell.y)),
+			Count:       cell.count,
+			LocationLat: cell.sumLat / float64(cell.count),
+			LocationLon: cell.sumLon / float64(cell.count),
+			Categories:  cell.categories,
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].ID < clusters[j].ID
+	})
+	return clusters
+}
+
+// categoryKey упорядочивает мероприятия без категории после остальных.
+func categoryKey(id *int32) int64 {
+	if id == nil {
+		return math.MaxInt64
+	}
+	return int64(*id)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+// pointsQuerier хранит точки в памяти и отвечает на ListEventPointsInBBox и ListEventClustersInBBox
+// так же, как запросы в базе. Box с minLon > maxLon считается ошибкой: в базе он вернул бы не ту область.
+type pointsQuerier struct {
+	dbsqlc.Querier
+	rows []dbsqlc.ListEventPointsInBBoxRow
+}
+
+func (q *pointsQuerier) inBox(row dbsqlc.ListEventPointsInBBoxRow, minLon, minLat, maxLon, maxLat float64) (bool, error) {
+	if minLon > maxLon {
+		return false, fmt.Errorf("box crosses antimeridian: %v > %v", minLon, maxLon)
+	}
+	return row.LocationLat >= minLat && row.LocationLat <= maxLat && row.LocationLon >= minLon && row.LocationLon <= maxLon, nil
+}
+
+func (q *pointsQuerier) ListEventPointsInBBox(_ context.Context, arg dbsqlc.ListEventPointsInBBoxParams) ([]dbsqlc.ListEventPointsInBBoxRow, error) {
+	var result []dbsqlc.ListEventPointsInBBoxRow
+	for _, row := range q.rows {
+		ok, err := q.inBox(row, arg.MinLon, arg.MinLat, arg.MaxLon, arg.MaxLat)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		result = append(result, row)
+		if len(result) == int(arg.Limit) {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (q *pointsQuerier) ListEventClustersInBBox(_ context.Context, arg dbsqlc.ListEventClustersInBBoxParams) ([]dbsqlc.ListEventClustersInBBoxRow, error) {
+	type key struct {
+		x, y     int32
+		category pgtype.Int4
+	}
+	index := make(map[key]int)
+	var result []dbsqlc.ListEventClustersInBBoxRow
+	for _, row := range q.rows {
+		ok, err := q.inBox(row, arg.MinLon, arg.MinLat, arg.MaxLon, arg.MaxLat)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		x, y := mercatorCell(row.LocationLat, row.LocationLon, arg.GridSize)
+		k := key{x: x, y: y, category: row.CategoryID}
+		i, found := index[k]
+		if !found {
+			i = len(result)
+			index[k] = i
+			result = append(result, dbsqlc.ListEventClustersInBBoxRow{CellX: x, CellY: y, CategoryID: row.CategoryID, CategoryName: row.CategoryName})
+		}
+		result[i].Count++
+		result[i].SumLat += row.LocationLat
+		result[i].SumLon += row.LocationLon
+	}
+	return result, nil
+}
+
+// mercatorCell повторяет расчёт ячейки из запроса ListEventClustersInBBox.
+func mercatorCell(lat, lon, size float64) (int32, int32) {
+	lat = math.Max(-85.05112878, math.Min(85.05112878, lat))
+	x := math.Floor((lon + 180) / 360 * size)
+	y := math.Floor((1 - math.Asinh(math.Tan(lat*math.Pi/180))/math.Pi) / 2 * size)
+	clamp := func(v float64) int32 { return int32(math.Max(0, math.Min(v, size-1))) }
+	return clamp(x), clamp(y)
+}
+
+// seedMapEvents генерирует мероприятия вокруг крупных городов и равномерный фон по стране.
+func seedMapEvents(n int) []dbsqlc.ListEventPointsInBBoxRow {
+	rng := rand.New(rand.NewPCG(1, 2))
+	cities := [][2]float64{
+		{55.7558, 37.6173}, {59.9343, 30.3351}, {55.0084, 82.9357}, {56.8389, 60.6057},
+		{55.7963, 49.1088}, {43.1155, 131.8855}, {54.7388, 55.9721}, {47.2357, 39.7015},
+	}
+	date := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	rows := make([]dbsqlc.ListEventPointsInBBoxRow, 0, n)
+	for i := 0; i < n; i++ {
+		var lat, lon float64
+		if i%5 == 0 {
+			lat = 42 + rng.Float64()*27
+			lon = 28 + rng.Float64()*150
+		} else {
+			city := cities[rng.IntN(len(cities))]
+			lat = city[0] + rng.NormFloat64()*0.15
+			lon = city[1] + rng.NormFloat64()*0.25
+		}
+		category := pgtype.Int4{Int32: int32(rng.IntN(8) + 1), Valid: true}
+		if i%17 == 0 {
+			category = pgtype.Int4{}
+		}
+		rows = append(rows, dbsqlc.ListEventPointsInBBoxRow{
+			ID:          int32(i + 1),
+			Title:       fmt.Sprintf("Мероприятие %d", i+1),
+			Date:        pgtype.Timestamp{Time: date.Add(time.Duration(i) * time.Hour), Valid: true},
+			LocationLat: lat,
+			LocationLon: lon,
+			CategoryID:  category,
+		})
+	}
+	return rows
+}
+
+func TestListMapClustersKeepsAllEvents(t *testing.T) {
+	rows := seedMapEvents(5_000)
+	svc := NewEventService(&pointsQuerier{rows: rows}, nil, nil)
+	world := model.MapBBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+
+	for _, zoom := range []int{0, 4, 8, 12} {
+		result, err := svc.ListMapClusters(context.Background(), ListMapClustersParams{BBox: world, Zoom: zoom})
+		if err != nil {
+			t.Fatalf("zoom %d: %v", zoom, err)
+		}
+		if len(result.Points) != 0 {
+			t.Fatalf("zoom %d: points must not be returned below zoom %d", zoom, MapPointsMinZoom)
+		}
+		total := 0
+		for _, cluster := range result.Clusters {
+			byCategory := 0
+			for _, category := range cluster.Categories {
+				byCategory += category.Count
+			}
+			if byCategory != cluster.Count {
+				t.Fatalf("zoom %d cluster %s: categories sum %d != count %d", zoom, cluster.ID, byCategory, cluster.Count)
+			}
+			total += cluster.Count
+		}
+		if total != len(rows) || result.Total != len(rows) || result.Truncated {
+			t.Fatalf("zoom %d: clustered %d of %d events (total %d, truncated %v)", zoom, total, len(rows), result.Total, result.Truncated)
+		}
+	}
+
+	moscow := model.MapBBox{MinLat: 55.7, MinLon: 37.55, MaxLat: 55.8, MaxLon: 37.7}
+	result, err := svc.ListMapClusters(context.Background(), ListMapClustersParams{BBox: moscow, Zoom: MapPointsMinZoom})
+	if err != nil {
+		t.Fatalf("points: %v", err)
+	}
+	if len(result.Clusters) != 0 || len(result.Points) == 0 || len(result.Points) != result.Total {
+		t.Fatalf("expected only points at zoom %d, got %d clusters and %d points", MapPointsMinZoom, len(result.Clusters), len(result.Points))
+	}
+}
+
+func TestListMapClustersAcrossAntimeridian(t *testing.T) {
+	rows := []dbsqlc.ListEventPointsInBBoxRow{
+		{ID: 1, LocationLat: 65.0, LocationLon: 179.5},
+		{ID: 2, LocationLat: 65.1, LocationLon: -179.5},
+		{ID: 3, LocationLat: 55.75, LocationLon: 37.62},
+	}
+	svc := NewEventService(&pointsQuerier{rows: rows}, nil, nil)
+	chukotka := model.MapBBox{MinLat: 60, MinLon: 170, MaxLat: 70, MaxLon: -170}
+
+	for _, zoom := range []int{3, MapPointsMinZoom} {
+		result, err := svc.ListMapClusters(context.Background(), ListMapClustersParams{BBox: chukotka, Zoom: zoom})
+		if err != nil {
+			t.Fatalf("zoom %d: %v", zoom, err)
+		}
+		if result.Total != 2 {
+			t.Fatalf("zoom %d: expected 2 events on both sides of the antimeridian, got %d", zoom, result.Total)
+		}
+		if zoom >= MapPointsMinZoom && (len(result.Points) != 2 || result.Points[0].ID != 1 || result.Points[1].ID != 2) {
+			t.Fatalf("zoom %d: unexpected points %+v", zoom, result.Points)
+		}
+		// Ячейки по разные стороны меридиана разные: кластер не должен получить центроид около 0° долготы.
+		for _, cluster := range result.Clusters {
+			if math.Abs(cluster.LocationLon) < 170 {
+				t.Fatalf("zoom %d: cluster %s centroid at lon %v", zoom, cluster.ID, cluster.LocationLon)
+			}
+		}
+	}
+}
+
+func TestBuildMapClustersComputesCentroid(t *testing.T) {
+	category := pgtype.Int4{Int32: 3, Valid: true}
+	rows := []dbsqlc.ListEventClustersInBBoxRow{
+		{CellX: 77, CellY: 40, Count: 1, SumLat: 55.77, SumLon: 37.62},
+		{CellX: 77, CellY: 40, CategoryID: category, Count: 2, SumLat: 55.75 + 55.76, SumLon: 37.61 + 37.63},
+		{CellX: 60, CellY: 38, CategoryID: category, Count: 1, SumLat: 59.93, SumLon: 30.33},
+	}
+	clusters := buildMapClusters(rows, 5)
+	if len(clusters) != 2 {
+		t.Fatalf("expected two clusters, got %d", len(clusters))
+	}
+	cluster := clusters[0]
+	if cluster.ID != "5/77/40" || cluster.Count != 3 || math.Abs(cluster.LocationLat-55.76) > 1e-9 || math.Abs(cluster.LocationLon-37.62) > 1e-9 {
+		t.Fatalf("unexpected cluster: %+v", cluster)
+	}
+	if len(cluster.Categories) != 2 || *cluster.Categories[0].CategoryID != 3 || cluster.Categories[0].Count != 2 || cluster.Categories[1].CategoryID != nil {
+		t.Fatalf("unexpected categories: %+v", cluster.Categories)
+	}
+	if clusters[1].ID != "5/60/38" || clusters[1].Count != 1 {
+		t.Fatalf("unexpected second cluster: %+v", clusters[1])
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -123,6 +124,21 @@ func mapOrganizerVerificationRequests(items []dbsqlc.OrganizerVerificationReques
 	return result
 }
 
+func mapPendingVerificationRequests(items []dbsqlc.ListPendingOrganizerVerificationRequestsRow) []model.PendingVerificationRequest {
+	result := make([]model.PendingVerificationRequest, 0, len(items))
+	for _, item := range items {
+		result = append(result, model.PendingVerificationRequest{
+			ID:               item.ID,
+			OrganizerID:      item.OrganizerID,
+			OrganizationName: item.OrganizationName,
+			Name:             item.Name,
+			Username:         textToPtr(item.Username),
+			SubmittedAt:      timestampToTime(item.SubmittedAt),
+		})
+	}
+	return result
+}
+
 func mapCategory(c dbsqlc.Category) model.Category {
 	return model.Category{
 		ID:          c.ID,
@@ -185,13 +201,42 @@ func mapEvents(items []dbsqlc.Event) ([]model.Event, error) {
 	return result, nil
 }
 
+func mapEventDraft(d dbsqlc.EventDraft) (model.EventDraft, error) {
+	locationLat, err := numericToFloat64Ptr(d.LocationLat)
+	if err != nil {
+		return model.EventDraft{}, fmt.Errorf("map event draft location_lat: %w", err)
+	}
+	locationLon, err := numericToFloat64Ptr(d.LocationLon)
+	if err != nil {
+		return model.EventDraft{}, fmt.Errorf("map event draft location_lon: %w", err)
+	}
+	return model.EventDraft{
+		OrganizerID:   d.OrganizerID,
+		Title:         textToPtr(d.Title),
+		Description:   textToPtr(d.Description),
+		Date:          timestampToPtr(d.Date),
+		DurationHours: int4ToPtr(d.DurationHours),
+		Location:      textToPtr(d.Location),
+		LocationLat:   locationLat,
+		LocationLon:   locationLon,
+		CategoryID:    int4ToPtr(d.CategoryID),
+		MaxVolunteers: int4ToPtr(d.MaxVolunteers),
+		Contacts:      textToPtr(d.Contacts),
+		UpdatedAt:     timestampToTime(d.UpdatedAt),
+	}, nil
+}
+
 func mapEventParticipant(ep dbsqlc.EventParticipant) model.EventParticipant {
 	return model.EventParticipant{
-		ID:            ep.ID,
-		EventID:       int4ToPtr(ep.EventID),
-		VolunteerID:   int8ToPtr(ep.VolunteerID),
-		ApplicationID: int4ToPtr(ep.ApplicationID),
-		JoinedChatAt:  timestampToTime(ep.JoinedChatAt),
+		ID:                 ep.ID,
+		EventID:            int4ToPtr(ep.EventID),
+		VolunteerID:        int8ToPtr(ep.VolunteerID),
+		ApplicationID:      int4ToPtr(ep.ApplicationID),
+		JoinedChatAt:       timestampToTime(ep.JoinedChatAt),
+		CheckedInAt:        timestampToPtr(ep.CheckedInAt),
+		CheckedOutAt:       timestampToPtr(ep.CheckedOutAt),
+		CheckInMethod:      textToPtr(ep.CheckInMethod),
+		AttendanceMarkedBy: int8ToPtr(ep.AttendanceMarkedBy),
 	}
 }
 
@@ -203,6 +248,84 @@ func mapEventParticipants(items []dbsqlc.EventParticipant) []model.EventParticip
 	return result
 }
 
+func mapAuthSession(s dbsqlc.AuthSession) model.AuthSession {
+	return model.AuthSession{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		UserData:  s.UserData,
+		ExpiresAt: timestampToTime(s.ExpiresAt),
+		RevokedAt: timestampToPtr(s.RevokedAt),
+		CreatedAt: timestampToTime(s.CreatedAt),
+	}
+}
+
+func mapEventCheckInCode(c dbsqlc.EventCheckInCode) model.EventCheckInCode {
+	return model.EventCheckInCode{
+		EventID:   c.EventID,
+		Code:      c.Code,
+		ExpiresAt: timestampToTime(c.ExpiresAt),
+		CreatedBy: int8ToPtr(c.CreatedBy),
+		CreatedAt: timestampToTime(c.CreatedAt),
+	}
+}
+
+func mapEventQuestion(q dbsqlc.EventQuestion) model.EventQuestion {
+	return model.EventQuestion{
+		ID:        q.ID,
+		EventID:   q.EventID,
+		Position:  q.Position,
+		Type:      q.Type,
+		Text:      q.Text,
+		Options:   q.Options,
+		Required:  q.Required,
+		CreatedAt: timestampToTime(q.CreatedAt),
+	}
+}
+
+func mapEventQuestions(items []dbsqlc.EventQuestion) []model.EventQuestion {
+	result := make([]model.EventQuestion, 0, len(items))
+	for _, item := range items {
+		result = append(result, mapEventQuestion(item))
+	}
+	return result
+}
+
+func mapApplicationAnswers(rows []dbsqlc.ListApplicationAnswersRow) []model.ApplicationAnswer {
+	result := make([]model.ApplicationAnswer, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.ApplicationAnswer{
+			ApplicationID: row.ApplicationID,
+			QuestionID:    row.QuestionID,
+			QuestionType:  row.QuestionType,
+			QuestionText:  row.QuestionText,
+			Values:        row.Answer,
+		})
+	}
+	return result
+}
+
+func mapParticipantAttendances(rows []dbsqlc.ListEventParticipantsWithUsersRow) []model.ParticipantAttendance {
+	result := make([]model.ParticipantAttendance, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.ParticipantAttendance{
+			Participant: mapEventParticipant(dbsqlc.EventParticipant{
+				ID:                 row.ID,
+				EventID:            row.EventID,
+				VolunteerID:        row.VolunteerID,
+				ApplicationID:      row.ApplicationID,
+				JoinedChatAt:       row.JoinedChatAt,
+				CheckedInAt:        row.CheckedInAt,
+				CheckedOutAt:       row.CheckedOutAt,
+				CheckInMethod:      row.CheckInMethod,
+				AttendanceMarkedBy: row.AttendanceMarkedBy,
+			}),
+			Username: textToPtr(row.Username),
+			Name:     row.Name,
+		})
+	}
+	return result
+}
+
 // EventParticipantWithUser больше не используется, так как доменная модель только EventParticipant.
 
 func mapEventMedium(m dbsqlc.EventMedium) model.EventMedia {
@@ -270,6 +393,65 @@ func mapMapEvents(items []dbsqlc.ListEventsForMapRow) ([]model.MapEvent, error)
 	return result, nil
 }
 
+func mapMapEventsInBBox(items []dbsqlc.ListEventsForMapInBBoxRow) ([]model.MapEvent, error) {
+	result := make([]model.MapEvent, 0, len(items))
+	for _, item := range items {
+		mapped, err := mapMapEvent(dbsqlc.ListEventsForMapRow(item))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, mapped)
+	}
+	return result, nil
+}
+
+// mapEventSnapshot строит MapEvent для потока изменений. Расстояние считает получатель,
+// название категории и статус заявки в снимок не попадают.
+func mapEventSnapshot(e model.Event) model.MapEvent {
+	current := int32(0)
+	if e.CurrentVolunteers != nil {
+		current = *e.CurrentVolunteers
+	}
+	return model.MapEvent{
+		ID:                e.ID,
+		Title:             e.Title,
+		Description:       e.Description,
+		Chat:              e.Chat,
+		Date:              e.Date,
+		DurationHours:     e.DurationHours,
+		Location:          e.Location,
+		LocationLat:       e.LocationLat,
+		LocationLon:       e.LocationLon,
+		CategoryID:        e.CategoryID,
+		OrganizerID:       e.OrganizerID,
+		Contacts:          e.Contacts,
+		MaxVolunteers:     e.MaxVolunteers,
+		CurrentVolunteers: current,
+		SlotsLeft:         max(e.MaxVolunteers-current, 0),
+		Status:            e.Status,
+		CancelledReason:   e.CancelledReason,
+		CompletedAt:       e.CompletedAt,
+		CreatedAt:         e.CreatedAt,
+		UpdatedAt:         e.UpdatedAt,
+	}
+}
+
+func mapMapPoints(items []dbsqlc.ListEventPointsInBBoxRow) []model.MapPoint {
+	result := make([]model.MapPoint, 0, len(items))
+	for _, item := range items {
+		result = append(result, model.MapPoint{
+			ID:           item.ID,
+			Title:        item.Title,
+			Date:         timestampToTime(item.Date),
+			LocationLat:  item.LocationLat,
+			LocationLon:  item.LocationLon,
+			CategoryID:   int4ToPtr(item.CategoryID),
+			CategoryName: textToPtr(item.CategoryName),
+		})
+	}
+	return result
+}
+
 func mapMapEventWithStatus(e dbsqlc.ListEventsForMapByVolunteerRow) (model.MapEvent, error) {
 	base := dbsqlc.ListEventsForMapRow{
 		ID:                e.ID,
@@ -315,6 +497,18 @@ func mapMapEventsWithStatus(items []dbsqlc.ListEventsForMapByVolunteerRow) ([]mo
 	return result, nil
 }
 
+func mapMapEventsWithStatusInBBox(items []dbsqlc.ListEventsForMapByVolunteerInBBoxRow) ([]model.MapEvent, error) {
+	result := make([]model.MapEvent, 0, len(items))
+	for _, item := range items {
+		mapped, err := mapMapEventWithStatus(dbsqlc.ListEventsForMapByVolunteerRow(item))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, mapped)
+	}
+	return result, nil
+}
+
 func mapVolunteerApplication(a dbsqlc.VolunteerApplication) model.VolunteerApplication {
 	status := textToPtr(a.Status)
 	rejectionReason := textToPtr(a.RejectionReason)
@@ -327,7 +521,31 @@ func mapVolunteerApplication(a dbsqlc.VolunteerApplication) model.VolunteerAppli
 		ReviewedBy:      int8ToPtr(a.ReviewedBy),
 		AppliedAt:       timestampToTime(a.AppliedAt),
 		ReviewedAt:      timestampToPtr(a.ReviewedAt),
+		Message:         textToPtr(a.Message),
+	}
+}
+
+func mapVolunteerApplicationsWithEvents(rows []dbsqlc.ListVolunteerApplicationsWithEventsRow) []model.VolunteerApplicationWithEvent {
+	result := make([]model.VolunteerApplicationWithEvent, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.VolunteerApplicationWithEvent{
+			Application: mapVolunteerApplication(dbsqlc.VolunteerApplication{
+				ID:              row.ID,
+				EventID:         row.EventID,
+				VolunteerID:     row.VolunteerID,
+				Status:          row.Status,
+				RejectionReason: row.RejectionReason,
+				ReviewedBy:      row.ReviewedBy,
+				AppliedAt:       row.AppliedAt,
+				ReviewedAt:      row.ReviewedAt,
+				Message:         row.Message,
+			}),
+			EventTitle:  row.EventTitle,
+			EventDate:   timestampToTime(row.EventDate),
+			EventStatus: textToPtr(row.EventStatus),
+		})
 	}
+	return result
 }
 
 func mapVolunteerApplications(items []dbsqlc.VolunteerApplication) []model.VolunteerApplication {
@@ -338,6 +556,62 @@ func mapVolunteerApplications(items []dbsqlc.VolunteerApplication) []model.Volun
 	return result
 }
 
+func mapPendingApplicants(items []dbsqlc.ListPendingApplicantsByEventRow) []model.PendingApplicant {
+	result := make([]model.PendingApplicant, 0, len(items))
+	for _, item := range items {
+		result = append(result, model.PendingApplicant{
+			ApplicationID: item.ID,
+			EventID:       int4ToPtr(item.EventID),
+			VolunteerID:   int8ToPtr(item.VolunteerID),
+			Name:          item.Name,
+			Username:      textToPtr(item.Username),
+			About:         textToPtr(item.About),
+			Message:       textToPtr(item.Message),
+			AppliedAt:     timestampToTime(item.AppliedAt),
+		})
+	}
+	return result
+}
+
+func mapEventPendingSummaries(items []dbsqlc.ListOrganizerEventsWithPendingApplicationsRow) []model.EventPendingSummary {
+	result := make([]model.EventPendingSummary, 0, len(items))
+	for _, item := range items {
+		result = append(result, model.EventPendingSummary{
+			ID:                item.ID,
+			Title:             item.Title,
+			Date:              timestampToTime(item.Date),
+			MaxVolunteers:     item.MaxVolunteers,
+			CurrentVolunteers: int4ToPtr(item.CurrentVolunteers),
+			PendingCount:      item.PendingCount,
+		})
+	}
+	return result
+}
+
+func mapNotification(n dbsqlc.Notification) model.Notification {
+	return model.Notification{
+		ID:            n.ID,
+		UserID:        n.UserID,
+		Kind:          n.Kind,
+		EventID:       int4ToPtr(n.EventID),
+		ApplicationID: int4ToPtr(n.ApplicationID),
+		Status:        n.Status,
+		Attempts:      n.Attempts,
+		LastError:     textToPtr(n.LastError),
+		ScheduledAt:   timestampToTime(n.ScheduledAt),
+		SentAt:        timestampToPtr(n.SentAt),
+		CreatedAt:     timestampToTime(n.CreatedAt),
+	}
+}
+
+func mapNotifications(items []dbsqlc.Notification) []model.Notification {
+	result := make([]model.Notification, 0, len(items))
+	for _, item := range items {
+		result = append(result, mapNotification(item))
+	}
+	return result
+}
+
 func textToPtr(t pgtype.Text) *string {
 	if !t.Valid {
 		return nil
@@ -465,8 +739,9 @@ func float64PtrToNumeric(f *float64) pgtype.Numeric {
 }
 
 func float64ToNumeric(f float64) pgtype.Numeric {
+	// pgtype.Numeric.Scan понимает только строки, поэтому передаём число в текстовом виде
 	var n pgtype.Numeric
-	_ = n.Scan(f)
+	_ = n.Scan(strconv.FormatFloat(f, 'f', -1, 64))
 	return n
 }
 
@@ -496,9 +771,11 @@ func int64PtrToInt8(i *int64) pgtype.Int8 {
 	return pgtype.Int8{Int64: *i, Valid: true}
 }
 
+// timePtrToTimestamp переводит время в UTC: pgx отбрасывает часовой пояс при записи в timestamp,
+// а все колонки времени хранят UTC.
 func timePtrToTimestamp(t *time.Time) pgtype.Timestamp {
 	if t == nil {
 		return pgtype.Timestamp{Valid: false}
 	}
-	return pgtype.Timestamp{Time: *t, Valid: true}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
 }
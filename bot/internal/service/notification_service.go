@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+// NotificationService manages persisted notification jobs of the scheduler.
+type NotificationService interface {
+	EnqueueEventReminders(ctx context.Context, kind string, minLead, lead time.Duration) (int64, error)
+	EnqueueApplicationStatusNotifications(ctx context.Context, lookback time.Duration) (int64, error)
+	EnqueueNewApplicationNotifications(ctx context.Context, lookback time.Duration) (int64, error)
+	ClaimDueNotifications(ctx context.Context, limit int32, lockTimeout time.Duration) ([]model.Notification, error)
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkNotificationFailed(ctx context.Context, id int64, reason string, maxAttempts int32, retryAfter time.Duration) error
+	MarkNotificationCancelled(ctx context.Context, id int64, reason string) error
+}
+
+type notificationService struct {
+	q dbsqlc.Querier
+}
+
+func NewNotificationService(q dbsqlc.Querier) NotificationService {
+	return &notificationService{q: q}
+}
+
+// EnqueueEventReminders ставит напоминания участникам событий, которые начнутся через (minLead, lead].
+// Повторный вызов не создаёт дубликатов.
+func (s *notificationService) EnqueueEventReminders(ctx context.Context, kind string, minLead, lead time.Duration) (int64, error) {
+	return s.q.EnqueueEventReminders(ctx, dbsqlc.EnqueueEventRemindersParams{
+		Kind:           kind,
+		MinLeadMinutes: int32(minLead / time.Minute),
+		LeadMinutes:    int32(lead / time.Minute),
+	})
+}
+
+// EnqueueApplicationStatusNotifications ставит уведомления о заявках, рассмотренных за последние lookback.
+func (s *notificationService) EnqueueApplicationStatusNotifications(ctx context.Context, lookback time.Duration) (int64, error) {
+	return s.q.EnqueueApplicationStatusNotifications(ctx, int32(lookback/time.Minute))
+}
+
+// EnqueueNewApplicationNotifications ставит организаторам уведомления о заявках, поданных за последние lookback.
+func (s *notificationService) EnqueueNewApplicationNotifications(ctx context.Context, lookback time.Duration) (int64, error) {
+	return s.q.EnqueueNewApplicationNotifications(ctx, int32(lookback/time.Minute))
+}
+
+// ClaimDueNotifications переводит до limit готовых уведомлений в статус sending и возвращает их.
+func (s *notificationService) ClaimDueNotifications(ctx context.Context, limit int32, lockTimeout time.Duration) ([]model.Notification, error) {
+	items, err := s.q.ClaimDueNotifications(ctx, dbsqlc.ClaimDueNotificationsParams{
+		LockTimeoutSeconds: int32(lockTimeout / time.Second),
+		Limit:              limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapNotifications(items), nil
+}
+
+func (s *notificationService) MarkNotificationSent(ctx context.Context, id int64) error {
+	return s.q.MarkNotificationSent(ctx, id)
+}
+
+// MarkNotificationFailed откладывает повторную отправку на retryAfter, после maxAttempts попыток уведомление помечается failed.
+func (s *notificationService) MarkNotificationFailed(ctx context.Context, id int64, reason string, maxAttempts int32, retryAfter time.Duration) error {
+	return s.q.MarkNotificationFailed(ctx, dbsqlc.MarkNotificationFailedParams{
+		MaxAttempts:       maxAttempts,
+		RetryAfterSeconds: int32(retryAfter / time.Second),
+		LastError:         stringToText(reason),
+		ID:                id,
+	})
+}
+
+// MarkNotificationCancelled закрывает уведомление, которое больше не актуально.
+func (s *notificationService) MarkNotificationCancelled(ctx context.Context, id int64, reason string) error {
+	return s.q.MarkNotificationCancelled(ctx, dbsqlc.MarkNotificationCancelledParams{
+		LastError: stringToText(reason),
+		ID:        id,
+	})
+}
+
+var _ NotificationService = (*notificationService)(nil)
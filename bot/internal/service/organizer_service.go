@@ -25,6 +25,9 @@ type OrganizerService interface {
 	ListUnverifiedOrganizers(ctx context.Context, limit, offset int32) ([]model.Organizer, error)
 	ListOrganizerVerificationHistory(ctx context.Context, organizerID int64, limit, offset int32) ([]model.OrganizerVerificationRequest, error)
 	CreateOrganizerVerificationRequest(ctx context.Context, organizerID int64, status string, comment *string) (model.OrganizerVerificationRequest, error)
+	GetOrganizerVerificationRequest(ctx context.Context, id int32) (model.OrganizerVerificationRequest, error)
+	ListPendingVerificationRequests(ctx context.Context, limit, offset int32) ([]model.PendingVerificationRequest, error)
+	CountPendingVerificationRequests(ctx context.Context) (int64, error)
 }
 
 type organizerService struct {
@@ -195,4 +198,28 @@ func (s *organizerService) CreateOrganizerVerificationRequest(ctx context.Contex
 	return mapOrganizerVerificationRequest(item), nil
 }
 
+func (s *organizerService) GetOrganizerVerificationRequest(ctx context.Context, id int32) (model.OrganizerVerificationRequest, error) {
+	item, err := s.q.GetOrganizerVerificationRequestByID(ctx, id)
+	if err != nil {
+		return model.OrganizerVerificationRequest{}, err
+	}
+	return mapOrganizerVerificationRequest(item), nil
+}
+
+func (s *organizerService) ListPendingVerificationRequests(ctx context.Context, limit, offset int32) ([]model.PendingVerificationRequest, error) {
+	params := dbsqlc.ListPendingOrganizerVerificationRequestsParams{
+		Limit:  limit,
+		Offset: offset,
+	}
+	items, err := s.q.ListPendingOrganizerVerificationRequests(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return mapPendingVerificationRequests(items), nil
+}
+
+func (s *organizerService) CountPendingVerificationRequests(ctx context.Context) (int64, error) {
+	return s.q.CountPendingOrganizerVerificationRequests(ctx)
+}
+
 var _ OrganizerService = (*organizerService)(nil)
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+const (
+	sessionIDBytes    = 16
+	refreshTokenBytes = 32
+)
+
+var (
+	// ErrInvalidRefreshToken refresh-токен не найден, истёк или его сессия завершена.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused refresh-токен уже обменивали; сессия отозвана, так как токен мог утечь.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// errInitDataReplayed откатывает транзакцию ClaimInitData, наружу не выходит.
+	errInitDataReplayed = errors.New("init data replayed")
+)
+
+// SessionService хранит сессии веб-приложения и одноразовые refresh-токены к ним.
+// Id сессии попадает в jti access-токена, поэтому отзыв сессии сразу отключает и выданные токены.
+type SessionService interface {
+	CreateSession(ctx context.Context, userID int64, userData []byte, ttl time.Duration) (model.AuthSession, string, error)
+	RefreshSession(ctx context.Context, refreshToken string, ttl time.Duration) (model.AuthSession, string, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeSessionByRefreshToken(ctx context.Context, refreshToken string) error
+	RevokeUserSessions(ctx context.Context, userID int64) error
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+	ClaimInitData(ctx context.Context, keys []string, expiresAt time.Time) (bool, error)
+}
+
+type sessionService struct {
+	q  dbsqlc.Querier
+	tx TxRunner
+}
+
+func NewSessionService(q dbsqlc.Querier, tx TxRunner) SessionService {
+	return &sessionService{q: q, tx: tx}
+}
+
+// CreateSession открывает сессию и возвращает её вместе с первым refresh-токеном.
+func (s *sessionService) CreateSession(ctx context.Context, userID int64, userData []byte, ttl time.Duration) (model.AuthSession, string, error) {
+	sessionID, err := randomToken(sessionIDBytes)
+	if err != nil {
+		return model.AuthSession{}, "", fmt.Errorf("generate session id: %w", err)
+	}
+	expiresAt := time.Now().UTC().Add(ttl)
+
+	var (
+		session      model.AuthSession
+		refreshToken string
+	)
+	err = s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		row, err := q.CreateAuthSession(ctx, dbsqlc.CreateAuthSessionParams{
+			ID:        sessionID,
+			UserID:    userID,
+			UserData:  userData,
+			ExpiresAt: timePtrToTimestamp(&expiresAt),
+		})
+		if err != nil {
+			return fmt.Errorf("create session: %w", err)
+		}
+		session = mapAuthSession(row)
+		refreshToken, err = createRefreshToken(ctx, q, sessionID, expiresAt)
+		return err
+	})
+	if err != nil {
+		return model.AuthSession{}, "", err
+	}
+	return session, refreshToken, nil
+}
+
+// RefreshSession обменивает refresh-токен на новый и продлевает сессию. Токен одноразовый:
+// повторное предъявление уже обменянного токена отзывает всю сессию.
+func (s *sessionService) RefreshSession(ctx context.Context, refreshToken string, ttl time.Duration) (model.AuthSession, string, error) {
+	var (
+		session    model.AuthSession
+		newToken   string
+		reuseFound bool
+	)
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		stored, err := q.GetRefreshTokenForUpdate(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		row, err := q.GetAuthSessionForUpdate(ctx, stored.SessionID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if row.RevokedAt.Valid || !timestampToTime(row.ExpiresAt).After(now) {
+			return ErrInvalidRefreshToken
+		}
+		if stored.UsedAt.Valid {
+			// Отзыв должен сохраниться, поэтому транзакция завершается без ошибки.
+			reuseFound = true
+			return q.RevokeAuthSession(ctx, row.ID)
+		}
+		if !timestampToTime(stored.ExpiresAt).After(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := q.MarkRefreshTokenUsed(ctx, stored.TokenHash); err != nil {
+			return fmt.Errorf("mark refresh token used: %w", err)
+		}
+		expiresAt := now.Add(ttl)
+		row, err = q.ExtendAuthSession(ctx, dbsqlc.ExtendAuthSessionParams{
+			ExpiresAt: timePtrToTimestamp(&expiresAt),
+			ID:        row.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("extend session: %w", err)
+		}
+		session = mapAuthSession(row)
+		newToken, err = createRefreshToken(ctx, q, row.ID, expiresAt)
+		return err
+	})
+	if err != nil {
+		return model.AuthSession{}, "", err
+	}
+	if reuseFound {
+		return model.AuthSession{}, "", ErrRefreshTokenReused
+	}
+	return session, newToken, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, sessionID string) error {
+	return s.q.RevokeAuthSession(ctx, sessionID)
+}
+
+// RevokeSessionByRefreshToken завершает сессию, к которой относится refresh-токен. Неизвестный токен не считается ошибкой.
+func (s *sessionService) RevokeSessionByRefreshToken(ctx context.Context, refreshToken string) error {
+	return s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		stored, err := q.GetRefreshTokenForUpdate(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		return q.RevokeAuthSession(ctx, stored.SessionID)
+	})
+}
+
+// RevokeUserSessions завершает все сессии пользователя, например при блокировке.
+func (s *sessionService) RevokeUserSessions(ctx context.Context, userID int64) error {
+	return s.q.RevokeUserAuthSessions(ctx, userID)
+}
+
+// IsRevoked сообщает, что сессия отозвана, истекла или не существует. Реализует auth.RevocationList.
+func (s *sessionService) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	now := time.Now().UTC()
+	active, err := s.q.IsAuthSessionActive(ctx, dbsqlc.IsAuthSessionActiveParams{
+		ID:  sessionID,
+		Now: timePtrToTimestamp(&now),
+	})
+	if err != nil {
+		return false, err
+	}
+	return !active, nil
+}
+
+func createRefreshToken(ctx context.Context, q dbsqlc.Querier, sessionID string, expiresAt time.Time) (string, error) {
+	token, err := randomToken(refreshTokenBytes)
+	if err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	if err := q.CreateRefreshToken(ctx, dbsqlc.CreateRefreshTokenParams{
+		TokenHash: hashToken(token),
+		SessionID: sessionID,
+		ExpiresAt: timePtrToTimestamp(&expiresAt),
+	}); err != nil {
+		return "", fmt.Errorf("create refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// hashToken — в БД хранится только хеш токена, чтобы утечка таблицы не давала рабочих токенов.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+var _ SessionService = (*sessionService)(nil)
+
+// ClaimInitData отмечает ключи initData использованными до expiresAt и сообщает, был ли хоть один
+// из них уже занят. Журнал общий для всех экземпляров API, поэтому повтор не пройдёт и на соседнем.
+func (s *sessionService) ClaimInitData(ctx context.Context, keys []string, expiresAt time.Time) (bool, error) {
+	now := time.Now().UTC()
+	expires := expiresAt.UTC()
+	replayed := false
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		if err := q.DeleteExpiredInitDataKeys(ctx, timePtrToTimestamp(&now)); err != nil {
+			return fmt.Errorf("delete expired init data keys: %w", err)
+		}
+		for _, key := range keys {
+			claimed, err := q.ClaimInitDataKey(ctx, dbsqlc.ClaimInitDataKeyParams{
+				Key:       key,
+				ExpiresAt: timePtrToTimestamp(&expires),
+				Now:       timePtrToTimestamp(&now),
+			})
+			if err != nil {
+				return fmt.Errorf("claim init data key: %w", err)
+			}
+			if claimed == 0 {
+				// Откатываем транзакцию, чтобы остальные ключи повтора не продлились.
+				replayed = true
+				return errInitDataReplayed
+			}
+		}
+		return nil
+	})
+	if replayed {
+		return true, nil
+	}
+	return false, err
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/repository"
+)
+
+// TxRunner runs a function inside a database transaction.
+// Сервисы, которым нужна атомарность нескольких запросов, получают его вместе с Querier.
+type TxRunner interface {
+	WithTx(ctx context.Context, fn func(q dbsqlc.Querier) error, opts ...repository.TxOption) error
+}
+
+var _ TxRunner = (*repository.Repository)(nil)
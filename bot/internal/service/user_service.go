@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
-	"strings"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
 
 	dbsqlc "maxBot/internal/db/sqlc"
 	"maxBot/internal/model"
 )
 
+// ErrUnknownRole роль не входит в volunteer, organizer и admin.
+var ErrUnknownRole = errors.New("unknown role")
+
 // UserService provides high-level operations over users.
 type UserService interface {
 	CreateUser(ctx context.Context, id int64, name string) (model.User, error)
@@ -27,14 +34,18 @@ type UserService interface {
 	UpdateUserLocation(ctx context.Context, id int64, lat, lon *float64) (model.User, error)
 	BlockUser(ctx context.Context, id int64) error
 	UnblockUser(ctx context.Context, id int64) error
+	GetUserStateParams(ctx context.Context, id int64) (map[string]string, error)
+	SetUserStateParams(ctx context.Context, id int64, params map[string]string) error
+	ClearUserStateParams(ctx context.Context, id int64) error
 }
 
 type userService struct {
-	q dbsqlc.Querier
+	q  dbsqlc.Querier
+	tx TxRunner
 }
 
-func NewUserService(q dbsqlc.Querier) UserService {
-	return &userService{q: q}
+func NewUserService(q dbsqlc.Querier, tx TxRunner) UserService {
+	return &userService{q: q, tx: tx}
 }
 
 func (s *userService) CreateUser(ctx context.Context, id int64, name string) (model.User, error) {
@@ -190,6 +201,10 @@ func (s *userService) UpdateUserProfile(ctx context.Context, id int64, username
 }
 
 func (s *userService) UpdateUserRole(ctx context.Context, id int64, role string) (model.User, error) {
+	if err := s.checkRole(ctx, id, role); err != nil {
+		return model.User{}, err
+	}
+
 	params := dbsqlc.UpdateUserRoleParams{
 		ID:   id,
 		Role: role,
@@ -232,14 +247,59 @@ func (s *userService) UpdateUserLocation(ctx context.Context, id int64, lat, lon
 	return mapUser(u)
 }
 
+// BlockUser блокирует пользователя и в той же транзакции завершает его сессии в веб-приложении,
+// поэтому выданные токены перестают работать сразу, а блокировка без отзыва сессий не сохранится.
 func (s *userService) BlockUser(ctx context.Context, id int64) error {
-	return s.q.BlockUser(ctx, id)
+	return s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		if err := q.BlockUser(ctx, id); err != nil {
+			return err
+		}
+		if err := NewSessionService(q, nil).RevokeUserSessions(ctx, id); err != nil {
+			return fmt.Errorf("revoke sessions: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *userService) UnblockUser(ctx context.Context, id int64) error {
 	return s.q.UnblockUser(ctx, id)
 }
 
+// GetUserStateParams возвращает параметры, сохранённые для текущего шага бота, или пустую мапу.
+func (s *userService) GetUserStateParams(ctx context.Context, id int64) (map[string]string, error) {
+	raw, err := s.q.GetUserStateParams(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("decode state params: %w", err)
+	}
+	return params, nil
+}
+
+// SetUserStateParams сохраняет параметры шага, который ожидает текстовый ввод пользователя.
+func (s *userService) SetUserStateParams(ctx context.Context, id int64, params map[string]string) error {
+	if params == nil {
+		params = map[string]string{}
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("encode state params: %w", err)
+	}
+	return s.q.SetUserStateParams(ctx, dbsqlc.SetUserStateParamsParams{
+		UserID: id,
+		Params: raw,
+	})
+}
+
+func (s *userService) ClearUserStateParams(ctx context.Context, id int64) error {
+	return s.q.DeleteUserStateParams(ctx, id)
+}
+
 // syncRoleSpecificTable синхронизирует запись в таблице volunteers или organizers
 // в зависимости от роли пользователя. Использует upsert для безопасного создания/обновления.
 func (s *userService) syncRoleSpecificTable(ctx context.Context, userID int64, role string) error {
@@ -267,24 +327,26 @@ func (s *userService) syncRoleSpecificTable(ctx context.Context, userID int64, r
 		_, err := s.q.UpsertOrganizer(ctx, params)
 		return err
 
-	case "admin":
-		// Для админов создаем запись в таблице admins
-		// Используем простой INSERT, игнорируя ошибку если запись уже существует
-		_, err := s.q.CreateAdmin(ctx, userID)
-		if err != nil {
-			// Проверяем, не является ли ошибка дубликатом ключа
-			// В случае конфликта просто игнорируем (запись уже существует)
-			if strings.Contains(err.Error(), "duplicate key") ||
-				strings.Contains(err.Error(), "unique constraint") {
-				return nil
-			}
-			return err
-		}
+	default:
+		// Запись в admins заводится только вручную, checkRole уже проверил её наличие
 		return nil
+	}
+}
 
-	default:
-		// Для неизвестных ролей ничего не делаем
+// checkRole проверяет, что пользователь может выбрать роль. Роль приходит из callback,
+// поэтому администратором можно стать только при наличии записи в таблице admins.
+func (s *userService) checkRole(ctx context.Context, userID int64, role string) error {
+	switch role {
+	case "volunteer", "organizer":
 		return nil
+	case "admin":
+		_, err := s.q.GetAdmin(ctx, userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotAdmin
+		}
+		return err
+	default:
+		return ErrUnknownRole
 	}
 }
 
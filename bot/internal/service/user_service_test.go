@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+)
+
+// blockQuerier записывает запросы блокировки по порядку.
+type blockQuerier struct {
+	dbsqlc.Querier
+	calls     []string
+	revokeErr error
+}
+
+func (q *blockQuerier) BlockUser(context.Context, int64) error {
+	q.calls = append(q.calls, "block")
+	return nil
+}
+
+func (q *blockQuerier) RevokeUserAuthSessions(context.Context, int64) error {
+	q.calls = append(q.calls, "revoke")
+	return q.revokeErr
+}
+
+func TestBlockUserRevokesSessionsInTx(t *testing.T) {
+	q := &blockQuerier{}
+	// Вне транзакции сервис ничего не вызывает: Querier у него пустой.
+	if err := NewUserService(nil, inlineTx{q: q}).BlockUser(context.Background(), 42); err != nil {
+		t.Fatalf("block: %v", err)
+	}
+	if len(q.calls) != 2 || q.calls[0] != "block" || q.calls[1] != "revoke" {
+		t.Fatalf("calls %v, want block then revoke", q.calls)
+	}
+
+	failing := errors.New("db down")
+	q = &blockQuerier{revokeErr: failing}
+	if err := NewUserService(nil, inlineTx{q: q}).BlockUser(context.Background(), 42); !errors.Is(err, failing) {
+		t.Fatalf("got %v, want revoke error so the transaction rolls back", err)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	maxbot "github.com/rectid/max-bot-api-client-go"
+
+	dbsqlc "maxBot/internal/db/sqlc"
+	"maxBot/internal/model"
+)
+
+var (
+	// ErrNotAdmin пользователь не является администратором.
+	ErrNotAdmin = errors.New("user is not an admin")
+	// ErrVerificationRequestNotFound заявка на верификацию не найдена.
+	ErrVerificationRequestNotFound = errors.New("verification request not found")
+	// ErrVerificationRequestNotPending заявка на верификацию уже рассмотрена.
+	ErrVerificationRequestNotPending = errors.New("verification request is not pending")
+)
+
+// VerificationReviewService handles admin decisions on organizer verification requests.
+type VerificationReviewService interface {
+	ReviewVerificationRequest(ctx context.Context, requestID int32, adminID int64, approve bool, comment *string) (model.OrganizerVerificationRequest, error)
+}
+
+type verificationReviewService struct {
+	tx  TxRunner
+	api *maxbot.Api
+}
+
+func NewVerificationReviewService(tx TxRunner, api *maxbot.Api) VerificationReviewService {
+	return &verificationReviewService{tx: tx, api: api}
+}
+
+// ReviewVerificationRequest закрывает заявку решением администратора. При одобрении организатору
+// проставляются verified_at/verified_by. После коммита организатор получает уведомление.
+func (s *verificationReviewService) ReviewVerificationRequest(ctx context.Context, requestID int32, adminID int64, approve bool, comment *string) (model.OrganizerVerificationRequest, error) {
+	status := "rejected"
+	if approve {
+		status = "approved"
+	}
+
+	var reviewed model.OrganizerVerificationRequest
+	err := s.tx.WithTx(ctx, func(q dbsqlc.Querier) error {
+		if _, err := q.GetAdmin(ctx, adminID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotAdmin
+			}
+			return err
+		}
+
+		item, err := q.ReviewOrganizerVerificationRequest(ctx, dbsqlc.ReviewOrganizerVerificationRequestParams{
+			ID:           requestID,
+			Status:       status,
+			AdminComment: stringPtrToText(comment),
+			ReviewedBy:   int64ToInt8(adminID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, getErr := q.GetOrganizerVerificationRequestByID(ctx, requestID); errors.Is(getErr, pgx.ErrNoRows) {
+				return ErrVerificationRequestNotFound
+			}
+			return ErrVerificationRequestNotPending
+		}
+		if err != nil {
+			return fmt.Errorf("review verification request: %w", err)
+		}
+		reviewed = mapOrganizerVerificationRequest(item)
+
+		if approve {
+			now := time.Now()
+			if _, err := q.SetOrganizerVerification(ctx, dbsqlc.SetOrganizerVerificationParams{
+				ID:         item.OrganizerID,
+				VerifiedAt: timePtrToTimestamp(&now),
+				VerifiedBy: int64ToInt8(adminID),
+			}); err != nil {
+				return fmt.Errorf("set organizer verification: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.OrganizerVerificationRequest{}, err
+	}
+
+	s.notifyOrganizer(ctx, reviewed)
+	return reviewed, nil
+}
+
+// notifyOrganizer сообщает организатору о решении. Ошибка отправки не отменяет уже сохранённое решение.
+func (s *verificationReviewService) notifyOrganizer(ctx context.Context, request model.OrganizerVerificationRequest) {
+	if s.api == nil {
+		return
+	}
+
+	text := "Ваша заявка на верификацию отклонена. Обновите данные в разделе «Верификация» и отправьте заявку повторно."
+	if request.Status == "approved" {
+		text = "Ваша организация успешно прошла верификацию! Теперь вам доступен полный функционал организатора."
+	}
+	if request.AdminComment != nil && *request.AdminComment != "" {
+		text += "\n\nКомментарий администратора: " + *request.AdminComment
+	}
+
+	msg := maxbot.NewMessage().
+		SetUser(request.OrganizerID).
+		SetText(text)
+	if _, err := s.api.Messages.Send(ctx, msg); err != nil {
+		log.Printf("failed to notify organizer %d about verification: %v", request.OrganizerID, err)
+	}
+}
+
+var _ VerificationReviewService = (*verificationReviewService)(nil)
@@ -19,6 +19,13 @@ type VolunteerApplicationService interface {
 	ListApplicationsByVolunteer(ctx context.Context, volunteerID *int64, limit, offset int32) ([]model.VolunteerApplication, error)
 	ListApplicationsForOrganizer(ctx context.Context, organizerID *int64, limit, offset int32) ([]model.VolunteerApplication, error)
 	ListPendingApplicationsByEvent(ctx context.Context, eventID *int32, limit, offset int32) ([]model.VolunteerApplication, error)
+	ListPendingApplicants(ctx context.Context, eventID int32, limit, offset int32) ([]model.PendingApplicant, error)
+	CountPendingApplicationsByEvent(ctx context.Context, eventID int32) (int64, error)
+	GetWaitlistPosition(ctx context.Context, eventID int32, volunteerID int64) (int64, error)
+	CountWaitlistedApplicationsByEvent(ctx context.Context, eventID int32) (int64, error)
+	CountApplicationsByVolunteerStatus(ctx context.Context, volunteerID int64) (map[string]int64, error)
+	ListVolunteerApplicationsWithEvents(ctx context.Context, volunteerID int64, status *string, eventStatuses []string, limit, offset int32) ([]model.VolunteerApplicationWithEvent, error)
+	CountVolunteerApplicationsWithEvents(ctx context.Context, volunteerID int64, status *string, eventStatuses []string) (int64, error)
 	UpdateVolunteerApplicationStatus(ctx context.Context, id int32, status string, rejectionReason *string, reviewedBy *int64) (model.VolunteerApplication, error)
 	ResetVolunteerApplicationReview(ctx context.Context, id int32) (model.VolunteerApplication, error)
 }
@@ -152,6 +159,82 @@ func (s *volunteerApplicationService) ListPendingApplicationsByEvent(ctx context
 	return mapVolunteerApplications(items), nil
 }
 
+func (s *volunteerApplicationService) ListPendingApplicants(ctx context.Context, eventID int32, limit, offset int32) ([]model.PendingApplicant, error) {
+	params := dbsqlc.ListPendingApplicantsByEventParams{
+		EventID: int32ToInt4(eventID),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	items, err := s.q.ListPendingApplicantsByEvent(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return mapPendingApplicants(items), nil
+}
+
+func (s *volunteerApplicationService) CountPendingApplicationsByEvent(ctx context.Context, eventID int32) (int64, error) {
+	return s.q.CountPendingApplicationsByEvent(ctx, int32ToInt4(eventID))
+}
+
+// GetWaitlistPosition возвращает позицию волонтёра в листе ожидания (с 1) или 0, если он не в листе.
+func (s *volunteerApplicationService) GetWaitlistPosition(ctx context.Context, eventID int32, volunteerID int64) (int64, error) {
+	return s.q.GetWaitlistPosition(ctx, dbsqlc.GetWaitlistPositionParams{
+		EventID:     int32ToInt4(eventID),
+		VolunteerID: int64ToInt8(volunteerID),
+	})
+}
+
+func (s *volunteerApplicationService) CountWaitlistedApplicationsByEvent(ctx context.Context, eventID int32) (int64, error) {
+	return s.q.CountWaitlistedApplicationsByEvent(ctx, int32ToInt4(eventID))
+}
+
+// CountApplicationsByVolunteerStatus возвращает количество заявок волонтёра по статусам.
+func (s *volunteerApplicationService) CountApplicationsByVolunteerStatus(ctx context.Context, volunteerID int64) (map[string]int64, error) {
+	rows, err := s.q.CountApplicationsByVolunteerGroupedByStatus(ctx, int64ToInt8(volunteerID))
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ListVolunteerApplicationsWithEvents возвращает заявки волонтёра с данными события.
+// Пустые status и eventStatuses не ограничивают выборку.
+func (s *volunteerApplicationService) ListVolunteerApplicationsWithEvents(ctx context.Context, volunteerID int64, status *string, eventStatuses []string, limit, offset int32) ([]model.VolunteerApplicationWithEvent, error) {
+	params := dbsqlc.ListVolunteerApplicationsWithEventsParams{
+		VolunteerID:   int64ToInt8(volunteerID),
+		Status:        stringPtrToText(status),
+		EventStatuses: nilIfEmpty(eventStatuses),
+		Limit:         limit,
+		Offset:        offset,
+	}
+	rows, err := s.q.ListVolunteerApplicationsWithEvents(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return mapVolunteerApplicationsWithEvents(rows), nil
+}
+
+func (s *volunteerApplicationService) CountVolunteerApplicationsWithEvents(ctx context.Context, volunteerID int64, status *string, eventStatuses []string) (int64, error) {
+	params := dbsqlc.CountVolunteerApplicationsWithEventsParams{
+		VolunteerID:   int64ToInt8(volunteerID),
+		Status:        stringPtrToText(status),
+		EventStatuses: nilIfEmpty(eventStatuses),
+	}
+	return s.q.CountVolunteerApplicationsWithEvents(ctx, params)
+}
+
+// nilIfEmpty превращает пустой срез в NULL, чтобы фильтр по массиву не применялся.
+func nilIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
 func (s *volunteerApplicationService) UpdateVolunteerApplicationStatus(ctx context.Context, id int32, status string, rejectionReason *string, reviewedBy *int64) (model.VolunteerApplication, error) {
 	params := dbsqlc.UpdateVolunteerApplicationStatusParams{
 		ID:              id,
@@ -0,0 +1,48 @@
+#!/usr/bin/env bash
+# Проверяет, что поиск карты в радиусе и в области идёт через GiST-индексы.
+# На маленькой базе планировщик честно выбирает Seq Scan, поэтому он отключается:
+# скрипт проверяет, что индекс применим к запросу, а не что он выгоднее.
+set -euo pipefail
+
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+ENV_FILE="${ENV_FILE:-${ROOT_DIR}/.env}"
+
+if [[ -f "${ENV_FILE}" ]]; then
+  set -a
+  source "${ENV_FILE}"
+  set +a
+fi
+
+if [[ -z "${DATABASE_URL:-}" ]]; then
+  echo "DATABASE_URL is not set. Provide it in the environment or via ENV_FILE" >&2
+  exit 1
+fi
+
+PSQL_BIN="${PSQL_BIN:-psql}"
+
+explain() {
+  local title="$1" index="$2" query="$3"
+  echo -e "\n--- ${title} ---"
+  local plan
+  plan="$(${PSQL_BIN} "${DATABASE_URL}" -X -q -At -c "SET enable_seqscan = off; EXPLAIN ${query}")"
+  echo "${plan}"
+  if ! grep -q "${index}" <<<"${plan}"; then
+    echo "FAIL: ${index} is not used" >&2
+    exit 1
+  fi
+}
+
+explain "radius 15 km around Moscow" idx_events_location_earth "
+SELECT e.id
+FROM events e
+WHERE e.status IN ('open', 'full')
+  AND earth_box(ll_to_earth(55.75, 37.61), 15 * 1000) @> ll_to_earth(e.location_lat::float8, e.location_lon::float8)
+  AND earth_distance(ll_to_earth(55.75, 37.61), ll_to_earth(e.location_lat::float8, e.location_lon::float8)) <= 15 * 1000"
+
+explain "bbox of central Moscow" idx_events_location_point "
+SELECT e.id
+FROM events e
+WHERE e.status IN ('open', 'full')
+  AND point(e.location_lon::float8, e.location_lat::float8) <@ box(point(37.3, 55.55), point(37.95, 55.95))"
+
+echo -e "\nOK: map queries are index-assisted"
@@ -29,8 +29,9 @@ export interface MapEventsResponse {
     lat: number;
     limit: number;
     lon: number;
-    offset: number;
+    nextCursor: string | null;
     radiusKm: number;
+    totalEstimate?: number;
   };
 }
 
@@ -38,9 +39,15 @@ export interface AuthSessionRequest {
   initData: string;
 }
 
+export interface AuthRefreshRequest {
+  refreshToken: string;
+}
+
 export interface AuthSessionResponse {
   token: string;
   expiresIn: number;
+  refreshToken: string;
+  refreshExpiresIn: number;
   user: User;
 }
 
@@ -49,7 +56,8 @@ export interface MapEventsParams {
   lon: number;
   radius_km: number;
   limit?: number;
-  offset?: number;
+  cursor?: string;
+  total?: boolean;
   categories?:  number[];
   category_id?: number[];
 }
@@ -22,18 +22,11 @@ export const useAuth = (): UseAuthReturn => {
   // Инициализация при загрузке и подписка на изменения
   useEffect(() => {
     const initializeAuth = () => {
-      const token = AuthService.getToken();
       const userData = AuthService.getUser();
 
-      if (token && userData) {
-        if (AuthService.isAuthenticated()) {
-          setIsAuthenticated(true);
-          setUser(userData);
-        } else {
-          AuthService.logout();
-          setIsAuthenticated(false);
-          setUser(null);
-        }
+      if (userData && AuthService.isAuthenticated()) {
+        setIsAuthenticated(true);
+        setUser(userData);
       }
     };
 
@@ -53,6 +46,9 @@ export const useAuth = (): UseAuthReturn => {
   }, []);
 
   const login = useCallback(async (initData: string): Promise<boolean> => {
+    // initData одноразовый: повторный вход с ним вернёт ошибку, а живую сессию продлит refresh-токен
+    if (AuthService.isAuthenticated()) return true;
+
     setLoading(true);
     setError(null);
 
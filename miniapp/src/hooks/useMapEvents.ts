@@ -43,7 +43,7 @@ export const useMapEvents = (): UseMapEventsReturn => {
     const base = isUserEvents ? `user_${user?.id}` : 'public';
     return `${base}_${params.lat}_${params.lon}_${params.radius_km}_${
       params.category_id?.join(',') || ''
-    }`;
+    }_${params.cursor || ''}`;
   };
 
   const fetchMapEvents = useCallback(
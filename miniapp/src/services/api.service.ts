@@ -4,11 +4,17 @@ import {
   MapEventsResponse,
   AuthSessionRequest,
   AuthSessionResponse,
+  AuthRefreshRequest,
   MapEventsParams,
 } from '../common/types/api';
 
 const BASE_URL = import.meta.env.VITE_BASE_URL;
 
+// Возвращает новый access-токен или null, если сессию продлить не удалось
+type TokenRefresher = () => Promise<string | null>;
+
+type RetriableRequestConfig = AxiosRequestConfig & { _retried?: boolean };
+
 class ApiError extends Error {
   constructor(message: string, public status?: number, public data?: any) {
     super(message);
@@ -19,6 +25,7 @@ class ApiError extends Error {
 export class ApiService {
   private client: AxiosInstance;
   private token: string | null = null;
+  private refresher: TokenRefresher | null = null;
 
   constructor(baseURL = BASE_URL) {
     this.client = axios.create({
@@ -44,8 +51,24 @@ export class ApiService {
     // Перехватчик ответов
     this.client.interceptors.response.use(
       (response) => response,
-      (error) => {
+      async (error) => {
         if (axios.isAxiosError(error)) {
+          // Access-токен истёк или отозван: один раз продлеваем сессию и повторяем запрос.
+          // Ручки /auth/* не повторяем, иначе неудачный refresh зациклится.
+          const config = error.config as RetriableRequestConfig | undefined;
+          if (
+            error.response?.status === 401 &&
+            config &&
+            !config._retried &&
+            !config.url?.startsWith('/auth/') &&
+            this.refresher
+          ) {
+            config._retried = true;
+            const token = await this.refresher();
+            // Новый токен подставит перехватчик запросов
+            if (token) return this.client.request(config);
+          }
+
           const status = error.response?.status;
           const data = error.response?.data;
           let message = error.message;
@@ -69,6 +92,10 @@ export class ApiService {
     this.token = null;
   }
 
+  setTokenRefresher(refresher: TokenRefresher | null) {
+    this.refresher = refresher;
+  }
+
   private async request<T = any>(config: AxiosRequestConfig): Promise<T> {
     const response = await this.client.request<T>(config);
     return response.data;
@@ -77,17 +104,28 @@ export class ApiService {
   async createSession(
     authData: AuthSessionRequest,
   ): Promise<AuthSessionResponse> {
-    const sessionParams = new URLSearchParams();
-
-    sessionParams.append('session', String(authData.initData));
-
+    // initData передаём в теле, чтобы он не попадал в логи запросов
     return this.request<AuthSessionResponse>({
-      method: 'GET',
+      method: 'POST',
       url: '/auth/session',
-      params: sessionParams,
+      data: { initData: String(authData.initData) },
+    });
+  }
+
+  async refreshSession(
+    data: AuthRefreshRequest,
+  ): Promise<AuthSessionResponse> {
+    return this.request<AuthSessionResponse>({
+      method: 'POST',
+      url: '/auth/refresh',
+      data,
     });
   }
 
+  async logoutSession(data: AuthRefreshRequest): Promise<void> {
+    await this.request({ method: 'POST', url: '/auth/logout', data });
+  }
+
   async getMapEvents(params: MapEventsParams): Promise<MapEventsResponse> {
     const searchParams = new URLSearchParams();
 
@@ -99,7 +137,8 @@ export class ApiService {
       searchParams.append('radius_km', String(params.radius_km));
 
     searchParams.append('limit', String(params.limit || 50));
-    searchParams.append('offset', String(params.offset || 0));
+    if (params.cursor) searchParams.append('cursor', params.cursor);
+    if (params.total) searchParams.append('total', 'true');
 
     const categories = params.categories || params.category_id;
     if (categories?.length) {
@@ -129,7 +168,8 @@ export class ApiService {
       searchParams.append('radius_km', String(params.radius_km));
 
     searchParams.append('limit', String(params.limit || 50));
-    searchParams.append('offset', String(params.offset || 0));
+    if (params.cursor) searchParams.append('cursor', params.cursor);
+    if (params.total) searchParams.append('total', 'true');
 
     const categories = params.categories || params.category_id;
     if (categories?.length) {
@@ -1,17 +1,24 @@
 // src/services/auth.service.ts
 import { apiService } from './api.service';
-import { User } from '../common/types/api';
+import { AuthSessionResponse, User } from '../common/types/api';
 
 type SessionStorageValue = {
   token: string;
   expiresAt: number; // ms since epoch
+  refreshToken: string;
+  refreshExpiresAt: number; // ms since epoch
   user: User;
 };
 
+// За сколько до истечения access-токена продлевать сессию
+const REFRESH_AHEAD_MS = 60_000;
+
 export class AuthService {
   private static readonly STORAGE_KEY = 'max_auth_session';
   private static cache: SessionStorageValue | null = null;
   private static authListeners: ((isAuthenticated: boolean) => void)[] = [];
+  private static refreshing: Promise<string | null> | null = null;
+  private static refreshTimer: ReturnType<typeof setTimeout> | null = null;
 
   private static readStorage(): SessionStorageValue | null {
     if (typeof window === 'undefined') return null;
@@ -19,7 +26,8 @@ export class AuthService {
       const raw = sessionStorage.getItem(this.STORAGE_KEY);
       if (!raw) return null;
       const parsed = JSON.parse(raw) as SessionStorageValue;
-      if (!parsed.token || !parsed.expiresAt) return null;
+      if (!parsed.token || !parsed.refreshToken || !parsed.refreshExpiresAt)
+        return null;
       return parsed;
     } catch {
       return null;
@@ -43,41 +51,53 @@ export class AuthService {
   static async login(initData: string): Promise<{ token: string; user: User }> {
     try {
       const response = await apiService.createSession({ initData });
-
-      const { token, expiresIn, user } = response;
-      const expiresAt = Date.now() + (Number(expiresIn) || 0) * 1000;
-
-      const payload = { token, expiresAt, user };
-      this.writeStorage(payload);
-      apiService.setToken(token);
-
-      return { token, user };
+      this.saveSession(response);
+      return { token: response.token, user: response.user };
     } catch (e) {
       this.clearAuth();
       return Promise.reject(e);
     }
   }
 
+  // Продлевает сессию по refresh-токену. Параллельные вызовы ждут один запрос:
+  // refresh-токен одноразовый, второй запрос с ним завершил бы сессию.
+  static refresh(): Promise<string | null> {
+    if (this.refreshing) return this.refreshing;
+
+    const session = this.getSession();
+    if (!session) return Promise.resolve(null);
+
+    this.refreshing = apiService
+      .refreshSession({ refreshToken: session.refreshToken })
+      .then((response) => {
+        this.saveSession(response);
+        return response.token;
+      })
+      .catch(() => {
+        this.clearAuth();
+        return null;
+      })
+      .finally(() => {
+        this.refreshing = null;
+      });
+    return this.refreshing;
+  }
+
+  // Завершает сессию на сервере, чтобы refresh-токен нельзя было использовать повторно
   static logout(): void {
+    const session = this.getSession();
+    if (session) {
+      apiService
+        .logoutSession({ refreshToken: session.refreshToken })
+        .catch(() => {});
+    }
     this.clearAuth();
-    apiService.clearToken();
   }
 
   static getToken(): string | null {
-    if (this.cache) {
-      if (this.cache.expiresAt > Date.now()) return this.cache.token;
-      this.clearAuth();
-      return null;
-    }
-
-    const stored = this.readStorage();
-    if (!stored) return null;
-    if (stored.expiresAt <= Date.now()) {
-      this.clearAuth();
-      return null;
-    }
-    this.cache = stored;
-    return stored.token;
+    const session = this.getSession();
+    if (!session || session.expiresAt <= Date.now()) return null;
+    return session.token;
   }
 
   static getUser(): User | null {
@@ -86,26 +106,22 @@ export class AuthService {
     return stored?.user ?? null;
   }
 
+  // Сессия жива, пока действует refresh-токен: истёкший access-токен продлевается сам
   static isAuthenticated(): boolean {
-    const token = this.getToken();
-    if (!token) return false;
-
-    const payload = this.decodeTokenPayload(token);
-    if (!payload) return true;
-    if (payload.exp) {
-      const expMs = payload.exp * 1000;
-      return expMs > Date.now();
-    }
-    return true;
+    return this.getSession() !== null;
   }
 
   static initializeAuth(): void {
-    const stored = this.readStorage();
-    if (stored && stored.expiresAt > Date.now()) {
-      this.cache = stored;
-      apiService.setToken(stored.token);
-    } else {
+    const session = this.getSession();
+    if (!session) {
       this.clearAuth();
+      return;
+    }
+    if (session.expiresAt > Date.now()) {
+      apiService.setToken(session.token);
+      this.scheduleRefresh(session.expiresAt);
+    } else {
+      void this.refresh();
     }
     this.notifyAuthChange();
   }
@@ -127,22 +143,48 @@ export class AuthService {
     this.authListeners.forEach((callback) => callback(isAuthenticated));
   }
 
-  private static clearAuth(): void {
-    this.writeStorage(null);
-    apiService.clearToken();
+  private static getSession(): SessionStorageValue | null {
+    const session = this.cache ?? this.readStorage();
+    if (!session) return null;
+    if (session.refreshExpiresAt <= Date.now()) {
+      this.clearAuth();
+      return null;
+    }
+    this.cache = session;
+    return session;
   }
 
-  private static decodeTokenPayload(token: string): any | null {
-    try {
-      const parts = token.split('.');
-      if (parts.length < 2) return null;
-      const raw = parts[1];
-      const b64 = raw.replace(/-/g, '+').replace(/_/g, '/');
-      const pad = b64.length % 4 === 0 ? '' : '='.repeat(4 - (b64.length % 4));
-      const decoded = atob(b64 + pad);
-      return JSON.parse(decoded);
-    } catch {
-      return null;
+  private static saveSession(response: AuthSessionResponse): void {
+    const now = Date.now();
+    const session: SessionStorageValue = {
+      token: response.token,
+      expiresAt: now + (Number(response.expiresIn) || 0) * 1000,
+      refreshToken: response.refreshToken,
+      refreshExpiresAt: now + (Number(response.refreshExpiresIn) || 0) * 1000,
+      user: response.user,
+    };
+    apiService.setToken(session.token);
+    this.writeStorage(session);
+    this.scheduleRefresh(session.expiresAt);
+  }
+
+  private static scheduleRefresh(expiresAt: number): void {
+    if (this.refreshTimer) clearTimeout(this.refreshTimer);
+    const delay = Math.max(expiresAt - Date.now() - REFRESH_AHEAD_MS, 0);
+    this.refreshTimer = setTimeout(() => {
+      this.refreshTimer = null;
+      void this.refresh();
+    }, delay);
+  }
+
+  private static clearAuth(): void {
+    if (this.refreshTimer) {
+      clearTimeout(this.refreshTimer);
+      this.refreshTimer = null;
     }
+    this.writeStorage(null);
+    apiService.clearToken();
   }
 }
+
+apiService.setTokenRefresher(() => AuthService.refresh());
@@ -22,7 +22,6 @@ const defaultParams: MapEventsParams = {
   lon: 30.325,
   radius_km: 10,
   limit: 50,
-  offset: 0,
 };
 
 export const useMapStore = create<MapState>((set) => ({